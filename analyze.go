@@ -0,0 +1,136 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+)
+
+// Analysis describes the dependency graph of an application
+// as built by [Analyze].
+type Analysis struct {
+	// ProvidedTypes lists the types made available to the application,
+	// including those provided by Fx itself, in the order they were provided.
+	ProvidedTypes []string
+
+	// Invokes lists the functions passed to [Invoke],
+	// in the order they would run.
+	Invokes []string
+
+	// ConstructionPlan lists the constructors that would be called
+	// to satisfy the invoked functions, in the order they would be called.
+	// Constructors whose values no invoked function needs are omitted.
+	ConstructionPlan []string
+
+	// UnsatisfiedInvokes holds an error for each invoked function
+	// whose dependencies could not be satisfied.
+	// Each error names the function and the missing types.
+	UnsatisfiedInvokes []error
+
+	// DotGraph is a DOT language visualization of the dependency graph.
+	DotGraph DotGraph
+
+	// Indexes in Invokes of the functions in UnsatisfiedInvokes.
+	unsatisfiedAt []int
+}
+
+// Analyze builds the dependency graph of an application from the given
+// options without running any constructors, decorators,
+// or invoked functions, and reports its shape.
+// It is intended for tools that inspect applications.
+//
+// Like [ValidateApp], Analyze resolves the dependencies of every invoked
+// function, but it does not stop at the first one that cannot be
+// satisfied; such functions are reported in the returned Analysis instead.
+// Analyze returns an error if the options themselves are invalid,
+// such as when a constructor is provided twice.
+//
+// Because no constructors run, custom loggers given with [WithLogger]
+// are not built, and no lifecycle hooks are registered.
+func Analyze(opts ...Option) (*Analysis, error) {
+	analysis := new(Analysis)
+	opts = append(opts, validate(true), analyze(analysis))
+	app := New(opts...)
+	if err := app.Err(); err != nil {
+		return nil, err
+	}
+
+	graph, err := app.dotGraph()
+	if err != nil {
+		return nil, err
+	}
+	analysis.DotGraph = graph
+	return analysis, nil
+}
+
+// analyze makes the App record the shape of its graph into a.
+func analyze(a *Analysis) Option {
+	return analyzeOption{analysis: a}
+}
+
+type analyzeOption struct {
+	analysis *Analysis
+}
+
+func (o analyzeOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.analyze Option should be passed to top-level App, " +
+			"not to fx.Module")
+	} else {
+		m.app.analysis = o.analysis
+	}
+}
+
+func (analyzeOption) String() string {
+	return "fx.analyze()"
+}
+
+// The following methods are no-ops on a nil Analysis,
+// so that callers need not check whether the App is being analyzed.
+
+func (a *Analysis) recordProvided(types []string) {
+	if a != nil {
+		a.ProvidedTypes = append(a.ProvidedTypes, types...)
+	}
+}
+
+func (a *Analysis) recordConstructor(name string) {
+	if a != nil {
+		a.ConstructionPlan = append(a.ConstructionPlan, name)
+	}
+}
+
+func (a *Analysis) recordInvoke(name string) {
+	if a != nil {
+		a.Invokes = append(a.Invokes, name)
+	}
+}
+
+// recordInvokeError records err from an invoked function and reports
+// whether the remaining functions should still be invoked.
+func (a *Analysis) recordInvokeError(err error) (keepGoing bool) {
+	if a == nil {
+		return false
+	}
+	a.UnsatisfiedInvokes = append(a.UnsatisfiedInvokes, err)
+	a.unsatisfiedAt = append(a.unsatisfiedAt, len(a.Invokes)-1)
+	return true
+}
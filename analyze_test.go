@@ -0,0 +1,119 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+type (
+	analyzeConfig struct{}
+	analyzeDB     struct{}
+	analyzeServer struct{}
+	analyzeCache  struct{}
+)
+
+func newAnalyzeDB(*analyzeConfig) *analyzeDB           { return &analyzeDB{} }
+func newAnalyzeServer(*analyzeDB) *analyzeServer       { return &analyzeServer{} }
+func newAnalyzeCache(*analyzeConfig) *analyzeCache     { return &analyzeCache{} }
+func runAnalyzeServer(*analyzeServer)                  {}
+func warmAnalyzeCache(*analyzeCache, *analyzeDB) error { return nil }
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reflects graph", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		record := func(name string) func() {
+			return func() { calls = append(calls, name) }
+		}
+
+		analysis, err := fx.Analyze(
+			fx.Supply(&analyzeConfig{}),
+			fx.Provide(newAnalyzeDB, newAnalyzeServer, newAnalyzeCache),
+			fx.Decorate(func(db *analyzeDB) *analyzeDB {
+				record("decorate")()
+				return db
+			}),
+			fx.Invoke(runAnalyzeServer),
+			fx.Invoke(record("invoke")),
+			fx.Module("child",
+				fx.Invoke(func(*analyzeDB) { record("child invoke")() }),
+			),
+		)
+		require.NoError(t, err)
+		assert.Empty(t, calls, "nothing may run during analysis")
+
+		assert.Subset(t, analysis.ProvidedTypes, []string{
+			"*fx_test.analyzeConfig",
+			"*fx_test.analyzeDB",
+			"*fx_test.analyzeServer",
+			"*fx_test.analyzeCache",
+			"fx.Lifecycle",
+		})
+		assert.Len(t, analysis.Invokes, 3)
+		assert.Equal(t, "go.uber.org/fx_test.runAnalyzeServer()", analysis.Invokes[1],
+			"invokes of child modules come first")
+		assert.Equal(t, []string{
+			"go.uber.org/fx_test.newAnalyzeDB()",
+			"go.uber.org/fx_test.newAnalyzeServer()",
+		}, analysis.ConstructionPlan, "unused constructors must be omitted")
+		assert.Empty(t, analysis.UnsatisfiedInvokes)
+		assert.Contains(t, string(analysis.DotGraph), "digraph")
+		assert.Contains(t, string(analysis.DotGraph), "*fx_test.analyzeServer")
+	})
+
+	t.Run("unsatisfied invokes", func(t *testing.T) {
+		t.Parallel()
+
+		analysis, err := fx.Analyze(
+			fx.Provide(newAnalyzeDB, newAnalyzeCache),
+			fx.Invoke(runAnalyzeServer),
+			fx.Invoke(warmAnalyzeCache),
+		)
+		require.NoError(t, err)
+
+		assert.Len(t, analysis.Invokes, 2)
+		require.Len(t, analysis.UnsatisfiedInvokes, 2, "analysis must not stop at the first failure")
+		assert.ErrorContains(t, analysis.UnsatisfiedInvokes[0], "runAnalyzeServer")
+		assert.ErrorContains(t, analysis.UnsatisfiedInvokes[0], "missing type: *fx_test.analyzeServer")
+		assert.ErrorContains(t, analysis.UnsatisfiedInvokes[1], "warmAnalyzeCache")
+		assert.ErrorContains(t, analysis.UnsatisfiedInvokes[1], "*fx_test.analyzeConfig")
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		t.Parallel()
+
+		analysis, err := fx.Analyze(
+			fx.Provide(newAnalyzeDB),
+			fx.Provide(newAnalyzeDB),
+		)
+		require.Error(t, err)
+		assert.Nil(t, analysis)
+		assert.Contains(t, err.Error(), "already provided")
+	})
+}
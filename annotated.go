@@ -237,6 +237,9 @@ func (pt paramTagsAnnotation) apply(ann *annotated) error {
 	if len(ann.ParamTags) > 0 {
 		return errors.New("cannot apply more than one line of ParamTags")
 	}
+	if ann.ParamStruct != nil {
+		return errors.New("cannot apply ParamTags with fx.ParamStruct")
+	}
 	for _, tag := range pt.tags {
 		if err := verifyAnnotateTag(tag); err != nil {
 			return err
@@ -353,6 +356,122 @@ func ParamTags(tags ...string) Annotation {
 	return paramTagsAnnotation{tags}
 }
 
+type paramStructAnnotation struct {
+	tags map[string]string
+}
+
+var _ Annotation = paramStructAnnotation{}
+
+func (ps paramStructAnnotation) apply(ann *annotated) error {
+	if ann.ParamStruct != nil {
+		return errors.New("cannot apply more than one fx.ParamStruct")
+	}
+	if len(ann.ParamTags) > 0 {
+		return errors.New("cannot apply fx.ParamStruct with ParamTags")
+	}
+	if ft := reflect.TypeOf(ann.Target); ft != nil && ft.Kind() == reflect.Func && ft.IsVariadic() {
+		return errors.New("cannot apply fx.ParamStruct to a variadic function")
+	}
+	for name, tag := range ps.tags {
+		if err := verifyAnnotateTag(tag); err != nil {
+			return fmt.Errorf("invalid tag for field %v: %w", name, err)
+		}
+	}
+	ann.ParamStruct = ps.tags
+	if ann.ParamStruct == nil {
+		ann.ParamStruct = map[string]string{}
+	}
+	return nil
+}
+
+// build builds and returns a function that takes a parameter object
+// in place of the struct parameter of the annotated function.
+func (ps paramStructAnnotation) build(ann *annotated) (interface{}, error) {
+	ft := reflect.TypeOf(ann.Target)
+
+	idx := -1
+	paramTypes := make([]reflect.Type, ft.NumIn())
+	for i := range paramTypes {
+		paramTypes[i] = ft.In(i)
+		if t := paramTypes[i]; t.Kind() == reflect.Struct && !isIn(t) {
+			if idx >= 0 {
+				return nil, fmt.Errorf("fx.ParamStruct: %v has more than one struct parameter", ft)
+			}
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return nil, fmt.Errorf("fx.ParamStruct: %v has no struct parameter "+
+			"that doesn't embed fx.In", ft)
+	}
+
+	structType := paramTypes[idx]
+	inFields := []reflect.StructField{_inAnnotationField}
+	var fieldIdx []int
+	for i := 0; i < structType.NumField(); i++ {
+		f := structType.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		inFields = append(inFields, reflect.StructField{
+			Name: f.Name,
+			Type: f.Type,
+			Tag:  reflect.StructTag(ps.tags[f.Name]),
+		})
+		fieldIdx = append(fieldIdx, i)
+	}
+	for name := range ps.tags {
+		if f, ok := structType.FieldByName(name); !ok || !f.IsExported() || len(f.Index) > 1 {
+			return nil, fmt.Errorf("fx.ParamStruct: %v has no exported field %v", structType, name)
+		}
+	}
+	paramTypes[idx] = reflect.StructOf(inFields)
+
+	resultTypes, _ := ann.currentResultTypes()
+	origFn := reflect.ValueOf(ann.Target)
+	newFnType := reflect.FuncOf(paramTypes, resultTypes, false)
+	newFn := reflect.MakeFunc(newFnType, func(args []reflect.Value) []reflect.Value {
+		param := reflect.New(structType).Elem()
+		for j, i := range fieldIdx {
+			param.Field(i).Set(args[idx].Field(j + 1))
+		}
+		args[idx] = param
+		return origFn.Call(args)
+	})
+	return newFn.Interface(), nil
+}
+
+// ParamStruct is an Annotation that turns the struct parameter of
+// a function into a parameter object, as if the struct embedded [In]:
+// each of its exported fields is built by the container.
+// Tags maps the names of fields to the tags they'd have in a parameter
+// object, so that a struct declared elsewhere, like the options of
+// a third-party constructor, can be wired without writing an adapter.
+//
+//	// Declared in a package that doesn't use Fx.
+//	type Options struct {
+//		Logger *zap.Logger
+//		DB     *sql.DB
+//		Cache  *redis.Client
+//	}
+//
+//	func NewClient(opts Options) *Client
+//
+//	fx.Provide(
+//		fx.Annotate(client.NewClient, fx.ParamStruct(map[string]string{
+//			"DB":    `name:"ro"`,
+//			"Cache": `optional:"true"`,
+//		})),
+//	)
+//
+// The function must have exactly one struct parameter that doesn't embed
+// [In]. Unexported fields of the struct are left as zero values,
+// and its own field tags are ignored.
+// ParamStruct cannot be used with [ParamTags] or a variadic function.
+func ParamStruct(tags map[string]string) Annotation {
+	return paramStructAnnotation{tags}
+}
+
 type resultTagsAnnotation struct {
 	tags []string
 }
@@ -538,6 +657,16 @@ func (rt resultTagsAnnotation) results(ann *annotated) (
 //		// ...
 //	}, fx.ResultTags(`name:"ro"`))
 //
+// As with fx.Out structs, a group tag may be followed by `,flatten`
+// to contribute each element of a slice result to the value group
+// individually. For example, the following adds each of the routes
+// returned by a third-party constructor to the "routes" group.
+//
+//	fx.Annotate(thirdparty.Routes, fx.ResultTags(`group:"routes,flatten"`))
+//
+// A group tag may also give the value a priority with `,priority=N`.
+// See the package documentation for value group priorities.
+//
 // ResultTags cannot be used on a function that returns an fx.Out struct.
 func ResultTags(tags ...string) Annotation {
 	return resultTagsAnnotation{tags}
@@ -1524,27 +1653,182 @@ func (fr *fromAnnotation) parameters(ann *annotated) (
 	}, nil
 }
 
+type groupPresenceAnnotation struct{}
+
+var _ Annotation = groupPresenceAnnotation{}
+
+// GroupPresence is an Annotation that lets a function tell apart a value
+// group that has no contributors at all from one whose contributors
+// produced no values.
+//
+// Each bool parameter that immediately follows a value group parameter
+// is set to true if at least one constructor contributes to that group,
+// and false otherwise. For example,
+//
+//	fx.Annotate(
+//		func(routes []Route, present bool) *Mux {
+//			if !present {
+//				// Nobody registered routes: routing is disabled.
+//			}
+//			// ...
+//		},
+//		fx.ParamTags(`group:"routes"`),
+//		fx.GroupPresence(),
+//	)
+//
+// The same applies to fields of an [In] struct: a bool field directly
+// after a value group field reports on that group.
+//
+// Because Fx always supplies value groups, even if empty, the bool parameter
+// is not itself requested from the container.
+// A group is present if a constructor that returns a result in that group
+// (with the same element type) is visible from the scope in which the
+// annotated function is used, whether or not that constructor actually
+// produces any values. [Decorate] does not affect presence.
+func GroupPresence() Annotation {
+	return groupPresenceAnnotation{}
+}
+
+func (groupPresenceAnnotation) apply(ann *annotated) error {
+	if ann.GroupPresence {
+		return errors.New("cannot apply more than one fx.GroupPresence")
+	}
+	ann.GroupPresence = true
+	return nil
+}
+
+// build is a no-op: GroupPresence is applied by annotated.Build
+// after all other parameter annotations so that it sees their group tags.
+func (groupPresenceAnnotation) build(ann *annotated) (interface{}, error) {
+	return ann.Target, nil
+}
+
+// presenceField is a bool field of an fx.In struct that reports whether
+// the value group of the preceding field has any contributors.
+type presenceField struct {
+	Index int          // index of the bool field in the original struct
+	Group string       // name of the value group
+	Type  reflect.Type // element type of the value group
+}
+
+// buildGroupPresence wraps the target so that bool parameters following
+// value group parameters are filled in from ann.groupPresent
+// instead of the container.
+func (ann *annotated) buildGroupPresence() (interface{}, error) {
+	paramTypes := ann.currentParamTypes()
+	resultTypes, _ := ann.currentResultTypes()
+
+	// newParams[i] is the struct replacing parameter i, if any.
+	newParams := make([]reflect.Type, len(paramTypes))
+	fieldMaps := make([][]int, len(paramTypes))
+	presence := make([][]presenceField, len(paramTypes))
+	var found bool
+	for i, pt := range paramTypes {
+		if !isIn(pt) {
+			newParams[i] = pt
+			continue
+		}
+
+		var fields []reflect.StructField
+		for j := 0; j < pt.NumField(); j++ {
+			f := pt.Field(j)
+			if j > 0 && f.Type.Kind() == reflect.Bool {
+				prev := pt.Field(j - 1)
+				if group, ok := prev.Tag.Lookup("group"); ok && prev.Type.Kind() == reflect.Slice {
+					name, _, _ := strings.Cut(group, ",")
+					presence[i] = append(presence[i], presenceField{
+						Index: j,
+						Group: name,
+						Type:  prev.Type.Elem(),
+					})
+					continue
+				}
+			}
+			fieldMaps[i] = append(fieldMaps[i], j)
+			fields = append(fields, reflect.StructField{
+				Name:      f.Name,
+				Type:      f.Type,
+				Tag:       f.Tag,
+				Anonymous: f.Anonymous,
+			})
+		}
+		if len(presence[i]) > 0 {
+			found = true
+			newParams[i] = reflect.StructOf(fields)
+		} else {
+			newParams[i] = pt
+		}
+	}
+	if !found {
+		return nil, errors.New("fx.GroupPresence requires a bool parameter " +
+			"immediately after a value group parameter")
+	}
+
+	groupPresent := ann.groupPresent
+	origFn := reflect.ValueOf(ann.Target)
+	newFnType := reflect.FuncOf(newParams, resultTypes, false)
+	newFn := reflect.MakeFunc(newFnType, func(args []reflect.Value) []reflect.Value {
+		for i, pt := range paramTypes {
+			if len(presence[i]) == 0 {
+				continue
+			}
+			orig := reflect.New(pt).Elem()
+			for j, idx := range fieldMaps[i] {
+				orig.Field(idx).Set(args[i].Field(j))
+			}
+			for _, pf := range presence[i] {
+				present := groupPresent != nil && groupPresent(pf.Group, pf.Type)
+				orig.Field(pf.Index).SetBool(present)
+			}
+			args[i] = orig
+		}
+		return origFn.Call(args)
+	})
+	return newFn.Interface(), nil
+}
+
 type annotated struct {
 	Target      interface{}
 	Annotations []Annotation
 	ParamTags   []string
+	ParamStruct map[string]string
 	ResultTags  []string
 	As          [][]asType
 	From        []reflect.Type
 	FuncPtr     uintptr
 	Hooks       []*lifecycleHookAnnotation
+
+	// GroupPresence is set if the fx.GroupPresence annotation was applied.
+	GroupPresence bool
+
 	// container is used to build private scopes for lifecycle hook functions
 	// added via fx.OnStart and fx.OnStop annotations.
 	container *dig.Container
+
+	// groupPresent reports whether a value group has contributors visible
+	// from the module in which this function is provided, decorated,
+	// or invoked. It's used by the fx.GroupPresence annotation.
+	groupPresent func(group string, t reflect.Type) bool
 }
 
 func (ann annotated) String() string {
 	var sb strings.Builder
 	sb.WriteString("fx.Annotate(")
 	sb.WriteString(fxreflect.FuncName(ann.Target))
+	sb.WriteString(ann.annotationsString())
+	return sb.String()
+}
+
+// annotationsString renders the annotations applied to ann,
+// each preceded by ", ".
+func (ann annotated) annotationsString() string {
+	var sb strings.Builder
 	if tags := ann.ParamTags; len(tags) > 0 {
 		fmt.Fprintf(&sb, ", fx.ParamTags(%q)", tags)
 	}
+	if tags := ann.ParamStruct; tags != nil {
+		fmt.Fprintf(&sb, ", fx.ParamStruct(%q)", tags)
+	}
 	if tags := ann.ResultTags; len(tags) > 0 {
 		fmt.Fprintf(&sb, ", fx.ResultTags(%q)", tags)
 	}
@@ -1554,6 +1838,9 @@ func (ann annotated) String() string {
 	if from := ann.From; len(from) > 0 {
 		fmt.Fprintf(&sb, ", fx.From(%v)", from)
 	}
+	if ann.GroupPresence {
+		sb.WriteString(", fx.GroupPresence()")
+	}
 	return sb.String()
 }
 
@@ -1586,6 +1873,12 @@ func (ann *annotated) Build() (interface{}, error) {
 		}
 	}
 
+	if ann.GroupPresence {
+		if ann.Target, err = ann.buildGroupPresence(); err != nil {
+			return nil, err
+		}
+	}
+
 	// need to call cleanUpAsResults before applying lifecycle annotations
 	// to exclude the original results from the hook's scope if any
 	// fx.As annotations were applied
@@ -1812,6 +2105,10 @@ func (ann *annotated) currentParamTypes() []reflect.Type {
 //	}
 func Annotate(t interface{}, anns ...Annotation) interface{} {
 	result := annotated{Target: t}
+	if v := reflect.ValueOf(t); v.Kind() == reflect.Func {
+		// Point dig at the annotated function in error messages.
+		result.FuncPtr = v.Pointer()
+	}
 	for _, ann := range anns {
 		if err := ann.apply(&result); err != nil {
 			return annotationError{
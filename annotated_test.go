@@ -426,6 +426,124 @@ func TestAnnotatedFromFailures(t *testing.T) {
 	}
 }
 
+func TestAnnotatedParamStruct(t *testing.T) {
+	t.Parallel()
+
+	type db struct{ name string }
+	type cache struct{}
+	type options struct {
+		Primary *db
+		Replica *db
+		Cache   *cache
+		Retries int `json:"retries"`
+
+		private string
+	}
+	type client struct{ opts options }
+
+	t.Run("fields built by the container", func(t *testing.T) {
+		t.Parallel()
+
+		var got *client
+		app := fxtest.New(t,
+			fx.Provide(
+				func() *db { return &db{name: "primary"} },
+				fx.Annotate(
+					func() *db { return &db{name: "replica"} },
+					fx.ResultTags(`name:"ro"`),
+				),
+				func() int { return 3 },
+				fx.Annotate(
+					func(log *bytes.Buffer, opts options) *client { return &client{opts: opts} },
+					fx.ParamStruct(map[string]string{
+						"Replica": `name:"ro"`,
+						"Cache":   `optional:"true"`,
+					}),
+				),
+				bytes.NewBuffer,
+			),
+			fx.Supply([]byte(nil)),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		require.NotNil(t, got)
+		assert.Equal(t, "primary", got.opts.Primary.name)
+		assert.Equal(t, "replica", got.opts.Replica.name)
+		assert.Nil(t, got.opts.Cache)
+		assert.Equal(t, 3, got.opts.Retries)
+		assert.Empty(t, got.opts.private)
+	})
+
+	t.Run("string", func(t *testing.T) {
+		t.Parallel()
+
+		ann := fx.Annotate(func(options) {}, fx.ParamStruct(map[string]string{"Cache": `optional:"true"`}))
+		assert.Contains(t, fmt.Sprint(ann), `fx.ParamStruct(map["Cache":"optional:\"true\""])`)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc    string
+			give    interface{}
+			wantErr string
+		}{
+			{
+				desc: "no struct parameter",
+				give: fx.Annotate(func(*db) int { return 0 }, fx.ParamStruct(nil)),
+				wantErr: "fx.ParamStruct: func(*fx_test.db) int has no struct parameter " +
+					"that doesn't embed fx.In",
+			},
+			{
+				desc:    "several struct parameters",
+				give:    fx.Annotate(func(options, options) int { return 0 }, fx.ParamStruct(nil)),
+				wantErr: "has more than one struct parameter",
+			},
+			{
+				desc:    "unknown field",
+				give:    fx.Annotate(func(options) int { return 0 }, fx.ParamStruct(map[string]string{"private": ""})),
+				wantErr: "fx.ParamStruct: fx_test.options has no exported field private",
+			},
+			{
+				desc:    "invalid tag",
+				give:    fx.Annotate(func(options) int { return 0 }, fx.ParamStruct(map[string]string{"Cache": `json:"cache"`})),
+				wantErr: "invalid tag for field Cache",
+			},
+			{
+				desc: "with ParamTags",
+				give: fx.Annotate(func(options) int { return 0 },
+					fx.ParamTags(`name:"foo"`), fx.ParamStruct(nil)),
+				wantErr: "cannot apply fx.ParamStruct with ParamTags",
+			},
+			{
+				desc:    "variadic",
+				give:    fx.Annotate(func(options, ...int) int { return 0 }, fx.ParamStruct(nil)),
+				wantErr: "cannot apply fx.ParamStruct to a variadic function",
+			},
+			{
+				desc: "twice",
+				give: fx.Annotate(func(options) int { return 0 },
+					fx.ParamStruct(nil), fx.ParamStruct(nil)),
+				wantErr: "cannot apply more than one fx.ParamStruct",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := NewForTest(t, fx.Provide(tt.give))
+				err := app.Err()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+}
+
 func TestAnnotatedAs(t *testing.T) {
 	t.Parallel()
 	type in struct {
@@ -1377,6 +1495,44 @@ func TestAnnotate(t *testing.T) {
 		defer app.RequireStart().RequireStop()
 	})
 
+	t.Run("provide with flattened group results", func(t *testing.T) {
+		t.Parallel()
+
+		var got []string
+		app := fxtest.New(t,
+			fx.Provide(
+				fx.Annotate(func() []string {
+					return []string{"a", "b"}
+				}, fx.ResultTags(`group:"letters,flatten"`)),
+				fx.Annotate(func() ([]string, error) {
+					return []string{"c"}, nil
+				}, fx.ResultTags(`group:"letters,flatten"`)),
+			),
+			fx.Invoke(fx.Annotate(func(letters []string) {
+				got = letters
+			}, fx.ParamTags(`group:"letters"`))),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.ElementsMatch(t, []string{"a", "b", "c"}, got)
+	})
+
+	t.Run("flatten a non-slice result", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			fx.Provide(
+				fx.Annotate(func() string {
+					return "a"
+				}, fx.ResultTags(`group:"letters,flatten"`)),
+			),
+			fx.Invoke(fx.Annotate(func([]string) {}, fx.ParamTags(`group:"letters"`))),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "flatten can be applied to slices only")
+	})
+
 	t.Run("provide an already provided function using Annotate", func(t *testing.T) {
 		t.Parallel()
 
@@ -2497,3 +2653,225 @@ func TestHookAnnotationFunctionFlexibility(t *testing.T) {
 		})
 	}
 }
+
+func TestGroupPresence(t *testing.T) {
+	t.Parallel()
+
+	type route string
+
+	consume := func(got *[]route, present *bool) interface{} {
+		return fx.Annotate(
+			func(routes []route, ok bool) {
+				*got = routes
+				*present = ok
+			},
+			fx.ParamTags(`group:"routes"`),
+			fx.GroupPresence(),
+		)
+	}
+
+	t.Run("no contributors", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			routes  []route
+			present = true
+		)
+		app := fxtest.New(t, fx.Invoke(consume(&routes, &present)))
+		defer app.RequireStart().RequireStop()
+
+		assert.Empty(t, routes)
+		assert.False(t, present)
+	})
+
+	t.Run("one contributor", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			routes  []route
+			present bool
+		)
+		app := fxtest.New(t,
+			fx.Provide(fx.Annotate(
+				func() route { return "/foo" },
+				fx.ResultTags(`group:"routes"`),
+			)),
+			fx.Invoke(consume(&routes, &present)),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, []route{"/foo"}, routes)
+		assert.True(t, present)
+	})
+
+	t.Run("contributor with no values", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			routes  []route
+			present bool
+		)
+		app := fxtest.New(t,
+			fx.Provide(fx.Annotate(
+				func() []route { return nil },
+				fx.ResultTags(`group:"routes,flatten"`),
+			)),
+			fx.Invoke(consume(&routes, &present)),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Empty(t, routes)
+		assert.True(t, present, "group with a contributor must be present even if empty")
+	})
+
+	t.Run("different element type", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			routes  []route
+			present = true
+		)
+		app := fxtest.New(t,
+			fx.Provide(fx.Annotate(
+				func() string { return "/foo" },
+				fx.ResultTags(`group:"routes"`),
+			)),
+			fx.Invoke(consume(&routes, &present)),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.False(t, present)
+	})
+
+	t.Run("different type with the same name", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			routes  []route
+			present = true
+		)
+		{
+			// Renders as the same type name as the route consumed.
+			type route string
+			app := fxtest.New(t,
+				fx.Provide(fx.Annotate(
+					func() route { return "/foo" },
+					fx.ResultTags(`group:"routes"`),
+				)),
+				fx.Invoke(consume(&routes, &present)),
+			)
+			defer app.RequireStart().RequireStop()
+		}
+
+		assert.False(t, present)
+	})
+
+	t.Run("fx.Out contributor", func(t *testing.T) {
+		t.Parallel()
+
+		type result struct {
+			fx.Out
+
+			Routes []route `group:"routes,flatten"`
+		}
+
+		var (
+			routes  []route
+			present bool
+		)
+		app := fxtest.New(t,
+			fx.Provide(func() result { return result{Routes: []route{"/foo", "/bar"}} }),
+			fx.Invoke(consume(&routes, &present)),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.ElementsMatch(t, []route{"/foo", "/bar"}, routes)
+		assert.True(t, present)
+	})
+
+	t.Run("private contributor", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			inner, outer               []route
+			innerPresent, outerPresent bool
+		)
+		app := fxtest.New(t,
+			fx.Module("child",
+				fx.Provide(
+					fx.Annotate(
+						func() route { return "/foo" },
+						fx.ResultTags(`group:"routes"`),
+					),
+					fx.Private,
+				),
+				fx.Invoke(consume(&inner, &innerPresent)),
+			),
+			fx.Invoke(consume(&outer, &outerPresent)),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.True(t, innerPresent)
+		assert.Equal(t, []route{"/foo"}, inner)
+		assert.False(t, outerPresent)
+		assert.Empty(t, outer)
+	})
+
+	t.Run("fx.In struct", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			fx.In
+
+			Routes  []route `group:"routes"`
+			Present bool
+		}
+
+		var got params
+		app := fxtest.New(t,
+			fx.Supply(fx.Annotated{Group: "routes", Target: route("/bar")}),
+			fx.Invoke(fx.Annotate(
+				func(p params) { got = p },
+				fx.GroupPresence(),
+			)),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, []route{"/bar"}, got.Routes)
+		assert.True(t, got.Present)
+	})
+
+	t.Run("no group parameter", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Invoke(fx.Annotate(
+				func(routes []route, present bool) {},
+				fx.GroupPresence(),
+			)),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			"fx.GroupPresence requires a bool parameter immediately after a value group parameter")
+	})
+
+	t.Run("applied twice", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Provide(fx.Annotate(
+				func(routes []route, present bool) int { return 0 },
+				fx.ParamTags(`group:"routes"`),
+				fx.GroupPresence(),
+				fx.GroupPresence(),
+			)),
+			fx.Invoke(func(int) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot apply more than one fx.GroupPresence")
+	})
+}
@@ -28,6 +28,8 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/dig"
@@ -115,6 +117,21 @@ func (og optionGroup) String() string {
 // If the timeout is exceeded, the application will fail to start.
 //
 // Defaults to [DefaultTimeout].
+//
+// Passed to a [Module], StartTimeout instead bounds the total time
+// that the OnStart hooks appended by the module's constructors
+// and invoked functions have to complete, including those of nested
+// modules that don't set their own timeout.
+// These hooks are still bound by the application's start timeout.
+// If the module's timeout is exceeded, the application fails to start
+// with an error that names the module.
+// With [ConcurrentStart], time during which several of these hooks
+// run at once counts once.
+//
+//	fx.Module("db",
+//	  fx.StartTimeout(5*time.Second),
+//	  fx.Provide(NewConnectionPool),
+//	)
 func StartTimeout(v time.Duration) Option {
 	return startTimeoutOption(v)
 }
@@ -122,10 +139,13 @@ func StartTimeout(v time.Duration) Option {
 type startTimeoutOption time.Duration
 
 func (t startTimeoutOption) apply(m *module) {
-	if m.parent != nil {
-		m.app.err = fmt.Errorf("fx.StartTimeout Option should be passed to top-level App, " +
-			"not to fx.Module")
-	} else {
+	switch {
+	case len(m.trial) > 0:
+		m.app.err = fmt.Errorf("fx.StartTimeout Option should be passed to top-level App, "+
+			"not to %v", m.trial)
+	case m.parent != nil:
+		m.budget().StartTimeout = time.Duration(t)
+	default:
 		m.app.startTimeout = time.Duration(t)
 	}
 }
@@ -139,6 +159,13 @@ func (t startTimeoutOption) String() string {
 // If the timeout is exceeded, the application will exit early.
 //
 // Defaults to [DefaultTimeout].
+//
+// Passed to a [Module], StopTimeout instead bounds the total time
+// that the module's OnStop hooks have to complete,
+// as [StartTimeout] does for its OnStart hooks.
+// If the module's timeout is exceeded, its remaining OnStop hooks
+// fail with an error that names the module,
+// and the hooks of other modules still run.
 func StopTimeout(v time.Duration) Option {
 	return stopTimeoutOption(v)
 }
@@ -146,10 +173,13 @@ func StopTimeout(v time.Duration) Option {
 type stopTimeoutOption time.Duration
 
 func (t stopTimeoutOption) apply(m *module) {
-	if m.parent != nil {
-		m.app.err = fmt.Errorf("fx.StopTimeout Option should be passed to top-level App, " +
-			"not to fx.Module")
-	} else {
+	switch {
+	case len(m.trial) > 0:
+		m.app.err = fmt.Errorf("fx.StopTimeout Option should be passed to top-level App, "+
+			"not to %v", m.trial)
+	case m.parent != nil:
+		m.budget().StopTimeout = time.Duration(t)
+	default:
 		m.app.stopTimeout = time.Duration(t)
 	}
 }
@@ -158,9 +188,92 @@ func (t stopTimeoutOption) String() string {
 	return fmt.Sprintf("fx.StopTimeout(%v)", time.Duration(t))
 }
 
+// WithRootContext sets the context that [App.Run] derives the contexts
+// it starts, stops, and reloads the application with from,
+// in place of [context.Background].
+// Use it to carry values such as trace spans into lifecycle hooks.
+//
+// The start and reload contexts inherit the root context's deadline
+// and cancellation, bounded further by [StartTimeout].
+// The stop context inherits only its values, bounded by [StopTimeout],
+// so that OnStop hooks get to run after the root context is done.
+// Run also shuts the application down once the root context is done,
+// as it would on SIGTERM.
+func WithRootContext(ctx context.Context) Option {
+	return rootContextOption{ctx: ctx}
+}
+
+type rootContextOption struct{ ctx context.Context }
+
+func (o rootContextOption) apply(m *module) {
+	switch {
+	case m.parent != nil:
+		m.app.err = fmt.Errorf("fx.WithRootContext Option should be passed to top-level App, " +
+			"not to fx.Module")
+	case o.ctx == nil:
+		m.app.err = errors.New("fx.WithRootContext: context must not be nil")
+	default:
+		m.app.rootCtx = o.ctx
+	}
+}
+
+func (o rootContextOption) String() string {
+	return "fx.WithRootContext()"
+}
+
+// ConstructorContext provides a [context.Context] to the application's
+// constructors, so that work they do while the application is built,
+// like dialing a server, respects the startup deadline.
+//
+// Constructors run during [New], before [App.Start] is called,
+// so the context can't be the one passed to Start.
+// Instead, it derives from the context set with [WithRootContext],
+// and its deadline is the start timeout, measured from the time New
+// was called. It's also canceled once the application is stopped.
+// Constructors must not retain it for work that outlives startup;
+// use [Lifecycle] hooks for that.
+//
+//	func NewClient(ctx context.Context, cfg Config) (*Client, error) {
+//		conn, err := grpc.DialContext(ctx, cfg.Addr, grpc.WithBlock())
+//		...
+//	}
+//
+// ConstructorContext can't be used with applications that provide
+// a context.Context of their own.
+func ConstructorContext() Option {
+	return constructorContextOption{}
+}
+
+type constructorContextOption struct{}
+
+func (constructorContextOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.ConstructorContext Option should be passed to top-level App, " +
+			"not to fx.Module")
+	} else {
+		m.app.constructorContext = true
+	}
+}
+
+func (constructorContextOption) String() string {
+	return "fx.ConstructorContext()"
+}
+
+// detachedContext carries the values of a context,
+// but neither its deadline nor its cancellation.
+type detachedContext struct{ parent context.Context }
+
+func (detachedContext) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}               { return nil }
+func (detachedContext) Err() error                          { return nil }
+func (c detachedContext) Value(key interface{}) interface{} { return c.parent.Value(key) }
+
 // RecoverFromPanics causes panics that occur in functions given to [Provide],
 // [Decorate], and [Invoke] to be recovered from.
 // This error can be retrieved as any other error, by using (*App).Err().
+// It's a [*PanicError] that holds the value the function panicked with,
+// and the stack of the panic for constructors.
+// Each panic is also reported with an [fxevent.Panicked] event.
 func RecoverFromPanics() Option {
 	return recoverFromPanicsOption{}
 }
@@ -263,6 +376,124 @@ func (l loggerOption) String() string {
 // Prefer to log to an in-memory buffer instead.
 var NopLogger = WithLogger(func() fxevent.Logger { return fxevent.NopLogger })
 
+// EventBufferLimit caps the memory used to hold Fx's own events while a
+// logger specified with [WithLogger] is being built.
+// Until that logger is available, events are buffered in memory;
+// in very large applications this buffer can grow significantly.
+//
+// The limit is an estimate given in bytes.
+// When a new event would exceed it, the oldest buffered events are
+// discarded to make room.
+// The number of discarded events is reported by [App.DroppedEvents].
+//
+// By default, the buffer is unbounded.
+func EventBufferLimit(bytes int) Option {
+	return eventBufferLimitOption(bytes)
+}
+
+type eventBufferLimitOption int
+
+func (o eventBufferLimitOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.EventBufferLimit Option should be passed to top-level App, " +
+			"not to fx.Module")
+	} else {
+		m.app.eventBufferLimit = int(o)
+	}
+}
+
+func (o eventBufferLimitOption) String() string {
+	return fmt.Sprintf("fx.EventBufferLimit(%d)", int(o))
+}
+
+// BeforeStop registers a function to run once the application has been
+// asked to stop, but before any OnStop hooks run.
+// This is the place to do last-moment work that must precede teardown,
+// such as failing readiness checks so that load balancers stop routing
+// traffic to this instance.
+//
+// When the application is run with [App.Run], BeforeStop functions run
+// after the [fxevent.Stopping] event is logged.
+// They are called from [App.Stop] in the order they were registered,
+// and they count against the stop timeout.
+// Like OnStop hooks, they are not called if the application
+// is not running.
+func BeforeStop(f func()) Option {
+	return beforeStopOption(f)
+}
+
+type beforeStopOption func()
+
+func (o beforeStopOption) apply(m *module) {
+	if m.rejectInTrial("fx.BeforeStop") {
+		return
+	}
+	m.app.beforeStop = append(m.app.beforeStop, o)
+}
+
+func (o beforeStopOption) String() string {
+	return fmt.Sprintf("fx.BeforeStop(%v)", fxreflect.FuncName(o))
+}
+
+// StartMiddleware registers a function that wraps the application's
+// entire start sequence. It is called once per [App.Start] with the start
+// context, and it must call next exactly once to run all OnStart hooks.
+// The context passed to next is the one the hooks receive.
+// This makes it the place to open a tracing span around startup:
+//
+//	fx.StartMiddleware(func(ctx context.Context, next func(context.Context) error) error {
+//		ctx, span := tracer.Start(ctx, "startup")
+//		defer span.End()
+//		return next(ctx)
+//	})
+//
+// When more than one middleware is registered, the one registered first
+// is outermost: it runs first and sees the result of the others.
+// If the start sequence fails, the application is rolled back
+// after all middleware has returned.
+// A middleware that returns without calling next fails the start.
+func StartMiddleware(f func(ctx context.Context, next func(context.Context) error) error) Option {
+	return startMiddlewareOption(f)
+}
+
+type startMiddlewareOption func(context.Context, func(context.Context) error) error
+
+func (o startMiddlewareOption) apply(m *module) {
+	if m.rejectInTrial("fx.StartMiddleware") {
+		return
+	}
+	m.app.startMiddleware = append(m.app.startMiddleware, o)
+}
+
+func (o startMiddlewareOption) String() string {
+	return fmt.Sprintf("fx.StartMiddleware(%v)", fxreflect.FuncName(o))
+}
+
+// NoEmptyModules causes [New] to fail if any [Module] contributes nothing
+// to the application: no provides, supplies, invokes, decorators,
+// or loggers.
+// This catches modules left empty by typos or leftover scaffolding.
+//
+// A module that only contains other modules is not empty.
+func NoEmptyModules() Option {
+	return noEmptyModulesOption{}
+}
+
+type noEmptyModulesOption struct{}
+
+func (noEmptyModulesOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.NoEmptyModules Option should be passed to top-level App, " +
+			"not to fx.Module")
+	} else {
+		m.app.noEmptyModules = true
+	}
+}
+
+func (noEmptyModulesOption) String() string {
+	return "fx.NoEmptyModules()"
+}
+
 // An App is a modular application built around dependency injection. Most
 // users will only need to use the New constructor and the all-in-one Run
 // convenience method. In more unusual cases, users may need to use the Err,
@@ -298,7 +529,12 @@ var NopLogger = WithLogger(func() fxevent.Logger { return fxevent.NopLogger })
 // execute one at a time, in reverse order, and must all complete within a
 // configurable deadline (again, 15 seconds by default).
 type App struct {
-	err       error
+	// Errors recorded while options are applied and the container is built:
+	// by New, then by App.Try and App.NewScope with the container locked.
+	err error
+	// The error of New, as reported by Err.
+	newErr error
+
 	clock     fxclock.Clock
 	lifecycle *lifecycleWrapper
 
@@ -309,15 +545,152 @@ type App struct {
 	// Timeouts used
 	startTimeout time.Duration
 	stopTimeout  time.Duration
+	// Context that Run derives its contexts from, if set with WithRootContext
+	rootCtx context.Context
+	// Whether to provide a context to constructors, and how to cancel it
+	constructorContext bool
+	constructorCancel  context.CancelFunc
 	// Decides how we react to errors when building the graph.
-	errorHooks []ErrorHandler
-	validate   bool
+	errorHooks     []ErrorHandler
+	failure        error   // error of New for errorHooks, if a constructor or invoke failed
+	failedModule   *module // where failure occurred
+	validate       bool
+	validateStrict bool      // also run the checks of ValidateAppStrict
+	analysis       *Analysis // set only by Analyze
+	plan           *Plan     // set only by PlanApp
+	// Types provided with Transient, if any
+	transients *transients
+	// Value groups contributed to with a priority
+	priorities groupPriorities
 	// Whether to recover from panics in Dig container
 	recoverFromPanics bool
+	// What happens when an OnStop hook panics, if set with OnStopPanic
+	stopPanicPolicy StopPanicPolicy
+	// What App.Start does with started hooks when one fails
+	rollbackPolicy RollbackPolicy
+	// Last panic recovered from, until it's attached to an error
+	recovered *PanicError
+
+	// Maximum estimated size of buffered events before a custom
+	// logger is available, and the number of events dropped because of it.
+	eventBufferLimit int
+	droppedEvents    int
+
+	// Functions registered with BeforeStop, run before OnStop hooks.
+	beforeStop []func()
+
+	// Options of the last Shutdown, until the Stop that carries it out.
+	shutdownOpts atomic.Pointer[shutdownOptions]
+
+	// Functions registered with StartMiddleware, outermost first.
+	startMiddleware []startMiddlewareOption
+
+	// Names of the modules that appended each lifecycle hook.
+	hookModules []string
+
+	// Functions that appended each lifecycle hook,
+	// and the dependencies between them.
+	hookNodes []hookNode
+	hookDeps  hookDependencies
+
+	// String renderings of the options applied by New.
+	appliedOptions []string
+
+	// Whether New fails on modules that contribute nothing.
+	noEmptyModules bool
+
+	// Whether New fails on types that shadow ones provided to an ancestor.
+	noShadowing bool
+
+	// Whether New reports every error rather than the first one,
+	// and whether the App was built to check another one for them.
+	reportAllErrors bool
+	checkOnly       bool
+
+	// Number of invoked functions that ran, and whether any options were
+	// returned by functions passed to Evaluate.
+	invoked   int
+	evaluated bool
+
+	// Whether to warn about types provided and decorated by the same module.
+	warnAmbiguousDecorations bool
+
+	// Whether to run constructors and hooks in runtime/trace regions.
+	traceRegions bool
+
+	// Tracer that records spans, if any, and the context of the
+	// fx.New span while New runs.
+	tracer Tracer
+	newCtx context.Context
+
+	// Whether to measure how long constructors run.
+	timeConstructors bool
+
+	// Names of the lifecycle phases, in the order they run.
+	lifecyclePhases []string
+
+	// Functions registered with MapResult.
+	resultMappers []resultMapper
+
+	// Values built by constructors; set only with TrackInstances.
+	instances *instanceTracker
+
+	// Channels waiting for the application to stop,
+	// for Shutdown with ShutdownWait.
+	stopWaitersMu sync.Mutex
+	stopWaiters   []chan error
+
+	// Outputs of the constructors that have run, for Resolve.
+	// Guarded by the container lock once New returns.
+	builtOutputs map[outputKey]struct{}
+
+	// Whether a module uses Undecorate, so that the values built by
+	// constructors are recorded before decoration.
+	undecorate bool
+
+	// Application that built this one with its SubAppFactory, if any,
+	// and the applications built by its SubAppFactory to stop with it.
+	parent    *App
+	subAppsMu sync.Mutex
+	subApps   []*App
+
+	// Serializes the use of the container by Try and NewScope.
+	// Use containerLock to access it.
+	scopeMu containerMutex
+
+	// Whether the application was built in the container of its parent,
+	// with ShareParentContainer.
+	sharedContainer bool
 
 	// Used to signal shutdowns.
 	receivers signalReceivers
 
+	// Name of the Windows service to run the application as, if any.
+	serviceName string
+
+	// Notifies systemd of the application's state, if enabled.
+	systemd *systemdNotifier
+
+	// Serves health probes, if enabled.
+	probes *probeServer
+
+	// Serves diagnostics, if enabled.
+	debug *debugServer
+
+	// Profiles startup, if enabled.
+	startupProfile *startupProfile
+
+	// Functions given to OnEvent.
+	eventSubscribers []func(fxevent.Event)
+
+	// Maximum number of OnStart hooks run at once, if set with
+	// ConcurrentStart.
+	startWorkers int
+
+	// Channels returned by StartProgress, until the next Start returns.
+	progressMu sync.Mutex
+	progress   []chan StartProgress
+
 	osExit func(code int) // os.Exit override; used for testing only
 }
 
@@ -335,6 +708,17 @@ type provide struct {
 
 	// Set if the type should be provided at private scope.
 	Private bool
+
+	// IsDerived is true when the Target constructor was passed to
+	// fx.SupplyDerived.
+	IsDerived bool
+
+	// Set if the constructor builds a new value for every consumer.
+	Transient bool
+
+	// Set if the constructor was passed to fx.Provide,
+	// rather than generated by Fx.
+	IsProvided bool
 }
 
 // invoke is a single invocation request to Fx.
@@ -356,8 +740,28 @@ type ErrorHandler interface {
 }
 
 // ErrorHook registers error handlers that implement error handling functions.
-// They are executed on invoke failures. Passing multiple ErrorHandlers appends
-// the new handlers to the application's existing list.
+// They are executed on invoke failures, and when [App.Start] or [App.Stop]
+// fails. Passing multiple ErrorHandlers appends the new handlers to the
+// application's existing list.
+//
+// The errors passed to the handlers describe what failed,
+// and can be told apart with [errors.As]:
+//   - [*ProvideError] for a constructor that failed
+//   - [*InvokeError] for an invoked function that failed
+//   - [*HookError] for an OnStart or OnStop hook that failed
+//   - [*TimeoutError] for an OnStart or OnStop hook that didn't finish in time
+//
+// Handlers run once for each hook that failed during Start or Stop.
+// Other failures of Start and Stop, such as those of [StartMiddleware],
+// are passed as they are.
+//
+// When passed to a [Module], the handlers only run for failures of
+// constructors provided to or functions invoked in that module or the
+// modules it contains, and they receive a [*ModuleError] that names the
+// module the failure occurred in.
+// They run before the handlers of enclosing modules,
+// and before those of the application.
+// Failures of Start and Stop only reach the handlers of the application.
 func ErrorHook(funcs ...ErrorHandler) Option {
 	return errorHookOption(funcs)
 }
@@ -365,7 +769,11 @@ func ErrorHook(funcs ...ErrorHandler) Option {
 type errorHookOption []ErrorHandler
 
 func (eho errorHookOption) apply(m *module) {
-	m.app.errorHooks = append(m.app.errorHooks, eho...)
+	if m.parent == nil {
+		m.app.errorHooks = append(m.app.errorHooks, eho...)
+	} else {
+		m.errorHooks = append(m.errorHooks, eho...)
+	}
 }
 
 func (eho errorHookOption) String() string {
@@ -376,6 +784,45 @@ func (eho errorHookOption) String() string {
 	return fmt.Sprintf("fx.ErrorHook(%v)", strings.Join(items, ", "))
 }
 
+// ModuleError is the error passed to the handlers registered with
+// [ErrorHook] inside a [Module].
+type ModuleError struct {
+	// Module is the path of the module in which the failure occurred:
+	// the names of the modules that contain it, from the outermost,
+	// and its own name, separated by "/".
+	Module string
+
+	// Err is the error that occurred.
+	Err error
+}
+
+func (e *ModuleError) Error() string {
+	return fmt.Sprintf("module %q: %v", e.Module, e.Err)
+}
+
+// Unwrap returns the error that occurred.
+func (e *ModuleError) Unwrap() error {
+	return e.Err
+}
+
+// handleError runs the error hooks registered in mod and the modules that
+// contain it, from the innermost, followed by those of the application.
+// mod may be nil if the error didn't occur in a specific module.
+func (app *App) handleError(err error, mod *module) {
+	if app.checkOnly {
+		return
+	}
+	for m := mod; m != nil && m.parent != nil; m = m.parent {
+		if len(m.errorHooks) > 0 {
+			errorHandlerList(m.errorHooks).HandleError(&ModuleError{
+				Module: mod.path(),
+				Err:    err,
+			})
+		}
+	}
+	errorHandlerList(app.errorHooks).HandleError(err)
+}
+
 type errorHandlerList []ErrorHandler
 
 func (ehl errorHandlerList) HandleError(err error) {
@@ -393,6 +840,7 @@ func validate(validate bool) Option {
 
 type validateOption struct {
 	validate bool
+	strict   bool // also run the checks of ValidateAppStrict
 }
 
 func (o validateOption) apply(m *module) {
@@ -401,15 +849,20 @@ func (o validateOption) apply(m *module) {
 			"not to fx.Module")
 	} else {
 		m.app.validate = o.validate
+		m.app.validateStrict = o.strict
 	}
 }
 
 func (o validateOption) String() string {
+	if o.strict {
+		return "fx.validate(strict)"
+	}
 	return fmt.Sprintf("fx.validate(%v)", o.validate)
 }
 
 // ValidateApp validates that supplied graph would run and is not missing any dependencies. This
 // method does not invoke actual input functions.
+// See [ValidateAppStrict] for additional checks.
 func ValidateApp(opts ...Option) error {
 	opts = append(opts, validate(true))
 	app := New(opts...)
@@ -429,6 +882,12 @@ func New(opts ...Option) *App {
 		stopTimeout:  DefaultTimeout,
 		receivers:    newSignalReceivers(),
 	}
+	app.receivers.reload = app.reloadOnSignal
+	defer func() {
+		// Try and NewScope record their errors in app.err too,
+		// so Err reports a copy that they don't affect.
+		app.newErr = app.err
+	}()
 	app.root = &module{
 		app: app,
 		// We start with a logger that writes to stderr. One of the
@@ -449,8 +908,33 @@ func New(opts ...Option) *App {
 	app.modules = append(app.modules, app.root)
 
 	for _, opt := range opts {
-		opt.apply(app.root)
+		app.root.applyOption(opt)
+	}
+
+	if app.noEmptyModules {
+		app.err = multierr.Append(app.err, app.root.checkEmptyModules())
+	}
+
+	app.root.log = app.subscribedLogger(app.root.log)
+	if app.debug != nil {
+		// Record events logged before custom loggers are built as well.
+		app.root.log = app.debug.recordingLogger(app.root.log)
+	}
+
+	if app.err == nil && !app.validate {
+		// Applications built by ValidateApp and the like never start.
+		app.err = app.startupProfile.begin()
 	}
+	defer func() {
+		// Start won't end the profile if the application failed to build.
+		if app.err != nil {
+			app.startupProfile.end()
+			// Nor will Stop be called to cancel the constructor context.
+			if app.constructorCancel != nil {
+				app.constructorCancel()
+			}
+		}
+	}()
 
 	// There are a few levels of wrapping on the lifecycle here. To quickly
 	// cover them:
@@ -461,39 +945,78 @@ func New(opts ...Option) *App {
 	//   the public fx.Hook type.
 	// - appLogger ensures that the lifecycle always logs events to the
 	//   "current" logger associated with the fx.App.
-	app.lifecycle = &lifecycleWrapper{
-		lifecycle.New(appLogger{app}, app.clock),
-	}
+	app.lifecycle = app.newLifecycle()
 
-	containerOptions := []dig.Option{
-		dig.DeferAcyclicVerification(),
-		dig.DryRun(app.validate),
+	if app.tracer != nil {
+		var end func(error)
+		app.newCtx, end = app.tracer.StartSpan(app.rootContext(), "fx.New")
+		defer func() {
+			// Constructors run after New returns have no parent span.
+			app.newCtx = nil
+			end(app.err)
+		}()
 	}
 
-	if app.recoverFromPanics {
-		containerOptions = append(containerOptions, dig.RecoverFromPanics())
+	frames := fxreflect.CallerStack(0, 0) // include New in the stack for default Provides
+	if app.sharedContainer {
+		// The parent's container stays locked while the child's
+		// constructors and invoked functions run.
+		err := app.containerLock().Do(func() error {
+			app.container = app.parent.container
+			app.construct(app.parent.root.scope.Scope("fx.SubApp"), frames, opts)
+			return nil
+		})
+		if err != nil {
+			app.err = err
+		}
+	} else {
+		app.container = dig.New(app.containerOptions()...)
+		app.construct(app.container, frames, opts)
 	}
+	return app
+}
 
-	app.container = dig.New(containerOptions...)
-
+// construct builds the modules of the application in the given root scope,
+// and runs their constructors and invoked functions.
+func (app *App) construct(root scope, frames fxreflect.Stack, opts []Option) {
 	for _, m := range app.modules {
-		m.build(app, app.container)
+		m.build(app, root)
 	}
 
 	// Provide Fx types first to increase the chance a custom logger
 	// can be successfully built in the face of unrelated DI failure.
 	// E.g., for a custom logger that relies on the Lifecycle type.
-	frames := fxreflect.CallerStack(0, 0) // include New in the stack for default Provides
 	app.root.provide(provide{
-		Target: func() Lifecycle { return app.lifecycle },
-		Stack:  frames,
+		Target: func() (Lifecycle, LifecycleInspector) {
+			return app.lifecycle, lifecycleInspector{app}
+		},
+		Stack: frames,
 	})
 	app.root.provide(provide{Target: app.shutdowner, Stack: frames})
-	app.root.provide(provide{Target: app.dotGraph, Stack: frames})
+	app.root.provide(provide{Target: app.introspect, Stack: frames})
+	if app.constructorContext {
+		ctx, cancel := app.clock.WithTimeout(app.rootContext(), app.StartTimeout())
+		app.constructorCancel = cancel
+		app.root.provide(provide{
+			Target: func() context.Context { return ctx },
+			Stack:  frames,
+		})
+	}
 
 	for _, m := range app.modules {
 		m.provideAll()
 	}
+	for _, m := range app.modules {
+		if app.err == nil {
+			app.err = m.evaluateAll()
+		}
+	}
+	if app.noShadowing && app.err == nil {
+		app.err = app.root.checkShadowing()
+	}
+	if app.err == nil {
+		app.err = app.priorities.sortGroups(app.root.scope)
+	}
 
 	// Run decorators before executing any Invokes -- including the one
 	// inside constructCustomLogger.
@@ -507,14 +1030,25 @@ func New(opts ...Option) *App {
 	for _, m := range app.modules {
 		m.constructAllCustomLoggers()
 	}
+	app.debug.recordEvents(app.root)
 
 	// This error might have come from the provide loop above. We've
 	// already flushed to the custom logger, so we can return.
 	if app.err != nil {
-		return app
+		if app.failure != nil {
+			app.handleError(app.failure, app.failedModule)
+		}
+		app.err = multierr.Append(app.err, app.remainingInvokeErrors(opts, 0))
+		return
+	}
+
+	if err := app.root.deriveAll(); err != nil {
+		app.err = err
+		app.handleError(err, nil)
+		return
 	}
 
-	if err := app.root.executeInvokes(); err != nil {
+	if mod, err := app.root.executeInvokes(); err != nil {
 		app.err = err
 
 		if dig.CanVisualizeError(err) {
@@ -525,10 +1059,56 @@ func New(opts ...Option) *App {
 				err:   err,
 			}
 		}
-		errorHandlerList(app.errorHooks).HandleError(err)
+		app.handleError(withError(app.failure, err), mod)
+		app.err = multierr.Append(app.err, app.remainingInvokeErrors(opts, app.invoked))
+		return
 	}
 
-	return app
+	if app.validateStrict {
+		if err := app.checkStrict(); err != nil {
+			app.err = err
+			app.handleError(err, nil)
+		}
+	}
+}
+
+// containerOptions returns the options of the containers
+// that back the application.
+func (app *App) containerOptions() []dig.Option {
+	opts := []dig.Option{
+		dig.DeferAcyclicVerification(),
+		dig.DryRun(app.validate),
+	}
+	if app.recoverFromPanics {
+		opts = append(opts, dig.RecoverFromPanics())
+	}
+	return opts
+}
+
+// newLifecycle returns a lifecycle that runs hooks
+// as configured by the application's options.
+func (app *App) newLifecycle() *lifecycleWrapper {
+	lc := &lifecycleWrapper{
+		lifecycle.New(appLogger{app}, app.clock),
+	}
+	if app.traceRegions {
+		lc.TraceRegions()
+	}
+	if app.tracer != nil {
+		lc.TraceSpans(app.tracer.StartSpan)
+	}
+	if app.startupProfile != nil {
+		lc.ProfileLabels()
+	}
+	if app.stopPanicPolicy != 0 {
+		lc.RecoverStopPanics(app.stopPanicPolicy == AbortOnStopPanic)
+	}
+	lc.SetPhases(app.lifecyclePhases)
+	lc.OnStartProgress(app.reportStartProgress)
+	if app.startWorkers > 1 {
+		lc.StartConcurrently(app.startWorkers, app.hookDependsOn)
+	}
+	return lc
 }
 
 func (app *App) log() fxevent.Logger {
@@ -541,7 +1121,12 @@ func (app *App) log() fxevent.Logger {
 // to the error and if possible, colorized to highlight the root cause of the
 // failure.
 //
-// Note that DotGraph does not yet recognize [Decorate] and [Replace].
+// Modules are rendered as clusters of the constructors provided to them,
+// functions passed to [Decorate] and [Replace] as hexagons,
+// and value groups as diamonds with edges labeled with the group name.
+//
+// Note that the graph attached to errors does not yet recognize
+// [Decorate], [Replace], or modules.
 type DotGraph string
 
 type errWithGraph interface {
@@ -561,6 +1146,10 @@ func (err errorWithGraph) Error() string {
 	return err.err.Error()
 }
 
+func (err errorWithGraph) Unwrap() error {
+	return err.err
+}
+
 // VisualizeError returns the visualization of the error if available.
 //
 // Note that VisualizeError does not yet recognize [Decorate] and [Replace].
@@ -595,28 +1184,44 @@ func (app *App) exit(code int) {
 // All of Run's functionality is implemented in terms of the exported
 // Start, Done, and Stop methods. Applications with more specialized needs
 // can use those methods directly instead of relying on Run.
+//
+// With the [WindowsService] option, Run also reports the application's
+// state to the Windows Service Control Manager.
 func (app *App) Run() {
+	code, ok := app.runService()
+	if !ok {
+		code = app.run(app.Wait)
+	}
+
 	// Historically, we do not os.Exit(0) even though most applications
 	// cede control to Fx with they call app.Run. To avoid a breaking
 	// change, never os.Exit for success.
-	if code := app.run(app.Wait); code != 0 {
+	if code != 0 {
 		app.exit(code)
 	}
 }
 
 func (app *App) run(done func() <-chan ShutdownSignal) (exitCode int) {
-	startCtx, cancel := app.clock.WithTimeout(context.Background(), app.StartTimeout())
+	rootCtx := app.rootContext()
+	startCtx, cancel := app.clock.WithTimeout(rootCtx, app.StartTimeout())
 	defer cancel()
 
 	if err := app.Start(startCtx); err != nil {
 		return 1
 	}
 
-	sig := <-done()
-	app.log().LogEvent(&fxevent.Stopping{Signal: sig.Signal})
+	var sig ShutdownSignal
+	select {
+	case sig = <-done():
+	case <-rootCtx.Done():
+		// Loggers expect a signal with every Stopping event,
+		// so report the root context as a SIGTERM would be.
+		sig = ShutdownSignal{Signal: _sigTERM, Reason: context.Cause(rootCtx)}
+	}
+	app.log().LogEvent(&fxevent.Stopping{Signal: sig.Signal, Reason: sig.Reason})
 	exitCode = sig.ExitCode
 
-	stopCtx, cancel := app.clock.WithTimeout(context.Background(), app.StopTimeout())
+	stopCtx, cancel := app.clock.WithTimeout(detachedContext{rootCtx}, app.StopTimeout())
 	defer cancel()
 
 	if err := app.Stop(stopCtx); err != nil {
@@ -626,6 +1231,15 @@ func (app *App) run(done func() <-chan ShutdownSignal) (exitCode int) {
 	return exitCode
 }
 
+// rootContext returns the context set with WithRootContext,
+// or the background context if there's none.
+func (app *App) rootContext() context.Context {
+	if app.rootCtx != nil {
+		return app.rootCtx
+	}
+	return context.Background()
+}
+
 // Err returns any error encountered during New's initialization. See the
 // documentation of the New method for details, but typical errors include
 // missing constructors, circular dependencies, constructor errors, and
@@ -634,7 +1248,7 @@ func (app *App) run(done func() <-chan ShutdownSignal) (exitCode int) {
 // Most users won't need to use this method, since both Run and Start
 // short-circuit if initialization failed.
 func (app *App) Err() error {
-	return app.err
+	return app.newErr
 }
 
 var (
@@ -642,6 +1256,11 @@ var (
 	_onStopHook  = "OnStop"
 )
 
+// ErrAlreadyStarted is returned by [App.Start] if the application is
+// already starting or started. Hooks are not run again in that case,
+// and the running application is left untouched.
+var ErrAlreadyStarted = lifecycle.ErrAlreadyStarted
+
 // Start kicks off all long-running goroutines, like network servers or
 // message queue consumers. It does this by interacting with the application's
 // Lifecycle.
@@ -656,25 +1275,63 @@ var (
 // start hooks aren't executed until all its dependencies' start hooks
 // complete. If any of the start hooks return an error, Start short-circuits,
 // calls Stop, and returns the inciting error.
+// Use [OnStartFailure] to report the errors of Stop differently,
+// or to leave the hooks that started running.
 //
 // Note that Start short-circuits immediately if the New constructor
 // encountered any errors in application initialization.
+//
+// Calling Start on an application that is already started fails with
+// [ErrAlreadyStarted]. An application may be started again after it has
+// been stopped.
 func (app *App) Start(ctx context.Context) (err error) {
+	ctx, endSpan := app.startSpan(ctx, "fx.Start")
+	defer func() { endSpan(err) }()
+
+	begin := app.clock.Now()
+	defer app.endStartProgress()
 	defer func() {
-		app.log().LogEvent(&fxevent.Started{Err: err})
+		err = multierr.Append(err, app.startupProfile.end())
+		app.log().LogEvent(&fxevent.Started{
+			Runtime: app.clock.Since(begin),
+			Err:     err,
+		})
+		if err == nil {
+			app.probes.setReady(true)
+			app.systemd.ready(app.clock)
+		} else if !errors.Is(err, ErrAlreadyStarted) && app.rollbackPolicy != NoRollback {
+			app.probes.stop(ctx)
+			app.debug.stop(ctx)
+		}
 	}()
 
-	if app.err != nil {
+	if app.newErr != nil {
 		// Some provides failed, short-circuit immediately.
-		return app.err
+		return app.newErr
+	}
+	if app.parent != nil {
+		// A child stopped earlier is stopped with its parent again.
+		app.parent.addSubApp(app)
 	}
 
-	return withTimeout(ctx, &withTimeoutParams{
+	if err := app.probes.start(); err != nil {
+		return err
+	}
+	if err := app.debug.start(); err != nil {
+		return err
+	}
+
+	app.lifecycle.TakeFailures() // of earlier attempts
+	err = withTimeout(ctx, &withTimeoutParams{
 		hook:      _onStartHook,
 		callback:  app.start,
 		lifecycle: app.lifecycle,
 		log:       app.log(),
 	})
+	if err != nil && !errors.Is(err, ErrAlreadyStarted) {
+		app.handleLifecycleError(ctx, _onStartHook, err)
+	}
+	return err
 }
 
 // withRollback will execute an anonymous function with a given context.
@@ -684,12 +1341,39 @@ func (app *App) withRollback(
 	f func(context.Context) error,
 ) error {
 	if err := f(ctx); err != nil {
-		app.log().LogEvent(&fxevent.RollingBack{StartErr: err})
+		// The application is already running;
+		// rolling back would stop it.
+		if errors.Is(err, ErrAlreadyStarted) {
+			return err
+		}
 
-		stopErr := app.lifecycle.Stop(ctx)
-		app.log().LogEvent(&fxevent.RolledBack{Err: stopErr})
+		policy := app.rollbackPolicy
+		if policy == 0 {
+			policy = RollbackStarted
+		}
+		name, caller := app.lifecycle.FailedStart()
+		app.log().LogEvent(&fxevent.RollingBack{
+			StartErr:     err,
+			FunctionName: name,
+			CallerName:   caller,
+			Policy:       policy.String(),
+		})
+
+		if policy == NoRollback {
+			app.log().LogEvent(&fxevent.RolledBack{Skipped: true})
+			return err
+		}
 
-		if stopErr != nil {
+		stopErr := app.lifecycle.Stop(ctx)
+		hooks, failed := app.lifecycle.StoppedHooks()
+		app.log().LogEvent(&fxevent.RolledBack{
+			Err:     stopErr,
+			Hooks:   hooks,
+			Failed:  failed,
+			Ignored: stopErr != nil && policy == RollbackIgnoringErrors,
+		})
+
+		if stopErr != nil && policy != RollbackIgnoringErrors {
 			return multierr.Append(err, stopErr)
 		}
 
@@ -701,40 +1385,180 @@ func (app *App) withRollback(
 
 func (app *App) start(ctx context.Context) error {
 	return app.withRollback(ctx, func(ctx context.Context) error {
-		if err := app.lifecycle.Start(ctx); err != nil {
-			return err
+		start := app.lifecycle.Start
+		for i := len(app.startMiddleware) - 1; i >= 0; i-- {
+			start = app.startMiddleware[i].wrap(start)
 		}
-		return nil
+		return start(ctx)
 	})
 }
 
+// wrap returns a function that runs next through the middleware.
+func (o startMiddlewareOption) wrap(next func(context.Context) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		var called bool
+		err := o(ctx, func(ctx context.Context) error {
+			called = true
+			return next(ctx)
+		})
+		if err == nil && !called {
+			err = fmt.Errorf("fx.StartMiddleware(%v) did not start the application: "+
+				"it returned without calling next", fxreflect.FuncName(o))
+		}
+		return err
+	}
+}
+
 // Stop gracefully stops the application. It executes any registered OnStop
 // hooks in reverse order, so that each constructor's stop hooks are called
-// before its dependencies' stop hooks.
+// before its dependencies' stop hooks. Functions registered with
+// [BeforeStop] run before any OnStop hooks.
 //
 // If the application didn't start cleanly, only hooks whose OnStart phase was
 // called are executed. However, all those hooks are executed, even if some
 // fail.
+//
+// Stopping an application that is not running, including one that was
+// already stopped, is a no-op that returns nil: OnStop hooks are run at
+// most once per Start.
 func (app *App) Stop(ctx context.Context) (err error) {
+	ctx, endSpan := app.startSpan(ctx, "fx.Stop")
+	defer func() { endSpan(err) }()
+
+	begin := app.clock.Now()
+	running := app.lifecycle.Running()
+	app.probes.setReady(false)
+	if running {
+		app.systemd.stopping()
+	}
+	defer func() {
+		app.log().LogEvent(&fxevent.Stopped{
+			Runtime: app.clock.Since(begin),
+			Err:     err,
+		})
+		if running {
+			app.notifyStopped(err)
+		}
+	}()
+
+	if app.constructorCancel != nil {
+		defer app.constructorCancel()
+	}
+	// Start ends the startup profile, unless the application never started.
 	defer func() {
-		app.log().LogEvent(&fxevent.Stopped{Err: err})
+		err = multierr.Append(err, app.startupProfile.end())
 	}()
 
+	shutdownOpts := app.shutdownOpts.Swap(nil)
 	cb := func(ctx context.Context) error {
 		defer app.receivers.Stop(ctx)
-		return app.lifecycle.Stop(ctx)
+		if app.lifecycle.Running() {
+			for _, f := range app.beforeStop {
+				f()
+			}
+		}
+		err := app.drain(ctx, shutdownOpts)
+		err = multierr.Append(err, app.stopSubApps(ctx))
+		err = multierr.Append(err, app.lifecycle.Stop(ctx))
+		err = multierr.Append(err, app.probes.stop(ctx))
+		return multierr.Append(err, app.debug.stop(ctx))
 	}
 
-	return withTimeout(ctx, &withTimeoutParams{
+	app.lifecycle.TakeFailures() // of earlier attempts
+	err = withTimeout(ctx, &withTimeoutParams{
 		hook:      _onStopHook,
 		callback:  cb,
 		lifecycle: app.lifecycle,
 		log:       app.log(),
 	})
+	if err != nil {
+		app.handleLifecycleError(ctx, _onStopHook, err)
+	}
+	if app.parent != nil {
+		app.parent.removeSubApp(app)
+	}
+	return err
+}
+
+// Restart stops the application and starts it again, as if by calling
+// [App.Stop] and then [App.Start] with the given context.
+// OnDrain, OnStop, and OnStart hooks run as they would for those calls,
+// against freshly reset lifecycle state.
+// Constructors are not run again: hooks operate on the values that were
+// built by [New].
+//
+// The context bounds the whole restart.
+// If the application fails to stop, Restart returns the error
+// without starting it again.
+// If the application isn't running, Restart only starts it.
+//
+// Restart emits an [fxevent.Restarting] event before stopping the
+// application, and an [fxevent.Restarted] event once it's done.
+func (app *App) Restart(ctx context.Context) (err error) {
+	begin := app.clock.Now()
+	app.log().LogEvent(&fxevent.Restarting{})
+	defer func() {
+		app.log().LogEvent(&fxevent.Restarted{
+			Runtime: app.clock.Since(begin),
+			Err:     err,
+		})
+	}()
+
+	if err := app.Stop(ctx); err != nil {
+		return fmt.Errorf("restart: %w", err)
+	}
+	if err := app.Start(ctx); err != nil {
+		return fmt.Errorf("restart: %w", err)
+	}
+	return nil
+}
+
+// Reload runs the OnReload hooks of a running application,
+// in the order their OnStart hooks ran.
+// All OnReload hooks run even if some of them fail;
+// Reload returns their errors combined.
+// It emits an [fxevent.Reloaded] event once done.
+//
+// Reload is a no-op if the application isn't running.
+// See [ReloadOnSignal] to reload the application on a signal.
+func (app *App) Reload(ctx context.Context) error {
+	return app.reload(ctx, nil)
+}
+
+// reloadOnSignal reloads the application after it receives a signal
+// registered with ReloadOnSignal, bounded by the start timeout.
+func (app *App) reloadOnSignal(sig os.Signal) {
+	ctx, cancel := app.clock.WithTimeout(app.rootContext(), app.StartTimeout())
+	defer cancel()
+	_ = app.reload(ctx, sig) // reported with fxevent.Reloaded
+}
+
+func (app *App) reload(ctx context.Context, sig os.Signal) (err error) {
+	begin := app.clock.Now()
+	defer func() {
+		app.log().LogEvent(&fxevent.Reloaded{
+			Signal:  sig,
+			Runtime: app.clock.Since(begin),
+			Err:     err,
+		})
+	}()
+	return app.lifecycle.Reload(ctx)
+}
+
+// drain runs the OnDrain hooks, within the drain timeout of the Shutdown
+// that stops the application, if any.
+func (app *App) drain(ctx context.Context, opts *shutdownOptions) error {
+	if opts != nil && opts.drainTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = app.clock.WithTimeout(ctx, opts.drainTimeout)
+		defer cancel()
+	}
+	return app.lifecycle.Drain(ctx)
 }
 
 // Done returns a channel of signals to block on after starting the
-// application. Applications listen for the SIGINT and SIGTERM signals; during
+// application. Applications listen for the SIGINT and SIGTERM signals,
+// unless configured otherwise with [ShutdownSignals]; during
 // development, users can send the application SIGTERM by pressing Ctrl-C in
 // the same terminal as the running process.
 //
@@ -756,6 +1580,43 @@ func (app *App) Wait() <-chan ShutdownSignal {
 	return app.receivers.Wait()
 }
 
+// DroppedEvents reports the number of Fx events that were discarded
+// because they did not fit within the limit set by [EventBufferLimit].
+// Events are only buffered during [New],
+// so this value does not change once New returns.
+func (app *App) DroppedEvents() int {
+	return app.droppedEvents
+}
+
+// AppliedOptions returns the String renderings of the options used to
+// build the application, in the order they were applied.
+//
+// Options passed to [Options] are listed individually,
+// and so are options passed to [If] or [When] that were included;
+// skipped ones are not listed.
+// Options passed to a [Module] are listed individually as well,
+// prefixed with the path of the module they were passed to,
+// such as "server/http: fx.Provide(...)".
+// Options passed to [App.Try] are not listed.
+func (app *App) AppliedOptions() []string {
+	return append([]string(nil), app.appliedOptions...)
+}
+
+// LoggerType reports the concrete type of the [fxevent.Logger] that the
+// application logs its events to, such as "*fxevent.ConsoleLogger" or
+// "*fxevent.ZapLogger". It reports "fxevent.NopLogger" for [NopLogger].
+//
+// The logger is final once [New] returns.
+// If the logger given to [WithLogger] failed to build,
+// the type of the fallback logger in use is reported.
+func (app *App) LoggerType() string {
+	log := unwrapLogger(app.log())
+	if reflect.TypeOf(log) == reflect.TypeOf(fxevent.NopLogger) {
+		return "fxevent.NopLogger"
+	}
+	return fmt.Sprintf("%T", log)
+}
+
 // StartTimeout returns the configured startup timeout.
 // This defaults to [DefaultTimeout], and can be changed with the
 // [StartTimeout] option.
@@ -772,8 +1633,20 @@ func (app *App) StopTimeout() time.Duration {
 
 func (app *App) dotGraph() (DotGraph, error) {
 	var b bytes.Buffer
-	err := dig.Visualize(app.container, &b)
-	return DotGraph(b.String()), err
+	writeDotGraph(&b, app.root)
+	return DotGraph(b.String()), nil
+}
+
+func (app *App) mermaidGraph() MermaidGraph {
+	var b bytes.Buffer
+	writeMermaidGraph(&b, app.Graph())
+	return MermaidGraph(b.String())
+}
+
+// introspect describes the application for the values Fx provides about it.
+func (app *App) introspect() (DotGraph, MermaidGraph, Modules, error) {
+	dot, err := app.dotGraph()
+	return dot, app.mermaidGraph(), app.root.loadedModules(), err
 }
 
 type withTimeoutParams struct {
@@ -21,17 +21,19 @@
 package fx
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/fx/fxevent"
-	"go.uber.org/fx/internal/fxclock"
 	"go.uber.org/fx/internal/fxlog"
 	"go.uber.org/fx/internal/fxreflect"
 )
@@ -66,6 +68,105 @@ func TestAppRun(t *testing.T) {
 	}, spy.EventTypes())
 }
 
+func TestAppRunRootContext(t *testing.T) {
+	t.Parallel()
+
+	type ctxKey struct{}
+
+	rootCtx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "root"))
+	defer cancel()
+
+	var startVal, stopVal interface{}
+	var stopErr error
+	spy := new(fxlog.Spy)
+	app := New(
+		WithLogger(func() fxevent.Logger { return spy }),
+		WithRootContext(rootCtx),
+		Invoke(func(lc Lifecycle) {
+			lc.Append(Hook{
+				OnStart: func(ctx context.Context) error {
+					startVal = ctx.Value(ctxKey{})
+					return nil
+				},
+				OnStop: func(ctx context.Context) error {
+					stopVal = ctx.Value(ctxKey{})
+					stopErr = ctx.Err()
+					return nil
+				},
+			})
+		}),
+	)
+	require.NoError(t, app.Err())
+
+	exitc := make(chan int, 1)
+	go func() {
+		exitc <- app.run(func() <-chan ShutdownSignal { return nil })
+	}()
+
+	// Run shuts the application down once the root context is done,
+	// but stops it with a context that isn't canceled.
+	require.Eventually(t, func() bool {
+		return len(spy.Events().SelectByTypeName("Started")) > 0
+	}, time.Second, time.Millisecond)
+	cancel()
+	assert.Equal(t, 0, <-exitc)
+
+	assert.Equal(t, "root", startVal)
+	assert.Equal(t, "root", stopVal)
+	assert.NoError(t, stopErr)
+
+	stopping := spy.Events().SelectByTypeName("Stopping")
+	require.Len(t, stopping, 1)
+	assert.ErrorIs(t, stopping[0].(*fxevent.Stopping).Reason, context.Canceled)
+	assert.Equal(t, _sigTERM, stopping[0].(*fxevent.Stopping).Signal)
+}
+
+func TestAppRunRootContextConsoleLogger(t *testing.T) {
+	t.Parallel()
+
+	rootCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var buf syncBuffer
+	app := New(
+		WithLogger(func() fxevent.Logger {
+			return &fxevent.ConsoleLogger{W: &buf}
+		}),
+		WithRootContext(rootCtx),
+	)
+	require.NoError(t, app.Err())
+
+	exitc := make(chan int, 1)
+	go func() {
+		exitc <- app.run(func() <-chan ShutdownSignal { return nil })
+	}()
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(buf.String(), "RUNNING")
+	}, time.Second, time.Millisecond)
+	cancel()
+	assert.Equal(t, 0, <-exitc)
+	assert.Contains(t, buf.String(), "[Fx] TERMINATED: context canceled")
+}
+
+// syncBuffer is a bytes.Buffer that's safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
 // TestValidateString verifies private option. Public options are tested in app_test.go.
 func TestValidateString(t *testing.T) {
 	t.Parallel()
@@ -91,23 +192,6 @@ func (o withExitOption) apply(m *module) {
 	m.app.osExit = o
 }
 
-// WithClock specifies how Fx accesses time operations.
-//
-// This is an internal option available only to tests defined in this package.
-func WithClock(clock fxclock.Clock) Option {
-	return withClockOption{clock}
-}
-
-type withClockOption struct{ clock fxclock.Clock }
-
-func (o withClockOption) apply(m *module) {
-	m.app.clock = o.clock
-}
-
-func (o withClockOption) String() string {
-	return fmt.Sprintf("WithClock(%v)", o.clock)
-}
-
 func TestAnnotationError(t *testing.T) {
 	wantErr := errors.New("want error")
 	err := &annotationError{
@@ -138,3 +222,31 @@ func TestStartDoesNotRegisterSignals(t *testing.T) {
 	_ = app.Wait() // User signals intent have fx listen for signals. This should call notify
 	assert.True(t, calledNotify, "notify should be called after Wait")
 }
+
+func TestSubAppsReleased(t *testing.T) {
+	t.Parallel()
+
+	var factory SubAppFactory
+	parent := New(
+		WithLogger(func() fxevent.Logger { return fxevent.NopLogger }),
+		SubApps(),
+		Populate(&factory),
+	)
+	require.NoError(t, parent.Err())
+	ctx := context.Background()
+	require.NoError(t, parent.Start(ctx))
+	defer func() { require.NoError(t, parent.Stop(ctx)) }()
+
+	failed := factory.New(Invoke(func() error { return errors.New("great sadness") }))
+	require.Error(t, failed.Err())
+	assert.Empty(t, parent.subApps, "children that failed to build must not be retained")
+
+	child := factory.New()
+	require.NoError(t, child.Err())
+	require.NoError(t, child.Start(ctx))
+	require.NoError(t, child.Stop(ctx))
+	assert.Empty(t, parent.subApps, "stopped children must be released")
+
+	require.NoError(t, child.Start(ctx))
+	assert.Equal(t, []*App{child}, parent.subApps, "restarted children must be stopped with the parent")
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"go.uber.org/dig"
 	. "go.uber.org/fx"
 	"go.uber.org/fx/fxevent"
 	"go.uber.org/fx/fxtest"
@@ -751,6 +752,375 @@ func TestWithLogger(t *testing.T) {
 	})
 }
 
+func TestLoggerType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc string
+		give []Option
+		want string
+	}{
+		{
+			desc: "default",
+			want: "*fxevent.ConsoleLogger",
+		},
+		{
+			desc: "NopLogger",
+			give: []Option{NopLogger},
+			want: "fxevent.NopLogger",
+		},
+		{
+			desc: "custom logger",
+			give: []Option{WithLogger(func() fxevent.Logger { return new(fxlog.Spy) })},
+			want: "*fxlog.Spy",
+		},
+		{
+			desc: "zap logger",
+			give: []Option{
+				WithLogger(func() fxevent.Logger {
+					return &fxevent.ZapLogger{Logger: zap.NewNop()}
+				}),
+			},
+			want: "*fxevent.ZapLogger",
+		},
+		{
+			desc: "custom logger fails",
+			give: []Option{
+				WithLogger(func() (fxevent.Logger, error) {
+					return nil, errors.New("great sadness")
+				}),
+			},
+			want: "*fxevent.ConsoleLogger",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Discard the output of the default logger.
+			app := New(append(tt.give, Logger(log.New(io.Discard, "", 0)))...)
+			assert.Equal(t, tt.want, app.LoggerType())
+		})
+	}
+}
+
+func TestAppliedOptions(t *testing.T) {
+	t.Parallel()
+
+	app := New(
+		NopLogger,
+		Options(
+			Invoke(connectDB),
+			Module("server",
+				Invoke(flushLogs),
+				Module("http", Invoke(connectDB)),
+			),
+		),
+		Module("empty"),
+		StartTimeout(time.Second),
+	)
+	require.NoError(t, app.Err())
+
+	assert.Equal(t, []string{
+		"fx.WithLogger(go.uber.org/fx.init.func1())",
+		"fx.Invoke(go.uber.org/fx_test.connectDB())",
+		"server: fx.Invoke(go.uber.org/fx_test.flushLogs())",
+		"server/http: fx.Invoke(go.uber.org/fx_test.connectDB())",
+		"fx.StartTimeout(1s)",
+	}, app.AppliedOptions())
+
+	t.Run("excludes Try", func(t *testing.T) {
+		want := app.AppliedOptions()
+		require.NoError(t, app.Try(Module("trial", Invoke(connectDB))))
+		assert.Equal(t, want, app.AppliedOptions())
+	})
+}
+
+func startServer(context.Context) error { return nil }
+func stopServer(context.Context) error  { return nil }
+func connectDB()                        {}
+func flushLogs()                        {}
+
+func TestRegisteredHooks(t *testing.T) {
+	t.Parallel()
+
+	type server struct{}
+
+	app := NewForTest(t,
+		Module("db",
+			Invoke(func(lc Lifecycle) {
+				lc.Append(StartHook(connectDB))
+			}),
+		),
+		Module("server",
+			Provide(func(lc Lifecycle) *server {
+				lc.Append(Hook{OnStart: startServer, OnStop: stopServer})
+				return &server{}
+			}),
+		),
+		Invoke(func(lc Lifecycle, _ *server) {
+			lc.Append(StopHook(flushLogs))
+		}),
+	)
+	require.NoError(t, app.Err())
+
+	hooks := app.RegisteredHooks()
+	for i := range hooks {
+		assert.Contains(t, hooks[i].Caller, "TestRegisteredHooks",
+			"unexpected caller for hook %d", i)
+		hooks[i].Caller = ""
+	}
+	assert.Equal(t, []HookInfo{
+		{OnStart: "go.uber.org/fx_test.connectDB()", Module: "db"},
+		{
+			OnStart: "go.uber.org/fx_test.startServer()",
+			OnStop:  "go.uber.org/fx_test.stopServer()",
+			Module:  "server",
+		},
+		{OnStop: "go.uber.org/fx_test.flushLogs()"},
+	}, hooks)
+
+	t.Run("no hooks", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t)
+		assert.Empty(t, app.RegisteredHooks())
+	})
+
+	t.Run("names and statuses", func(t *testing.T) {
+		t.Parallel()
+
+		var inspector LifecycleInspector
+		app, spy := NewSpied(
+			Invoke(func(lc Lifecycle, li LifecycleInspector) {
+				inspector = li
+				lc.Append(Hook{Name: "db", OnStart: startServer, OnStop: stopServer})
+				lc.Append(Hook{
+					Name:   "http-server",
+					OnStop: func(context.Context) error { return errors.New("great sadness") },
+				})
+				lc.Append(Hook{
+					Name:    "cache",
+					OnStart: func(context.Context) error { return errors.New("great sadness") },
+				})
+			}),
+		)
+		require.NoError(t, app.Err())
+
+		statuses := func() []HookStatus {
+			var ss []HookStatus
+			for _, h := range inspector.Hooks() {
+				ss = append(ss, h.Status)
+			}
+			return ss
+		}
+		assert.Equal(t, []HookStatus{HookPending, HookPending, HookPending}, statuses())
+
+		require.Error(t, app.Start(context.Background()))
+		assert.Equal(t, []HookStatus{HookStopped, HookStopFailed, HookStartFailed}, statuses())
+		assert.Equal(t, "db", inspector.Hooks()[0].Name)
+		assert.Equal(t, "go.uber.org/fx_test.startServer()", inspector.Hooks()[0].OnStart)
+
+		executed := spy.Events().SelectByTypeName("OnStartExecuted")
+		require.Len(t, executed, 2)
+		assert.Equal(t, "db", executed[0].(*fxevent.OnStartExecuted).FunctionName)
+		assert.Equal(t, "cache", executed[1].(*fxevent.OnStartExecuted).FunctionName)
+	})
+}
+
+func TestRunRuntime(t *testing.T) {
+	t.Parallel()
+
+	type config struct{}
+
+	clock := fxclock.NewMock()
+	_, spy := NewSpied(
+		WithClock(clock),
+		TimeConstructors(),
+		Provide(func() *config {
+			clock.Add(2 * time.Second)
+			return &config{}
+		}),
+		Decorate(func(c *config) *config {
+			clock.Add(time.Second)
+			return c
+		}),
+		Invoke(func(*config) {}),
+	)
+
+	runs := spy.Events().SelectByTypeName("Run")
+	require.Len(t, runs, 2)
+	assert.Equal(t, "provide", runs[0].(*fxevent.Run).Kind)
+	assert.Equal(t, 2*time.Second, runs[0].(*fxevent.Run).Runtime)
+	assert.Equal(t, "decorate", runs[1].(*fxevent.Run).Kind)
+	assert.Zero(t, runs[1].(*fxevent.Run).Runtime, "decorators are not timed")
+}
+
+func TestRunRuntimeDisabled(t *testing.T) {
+	t.Parallel()
+
+	type config struct{}
+
+	clock := fxclock.NewMock()
+	var wrapped bool
+	_, spy := NewSpied(
+		WithClock(clock),
+		Provide(func() *config {
+			pcs := make([]uintptr, 32)
+			frames := runtime.CallersFrames(pcs[:runtime.Callers(1, pcs)])
+			for f, more := frames.Next(); more; f, more = frames.Next() {
+				wrapped = wrapped || strings.Contains(f.Function, "instrumentedContainer")
+			}
+			clock.Add(2 * time.Second)
+			return &config{}
+		}),
+		Invoke(func(*config) {}),
+	)
+
+	runs := spy.Events().SelectByTypeName("Run")
+	require.Len(t, runs, 1)
+	assert.Zero(t, runs[0].(*fxevent.Run).Runtime,
+		"constructors are not timed without fx.TimeConstructors")
+	assert.False(t, wrapped, "constructor must not be wrapped")
+}
+
+func TestHookStatusString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		give HookStatus
+		want string
+	}{
+		{HookPending, "pending"},
+		{HookStarted, "started"},
+		{HookStartFailed, "start failed"},
+		{HookStopped, "stopped"},
+		{HookStopFailed, "stop failed"},
+		{HookStatus(42), "HookStatus(42)"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.give.String())
+	}
+}
+
+type (
+	genericUser              struct{}
+	genericOrder             struct{}
+	genericRepository[T any] struct{}
+)
+
+func newGenericRepository[T any]() *genericRepository[T] { return &genericRepository[T]{} }
+
+func TestGenericInstantiations(t *testing.T) {
+	t.Parallel()
+
+	var (
+		users  *genericRepository[genericUser]
+		orders *genericRepository[genericOrder]
+	)
+	app, spy := NewSpied(
+		Provide(
+			newGenericRepository[genericUser],
+			newGenericRepository[genericOrder],
+		),
+		Populate(&users, &orders),
+	)
+	require.NoError(t, app.Err())
+	assert.NotNil(t, users)
+	assert.NotNil(t, orders)
+
+	var provided []string
+	for _, e := range spy.Events().SelectByTypeName("Provided") {
+		e := e.(*fxevent.Provided)
+		if strings.Contains(e.ConstructorName, "newGenericRepository") {
+			provided = append(provided, strings.Join(e.OutputTypeNames, ", ")+" <= "+e.ConstructorName)
+		}
+	}
+	assert.Equal(t, []string{
+		"*fx_test.genericRepository[go.uber.org/fx_test.genericUser] <= " +
+			"go.uber.org/fx_test.newGenericRepository[...]() *fx_test.genericRepository[go.uber.org/fx_test.genericUser]",
+		"*fx_test.genericRepository[go.uber.org/fx_test.genericOrder] <= " +
+			"go.uber.org/fx_test.newGenericRepository[...]() *fx_test.genericRepository[go.uber.org/fx_test.genericOrder]",
+	}, provided)
+
+	var ran []string
+	for _, e := range spy.Events().SelectByTypeName("Run") {
+		if e := e.(*fxevent.Run); strings.Contains(e.Name, "newGenericRepository") {
+			ran = append(ran, e.Name)
+		}
+	}
+	assert.Len(t, ran, 2)
+	assert.NotEqual(t, ran[0], ran[1], "instantiations must be told apart")
+
+	t.Run("missing instantiation", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			Provide(newGenericRepository[genericUser]),
+			Invoke(func(*genericRepository[genericOrder]) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			"missing type: *fx_test.genericRepository[go.uber.org/fx_test.genericOrder]")
+	})
+}
+
+func TestEventBufferLimit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("drops events that do not fit", func(t *testing.T) {
+		t.Parallel()
+
+		var spy fxlog.Spy
+		app := New(
+			EventBufferLimit(1),
+			Supply(&spy),
+			Provide(bytes.NewReader, bytes.NewBufferString, strings.NewReader),
+			WithLogger(func(spy *fxlog.Spy) fxevent.Logger {
+				return spy
+			}),
+		)
+		require.NoError(t, app.Err())
+
+		// Everything buffered before the logger was built is too large
+		// to fit, so only events logged afterwards are visible.
+		assert.Equal(t, []string{"LoggerInitialized"}, spy.EventTypes())
+		assert.Equal(t, 8, app.DroppedEvents())
+	})
+
+	t.Run("unbounded by default", func(t *testing.T) {
+		t.Parallel()
+
+		var spy fxlog.Spy
+		app := New(
+			Supply(&spy),
+			WithLogger(func(spy *fxlog.Spy) fxevent.Logger {
+				return spy
+			}),
+		)
+		require.NoError(t, app.Err())
+
+		assert.Equal(t, []string{
+			"Provided", "Provided", "Provided", "Supplied", "Run", "LoggerInitialized",
+		}, spy.EventTypes())
+		assert.Zero(t, app.DroppedEvents())
+	})
+
+	t.Run("in module", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, Module("foo", EventBufferLimit(1024)))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			"fx.EventBufferLimit Option should be passed to top-level App, not to fx.Module")
+	})
+}
+
 func getInt() int { return 0 }
 
 func decorateInt(i int) int { return i }
@@ -1007,6 +1377,86 @@ func TestRunEventEmission(t *testing.T) {
 	}
 }
 
+func TestRecoverFromPanicsErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc      string
+		giveOpts  []Option
+		wantKind  string
+		wantValue interface{}
+		wantStack bool
+	}{
+		{
+			desc: "Provide",
+			giveOpts: []Option{
+				Module("mod", Provide(func() int { panic("bad provide") })),
+				Invoke(func(int) {}),
+			},
+			wantKind:  "provide",
+			wantValue: "bad provide",
+			wantStack: true,
+		},
+		{
+			desc: "Decorate",
+			giveOpts: []Option{
+				Supply(5),
+				Module("mod",
+					Decorate(func(int) int { panic("bad decorate") }),
+					Invoke(func(int) {}),
+				),
+			},
+			wantKind:  "decorate",
+			wantValue: "bad decorate",
+		},
+		{
+			desc: "Invoke",
+			giveOpts: []Option{
+				Module("mod", Invoke(func() { panic(42) })),
+			},
+			wantKind:  "invoke",
+			wantValue: 42,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			app, spy := NewSpied(append(tt.giveOpts, RecoverFromPanics())...)
+			err := app.Err()
+			require.Error(t, err)
+
+			var panicErr *PanicError
+			require.ErrorAs(t, err, &panicErr)
+			assert.Contains(t, panicErr.Function, "TestRecoverFromPanicsErrors")
+			assert.Equal(t, tt.wantValue, panicErr.Value)
+			assert.Equal(t, err.Error(), panicErr.Err.Error())
+
+			var digErr dig.PanicError
+			assert.ErrorAs(t, err, &digErr, "must still wrap the dig.PanicError")
+
+			events := spy.Events().SelectByTypeName("Panicked")
+			require.Len(t, events, 1)
+			e := events[0].(*fxevent.Panicked)
+			assert.Equal(t, panicErr.Function, e.Name)
+			assert.Equal(t, tt.wantKind, e.Kind)
+			assert.Equal(t, "mod", e.ModuleName)
+			assert.Equal(t, tt.wantValue, e.Value)
+			assert.Equal(t, string(panicErr.Stack), e.Stack)
+
+			if tt.wantStack {
+				// The stack leads to where the panic occurred.
+				assert.Contains(t, string(panicErr.Stack), "TestRecoverFromPanicsErrors")
+				assert.Contains(t, string(panicErr.Stack), "app_test.go")
+			} else {
+				assert.Empty(t, panicErr.Stack)
+			}
+		})
+	}
+}
+
 type customError struct {
 	err error
 }
@@ -1106,6 +1556,121 @@ func TestInvokes(t *testing.T) {
 	})
 }
 
+func TestInvokeInto(t *testing.T) {
+	t.Parallel()
+
+	type config struct{ addr string }
+	type server struct{ addr string }
+
+	t.Run("sets targets", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			srv  *server
+			addr fmt.Stringer
+		)
+		app := fxtest.New(t,
+			Supply(&config{addr: ":80"}),
+			InvokeInto(func(cfg *config) (*server, *bytes.Buffer, error) {
+				return &server{addr: cfg.addr}, bytes.NewBufferString(cfg.addr), nil
+			}, &srv, &addr),
+		)
+		defer app.RequireStart().RequireStop()
+
+		require.NotNil(t, srv)
+		assert.Equal(t, ":80", srv.addr)
+		assert.Equal(t, ":80", addr.String())
+	})
+
+	t.Run("annotated", func(t *testing.T) {
+		t.Parallel()
+
+		var srv *server
+		app := fxtest.New(t,
+			Provide(Annotate(
+				func() *config { return &config{addr: ":443"} },
+				ResultTags(`name:"tls"`),
+			)),
+			InvokeInto(Annotate(
+				func(cfg *config) *server { return &server{addr: cfg.addr} },
+				ParamTags(`name:"tls"`),
+			), &srv),
+		)
+		defer app.RequireStart().RequireStop()
+
+		require.NotNil(t, srv)
+		assert.Equal(t, ":443", srv.addr)
+	})
+
+	t.Run("error leaves targets unset", func(t *testing.T) {
+		t.Parallel()
+
+		srv := &server{addr: "unchanged"}
+		app := NewForTest(t,
+			InvokeInto(func() (*server, error) {
+				return &server{}, errors.New("great sadness")
+			}, &srv),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+		assert.Equal(t, "unchanged", srv.addr)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			srv *server
+			n   int
+		)
+		tests := []struct {
+			desc    string
+			give    Option
+			wantErr string
+		}{
+			{
+				desc:    "not a function",
+				give:    InvokeInto(42),
+				wantErr: "42 is not a function, got int",
+			},
+			{
+				desc:    "too few targets",
+				give:    InvokeInto(func() (*server, int) { return nil, 0 }, &srv),
+				wantErr: "returns 2 values, got 1 targets",
+			},
+			{
+				desc:    "not a pointer",
+				give:    InvokeInto(func() *server { return nil }, srv),
+				wantErr: "target 1 is not a non-nil pointer, got *fx_test.server",
+			},
+			{
+				desc:    "nil pointer",
+				give:    InvokeInto(func() int { return 0 }, (*int)(nil)),
+				wantErr: "target 1 is not a non-nil pointer, got *int",
+			},
+			{
+				desc:    "wrong type",
+				give:    InvokeInto(func() *server { return nil }, &n),
+				wantErr: "target 1: cannot assign *fx_test.server to int",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := NewForTest(t, tt.give)
+				err := app.Err()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "failed to InvokeInto")
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+}
+
 func TestError(t *testing.T) {
 	t.Parallel()
 
@@ -1547,11 +2112,69 @@ func TestAppStart(t *testing.T) {
 		assert.Contains(t, "context deadline exceeded", err.Error())
 	})
 
-	t.Run("CtxCancelledDuringStart", func(t *testing.T) {
+	t.Run("HookTimeout", func(t *testing.T) {
 		t.Parallel()
 
-		type A struct{}
-		running := make(chan struct{})
+		mockClock := fxclock.NewMock()
+		spy := new(fxlog.Spy)
+		app := New(
+			WithLogger(func() fxevent.Logger { return spy }),
+			WithClock(mockClock),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{
+					OnStart: func(ctx context.Context) error {
+						mockClock.Add(2 * time.Second)
+						return ctx.Err()
+					},
+					Timeout: time.Second,
+				})
+				lc.Append(Hook{
+					OnStart: func(context.Context) error {
+						t.Error("this hook should not run")
+						return nil
+					},
+				})
+			}),
+		)
+
+		ctx, cancel := mockClock.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		err := app.Start(ctx)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Contains(t, err.Error(), "hook did not finish within its 1s timeout")
+		assert.NoError(t, ctx.Err(), "application deadline must not be reached")
+	})
+
+	t.Run("HookRetry", func(t *testing.T) {
+		t.Parallel()
+
+		var attempts int
+		app := NewForTest(t,
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{
+					OnStart: func(context.Context) error {
+						attempts++
+						if attempts < 2 {
+							return errors.New("database not reachable")
+						}
+						return nil
+					},
+					Retry: RetryPolicy{Attempts: 2},
+				})
+			}),
+		)
+		require.NoError(t, app.Start(context.Background()))
+		require.NoError(t, app.Stop(context.Background()))
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("CtxCancelledDuringStart", func(t *testing.T) {
+		t.Parallel()
+
+		type A struct{}
+		running := make(chan struct{})
 		newA := func(lc Lifecycle) *A {
 			lc.Append(
 				Hook{
@@ -1889,81 +2512,1218 @@ func TestAppStart(t *testing.T) {
 		assert.Contains(t, "goroutine exited without returning", err)
 	})
 
-	t.Run("StartTwiceWithHooksErrors", func(t *testing.T) {
+	t.Run("StartTwiceWithHooksErrors", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		app := fxtest.New(t,
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{
+					OnStart: func(ctx context.Context) error { return nil },
+					OnStop:  func(ctx context.Context) error { return nil },
+				})
+			}),
+		)
+		assert.NoError(t, app.Start(ctx))
+		err := app.Start(ctx)
+		if assert.Error(t, err) {
+			assert.ErrorContains(t, err, "attempted to start lifecycle when in state: started")
+			assert.ErrorIs(t, err, ErrAlreadyStarted)
+		}
+		app.Stop(ctx)
+		assert.NoError(t, app.Start(ctx))
+		app.Stop(ctx)
+	})
+
+	t.Run("StartTwiceRunsHooksOnce", func(t *testing.T) {
+		t.Parallel()
+
+		var starts, stops int
+		app, spy := NewSpied(
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{
+					OnStart: func(context.Context) error {
+						starts++
+						return nil
+					},
+					OnStop: func(context.Context) error {
+						stops++
+						return nil
+					},
+				})
+			}),
+		)
+		require.NoError(t, app.Start(context.Background()))
+
+		err := app.Start(context.Background())
+		require.ErrorIs(t, err, ErrAlreadyStarted)
+		assert.Equal(t, 1, starts, "OnStart must run exactly once")
+		assert.Zero(t, stops, "failed Start must not stop a running app")
+		assert.Empty(t, spy.Events().SelectByTypeName("RollingBack"),
+			"failed Start must not roll back a running app")
+
+		require.NoError(t, app.Stop(context.Background()))
+		assert.Equal(t, 1, stops)
+	})
+
+	t.Run("Runtime", func(t *testing.T) {
+		t.Parallel()
+
+		mockClock := fxclock.NewMock()
+		sleep := func(d time.Duration) func(context.Context) error {
+			return func(context.Context) error {
+				mockClock.Add(d)
+				return nil
+			}
+		}
+
+		app, spy := NewSpied(
+			WithClock(mockClock),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{
+					OnStart: sleep(100 * time.Millisecond),
+					OnStop:  sleep(time.Second),
+				})
+				lc.Append(Hook{
+					OnStart: sleep(300 * time.Millisecond),
+					OnStop:  sleep(2 * time.Second),
+				})
+			}),
+		)
+		require.NoError(t, app.Start(context.Background()))
+		require.NoError(t, app.Stop(context.Background()))
+
+		hookRuntime := func(typeName string) (total time.Duration) {
+			for _, e := range spy.Events().SelectByTypeName(typeName) {
+				switch e := e.(type) {
+				case *fxevent.OnStartExecuted:
+					total += e.Runtime
+				case *fxevent.OnStopExecuted:
+					total += e.Runtime
+				}
+			}
+			return total
+		}
+
+		started := spy.Events().SelectByTypeName("Started")
+		require.Len(t, started, 1)
+		assert.Equal(t, 400*time.Millisecond, started[0].(*fxevent.Started).Runtime)
+		assert.Equal(t, hookRuntime("OnStartExecuted"), started[0].(*fxevent.Started).Runtime)
+
+		stopped := spy.Events().SelectByTypeName("Stopped")
+		require.Len(t, stopped, 1)
+		assert.Equal(t, 3*time.Second, stopped[0].(*fxevent.Stopped).Runtime)
+		assert.Equal(t, hookRuntime("OnStopExecuted"), stopped[0].(*fxevent.Stopped).Runtime)
+	})
+}
+
+func TestAppStop(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Timeout", func(t *testing.T) {
+		t.Parallel()
+
+		mockClock := fxclock.NewMock()
+
+		block := func(ctx context.Context) error {
+			mockClock.Add(5 * time.Second)
+			return ctx.Err()
+		}
+		// NOTE: for tests that gets cancelled/times out during lifecycle methods, it's possible
+		// for them to run into race with fxevent logs getting written to testing.T with the
+		// remainder of the tests. As a workaround, we provide fxlog.Spy to prevent the lifecycle
+		// goroutine from writing to testing.T.
+		spy := new(fxlog.Spy)
+		app := New(Invoke(func(l Lifecycle) { l.Append(Hook{OnStop: block}) }),
+			WithLogger(func() fxevent.Logger { return spy }),
+			WithClock(mockClock),
+		)
+
+		err := app.Start(context.Background())
+		require.Nil(t, err)
+
+		ctx, cancel := mockClock.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		err = app.Stop(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "context deadline exceeded")
+	})
+
+	t.Run("StopError", func(t *testing.T) {
+		t.Parallel()
+
+		failStop := func(lc Lifecycle) struct{} {
+			lc.Append(Hook{OnStop: func(context.Context) error {
+				return errors.New("OnStop fail")
+			}})
+			return struct{}{}
+		}
+		app := fxtest.New(t,
+			Provide(failStop),
+			Invoke(func(struct{}) {}),
+		)
+		app.RequireStart()
+		err := app.Stop(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "OnStop fail")
+	})
+
+	t.Run("StopTwiceRunsHooksOnce", func(t *testing.T) {
+		t.Parallel()
+
+		var stops, beforeStops int
+		app := fxtest.New(t,
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{OnStop: func(context.Context) error {
+					stops++
+					return nil
+				}})
+			}),
+			BeforeStop(func() { beforeStops++ }),
+		)
+		app.RequireStart()
+
+		require.NoError(t, app.Stop(context.Background()))
+		require.NoError(t, app.Stop(context.Background()), "second Stop must be a no-op")
+		assert.Equal(t, 1, stops, "OnStop must run exactly once")
+		assert.Equal(t, 1, beforeStops, "BeforeStop must run exactly once")
+	})
+
+	t.Run("StopWithoutStart", func(t *testing.T) {
+		t.Parallel()
+
+		var stops int
+		app := fxtest.New(t,
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{OnStop: func(context.Context) error {
+					stops++
+					return nil
+				}})
+			}),
+			BeforeStop(func() { stops++ }),
+		)
+
+		require.NoError(t, app.Stop(context.Background()))
+		assert.Zero(t, stops)
+	})
+}
+
+func TestRestart(t *testing.T) {
+	t.Parallel()
+
+	t.Run("stops and starts", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			calls        []string
+			constructors int
+		)
+		type server struct{}
+		app, spy := NewSpied(
+			Provide(func(lc Lifecycle) *server {
+				constructors++
+				lc.Append(Hook{
+					OnStart: func(context.Context) error {
+						calls = append(calls, "start")
+						return nil
+					},
+					OnStop: func(context.Context) error {
+						calls = append(calls, "stop")
+						return nil
+					},
+				})
+				return &server{}
+			}),
+			Invoke(func(*server) {}),
+		)
+		require.NoError(t, app.Start(context.Background()))
+		spy.Reset()
+
+		require.NoError(t, app.Restart(context.Background()))
+		assert.Equal(t, []string{"start", "stop", "start"}, calls)
+		assert.Equal(t, 1, constructors, "constructors must not run again")
+		assert.Equal(t, []string{
+			"Restarting",
+			"OnStopExecuting", "OnStopExecuted", "Stopped",
+			"OnStartExecuting", "OnStartExecuted", "Started",
+			"Restarted",
+		}, spy.EventTypes())
+
+		require.NoError(t, app.Stop(context.Background()))
+		assert.Equal(t, []string{"start", "stop", "start", "stop"}, calls)
+	})
+
+	t.Run("not running", func(t *testing.T) {
+		t.Parallel()
+
+		var started int
+		app := NewForTest(t,
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{
+					OnStart: func(context.Context) error {
+						started++
+						return nil
+					},
+				})
+			}),
+		)
+		require.NoError(t, app.Restart(context.Background()))
+		assert.Equal(t, 1, started)
+		require.NoError(t, app.Stop(context.Background()))
+	})
+
+	t.Run("stop fails", func(t *testing.T) {
+		t.Parallel()
+
+		var started int
+		app, spy := NewSpied(
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{
+					OnStart: func(context.Context) error {
+						started++
+						return nil
+					},
+					OnStop: func(context.Context) error {
+						return errors.New("great sadness")
+					},
+				})
+			}),
+		)
+		require.NoError(t, app.Start(context.Background()))
+
+		err := app.Restart(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "restart: great sadness")
+		assert.Equal(t, 1, started, "must not start after a failed stop")
+
+		restarted := spy.Events().SelectByTypeName("Restarted")
+		require.Len(t, restarted, 1)
+		assert.ErrorContains(t, restarted[0].(*fxevent.Restarted).Err, "great sadness")
+	})
+}
+
+func TestReload(t *testing.T) {
+	t.Parallel()
+
+	newApp := func(calls *[]string, errs map[string]error) (*App, *fxlog.Spy) {
+		hook := func(name string) Hook {
+			return Hook{
+				OnReload: func(context.Context) error {
+					*calls = append(*calls, name)
+					return errs[name]
+				},
+			}
+		}
+		return NewSpied(
+			Invoke(func(lc Lifecycle) {
+				lc.Append(hook("first"))
+				lc.Append(Hook{OnStart: func(context.Context) error { return nil }})
+				lc.Append(hook("second"))
+			}),
+		)
+	}
+
+	t.Run("runs hooks in start order", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		app, spy := newApp(&calls, nil)
+		require.NoError(t, app.Start(context.Background()))
+		defer app.Stop(context.Background())
+		spy.Reset()
+
+		require.NoError(t, app.Reload(context.Background()))
+		assert.Equal(t, []string{"first", "second"}, calls)
+		assert.Equal(t, []string{"Reloaded"}, spy.EventTypes())
+	})
+
+	t.Run("combines errors", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		app, spy := newApp(&calls, map[string]error{
+			"first":  errors.New("great sadness"),
+			"second": errors.New("even more sadness"),
+		})
+		require.NoError(t, app.Start(context.Background()))
+		defer app.Stop(context.Background())
+
+		err := app.Reload(context.Background())
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "great sadness")
+		assert.ErrorContains(t, err, "even more sadness")
+		assert.Equal(t, []string{"first", "second"}, calls)
+
+		reloaded := spy.Events().SelectByTypeName("Reloaded")
+		require.Len(t, reloaded, 1)
+		assert.Equal(t, err, reloaded[0].(*fxevent.Reloaded).Err)
+	})
+
+	t.Run("not running", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		app, _ := newApp(&calls, nil)
+		require.NoError(t, app.Reload(context.Background()))
+
+		require.NoError(t, app.Start(context.Background()))
+		require.NoError(t, app.Stop(context.Background()))
+		require.NoError(t, app.Reload(context.Background()))
+		assert.Empty(t, calls)
+	})
+}
+
+func TestBeforeStop(t *testing.T) {
+	t.Parallel()
+
+	t.Run("runs before OnStop hooks", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		app := fxtest.New(t,
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{OnStop: func(context.Context) error {
+					calls = append(calls, "first OnStop")
+					return nil
+				}})
+				lc.Append(Hook{OnStop: func(context.Context) error {
+					calls = append(calls, "second OnStop")
+					return nil
+				}})
+			}),
+			BeforeStop(func() { calls = append(calls, "BeforeStop 1") }),
+			Module("child",
+				BeforeStop(func() { calls = append(calls, "BeforeStop 2") }),
+			),
+		)
+		app.RequireStart()
+		assert.Empty(t, calls, "BeforeStop must not run on start")
+
+		app.RequireStop()
+		assert.Equal(t, []string{
+			"BeforeStop 1",
+			"BeforeStop 2",
+			"second OnStop",
+			"first OnStop",
+		}, calls)
+	})
+
+	t.Run("runs after Stopping event", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			spy      *fxlog.Spy
+			stopping int
+			ran      bool
+		)
+		app, spy := NewSpied(
+			Invoke(func(sd Shutdowner, lc Lifecycle) {
+				lc.Append(Hook{
+					OnStart: func(context.Context) error {
+						return sd.Shutdown()
+					},
+					OnStop: func(context.Context) error {
+						assert.True(t, ran, "BeforeStop must run before OnStop")
+						return nil
+					},
+				})
+			}),
+			BeforeStop(func() {
+				ran = true
+				stopping = len(spy.Events().SelectByTypeName("Stopping"))
+			}),
+		)
+		app.Run()
+
+		assert.True(t, ran, "BeforeStop must run")
+		assert.Equal(t, 1, stopping, "Stopping must be logged before BeforeStop runs")
+	})
+
+	t.Run("counts against stop timeout", func(t *testing.T) {
+		t.Parallel()
+
+		mockClock := fxclock.NewMock()
+		var stopped bool
+		app := New(
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{OnStop: func(context.Context) error {
+					stopped = true
+					return nil
+				}})
+			}),
+			BeforeStop(func() { mockClock.Add(5 * time.Second) }),
+			WithLogger(func() fxevent.Logger { return new(fxlog.Spy) }),
+			WithClock(mockClock),
+		)
+		require.NoError(t, app.Start(context.Background()))
+
+		ctx, cancel := mockClock.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		err := app.Stop(ctx)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.False(t, stopped, "OnStop must not run after the deadline")
+	})
+}
+
+func TestStartMiddleware(t *testing.T) {
+	t.Parallel()
+
+	type ctxKey struct{}
+
+	t.Run("wraps all hooks", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		hook := func(name string) Hook {
+			return Hook{OnStart: func(ctx context.Context) error {
+				calls = append(calls, fmt.Sprintf("%v sees %v", name, ctx.Value(ctxKey{})))
+				return nil
+			}}
+		}
+		middleware := func(name string) func(context.Context, func(context.Context) error) error {
+			return func(ctx context.Context, next func(context.Context) error) error {
+				calls = append(calls, name+" before")
+				err := next(context.WithValue(ctx, ctxKey{}, name))
+				calls = append(calls, name+" after")
+				return err
+			}
+		}
+
+		app := fxtest.New(t,
+			StartMiddleware(middleware("outer")),
+			StartMiddleware(middleware("inner")),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(hook("hook 1"))
+				lc.Append(hook("hook 2"))
+			}),
+		)
+		app.RequireStart()
+		assert.Equal(t, []string{
+			"outer before",
+			"inner before",
+			"hook 1 sees inner",
+			"hook 2 sees inner",
+			"inner after",
+			"outer after",
+		}, calls)
+
+		// Middleware only wraps start.
+		calls = nil
+		app.RequireStop()
+		assert.Empty(t, calls)
+	})
+
+	t.Run("sees start error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			gotErr  error
+			stopped bool
+		)
+		app := NewForTest(t,
+			StartMiddleware(func(ctx context.Context, next func(context.Context) error) error {
+				gotErr = next(ctx)
+				assert.False(t, stopped, "rollback must happen after middleware returns")
+				return gotErr
+			}),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{OnStop: func(context.Context) error {
+					stopped = true
+					return nil
+				}})
+				lc.Append(StartHook(func() error { return errors.New("great sadness") }))
+			}),
+		)
+		err := app.Start(context.Background())
+		require.Error(t, err)
+		assert.ErrorContains(t, gotErr, "great sadness")
+		assert.True(t, stopped, "app must be rolled back")
+	})
+
+	t.Run("next not called", func(t *testing.T) {
+		t.Parallel()
+
+		var started bool
+		app := NewForTest(t,
+			StartMiddleware(func(context.Context, func(context.Context) error) error {
+				return nil
+			}),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(StartHook(func() { started = true }))
+			}),
+		)
+		err := app.Start(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "did not start the application: it returned without calling next")
+		assert.False(t, started)
+	})
+}
+
+func TestLifecyclePhases(t *testing.T) {
+	t.Parallel()
+
+	t.Run("hooks run by phase", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		hook := func(name, phase string) Hook {
+			return Hook{
+				OnStart: func(context.Context) error {
+					calls = append(calls, "start "+name)
+					return nil
+				},
+				OnStop: func(context.Context) error {
+					calls = append(calls, "stop "+name)
+					return nil
+				},
+				Phase: phase,
+			}
+		}
+
+		app := NewForTest(t,
+			LifecyclePhases("migrate", "serve", "announce"),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(hook("register", "announce"))
+				lc.Append(hook("server", "serve"))
+				lc.Append(hook("migrations", "migrate"))
+				lc.Append(hook("db", ""))
+				lc.Append(hook("health", "serve"))
+			}),
+		)
+		require.NoError(t, app.Start(context.Background()))
+		require.NoError(t, app.Stop(context.Background()))
+
+		assert.Equal(t, []string{
+			"start db",
+			"start migrations",
+			"start server",
+			"start health",
+			"start register",
+			"stop register",
+			"stop health",
+			"stop server",
+			"stop migrations",
+			"stop db",
+		}, calls)
+	})
+
+	t.Run("failed phase rolls back", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		app := NewForTest(t,
+			LifecyclePhases("migrate", "serve"),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{
+					OnStart: func(context.Context) error { return errors.New("great sadness") },
+					Phase:   "serve",
+				})
+				lc.Append(Hook{
+					OnStart: func(context.Context) error {
+						calls = append(calls, "start migrations")
+						return nil
+					},
+					OnStop: func(context.Context) error {
+						calls = append(calls, "stop migrations")
+						return nil
+					},
+					Phase: "migrate",
+				})
+			}),
+		)
+		err := app.Start(context.Background())
+		assert.ErrorContains(t, err, "great sadness")
+		assert.Equal(t, []string{"start migrations", "stop migrations"}, calls)
+	})
+
+	t.Run("unknown phase", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			LifecyclePhases("serve"),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{OnStart: startServer, Phase: "sevre"})
+			}),
+		)
+		err := app.Start(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `uses unknown lifecycle phase "sevre"`)
+	})
+
+	t.Run("registered hooks report phases", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			LifecyclePhases("serve"),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{OnStart: startServer, Phase: "serve"})
+			}),
+		)
+		hooks := app.RegisteredHooks()
+		require.Len(t, hooks, 1)
+		assert.Equal(t, "serve", hooks[0].Phase)
+	})
+
+	t.Run("invalid phases", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc string
+			give []Option
+			want string
+		}{
+			{
+				desc: "duplicate",
+				give: []Option{LifecyclePhases("serve", "serve")},
+				want: `fx.LifecyclePhases: phase "serve" declared more than once`,
+			},
+			{
+				desc: "duplicate across options",
+				give: []Option{LifecyclePhases("serve"), LifecyclePhases("serve")},
+				want: `fx.LifecyclePhases: phase "serve" declared more than once`,
+			},
+			{
+				desc: "empty",
+				give: []Option{LifecyclePhases("")},
+				want: "fx.LifecyclePhases: phase names must not be empty",
+			},
+			{
+				desc: "in module",
+				give: []Option{Module("child", LifecyclePhases("serve"))},
+				want: "fx.LifecyclePhases Option should be passed to top-level App, not to fx.Module",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := NewForTest(t, tt.give...)
+				assert.ErrorContains(t, app.Err(), tt.want)
+			})
+		}
+	})
+}
+
+func TestHookOrderingConstraints(t *testing.T) {
+	t.Parallel()
+
+	// hook returns a named hook that records its calls.
+	hook := func(calls *[]string, name string, opts func(*Hook)) Hook {
+		h := Hook{
+			Name: name,
+			OnStart: func(context.Context) error {
+				*calls = append(*calls, "start "+name)
+				return nil
+			},
+			OnStop: func(context.Context) error {
+				*calls = append(*calls, "stop "+name)
+				return nil
+			},
+		}
+		if opts != nil {
+			opts(&h)
+		}
+		return h
+	}
+
+	t.Run("run after and before", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		app := NewForTest(t,
+			Invoke(func(lc Lifecycle) {
+				lc.Append(hook(&calls, "consumer", func(h *Hook) {
+					h.RunAfter = []string{"db-migrations"}
+				}))
+				lc.Append(hook(&calls, "server", nil))
+				lc.Append(hook(&calls, "db-migrations", nil))
+				lc.Append(hook(&calls, "cache-warmup", func(h *Hook) {
+					h.RunBefore = []string{"server"}
+				}))
+			}),
+		)
+		require.NoError(t, app.Err())
+		require.NoError(t, app.Start(context.Background()))
+		require.NoError(t, app.Stop(context.Background()))
+
+		// Each hook that's free to start does so in the order it was appended.
+		assert.Equal(t, []string{
+			"start db-migrations",
+			"start consumer",
+			"start cache-warmup",
+			"start server",
+			"stop server",
+			"stop cache-warmup",
+			"stop consumer",
+			"stop db-migrations",
+		}, calls)
+	})
+
+	t.Run("within phases", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		app := NewForTest(t,
+			LifecyclePhases("migrate", "serve"),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(hook(&calls, "api", func(h *Hook) {
+					h.Phase = "serve"
+					h.RunAfter = []string{"grpc"}
+				}))
+				lc.Append(hook(&calls, "grpc", func(h *Hook) { h.Phase = "serve" }))
+				lc.Append(hook(&calls, "migrations", func(h *Hook) { h.Phase = "migrate" }))
+			}),
+		)
+		require.NoError(t, app.Start(context.Background()))
+		require.NoError(t, app.Stop(context.Background()))
+
+		assert.Equal(t, []string{
+			"start migrations",
+			"start grpc",
+			"start api",
+			"stop api",
+			"stop grpc",
+			"stop migrations",
+		}, calls)
+	})
+
+	tests := []struct {
+		desc    string
+		phases  []string
+		hooks   func(calls *[]string) []Hook
+		wantErr string
+	}{
+		{
+			desc: "unknown hook",
+			hooks: func(calls *[]string) []Hook {
+				return []Hook{hook(calls, "consumer", func(h *Hook) {
+					h.RunAfter = []string{"db-migraitons"}
+				})}
+			},
+			wantErr: `hook consumer appended by go.uber.org/fx_test.TestHookOrderingConstraints`,
+		},
+		{
+			desc: "cycle",
+			hooks: func(calls *[]string) []Hook {
+				return []Hook{
+					hook(calls, "a", func(h *Hook) { h.RunAfter = []string{"b"} }),
+					hook(calls, "b", func(h *Hook) { h.RunAfter = []string{"a"} }),
+					hook(calls, "c", nil),
+				}
+			},
+			wantErr: "ordering constraints of lifecycle hooks can't be satisfied: a, b",
+		},
+		{
+			desc:   "contradicts phases",
+			phases: []string{"migrate", "serve"},
+			hooks: func(calls *[]string) []Hook {
+				return []Hook{
+					hook(calls, "server", func(h *Hook) { h.Phase = "serve" }),
+					hook(calls, "migrations", func(h *Hook) {
+						h.Phase = "migrate"
+						h.RunAfter = []string{"server"}
+					}),
+				}
+			},
+			wantErr: "ordering constraints of lifecycle hooks can't be satisfied: migrations, server",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls []string
+			app := NewForTest(t,
+				LifecyclePhases(tt.phases...),
+				Invoke(func(lc Lifecycle) {
+					for _, h := range tt.hooks(&calls) {
+						lc.Append(h)
+					}
+				}),
+			)
+			require.NoError(t, app.Err())
+			err := app.Start(context.Background())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Empty(t, calls, "no hook must run")
+		})
+	}
+}
+
+func TestOnStopPanic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc      string
+		give      StopPanicPolicy
+		wantCalls []string
+	}{
+		{
+			desc:      "continue",
+			give:      ContinueOnStopPanic,
+			wantCalls: []string{"stop third", "stop second", "stop first"},
+		},
+		{
+			desc:      "abort",
+			give:      AbortOnStopPanic,
+			wantCalls: []string{"stop third", "stop second"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls []string
+			stop := func(name string) func(context.Context) error {
+				return func(context.Context) error {
+					calls = append(calls, "stop "+name)
+					return nil
+				}
+			}
+			app := NewForTest(t,
+				OnStopPanic(tt.give),
+				Invoke(func(lc Lifecycle) {
+					lc.Append(Hook{OnStop: stop("first")})
+					lc.Append(Hook{OnStop: func(ctx context.Context) error {
+						_ = stop("second")(ctx)
+						panic("great sadness")
+					}})
+					lc.Append(Hook{OnStop: stop("third")})
+				}),
+			)
+			require.NoError(t, app.Start(context.Background()))
+
+			err := app.Stop(context.Background())
+			require.Error(t, err)
+			assert.EqualError(t, err, "panic: great sadness")
+			assert.Contains(t, fmt.Sprintf("%+v", err), "TestOnStopPanic",
+				"the stack of the panic must be included")
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+
+	t.Run("with hook timeout", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			OnStopPanic(ContinueOnStopPanic),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{
+					OnStop:  func(context.Context) error { panic("great sadness") },
+					Timeout: time.Minute,
+				})
+			}),
+		)
+		require.NoError(t, app.Start(context.Background()))
+		assert.EqualError(t, app.Stop(context.Background()), "panic: great sadness")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc    string
+			give    Option
+			wantErr string
+		}{
+			{
+				desc:    "unknown policy",
+				give:    OnStopPanic(StopPanicPolicy(42)),
+				wantErr: "fx.OnStopPanic: unknown policy StopPanicPolicy(42)",
+			},
+			{
+				desc: "in module",
+				give: Module("child", OnStopPanic(AbortOnStopPanic)),
+				wantErr: "fx.OnStopPanic Option should be passed to top-level App, " +
+					"not to fx.Module",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := NewForTest(t, tt.give)
+				assert.EqualError(t, app.Err(), tt.wantErr)
+			})
+		}
+	})
+}
+
+func TestOnStartFailure(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc        string
+		give        Option
+		wantErrs    []string
+		wantCalls   []string
+		wantRolled  fxevent.RolledBack
+		wantPolicy  string
+		wantRunning bool
+	}{
+		{
+			desc:      "default",
+			give:      Options(),
+			wantErrs:  []string{"start third", "stop second"},
+			wantCalls: []string{"stop second", "stop first"},
+			wantRolled: fxevent.RolledBack{
+				Hooks:  []string{"second", "first"},
+				Failed: []string{"second"},
+			},
+			wantPolicy: "RollbackStarted",
+		},
+		{
+			desc:      "rollback started",
+			give:      OnStartFailure(RollbackStarted),
+			wantErrs:  []string{"start third", "stop second"},
+			wantCalls: []string{"stop second", "stop first"},
+			wantRolled: fxevent.RolledBack{
+				Hooks:  []string{"second", "first"},
+				Failed: []string{"second"},
+			},
+			wantPolicy: "RollbackStarted",
+		},
+		{
+			desc:      "ignoring errors",
+			give:      OnStartFailure(RollbackIgnoringErrors),
+			wantErrs:  []string{"start third"},
+			wantCalls: []string{"stop second", "stop first"},
+			wantRolled: fxevent.RolledBack{
+				Hooks:   []string{"second", "first"},
+				Failed:  []string{"second"},
+				Ignored: true,
+			},
+			wantPolicy: "RollbackIgnoringErrors",
+		},
+		{
+			desc:        "no rollback",
+			give:        OnStartFailure(NoRollback),
+			wantErrs:    []string{"start third"},
+			wantRolled:  fxevent.RolledBack{Skipped: true},
+			wantPolicy:  "NoRollback",
+			wantRunning: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls []string
+			stop := func(name string, err error) func(context.Context) error {
+				return func(context.Context) error {
+					calls = append(calls, "stop "+name)
+					return err
+				}
+			}
+			noop := func(context.Context) error { return nil }
+			app, spy := NewSpied(
+				tt.give,
+				Invoke(func(lc Lifecycle) {
+					lc.Append(Hook{Name: "first", OnStart: noop, OnStop: stop("first", nil)})
+					lc.Append(Hook{
+						Name:    "second",
+						OnStart: noop,
+						OnStop:  stop("second", errors.New("stop second")),
+					})
+					lc.Append(Hook{
+						Name:    "third",
+						OnStart: func(context.Context) error { return errors.New("start third") },
+						OnStop:  stop("third", nil),
+					})
+				}),
+			)
+
+			err := app.Start(context.Background())
+			var errs []string
+			for _, err := range multierr.Errors(err) {
+				errs = append(errs, err.Error())
+			}
+			assert.Equal(t, tt.wantErrs, errs)
+			assert.Equal(t, tt.wantCalls, calls)
+
+			rollingBack := spy.Events().SelectByTypeName("RollingBack")
+			require.Equal(t, 1, rollingBack.Len())
+			rb := rollingBack[0].(*fxevent.RollingBack)
+			assert.Equal(t, "third", rb.FunctionName)
+			assert.Contains(t, rb.CallerName, "TestOnStartFailure")
+			assert.Equal(t, tt.wantPolicy, rb.Policy)
+
+			rolledBack := spy.Events().SelectByTypeName("RolledBack")
+			require.Equal(t, 1, rolledBack.Len())
+			got := *rolledBack[0].(*fxevent.RolledBack)
+			got.Err = nil
+			assert.Equal(t, tt.wantRolled, got)
+
+			if tt.wantRunning {
+				require.Empty(t, calls, "hooks must be left running")
+				assert.Error(t, app.Stop(context.Background()))
+				assert.Equal(t, []string{"stop second", "stop first"}, calls)
+			}
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
 		t.Parallel()
 
-		ctx, cancel := context.WithCancel(context.Background())
-		defer cancel()
+		tests := []struct {
+			desc    string
+			give    Option
+			wantErr string
+		}{
+			{
+				desc:    "unknown policy",
+				give:    OnStartFailure(RollbackPolicy(42)),
+				wantErr: "fx.OnStartFailure: unknown policy RollbackPolicy(42)",
+			},
+			{
+				desc: "in module",
+				give: Module("child", OnStartFailure(NoRollback)),
+				wantErr: "fx.OnStartFailure Option should be passed to top-level App, " +
+					"not to fx.Module",
+			},
+		}
 
-		app := fxtest.New(t,
-			Invoke(func(lc Lifecycle) {
-				lc.Append(Hook{
-					OnStart: func(ctx context.Context) error { return nil },
-					OnStop:  func(ctx context.Context) error { return nil },
-				})
-			}),
-		)
-		assert.NoError(t, app.Start(ctx))
-		err := app.Start(ctx)
-		if assert.Error(t, err) {
-			assert.ErrorContains(t, err, "attempted to start lifecycle when in state: started")
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := NewForTest(t, tt.give)
+				assert.EqualError(t, app.Err(), tt.wantErr)
+			})
 		}
-		app.Stop(ctx)
-		assert.NoError(t, app.Start(ctx))
-		app.Stop(ctx)
 	})
 }
 
-func TestAppStop(t *testing.T) {
+func TestWithRootContext(t *testing.T) {
 	t.Parallel()
 
-	t.Run("Timeout", func(t *testing.T) {
-		t.Parallel()
+	tests := []struct {
+		desc    string
+		give    Option
+		wantErr string
+	}{
+		{
+			desc:    "nil context",
+			give:    WithRootContext(nil),
+			wantErr: "fx.WithRootContext: context must not be nil",
+		},
+		{
+			desc: "in module",
+			give: Module("child", WithRootContext(context.Background())),
+			wantErr: "fx.WithRootContext Option should be passed to top-level App, " +
+				"not to fx.Module",
+		},
+	}
 
-		mockClock := fxclock.NewMock()
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
 
-		block := func(ctx context.Context) error {
-			mockClock.Add(5 * time.Second)
-			return ctx.Err()
-		}
-		// NOTE: for tests that gets cancelled/times out during lifecycle methods, it's possible
-		// for them to run into race with fxevent logs getting written to testing.T with the
-		// remainder of the tests. As a workaround, we provide fxlog.Spy to prevent the lifecycle
-		// goroutine from writing to testing.T.
-		spy := new(fxlog.Spy)
-		app := New(Invoke(func(l Lifecycle) { l.Append(Hook{OnStop: block}) }),
-			WithLogger(func() fxevent.Logger { return spy }),
-			WithClock(mockClock),
+			app := NewForTest(t, tt.give)
+			assert.EqualError(t, app.Err(), tt.wantErr)
+		})
+	}
+}
+
+func TestConstructorContext(t *testing.T) {
+	t.Parallel()
+
+	type ctxKey struct{}
+
+	t.Run("canceled on stop", func(t *testing.T) {
+		t.Parallel()
+
+		var got context.Context
+		app := fxtest.New(t,
+			ConstructorContext(),
+			WithRootContext(context.WithValue(context.Background(), ctxKey{}, "root")),
+			StartTimeout(time.Minute),
+			Provide(func(ctx context.Context) *bytes.Buffer {
+				got = ctx
+				return new(bytes.Buffer)
+			}),
+			Invoke(func(*bytes.Buffer) {}),
 		)
+		require.NotNil(t, got, "constructor must be called")
 
-		err := app.Start(context.Background())
-		require.Nil(t, err)
+		_, ok := got.Deadline()
+		assert.True(t, ok, "context must have a deadline")
+		assert.Equal(t, "root", got.Value(ctxKey{}))
 
-		ctx, cancel := mockClock.WithTimeout(context.Background(), time.Second)
-		defer cancel()
+		app.RequireStart()
+		assert.NoError(t, got.Err(), "context must not be canceled by Start")
 
-		err = app.Stop(ctx)
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "context deadline exceeded")
+		app.RequireStop()
+		assert.ErrorIs(t, got.Err(), context.Canceled)
 	})
 
-	t.Run("StopError", func(t *testing.T) {
+	t.Run("canceled on failure", func(t *testing.T) {
 		t.Parallel()
 
-		failStop := func(lc Lifecycle) struct{} {
-			lc.Append(Hook{OnStop: func(context.Context) error {
-				return errors.New("OnStop fail")
-			}})
-			return struct{}{}
-		}
-		app := fxtest.New(t,
-			Provide(failStop),
-			Invoke(func(struct{}) {}),
+		var got context.Context
+		app := NewForTest(t,
+			ConstructorContext(),
+			Invoke(func(ctx context.Context) error {
+				got = ctx
+				return errors.New("great sadness")
+			}),
 		)
-		app.RequireStart()
-		err := app.Stop(context.Background())
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "OnStop fail")
+		require.Error(t, app.Err())
+		require.NotNil(t, got, "invoke must be called")
+		assert.ErrorIs(t, got.Err(), context.Canceled)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc    string
+			give    Option
+			wantErr string
+		}{
+			{
+				desc: "in module",
+				give: Module("child", ConstructorContext()),
+				wantErr: "fx.ConstructorContext Option should be passed to top-level App, " +
+					"not to fx.Module",
+			},
+			{
+				desc: "context already provided",
+				give: Options(
+					ConstructorContext(),
+					Provide(context.Background),
+				),
+				wantErr: "already provided",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := NewForTest(t, tt.give)
+				require.Error(t, app.Err())
+				assert.Contains(t, app.Err().Error(), tt.wantErr)
+			})
+		}
 	})
 }
 
@@ -2596,11 +4356,219 @@ func TestErrorHook(t *testing.T) {
 		assert.Contains(t, graphStr, `"fx_test.B" [color=red];`)
 		assert.Contains(t, graphStr, `"fx_test.A" [color=orange];`)
 	})
+
+	t.Run("ModuleScoped", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		var innerErr, appErr error
+		record := func(name string, dst *error) ErrorHandler {
+			return errHandlerFunc(func(err error) {
+				calls = append(calls, name)
+				if dst != nil {
+					*dst = err
+				}
+			})
+		}
+		NewForTest(t,
+			Module("outer",
+				ErrorHook(record("outer", nil)),
+				Module("inner",
+					ErrorHook(record("inner", &innerErr)),
+					Invoke(func() error { return errors.New("great sadness") }),
+				),
+			),
+			Module("sibling",
+				ErrorHook(record("sibling", nil)),
+			),
+			ErrorHook(record("app", &appErr)),
+		)
+		assert.Equal(t, []string{"inner", "outer", "app"}, calls)
+
+		var modErr *ModuleError
+		require.ErrorAs(t, innerErr, &modErr)
+		assert.Equal(t, "outer/inner", modErr.Module)
+		assert.ErrorContains(t, modErr, `module "outer/inner": `)
+		assert.ErrorContains(t, modErr.Err, "great sadness")
+
+		require.Error(t, appErr)
+		assert.False(t, errors.As(appErr, &modErr), "application hooks must receive the error unwrapped")
+	})
+
+	t.Run("ErrorTypes", func(t *testing.T) {
+		t.Parallel()
+
+		type A struct{}
+		type B struct{}
+
+		t.Run("ProvideError", func(t *testing.T) {
+			t.Parallel()
+
+			var errs []error
+			h := errHandlerFunc(func(err error) { errs = append(errs, err) })
+			app := NewForTest(t,
+				Module("mod", Provide("not a function")),
+				ErrorHook(h),
+			)
+			require.Error(t, app.Err())
+			require.Len(t, errs, 1)
+
+			var provideErr *ProvideError
+			require.ErrorAs(t, errs[0], &provideErr)
+			assert.Equal(t, "mod", provideErr.Module)
+			assert.Equal(t, app.Err().Error(), provideErr.Error())
+		})
+
+		t.Run("ConstructorFailed", func(t *testing.T) {
+			t.Parallel()
+
+			var errs []error
+			h := errHandlerFunc(func(err error) { errs = append(errs, err) })
+			NewForTest(t,
+				Module("mod",
+					Provide(func() (B, error) { return B{}, errors.New("great sadness") }),
+				),
+				Provide(func(B) A { return A{} }),
+				Invoke(func(A) {}),
+				ErrorHook(h),
+			)
+			require.Len(t, errs, 1)
+
+			var provideErr *ProvideError
+			require.ErrorAs(t, errs[0], &provideErr)
+			assert.Contains(t, provideErr.Constructor, "TestErrorHook")
+			assert.NotContains(t, provideErr.Constructor, "app_test.go")
+			assert.Equal(t, "mod", provideErr.Module)
+			assert.ErrorContains(t, provideErr, "great sadness")
+
+			_, err := VisualizeError(errs[0])
+			assert.NoError(t, err, "graph must still be attached")
+		})
+
+		t.Run("InvokeError", func(t *testing.T) {
+			t.Parallel()
+
+			var errs []error
+			h := errHandlerFunc(func(err error) { errs = append(errs, err) })
+			NewForTest(t,
+				Module("mod",
+					Invoke(func() error { return errors.New("great sadness") }),
+				),
+				ErrorHook(h),
+			)
+			require.Len(t, errs, 1)
+
+			var invokeErr *InvokeError
+			require.ErrorAs(t, errs[0], &invokeErr)
+			assert.Contains(t, invokeErr.Function, "TestErrorHook")
+			assert.Equal(t, "mod", invokeErr.Module)
+			assert.EqualError(t, invokeErr, "great sadness")
+
+			var provideErr *ProvideError
+			assert.False(t, errors.As(errs[0], &provideErr))
+		})
+
+		t.Run("HookError", func(t *testing.T) {
+			t.Parallel()
+
+			var errs []error
+			h := errHandlerFunc(func(err error) { errs = append(errs, err) })
+			app := NewForTest(t,
+				Invoke(func(lc Lifecycle) {
+					lc.Append(Hook{
+						OnStop: func(context.Context) error { return errors.New("stop failed") },
+					})
+					lc.Append(Hook{
+						OnStart: func(context.Context) error { return errors.New("start failed") },
+					})
+				}),
+				ErrorHook(h),
+			)
+			require.NoError(t, app.Err())
+			require.Error(t, app.Start(context.Background()))
+			require.Len(t, errs, 2)
+
+			var startErr, stopErr *HookError
+			require.ErrorAs(t, errs[0], &startErr)
+			assert.Equal(t, "OnStart", startErr.Hook)
+			assert.Contains(t, startErr.Function, "TestErrorHook")
+			assert.Contains(t, startErr.Caller, "TestErrorHook")
+			assert.EqualError(t, startErr, "start failed")
+
+			require.ErrorAs(t, errs[1], &stopErr)
+			assert.Equal(t, "OnStop", stopErr.Hook)
+			assert.EqualError(t, stopErr, "stop failed")
+		})
+
+		t.Run("TimeoutError", func(t *testing.T) {
+			t.Parallel()
+
+			var errs []error
+			h := errHandlerFunc(func(err error) { errs = append(errs, err) })
+			app := NewForTest(t,
+				Invoke(func(lc Lifecycle) {
+					lc.Append(Hook{
+						Name: "slow",
+						OnStart: func(ctx context.Context) error {
+							<-ctx.Done()
+							return ctx.Err()
+						},
+						Timeout: time.Millisecond,
+					})
+				}),
+				ErrorHook(h),
+			)
+			require.NoError(t, app.Err())
+			require.Error(t, app.Start(context.Background()))
+			require.Len(t, errs, 1)
+
+			var timeoutErr *TimeoutError
+			require.ErrorAs(t, errs[0], &timeoutErr)
+			assert.Equal(t, "OnStart", timeoutErr.Hook)
+			assert.Equal(t, "slow", timeoutErr.Function)
+			assert.Equal(t, time.Millisecond, timeoutErr.Timeout)
+			assert.ErrorIs(t, timeoutErr, context.DeadlineExceeded)
+		})
+
+		t.Run("StartDeadline", func(t *testing.T) {
+			t.Parallel()
+
+			var errs []error
+			h := errHandlerFunc(func(err error) { errs = append(errs, err) })
+			block := make(chan struct{})
+			defer close(block)
+			app := NewForTest(t,
+				Invoke(func(lc Lifecycle) {
+					lc.Append(Hook{
+						Name: "stuck",
+						OnStart: func(context.Context) error {
+							<-block
+							return nil
+						},
+					})
+				}),
+				ErrorHook(h),
+			)
+			require.NoError(t, app.Err())
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+			defer cancel()
+			require.Error(t, app.Start(ctx))
+			require.Len(t, errs, 1)
+
+			var timeoutErr *TimeoutError
+			require.ErrorAs(t, errs[0], &timeoutErr)
+			assert.Equal(t, "OnStart", timeoutErr.Hook)
+			assert.Equal(t, "stuck", timeoutErr.Function)
+			assert.Zero(t, timeoutErr.Timeout)
+		})
+	})
 }
 
 func TestOptionString(t *testing.T) {
 	t.Parallel()
 
+	signals := make(chan os.Signal)
 	tests := []struct {
 		desc string
 		give Option
@@ -2659,6 +4627,11 @@ func TestOptionString(t *testing.T) {
 			give: StopTimeout(5 * time.Second),
 			want: "fx.StopTimeout(5s)",
 		},
+		{
+			desc: "EventBufferLimit",
+			give: EventBufferLimit(1024),
+			want: "fx.EventBufferLimit(1024)",
+		},
 		{
 			desc: "RecoverFromPanics",
 			give: RecoverFromPanics(),
@@ -2694,6 +4667,216 @@ func TestOptionString(t *testing.T) {
 			give: Replace(bytes.NewReader(nil)),
 			want: "fx.Replace(*bytes.Reader)",
 		},
+		{
+			desc: "SupplyAs",
+			give: SupplyAs[io.Reader](bytes.NewReader(nil)),
+			want: "fx.SupplyAs(io.Reader)",
+		},
+		{
+			desc: "ReplaceAs",
+			give: ReplaceAs[io.Reader](bytes.NewReader(nil)),
+			want: "fx.ReplaceAs(io.Reader)",
+		},
+		{
+			desc: "Select",
+			give: Select(new(io.Reader), "reader", map[string]interface{}{
+				"buffer": bytes.NewBufferString,
+				"reader": strings.NewReader,
+			}),
+			want: `fx.Select(io.Reader, "reader", buffer=bytes.NewBufferString(), reader=strings.NewReader())`,
+		},
+		{
+			desc: "TrackInstances",
+			give: TrackInstances(),
+			want: "fx.TrackInstances()",
+		},
+		{
+			desc: "SubApps",
+			give: SubApps(),
+			want: "fx.SubApps()",
+		},
+		{
+			desc: "Inherit",
+			give: Inherit(new(*bytes.Buffer), new(io.Reader)),
+			want: "fx.Inherit(*bytes.Buffer, io.Reader)",
+		},
+		{
+			desc: "NoEmptyModules",
+			give: NoEmptyModules(),
+			want: "fx.NoEmptyModules()",
+		},
+		{
+			desc: "MapResult",
+			give: MapResult(new(string), strings.TrimSpace),
+			want: "fx.MapResult(string, strings.TrimSpace())",
+		},
+		{
+			desc: "Undecorate",
+			give: Undecorate(new(*bytes.Buffer), new(io.Reader)),
+			want: "fx.Undecorate(*bytes.Buffer, io.Reader)",
+		},
+		{
+			desc: "SupplyDerived",
+			give: SupplyDerived(bytes.NewBufferString),
+			want: "fx.SupplyDerived(bytes.NewBufferString())",
+		},
+		{
+			desc: "WarnAmbiguousDecorations",
+			give: WarnAmbiguousDecorations(),
+			want: "fx.WarnAmbiguousDecorations()",
+		},
+		{
+			desc: "TraceRegions",
+			give: TraceRegions(),
+			want: "fx.TraceRegions()",
+		},
+		{
+			desc: "TimeConstructors",
+			give: TimeConstructors(),
+			want: "fx.TimeConstructors()",
+		},
+		{
+			desc: "DefaultAnnotations",
+			give: DefaultAnnotations(ParamTags(`name:"foo"`), ResultTags(`name:"bar"`)),
+			want: `fx.DefaultAnnotations(fx.ParamTags(["name:\"foo\""]), fx.ResultTags(["name:\"bar\""]))`,
+		},
+		{
+			desc: "NoShadowing",
+			give: NoShadowing(),
+			want: "fx.NoShadowing()",
+		},
+		{
+			desc: "DelegateSignals",
+			give: DelegateSignals(signals),
+			want: fmt.Sprintf("fx.DelegateSignals(%v)", signals),
+		},
+		{
+			desc: "LifecyclePhases",
+			give: LifecyclePhases("migrate", "serve"),
+			want: `fx.LifecyclePhases(["migrate" "serve"])`,
+		},
+		{
+			desc: "ReloadOnSignal",
+			give: ReloadOnSignal(os.Interrupt),
+			want: "fx.ReloadOnSignal([interrupt])",
+		},
+		{
+			desc: "ShutdownSignals",
+			give: ShutdownSignals(os.Interrupt),
+			want: "fx.ShutdownSignals([interrupt])",
+		},
+		{
+			desc: "WindowsService",
+			give: WindowsService("myservice"),
+			want: `fx.WindowsService("myservice")`,
+		},
+		{
+			desc: "SystemdNotify",
+			give: SystemdNotify(),
+			want: "fx.SystemdNotify()",
+		},
+		{
+			desc: "HealthProbes",
+			give: HealthProbes(":8081", ProbeHealthChecks()),
+			want: `fx.HealthProbes(":8081")`,
+		},
+		{
+			desc: "DebugServer",
+			give: DebugServer("localhost:6060"),
+			want: `fx.DebugServer("localhost:6060")`,
+		},
+		{
+			desc: "ProfileStartup",
+			give: ProfileStartup("startup"),
+			want: `fx.ProfileStartup("startup")`,
+		},
+		{
+			desc: "OnStopPanic",
+			give: OnStopPanic(ContinueOnStopPanic),
+			want: "fx.OnStopPanic(ContinueOnStopPanic)",
+		},
+		{
+			desc: "WithRootContext",
+			give: WithRootContext(context.Background()),
+			want: "fx.WithRootContext()",
+		},
+		{
+			desc: "ConstructorContext",
+			give: ConstructorContext(),
+			want: "fx.ConstructorContext()",
+		},
+		{
+			desc: "ShareParentContainer",
+			give: ShareParentContainer(),
+			want: "fx.ShareParentContainer()",
+		},
+		{
+			desc: "StableGroups",
+			give: StableGroups(),
+			want: "fx.StableGroups()",
+		},
+		{
+			desc: "Evaluate",
+			give: Evaluate(bytes.NewBuffer),
+			want: "fx.Evaluate(bytes.NewBuffer())",
+		},
+		{
+			desc: "ReportAllErrors",
+			give: ReportAllErrors(),
+			want: "fx.ReportAllErrors()",
+		},
+		{
+			desc: "OnEvent",
+			give: OnEvent(ignoreEvent),
+			want: "fx.OnEvent(go.uber.org/fx_test.ignoreEvent())",
+		},
+		{
+			desc: "ConcurrentStart",
+			give: ConcurrentStart(4),
+			want: "fx.ConcurrentStart(4)",
+		},
+		{
+			desc: "OnStartFailure",
+			give: OnStartFailure(RollbackIgnoringErrors),
+			want: "fx.OnStartFailure(RollbackIgnoringErrors)",
+		},
+		{
+			desc: "If",
+			give: If(true, Provide(bytes.NewReader), Provide(bytes.NewBuffer)),
+			want: "fx.If(true, fx.Provide(bytes.NewReader()), fx.Provide(bytes.NewBuffer()))",
+		},
+		{
+			desc: "If skipped",
+			give: If(false, Provide(bytes.NewReader)),
+			want: "fx.If(false, fx.Provide(bytes.NewReader())) (skipped)",
+		},
+		{
+			desc: "When",
+			give: When(testing.Short, Provide(bytes.NewReader)),
+			want: "fx.When(testing.Short(), fx.Provide(bytes.NewReader()))",
+		},
+		{
+			desc: "WithClock",
+			give: WithClock(&stringClock{}),
+			want: "fx.WithClock(fakeClock)",
+		},
+		{
+			desc: "ModuleInfo",
+			give: ModuleInfo{Version: "1.0.0", Owner: "team"},
+			want: `fx.ModuleInfo{Version: "1.0.0", Owner: "team"}`,
+		},
+		{
+			desc: "BeforeStop",
+			give: BeforeStop(func() {}),
+			want: "fx.BeforeStop(go.uber.org/fx_test.TestOptionString.func4())",
+		},
+		{
+			desc: "StartMiddleware",
+			give: StartMiddleware(func(ctx context.Context, next func(context.Context) error) error {
+				return next(ctx)
+			}),
+			want: "fx.StartMiddleware(go.uber.org/fx_test.TestOptionString.func5())",
+		},
 	}
 
 	for _, tt := range tests {
@@ -2735,3 +4918,8 @@ func (h testErrorHandler) HandleError(err error) {
 func (h testErrorHandler) String() string {
 	return h.t.Name()
 }
+
+// stringClock is a clock with a stable string representation.
+type stringClock struct{ *fxclock.Mock }
+
+func (*stringClock) String() string { return "fakeClock" }
@@ -0,0 +1,102 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"context"
+	"reflect"
+)
+
+var (
+	_typeOfCleanup    = reflect.TypeOf(func() {})
+	_typeOfCleanupCtx = reflect.TypeOf(func(context.Context) error { return nil })
+)
+
+// cleanupResult returns the index of the cleanup function returned by
+// a constructor of type ft, or -1 if it doesn't return one.
+//
+// A cleanup function is a func() or a func(context.Context) error
+// returned after at least one other value,
+// and before the error, if the constructor returns one.
+// A constructor that returns only a function provides that function.
+func cleanupResult(ft reflect.Type) int {
+	n := ft.NumOut()
+	if n > 0 && ft.Out(n-1) == _typeOfError {
+		n--
+	}
+	if n < 2 {
+		return -1
+	}
+	switch ft.Out(n - 1) {
+	case _typeOfCleanup, _typeOfCleanupCtx:
+		return n - 1
+	}
+	return -1
+}
+
+// withCleanup wraps a constructor that returns a cleanup function
+// at result index idx into one that appends the cleanup function
+// to the Lifecycle as an OnStop hook instead of returning it.
+//
+// The wrapper takes the Lifecycle as its first parameter,
+// followed by the parameters of the constructor.
+func withCleanup(constructor interface{}, idx int) interface{} {
+	fv := reflect.ValueOf(constructor)
+	ft := fv.Type()
+
+	ins := make([]reflect.Type, 0, ft.NumIn()+1)
+	ins = append(ins, _typeOfLifecycle)
+	for i := 0; i < ft.NumIn(); i++ {
+		ins = append(ins, ft.In(i))
+	}
+	outs := make([]reflect.Type, 0, ft.NumOut()-1)
+	for i := 0; i < ft.NumOut(); i++ {
+		if i != idx {
+			outs = append(outs, ft.Out(i))
+		}
+	}
+
+	wrapperType := reflect.FuncOf(ins, outs, ft.IsVariadic())
+	return reflect.MakeFunc(wrapperType, func(args []reflect.Value) []reflect.Value {
+		lc := args[0].Interface().(Lifecycle)
+
+		var results []reflect.Value
+		if ft.IsVariadic() {
+			results = fv.CallSlice(args[1:])
+		} else {
+			results = fv.Call(args[1:])
+		}
+
+		// Like other results, the cleanup function is ignored
+		// if the constructor failed.
+		failed := ft.Out(ft.NumOut()-1) == _typeOfError && !results[len(results)-1].IsNil()
+		if cleanup := results[idx]; !failed && !cleanup.IsNil() {
+			switch f := cleanup.Interface().(type) {
+			case func():
+				lc.Append(StopHook(f))
+			case func(context.Context) error:
+				lc.Append(StopHook(f))
+			}
+		}
+
+		return append(results[:idx:idx], results[idx+1:]...)
+	}).Interface()
+}
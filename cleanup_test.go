@@ -0,0 +1,168 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestProvideCleanup(t *testing.T) {
+	t.Parallel()
+
+	type db struct{}
+	type cache struct{}
+
+	t.Run("runs on stop in reverse order", func(t *testing.T) {
+		t.Parallel()
+
+		var stopped []string
+		app := fxtest.New(t,
+			fx.Provide(
+				func() (*db, func()) {
+					return &db{}, func() { stopped = append(stopped, "db") }
+				},
+				func(*db) (*cache, func(context.Context) error, error) {
+					return &cache{}, func(context.Context) error {
+						stopped = append(stopped, "cache")
+						return nil
+					}, nil
+				},
+			),
+			fx.Invoke(func(*cache) {}),
+		)
+		app.RequireStart()
+		assert.Empty(t, stopped)
+		app.RequireStop()
+		assert.Equal(t, []string{"cache", "db"}, stopped)
+	})
+
+	t.Run("stop error", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			fx.Provide(func() (*db, func(context.Context) error) {
+				return &db{}, func(context.Context) error {
+					return errors.New("great sadness")
+				}
+			}),
+			fx.Invoke(func(*db) {}),
+		)
+		app.RequireStart()
+		assert.EqualError(t, app.Stop(context.Background()), "great sadness")
+	})
+
+	t.Run("ignored on constructor error", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			fx.Provide(func() (*db, func(), error) {
+				return nil, func() {
+					assert.Fail(t, "cleanup must not run")
+				}, errors.New("great sadness")
+			}),
+			fx.Invoke(func(*db) {}),
+		)
+		require.Error(t, app.Err())
+		assert.Contains(t, app.Err().Error(), "great sadness")
+		assert.Empty(t, app.RegisteredHooks())
+	})
+
+	t.Run("nil cleanup", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			fx.Provide(func() (*db, func()) { return &db{}, nil }),
+			fx.Invoke(func(*db) {}),
+		)
+		assert.Empty(t, app.RegisteredHooks())
+		app.RequireStart().RequireStop()
+	})
+
+	t.Run("annotated", func(t *testing.T) {
+		t.Parallel()
+
+		var stopped bool
+		var got struct {
+			fx.In
+
+			DB *db `name:"primary"`
+		}
+		app := fxtest.New(t,
+			fx.Provide(fx.Annotated{
+				Name: "primary",
+				Target: func() (*db, func()) {
+					return &db{}, func() { stopped = true }
+				},
+			}),
+			fx.Populate(&got),
+		)
+		require.NotNil(t, got.DB)
+		app.RequireStart().RequireStop()
+		assert.True(t, stopped)
+	})
+
+	t.Run("variadic", func(t *testing.T) {
+		t.Parallel()
+
+		var stopped bool
+		app := fxtest.New(t,
+			fx.Provide(func(...string) (*db, func()) {
+				return &db{}, func() { stopped = true }
+			}),
+			fx.Invoke(func(*db) {}),
+		)
+		app.RequireStart().RequireStop()
+		assert.True(t, stopped)
+	})
+
+	t.Run("function alone is provided", func(t *testing.T) {
+		t.Parallel()
+
+		var called bool
+		app := fxtest.New(t,
+			fx.Provide(func() func() {
+				return func() { called = true }
+			}),
+			fx.Invoke(func(f func()) { f() }),
+		)
+		assert.True(t, called)
+		assert.Empty(t, app.RegisteredHooks())
+	})
+
+	t.Run("missing dependency names the constructor", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			fx.Provide(func(*cache) (*db, func()) { return &db{}, nil }),
+			fx.Invoke(func(*db) {}),
+		)
+		require.Error(t, app.Err())
+		assert.Contains(t, app.Err().Error(), "cleanup_test.go")
+		assert.NotContains(t, app.Err().Error(), "makeFuncStub")
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Clock is the source of time of an application.
+// Fx uses it to enforce start and stop timeouts, the timeouts of hooks,
+// the backoff between hook retries, and to measure the durations
+// it reports in events.
+//
+// Applications use the system clock by default.
+// Tests can use [fxtest.Clock] to control the passage of time.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// Since returns the time elapsed since t.
+	Since(t time.Time) time.Duration
+
+	// Sleep pauses the current goroutine for at least d.
+	Sleep(d time.Duration)
+
+	// WithTimeout returns a copy of ctx that is canceled
+	// once d has elapsed on this clock.
+	WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc)
+}
+
+// WithClock makes the application use the given clock instead of
+// the system clock. It's intended for tests, with [fxtest.Clock],
+// to exercise timeouts and retries deterministically.
+//
+//	clock := fxtest.NewClock()
+//	app := fx.New(
+//		fx.WithClock(clock),
+//		fx.StartTimeout(time.Second),
+//		...
+//	)
+func WithClock(clock Clock) Option {
+	return withClockOption{clock}
+}
+
+type withClockOption struct{ clock Clock }
+
+func (o withClockOption) apply(m *module) {
+	switch {
+	case m.parent != nil:
+		m.app.err = fmt.Errorf("fx.WithClock Option should be passed to top-level App, " +
+			"not to fx.Module")
+	case o.clock == nil:
+		m.app.err = fmt.Errorf("fx.WithClock: clock must not be nil")
+	default:
+		m.app.clock = o.clock
+	}
+}
+
+func (o withClockOption) String() string {
+	return fmt.Sprintf("fx.WithClock(%v)", o.clock)
+}
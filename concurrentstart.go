@@ -0,0 +1,158 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ConcurrentStart makes [App.Start] run up to workers OnStart hooks at once,
+// instead of one at a time.
+// Use it when starting is dominated by independent, slow operations,
+// such as dialing unrelated network services.
+//
+//	fx.New(
+//		fx.ConcurrentStart(8),
+//		...
+//	)
+//
+// Dependency order is still respected: an OnStart hook runs only once the
+// hooks appended by the constructors, decorators, and invoked functions
+// that its own function depends on, directly or not, have started.
+// Hooks appended by the same function run in the order they were appended.
+// Hooks also wait for the hooks of earlier [LifecyclePhases],
+// and for the hooks they're ordered after with [Hook.RunAfter] and
+// [Hook.RunBefore].
+// Hooks appended outside of those functions, such as by another hook,
+// start only after every hook before them, and before every hook after them.
+//
+// Dependencies that aren't visible to Fx, such as on a package variable,
+// aren't taken into account.
+// Hook functions, and the application's [fxevent.Logger],
+// may be called concurrently.
+//
+// Once a hook fails, no more hooks are started;
+// Start waits for the hooks already running to return before rolling back.
+// OnStop hooks still run one at a time, in the reverse of the order
+// in which their OnStart hooks completed.
+func ConcurrentStart(workers int) Option {
+	return concurrentStartOption(workers)
+}
+
+type concurrentStartOption int
+
+func (o concurrentStartOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.ConcurrentStart Option should be passed to top-level App, " +
+			"not to fx.Module")
+		return
+	}
+	if o < 1 {
+		m.app.err = fmt.Errorf("fx.ConcurrentStart: workers must be positive, got %d", int(o))
+		return
+	}
+	m.app.startWorkers = int(o)
+}
+
+func (o concurrentStartOption) String() string {
+	return fmt.Sprintf("fx.ConcurrentStart(%d)", int(o))
+}
+
+// hookNode is the function that appended a lifecycle hook:
+// the one at index node in the graph nodes of mod, if node isn't -1.
+type hookNode struct {
+	mod  *module
+	node int
+}
+
+// hookDependencies reports which functions of the dependency graph
+// depend on which others, directly or not.
+type hookDependencies struct {
+	once sync.Once
+
+	ids   map[hookNode]int // graph node ID of each function
+	edges map[int][]int    // graph node ID => IDs of the nodes it depends on
+	reach map[int]map[int]bool
+}
+
+// hookDependsOn reports whether the lifecycle hook at index i
+// must start after the one at index j, appended before it.
+func (app *App) hookDependsOn(i, j int) bool {
+	if i >= len(app.hookNodes) || j >= len(app.hookNodes) {
+		return true
+	}
+	a, b := app.hookNodes[i], app.hookNodes[j]
+	if a.node < 0 || b.node < 0 || a == b {
+		return true
+	}
+
+	deps := &app.hookDeps
+	deps.once.Do(func() { deps.build(app) })
+	from, ok := deps.ids[a]
+	if !ok {
+		return true
+	}
+	to, ok := deps.ids[b]
+	if !ok {
+		return true
+	}
+	return deps.reaches(from, to)
+}
+
+func (d *hookDependencies) build(app *App) {
+	g, nodes := app.graph()
+
+	d.ids = make(map[hookNode]int, len(nodes))
+	local := make(map[*module]int) // number of nodes of each module so far
+	for _, n := range nodes {
+		d.ids[hookNode{mod: n.mod, node: local[n.mod]}] = n.ID
+		local[n.mod]++
+	}
+
+	d.edges = make(map[int][]int)
+	for _, e := range g.Edges {
+		d.edges[e.From] = append(d.edges[e.From], e.To)
+	}
+	d.reach = make(map[int]map[int]bool)
+}
+
+// reaches reports whether the graph node from depends on the node to,
+// directly or not.
+func (d *hookDependencies) reaches(from, to int) bool {
+	seen, ok := d.reach[from]
+	if !ok {
+		seen = make(map[int]bool)
+		stack := []int{from}
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, dep := range d.edges[n] {
+				if !seen[dep] {
+					seen[dep] = true
+					stack = append(stack, dep)
+				}
+			}
+		}
+		d.reach[from] = seen
+	}
+	return seen[to]
+}
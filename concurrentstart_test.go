@@ -0,0 +1,234 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestConcurrentStart(t *testing.T) {
+	t.Parallel()
+
+	type (
+		dialerA  struct{}
+		dialerB  struct{}
+		consumer struct{}
+	)
+
+	t.Run("independent hooks run concurrently", func(t *testing.T) {
+		t.Parallel()
+
+		// Each hook waits for the other to start.
+		var wg sync.WaitGroup
+		wg.Add(2)
+		dial := func(context.Context) error {
+			wg.Done()
+			done := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+				return nil
+			case <-time.After(5 * time.Second):
+				return errors.New("hooks did not run concurrently")
+			}
+		}
+
+		app := fxtest.New(t,
+			ConcurrentStart(4),
+			Provide(
+				func(lc Lifecycle) *dialerA {
+					lc.Append(Hook{OnStart: dial})
+					return &dialerA{}
+				},
+				func(lc Lifecycle) *dialerB {
+					lc.Append(Hook{OnStart: dial})
+					return &dialerB{}
+				},
+			),
+			Invoke(func(*dialerA, *dialerB) {}),
+		)
+		app.RequireStart().RequireStop()
+	})
+
+	t.Run("dependency order", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			mu    sync.Mutex
+			calls []string
+		)
+		record := func(call string) func(context.Context) error {
+			return func(context.Context) error {
+				mu.Lock()
+				defer mu.Unlock()
+				calls = append(calls, call)
+				return nil
+			}
+		}
+
+		app := fxtest.New(t,
+			ConcurrentStart(4),
+			Provide(
+				func(lc Lifecycle) *dialerA {
+					lc.Append(Hook{OnStart: record("start a"), OnStop: record("stop a")})
+					return &dialerA{}
+				},
+				func(lc Lifecycle, _ *dialerA) *consumer {
+					lc.Append(Hook{OnStart: record("start consumer"), OnStop: record("stop consumer")})
+					return &consumer{}
+				},
+			),
+			Invoke(func(lc Lifecycle, _ *consumer) {
+				lc.Append(Hook{OnStart: record("start invoke"), OnStop: record("stop invoke")})
+			}),
+		)
+		app.RequireStart().RequireStop()
+
+		assert.Equal(t, []string{
+			"start a",
+			"start consumer",
+			"start invoke",
+			"stop invoke",
+			"stop consumer",
+			"stop a",
+		}, calls)
+	})
+
+	t.Run("bounded by workers", func(t *testing.T) {
+		t.Parallel()
+
+		var running, maxRunning int32
+		hook := func(context.Context) error {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}
+
+		app := fxtest.New(t,
+			ConcurrentStart(2),
+			Provide(
+				func(lc Lifecycle) *dialerA {
+					lc.Append(Hook{OnStart: hook})
+					return &dialerA{}
+				},
+				func(lc Lifecycle) *dialerB {
+					lc.Append(Hook{OnStart: hook})
+					return &dialerB{}
+				},
+				func(lc Lifecycle) *consumer {
+					lc.Append(Hook{OnStart: hook})
+					return &consumer{}
+				},
+			),
+			Invoke(func(*dialerA, *dialerB, *consumer) {}),
+		)
+		app.RequireStart().RequireStop()
+		assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(2))
+	})
+
+	t.Run("failure rolls back started hooks", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			mu      sync.Mutex
+			stopped []string
+		)
+		stop := func(name string) func(context.Context) error {
+			return func(context.Context) error {
+				mu.Lock()
+				defer mu.Unlock()
+				stopped = append(stopped, name)
+				return nil
+			}
+		}
+
+		app := New(
+			NopLogger,
+			ConcurrentStart(4),
+			Provide(
+				func(lc Lifecycle) *dialerA {
+					lc.Append(Hook{
+						OnStart: func(context.Context) error { return nil },
+						OnStop:  stop("a"),
+					})
+					return &dialerA{}
+				},
+				func(lc Lifecycle, _ *dialerA) *dialerB {
+					lc.Append(Hook{
+						OnStart: func(context.Context) error { return errors.New("great sadness") },
+						OnStop:  stop("b"),
+					})
+					return &dialerB{}
+				},
+				func(lc Lifecycle, _ *dialerB) *consumer {
+					lc.Append(Hook{
+						OnStart: func(context.Context) error {
+							t.Error("hook depending on a failed hook must not start")
+							return nil
+						},
+						OnStop: stop("consumer"),
+					})
+					return &consumer{}
+				},
+			),
+			Invoke(func(*consumer) {}),
+		)
+		require.NoError(t, app.Err())
+
+		err := app.Start(context.Background())
+		assert.ErrorContains(t, err, "great sadness")
+		assert.Equal(t, []string{"a"}, stopped)
+	})
+
+	t.Run("invalid workers", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, ConcurrentStart(0))
+		assert.ErrorContains(t, app.Err(), "fx.ConcurrentStart: workers must be positive, got 0")
+	})
+
+	t.Run("in module", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, Module("child", ConcurrentStart(2)))
+		assert.ErrorContains(t, app.Err(), "fx.ConcurrentStart Option should be passed to top-level App")
+	})
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// If includes the given options in the application only if cond is true.
+// Otherwise, the options are skipped as if they had not been passed at all.
+//
+// Use If to replace conditional glue code with declarative options.
+//
+//	fx.New(
+//		fx.Provide(NewServer),
+//		fx.If(cfg.Debug,
+//			fx.Provide(NewDebugHandler),
+//			fx.Invoke(RegisterDebugHandler),
+//		),
+//	)
+//
+// Skipped options are still shown, marked as skipped,
+// in the String representation of the option.
+// Options skipped by [When] are not marked: printing the option
+// doesn't call its predicate.
+func If(cond bool, opts ...Option) Option {
+	return &conditionalOption{
+		funcName: "fx.If",
+		condName: fmt.Sprint(cond),
+		cond:     func() bool { return cond },
+		opts:     opts,
+		skipped:  !cond,
+	}
+}
+
+// When includes the given options in the application
+// only if the predicate returns true.
+// The predicate is called each time the option is used
+// to construct an application, when the option is applied.
+// So an option reused across applications checks the predicate
+// for each of them.
+//
+//	fx.When(featureflag.Enabled("new-cache"),
+//		fx.Decorate(WrapCache),
+//	)
+//
+// See [If] for a version that accepts a boolean.
+func When(predicate func() bool, opts ...Option) Option {
+	if predicate == nil {
+		return Error(fmt.Errorf("fx.When: predicate must not be nil"))
+	}
+	return &conditionalOption{
+		funcName: "fx.When",
+		condName: fxreflect.FuncName(predicate),
+		cond:     predicate,
+		opts:     opts,
+	}
+}
+
+type conditionalOption struct {
+	funcName string // fx.If or fx.When
+	condName string
+	cond     func() bool
+	opts     []Option
+
+	// Whether the options are known to be skipped without calling cond,
+	// as they are for fx.If(false, ...).
+	skipped bool
+}
+
+// Enabled reports whether the options should be included,
+// calling the predicate.
+func (o *conditionalOption) Enabled() bool {
+	return o.cond()
+}
+
+func (o *conditionalOption) apply(m *module) {
+	if !o.Enabled() {
+		return
+	}
+	for _, opt := range o.opts {
+		opt.apply(m)
+	}
+}
+
+func (o *conditionalOption) String() string {
+	items := make([]string, len(o.opts))
+	for i, opt := range o.opts {
+		items[i] = fmt.Sprint(opt)
+	}
+
+	s := fmt.Sprintf("%s(%s, %s)", o.funcName, o.condName, strings.Join(items, ", "))
+	if o.skipped {
+		s += " (skipped)"
+	}
+	return s
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+)
+
+func TestConditionalOptions(t *testing.T) {
+	t.Parallel()
+
+	type config struct{ name string }
+	newConfig := func() *config { return &config{name: "default"} }
+	enabled := func() bool { return true }
+	disabled := func() bool { return false }
+
+	tests := []struct {
+		desc string
+		give Option
+		want bool // whether *config is provided
+	}{
+		{desc: "If true", give: If(true, Provide(newConfig)), want: true},
+		{desc: "If false", give: If(false, Provide(newConfig)), want: false},
+		{desc: "When true", give: When(enabled, Provide(newConfig)), want: true},
+		{desc: "When false", give: When(disabled, Provide(newConfig)), want: false},
+		{
+			desc: "nested",
+			give: If(true, Module("child", When(enabled, Provide(newConfig)))),
+			want: true,
+		},
+		{
+			desc: "nested skipped",
+			give: If(true, If(false, Provide(newConfig))),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg *config
+			app := NewForTest(t,
+				tt.give,
+				Invoke(func(c *config) { cfg = c }),
+			)
+			if !tt.want {
+				assert.ErrorContains(t, app.Err(), "missing type: *fx_test.config")
+				return
+			}
+			require.NoError(t, app.Err())
+			assert.Equal(t, "default", cfg.name)
+		})
+	}
+
+	t.Run("When calls predicate once per use", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		opt := When(func() bool {
+			calls++
+			return calls > 1
+		}, Provide(newConfig))
+
+		assert.NotContains(t, fmt.Sprint(opt), "(skipped)")
+		assert.Equal(t, 0, calls, "String must not call the predicate")
+
+		app := NewForTest(t, opt, Invoke(func(*config) {}))
+		assert.ErrorContains(t, app.Err(), "missing type: *fx_test.config")
+		assert.Equal(t, 1, calls)
+
+		app = NewForTest(t, opt, Invoke(func(*config) {}))
+		require.NoError(t, app.Err(), "the predicate must be checked again")
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("When nil predicate", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, When(nil, Invoke(func() {})))
+		assert.ErrorContains(t, app.Err(), "fx.When: predicate must not be nil")
+	})
+
+	t.Run("AppliedOptions", func(t *testing.T) {
+		t.Parallel()
+
+		app := New(
+			NopLogger,
+			If(true, Invoke(connectDB)),
+			If(false, Invoke(flushLogs)),
+		)
+		require.NoError(t, app.Err())
+		assert.Equal(t, []string{
+			"fx.WithLogger(go.uber.org/fx.init.func1())",
+			"fx.Invoke(go.uber.org/fx_test.connectDB())",
+		}, app.AppliedOptions())
+	})
+}
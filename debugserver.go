@@ -0,0 +1,271 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/fx/fxevent"
+)
+
+// _debugEventHistory is the number of recent events served by DebugServer.
+const _debugEventHistory = 256
+
+// DebugServer makes the application serve diagnostics over HTTP on the
+// given address, such as "localhost:6060". It serves:
+//
+//   - /debug/fx/graph: the dependency graph as JSON, as reported by [App.Graph]
+//   - /debug/fx/graph.dot: the dependency graph as a [DotGraph]
+//   - /debug/fx/hooks: the lifecycle hooks as JSON, as reported by
+//     [App.RegisteredHooks], with their status and how long they ran
+//   - /debug/fx/state: the state of the application,
+//     such as "starting", "started", or "stopped"
+//   - /debug/fx/events: the most recent Fx events, oldest first
+//
+// Diagnostics are served from the beginning of [App.Start] to the end of
+// [App.Stop].
+// The server isn't authenticated:
+// bind it to an address that only operators can reach.
+// Requests whose headers take more than 10 seconds to arrive are dropped.
+func DebugServer(addr string) Option {
+	return debugServerOption(addr)
+}
+
+type debugServerOption string
+
+func (o debugServerOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.DebugServer Option should be passed to top-level App, " +
+			"not to fx.Module")
+		return
+	}
+	m.app.debug = newDebugServer(m.app, string(o))
+}
+
+func (o debugServerOption) String() string {
+	return fmt.Sprintf("fx.DebugServer(%q)", string(o))
+}
+
+// debugServer serves the diagnostics of an application.
+// A nil debugServer serves nothing.
+type debugServer struct {
+	app    *App
+	events eventHistory
+	srv    httpServer
+}
+
+func newDebugServer(app *App, addr string) *debugServer {
+	s := &debugServer{app: app}
+	s.events.buf = make([]recordedEvent, _debugEventHistory)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/fx/", s.index)
+	mux.HandleFunc("/debug/fx/graph", s.graph)
+	mux.HandleFunc("/debug/fx/graph.dot", s.dotGraph)
+	mux.HandleFunc("/debug/fx/hooks", s.hooks)
+	mux.HandleFunc("/debug/fx/state", s.state)
+	mux.HandleFunc("/debug/fx/events", s.recentEvents)
+	s.srv = httpServer{name: "fx.DebugServer", addr: addr, handler: mux}
+	return s
+}
+
+// recordEvents makes the loggers of m and its descendants
+// record the events they log.
+// Call it once custom loggers have been built.
+func (s *debugServer) recordEvents(m *module) {
+	if s == nil {
+		return
+	}
+	m.log = s.recordingLogger(m.log)
+	for _, mod := range m.modules {
+		s.recordEvents(mod)
+	}
+}
+
+// recordingLogger returns a logger that records the events it logs
+// and passes them on to log.
+func (s *debugServer) recordingLogger(log fxevent.Logger) fxevent.Logger {
+	if _, ok := log.(historyLogger); ok {
+		return log
+	}
+	return historyLogger{Logger: log, h: &s.events, clock: s.app.clock}
+}
+
+// start begins serving diagnostics if it isn't doing so already.
+func (s *debugServer) start() error {
+	if s == nil {
+		return nil
+	}
+	return s.srv.start()
+}
+
+// stop stops serving diagnostics, waiting for in-flight requests
+// until ctx is done.
+func (s *debugServer) stop(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	return s.srv.stop(ctx)
+}
+
+func (s *debugServer) index(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/debug/fx/" {
+		http.NotFound(w, r)
+		return
+	}
+	for _, path := range []string{"graph", "graph.dot", "hooks", "state", "events"} {
+		fmt.Fprintf(w, "/debug/fx/%v\n", path)
+	}
+}
+
+func (s *debugServer) graph(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, s.app.Graph())
+}
+
+func (s *debugServer) dotGraph(w http.ResponseWriter, _ *http.Request) {
+	dot, err := s.app.dotGraph()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+	fmt.Fprint(w, dot)
+}
+
+// debugHook is a HookInfo as served by DebugServer.
+type debugHook struct {
+	Name         string `json:"name,omitempty"`
+	OnStart      string `json:"onStart,omitempty"`
+	OnStop       string `json:"onStop,omitempty"`
+	Caller       string `json:"caller"`
+	Phase        string `json:"phase,omitempty"`
+	Module       string `json:"module,omitempty"`
+	Status       string `json:"status"`
+	StartRuntime string `json:"startRuntime,omitempty"`
+	StopRuntime  string `json:"stopRuntime,omitempty"`
+}
+
+func (s *debugServer) hooks(w http.ResponseWriter, _ *http.Request) {
+	infos := s.app.RegisteredHooks()
+	hooks := make([]debugHook, len(infos))
+	for i, h := range infos {
+		hooks[i] = debugHook{
+			Name:    h.Name,
+			OnStart: h.OnStart,
+			OnStop:  h.OnStop,
+			Caller:  h.Caller,
+			Phase:   h.Phase,
+			Module:  h.Module,
+			Status:  h.Status.String(),
+		}
+		if h.StartRuntime > 0 {
+			hooks[i].StartRuntime = h.StartRuntime.String()
+		}
+		if h.StopRuntime > 0 {
+			hooks[i].StopRuntime = h.StopRuntime.String()
+		}
+	}
+	writeJSON(w, hooks)
+}
+
+func (s *debugServer) state(w http.ResponseWriter, _ *http.Request) {
+	fmt.Fprintln(w, s.app.lifecycle.State())
+}
+
+func (s *debugServer) recentEvents(w http.ResponseWriter, _ *http.Request) {
+	var buf bytes.Buffer
+	logger := &fxevent.ConsoleLogger{W: &buf}
+	for _, e := range s.events.recent() {
+		buf.Reset()
+		logger.LogEvent(e.Event)
+		if buf.Len() == 0 {
+			continue // not rendered, such as successful invokes
+		}
+		fmt.Fprintf(w, "%v %s", e.At.Format(time.RFC3339Nano), buf.Bytes())
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// recordedEvent is an event logged by the application.
+type recordedEvent struct {
+	At    time.Time
+	Event fxevent.Event
+}
+
+// eventHistory holds the most recent events logged by the application.
+type eventHistory struct {
+	mu   sync.Mutex
+	buf  []recordedEvent // ring buffer
+	next int             // index in buf of the next event
+	full bool            // whether buf has wrapped around
+}
+
+func (h *eventHistory) record(e recordedEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.buf[h.next] = e
+	h.next++
+	if h.next == len(h.buf) {
+		h.next = 0
+		h.full = true
+	}
+}
+
+// recent returns the recorded events, oldest first.
+func (h *eventHistory) recent() []recordedEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.full {
+		return append([]recordedEvent(nil), h.buf[:h.next]...)
+	}
+	events := make([]recordedEvent, 0, len(h.buf))
+	events = append(events, h.buf[h.next:]...)
+	return append(events, h.buf[:h.next]...)
+}
+
+// historyLogger records the events it logs in an eventHistory.
+type historyLogger struct {
+	fxevent.Logger
+
+	h     *eventHistory
+	clock Clock
+}
+
+func (l historyLogger) LogEvent(e fxevent.Event) {
+	l.h.record(recordedEvent{At: l.clock.Now(), Event: e})
+	l.Logger.LogEvent(e)
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxevent"
+)
+
+func TestDebugServer(t *testing.T) {
+	t.Parallel()
+
+	get := func(t *testing.T, app *App, path string) string {
+		res, err := http.Get("http://" + app.debug.srv.listenAddr().String() + path)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode, path)
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	type server struct{}
+	app := New(
+		NopLogger,
+		DebugServer("127.0.0.1:0"),
+		Module("http",
+			Provide(func() *server { return &server{} }),
+			Invoke(func(lc Lifecycle, _ *server) {
+				lc.Append(Hook{
+					Name:    "listen",
+					OnStart: func(context.Context) error { return nil },
+				})
+			}),
+		),
+	)
+	require.NoError(t, app.Err())
+
+	ctx := context.Background()
+	require.NoError(t, app.Start(ctx))
+
+	t.Run("index", func(t *testing.T) {
+		assert.Contains(t, get(t, app, "/debug/fx/"), "/debug/fx/hooks\n")
+	})
+
+	t.Run("graph", func(t *testing.T) {
+		var g Graph
+		require.NoError(t, json.Unmarshal([]byte(get(t, app, "/debug/fx/graph")), &g))
+		require.Len(t, g.Root.Modules, 1)
+		assert.Equal(t, "http", g.Root.Modules[0].Name)
+	})
+
+	t.Run("graph.dot", func(t *testing.T) {
+		assert.True(t, strings.HasPrefix(get(t, app, "/debug/fx/graph.dot"), "digraph"))
+	})
+
+	t.Run("hooks", func(t *testing.T) {
+		var hooks []debugHook
+		require.NoError(t, json.Unmarshal([]byte(get(t, app, "/debug/fx/hooks")), &hooks))
+		require.Len(t, hooks, 1)
+		assert.Equal(t, "listen", hooks[0].Name)
+		assert.Equal(t, "http", hooks[0].Module)
+		assert.Equal(t, "started", hooks[0].Status)
+	})
+
+	t.Run("state", func(t *testing.T) {
+		assert.Equal(t, "started\n", get(t, app, "/debug/fx/state"))
+	})
+
+	t.Run("events", func(t *testing.T) {
+		events := get(t, app, "/debug/fx/events")
+		assert.Contains(t, events, "[Fx] INVOKE")
+		assert.Contains(t, events, "[Fx] RUNNING")
+	})
+
+	addr := app.debug.srv.listenAddr().String()
+	require.NoError(t, app.Stop(ctx))
+	_, err := http.Get("http://" + addr + "/debug/fx/state")
+	assert.Error(t, err, "diagnostics must not be served once stopped")
+}
+
+func TestDebugServerCustomLogger(t *testing.T) {
+	t.Parallel()
+
+	var logged int
+	app := New(
+		DebugServer("127.0.0.1:0"),
+		WithLogger(func() fxevent.Logger { return spyLogger{&logged} }),
+	)
+	require.NoError(t, app.Err())
+
+	ctx := context.Background()
+	require.NoError(t, app.Start(ctx))
+	defer app.Stop(ctx)
+
+	assert.NotZero(t, logged, "custom logger must still receive events")
+	var sawStarted bool
+	for _, e := range app.debug.events.recent() {
+		if _, ok := e.Event.(*fxevent.Started); ok {
+			sawStarted = true
+		}
+	}
+	assert.True(t, sawStarted, "events logged with a custom logger must be recorded")
+}
+
+type spyLogger struct{ n *int }
+
+func (l spyLogger) LogEvent(fxevent.Event) { *l.n++ }
+
+func TestEventHistory(t *testing.T) {
+	t.Parallel()
+
+	h := eventHistory{buf: make([]recordedEvent, 3)}
+	assert.Empty(t, h.recent())
+
+	record := func(n int) {
+		h.record(recordedEvent{Event: &fxevent.Started{Runtime: time.Duration(n)}})
+	}
+	runtimes := func() []time.Duration {
+		var ds []time.Duration
+		for _, e := range h.recent() {
+			ds = append(ds, e.Event.(*fxevent.Started).Runtime)
+		}
+		return ds
+	}
+
+	record(1)
+	record(2)
+	assert.Equal(t, []time.Duration{1, 2}, runtimes())
+
+	record(3)
+	record(4)
+	assert.Equal(t, []time.Duration{2, 3, 4}, runtimes())
+}
+
+func TestDebugServerInModule(t *testing.T) {
+	t.Parallel()
+
+	app := New(NopLogger, Module("child", DebugServer(":0")))
+	assert.ErrorContains(t, app.Err(),
+		"fx.DebugServer Option should be passed to top-level App, not to fx.Module")
+}
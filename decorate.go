@@ -26,6 +26,7 @@ import (
 	"strings"
 
 	"go.uber.org/dig"
+	"go.uber.org/fx/fxevent"
 	"go.uber.org/fx/internal/fxreflect"
 )
 
@@ -150,7 +151,16 @@ import (
 //	}),
 //
 // Decorations specified in the top-level fx.New call apply across the
-// application and chain with module-specific decorators.
+// application, including inside nested modules,
+// and chain with module-specific decorators.
+// There's no need to repeat such a decorator in every module.
+// They can't decorate values provided with [Private] inside a module,
+// because those values aren't visible from the top level:
+// decorate them inside the module that provides them instead.
+//
+// Because decorations are always private to the module they're declared in,
+// and the modules it contains, passing [Private] to Decorate has no effect.
+// It's accepted so that modules can state that intent explicitly.
 //
 //	fx.New(
 //	  // ...
@@ -189,6 +199,11 @@ type decorateOption struct {
 
 func (o decorateOption) apply(mod *module) {
 	for _, target := range o.Targets {
+		// Decorations never leak out of the module they're declared in,
+		// so fx.Private only documents intent.
+		if _, ok := target.(privateOption); ok {
+			continue
+		}
 		mod.decorators = append(mod.decorators, decorator{
 			Target: target,
 			Stack:  o.Stack,
@@ -204,6 +219,65 @@ func (o decorateOption) String() string {
 	return fmt.Sprintf("fx.Decorate(%s)", strings.Join(items, ", "))
 }
 
+// WarnAmbiguousDecorations makes Fx log an [fxevent.AmbiguousDecoration]
+// warning for every type that a module both provides and decorates.
+// Such a decorator receives the value the module itself provides,
+// and the module's own consumers only ever see the decorated value;
+// this is rarely intended, and usually means that either the provide
+// or the decorator belongs in a different module.
+//
+// The warning names where the type was provided and where it was decorated.
+// It does not cause the application to fail.
+func WarnAmbiguousDecorations() Option {
+	return warnAmbiguousDecorationsOption{}
+}
+
+type warnAmbiguousDecorationsOption struct{}
+
+func (warnAmbiguousDecorationsOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.WarnAmbiguousDecorations Option should be passed to top-level App, " +
+			"not to fx.Module")
+	} else {
+		m.app.warnAmbiguousDecorations = true
+	}
+}
+
+func (warnAmbiguousDecorationsOption) String() string {
+	return "fx.WarnAmbiguousDecorations()"
+}
+
+// recordProvidedAt records that types were provided to m at stack,
+// if the application warns about ambiguous decorations.
+func (m *module) recordProvidedAt(types []string, stack fxreflect.Stack) {
+	if !m.app.warnAmbiguousDecorations {
+		return
+	}
+	if m.providedAt == nil {
+		m.providedAt = make(map[string]fxreflect.Stack)
+	}
+	for _, typ := range types {
+		m.providedAt[typ] = stack
+	}
+}
+
+// warnAmbiguousDecorations logs a warning for each of the decorated types
+// that m also provides.
+func (m *module) warnAmbiguousDecorations(types []string, stack fxreflect.Stack) {
+	for _, typ := range types {
+		providedAt, ok := m.providedAt[typ]
+		if !ok {
+			continue
+		}
+		m.log.LogEvent(&fxevent.AmbiguousDecoration{
+			TypeName:           typ,
+			ModuleName:         m.name,
+			ProvideStackTrace:  providedAt.Strings(),
+			DecorateStackTrace: stack.Strings(),
+		})
+	}
+}
+
 // decorator is a single decorator used in Fx.
 type decorator struct {
 	// Decorator provided to Fx.
@@ -215,6 +289,10 @@ type decorator struct {
 	// Whether this decorator was specified via fx.Replace
 	IsReplace   bool
 	ReplaceType reflect.Type // set only if IsReplace
+
+	// Whether this decorator was specified via fx.Undecorate
+	IsUndecorate   bool
+	UndecorateType reflect.Type // set only if IsUndecorate
 }
 
 func runDecorator(c container, d decorator, opts ...dig.DecorateOption) (err error) {
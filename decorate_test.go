@@ -28,6 +28,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
 	"go.uber.org/fx/fxtest"
 )
 
@@ -102,6 +103,30 @@ func TestDecorateSuccess(t *testing.T) {
 		defer app.RequireStart().RequireStop()
 	})
 
+	t.Run("decorate with Private", func(t *testing.T) {
+		type Config struct {
+			Name string
+		}
+		app := fxtest.New(t,
+			fx.Supply(&Config{Name: "config"}),
+			fx.Module("child",
+				fx.Decorate(
+					func(c *Config) *Config {
+						return &Config{Name: "decorated " + c.Name}
+					},
+					fx.Private,
+				),
+				fx.Invoke(func(c *Config) {
+					assert.Equal(t, "decorated config", c.Name)
+				}),
+			),
+			fx.Invoke(func(c *Config) {
+				assert.Equal(t, "config", c.Name)
+			}),
+		)
+		defer app.RequireStart().RequireStop()
+	})
+
 	t.Run("use Decorate with Annotate", func(t *testing.T) {
 		type Coffee struct {
 			Name  string
@@ -467,6 +492,31 @@ func TestDecorateFailure(t *testing.T) {
 		assert.Contains(t, err.Error(), "missing dependencies")
 	})
 
+	t.Run("root decorator cannot see private values", func(t *testing.T) {
+		type Logger struct {
+			Name string
+		}
+
+		app := NewForTest(t,
+			fx.Decorate(func(l *Logger) *Logger {
+				return &Logger{Name: "decorated " + l.Name}
+			}),
+			fx.Module("child",
+				fx.Provide(
+					func() *Logger { return &Logger{Name: "logger"} },
+					fx.Private,
+				),
+				fx.Invoke(func(l *Logger) {
+					assert.Fail(t, "this should never run")
+				}),
+			),
+		)
+
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing type: *fx_test.Logger")
+	})
+
 	t.Run("decorate cannot provide a non-existent type", func(t *testing.T) {
 		type Logger struct {
 			Name string
@@ -486,3 +536,87 @@ func TestDecorateFailure(t *testing.T) {
 		assert.Contains(t, err.Error(), "missing dependencies")
 	})
 }
+
+func TestWarnAmbiguousDecorations(t *testing.T) {
+	t.Parallel()
+
+	type Logger struct{ Name string }
+	newLogger := func() *Logger { return &Logger{Name: "raw"} }
+	decorateLogger := func(l *Logger) *Logger { return &Logger{Name: "decorated " + l.Name} }
+
+	warnings := func(t *testing.T, opts ...fx.Option) []*fxevent.AmbiguousDecoration {
+		app, spy := NewSpied(append(opts, fx.Invoke(func(*Logger) {}))...)
+		require.NoError(t, app.Err())
+
+		var events []*fxevent.AmbiguousDecoration
+		for _, e := range spy.Events().SelectByTypeName("AmbiguousDecoration") {
+			events = append(events, e.(*fxevent.AmbiguousDecoration))
+		}
+		return events
+	}
+
+	t.Run("provided and decorated in module", func(t *testing.T) {
+		t.Parallel()
+
+		events := warnings(t,
+			fx.WarnAmbiguousDecorations(),
+			fx.Module("child",
+				fx.Provide(newLogger),
+				fx.Decorate(decorateLogger),
+			),
+		)
+		require.Len(t, events, 1)
+		assert.Equal(t, "*fx_test.Logger", events[0].TypeName)
+		assert.Equal(t, "child", events[0].ModuleName)
+		require.NotEmpty(t, events[0].ProvideStackTrace)
+		require.NotEmpty(t, events[0].DecorateStackTrace)
+		assert.Contains(t, events[0].ProvideStackTrace[0], "decorate_test.go")
+		assert.Contains(t, events[0].DecorateStackTrace[0], "decorate_test.go")
+		assert.NotEqual(t, events[0].ProvideStackTrace[0], events[0].DecorateStackTrace[0])
+	})
+
+	t.Run("supplied and decorated at top level", func(t *testing.T) {
+		t.Parallel()
+
+		events := warnings(t,
+			fx.WarnAmbiguousDecorations(),
+			fx.Supply(&Logger{}),
+			fx.Decorate(decorateLogger),
+		)
+		require.Len(t, events, 1)
+		assert.Equal(t, "*fx_test.Logger", events[0].TypeName)
+	})
+
+	t.Run("decorated in another module", func(t *testing.T) {
+		t.Parallel()
+
+		events := warnings(t,
+			fx.WarnAmbiguousDecorations(),
+			fx.Provide(newLogger),
+			fx.Module("child",
+				fx.Decorate(decorateLogger),
+			),
+		)
+		assert.Empty(t, events)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+
+		events := warnings(t,
+			fx.Provide(newLogger),
+			fx.Decorate(decorateLogger),
+		)
+		assert.Empty(t, events)
+	})
+
+	t.Run("in module", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, fx.Module("child", fx.WarnAmbiguousDecorations()))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			"fx.WarnAmbiguousDecorations Option should be passed to top-level App, not to fx.Module")
+	})
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"reflect"
+	"strings"
+)
+
+// DefaultAnnotations applies the given annotations to every constructor
+// passed to [Provide] in the module that declares it and in that module's
+// descendants, as if each constructor had been wrapped with [Annotate].
+//
+// For example, the following tags every *sql.DB provided in the
+// "storage" module with the name "primary".
+//
+//	fx.Module("storage",
+//		fx.DefaultAnnotations(fx.ResultTags(`name:"primary"`)),
+//		fx.Provide(NewDB),
+//	)
+//
+// Annotations passed to [Annotate] at the provide site compose with the
+// defaults and take precedence over a default of the same kind:
+// a constructor annotated with its own [ResultTags] ignores a default
+// ResultTags but still receives a default [ParamTags].
+// Likewise, defaults declared by a module override defaults of the same
+// kind declared by its ancestors.
+//
+// DefaultAnnotations does not apply to values passed to [Supply] or
+// [SupplyDerived], to [Annotated] structs, to functions passed to
+// [Decorate] or [Invoke], or to the types Fx provides itself,
+// such as [Lifecycle] and [Shutdowner].
+func DefaultAnnotations(anns ...Annotation) Option {
+	return defaultAnnotationsOption{Annotations: anns}
+}
+
+type defaultAnnotationsOption struct {
+	Annotations []Annotation
+}
+
+func (o defaultAnnotationsOption) apply(m *module) {
+	m.defaultAnnotations = append(m.defaultAnnotations, o.Annotations...)
+}
+
+func (o defaultAnnotationsOption) String() string {
+	var ann annotated
+	for _, a := range o.Annotations {
+		// Invalid annotations are reported when they're applied
+		// to a constructor.
+		_ = a.apply(&ann)
+	}
+	return "fx.DefaultAnnotations(" + strings.TrimPrefix(ann.annotationsString(), ", ") + ")"
+}
+
+// withDefaultAnnotations applies the default annotations in effect for this
+// module to the given constructor.
+func (m *module) withDefaultAnnotations(target interface{}) interface{} {
+	defaults := m.effectiveDefaultAnnotations()
+	if len(defaults) == 0 {
+		return target
+	}
+
+	switch t := target.(type) {
+	case annotated:
+		return Annotate(t.Target, overrideAnnotations(defaults, t.Annotations)...)
+	case annotationError, Annotated:
+		return target
+	default:
+		if reflect.TypeOf(target).Kind() != reflect.Func {
+			return target
+		}
+		return Annotate(target, defaults...)
+	}
+}
+
+// effectiveDefaultAnnotations returns the default annotations in effect for
+// this module, with those declared closest to it taking precedence.
+func (m *module) effectiveDefaultAnnotations() []Annotation {
+	var chain []*module
+	for mod := m; mod != nil; mod = mod.parent {
+		chain = append(chain, mod)
+	}
+
+	var anns []Annotation
+	for i := len(chain) - 1; i >= 0; i-- {
+		anns = overrideAnnotations(anns, chain[i].defaultAnnotations)
+	}
+	return anns
+}
+
+// overrideAnnotations returns the given overrides followed by the
+// annotations in base whose kind does not appear in overrides.
+func overrideAnnotations(base, overrides []Annotation) []Annotation {
+	if len(overrides) == 0 {
+		return base
+	}
+
+	kinds := make(map[reflect.Type]struct{}, len(overrides))
+	for _, ann := range overrides {
+		kinds[reflect.TypeOf(ann)] = struct{}{}
+	}
+
+	result := append([]Annotation(nil), overrides...)
+	for _, ann := range base {
+		if _, ok := kinds[reflect.TypeOf(ann)]; !ok {
+			result = append(result, ann)
+		}
+	}
+	return result
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestDefaultAnnotations(t *testing.T) {
+	t.Parallel()
+
+	type named struct {
+		In
+
+		Primary string `name:"primary"`
+	}
+
+	t.Run("applies to provides without annotations", func(t *testing.T) {
+		t.Parallel()
+
+		var got string
+		app := fxtest.New(t,
+			Module("storage",
+				DefaultAnnotations(ResultTags(`name:"primary"`)),
+				Provide(func() string { return "db" }),
+			),
+			Invoke(func(p named) { got = p.Primary }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "db", got)
+	})
+
+	t.Run("does not apply to types provided by Fx", func(t *testing.T) {
+		t.Parallel()
+
+		var got string
+		app := fxtest.New(t,
+			DefaultAnnotations(ResultTags(`name:"primary"`)),
+			SubApps(),
+			Provide(func() string { return "db" }),
+			Invoke(func(Lifecycle, Shutdowner, DotGraph, SubAppFactory) {}),
+			Invoke(func(p named) { got = p.Primary }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "db", got)
+	})
+
+	t.Run("composes with provide-site annotations", func(t *testing.T) {
+		t.Parallel()
+
+		var got string
+		app := fxtest.New(t,
+			DefaultAnnotations(ResultTags(`name:"primary"`)),
+			Supply(Annotated{Name: "host", Target: "localhost"}),
+			Provide(Annotate(
+				func(host string) string { return "db@" + host },
+				ParamTags(`name:"host"`),
+			)),
+			Invoke(func(p named) { got = p.Primary }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "db@localhost", got)
+	})
+
+	t.Run("provide-site annotations override", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			In
+
+			Replica string `name:"replica"`
+		}
+
+		var got string
+		app := fxtest.New(t,
+			DefaultAnnotations(ResultTags(`name:"primary"`)),
+			Provide(Annotate(
+				func() string { return "db" },
+				ResultTags(`name:"replica"`),
+			)),
+			Invoke(func(p params) { got = p.Replica }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "db", got)
+	})
+
+	t.Run("module overrides ancestor defaults", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			In
+
+			Primary string `name:"primary"`
+			Replica string `name:"replica"`
+		}
+
+		var got params
+		app := fxtest.New(t,
+			DefaultAnnotations(ResultTags(`name:"primary"`)),
+			Provide(func() string { return "db" }),
+			Module("replica",
+				DefaultAnnotations(ResultTags(`name:"replica"`)),
+				Provide(func() string { return "replica-db" }),
+			),
+			Invoke(func(p params) { got = p }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "db", got.Primary)
+		assert.Equal(t, "replica-db", got.Replica)
+	})
+
+	t.Run("does not apply outside the module", func(t *testing.T) {
+		t.Parallel()
+
+		var got string
+		app := fxtest.New(t,
+			Module("storage",
+				DefaultAnnotations(ResultTags(`name:"primary"`)),
+			),
+			Provide(func() string { return "db" }),
+			Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "db", got)
+	})
+
+	t.Run("does not apply to supplied values", func(t *testing.T) {
+		t.Parallel()
+
+		var got string
+		app := fxtest.New(t,
+			DefaultAnnotations(ResultTags(`name:"primary"`)),
+			Supply("db"),
+			Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "db", got)
+	})
+
+	t.Run("invalid annotation", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			DefaultAnnotations(ResultTags(`name:"a"`), ResultTags(`name:"b"`)),
+			Provide(func() string { return "db" }),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "encountered error while applying annotation")
+	})
+}
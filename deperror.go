@@ -0,0 +1,480 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"go.uber.org/dig"
+)
+
+// dependencyError is an error that Fx reported while building the
+// dependencies of an invoked function.
+//
+// Its message is that of the wrapped error, followed by suggestions
+// for the types it misses, if any. Formatting it with "%+v"
+// follows dig's own rendering
+// with the chain of functions from the invoked function to the failure
+// as a tree, naming the module each function was passed to.
+// Functions in a dependency cycle are annotated with their modules instead.
+type dependencyError struct {
+	err   error
+	steps []dependencyStep // from the invoked function to the failure
+	cause error            // nil for cycles
+
+	// whether the last function in steps is a constructor
+	// that returned cause
+	constructorFailed bool
+
+	// lines of a dependency cycle, if that's what err is
+	cycle []string
+
+	// values that may have been meant instead of missing ones
+	suggestions []string
+}
+
+// dependencyStep is a function in the path to a dependency failure.
+type dependencyStep struct {
+	// Value that the function was called to build.
+	// Empty for the invoked function.
+	Type string
+
+	// Function, as rendered by dig, including its file and line.
+	Function string
+
+	// Path of the module the function was passed to, if known.
+	Module string
+}
+
+// dig doesn't export the errors that make up the path to a failure,
+// so the path is read from their messages. Each link of the error chain
+// that is a dig.Error must start with one of these prefixes, followed by
+// the function or the value it names; otherwise, newDependencyError
+// leaves the error unchanged rather than report a partial path.
+// TestDependencyErrorDigMessages pins the messages of the dig version
+// that Fx depends on.
+
+// Prefixes of dig's error messages that name a function
+// in the path to a failure.
+var _dependencyFuncPrefixes = []string{
+	"could not build arguments for function ",
+	"missing dependencies for function ",
+	_dependencyConstructorPrefix,
+}
+
+// Prefixes of dig's error messages that name a value
+// in the path to a failure.
+var _dependencyTypePrefixes = []string{
+	"failed to build ",
+	"could not build value group ",
+}
+
+const _dependencyConstructorPrefix = "received non-nil error from function "
+
+// _funcLocation matches the "(file:line)" suffix that dig adds
+// to the name of a function.
+var _funcLocation = regexp.MustCompile(`\(([^()]+:\d+)\)$`)
+
+// newDependencyError wraps err, which was returned by invoking a function,
+// in a dependencyError if dig failed to build that function's dependencies.
+// Otherwise, or if the path to the failure can't be read from err,
+// err is returned unchanged.
+//
+// modules maps the locations of functions to the paths of the modules
+// they were passed to.
+func newDependencyError(err error, modules map[string]string) error {
+	if err == nil {
+		return nil
+	}
+
+	if dig.IsCycleDetected(err) {
+		lines := strings.Split(fmt.Sprintf("%+v", err), "\n")
+		for i, line := range lines {
+			if mod := modules[funcLocation(line)]; mod != "" {
+				lines[i] = fmt.Sprintf("%v in module %q", line, mod)
+			}
+		}
+		return &dependencyError{err: err, cycle: lines}
+	}
+
+	var (
+		steps             []dependencyStep
+		typ               string
+		cause             error
+		constructorFailed bool
+	)
+	for e := err; cause == nil; {
+		// The path ends at the first error that doesn't come from dig,
+		// or at the last error in the chain.
+		next := errors.Unwrap(e)
+		if _, ok := e.(dig.Error); !ok || next == nil {
+			cause = e
+			break
+		}
+
+		msg, ok := strings.CutSuffix(e.Error(), ": "+next.Error())
+		if !ok {
+			return err
+		}
+
+		if t, ok := cutAnyPrefix(msg, _dependencyTypePrefixes); ok {
+			typ = t
+			e = next
+			continue
+		}
+
+		fn, ok := cutAnyPrefix(msg, _dependencyFuncPrefixes)
+		if !ok {
+			// dig's wording changed: don't guess at the path.
+			return err
+		}
+		steps = append(steps, dependencyStep{
+			Type:     typ,
+			Function: fn,
+			Module:   modules[funcLocation(fn)],
+		})
+		typ = ""
+		e = next
+		// The constructor's own error isn't part of the path.
+		if strings.HasPrefix(msg, _dependencyConstructorPrefix) {
+			cause = next
+			constructorFailed = true
+		}
+	}
+
+	if len(steps) == 0 {
+		return err
+	}
+	return &dependencyError{
+		err:               err,
+		steps:             steps,
+		cause:             cause,
+		constructorFailed: constructorFailed,
+	}
+}
+
+func (e *dependencyError) Error() string {
+	if len(e.suggestions) == 0 {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("%v (%v)", e.err, strings.Join(e.suggestions, "; "))
+}
+
+func (e *dependencyError) Unwrap() error { return e.err }
+
+// Format implements fmt.Formatter.
+// With "%+v", it adds the path to the failure as a tree.
+func (e *dependencyError) Format(w fmt.State, c rune) {
+	if c != 'v' || !w.Flag('+') {
+		io.WriteString(w, e.Error())
+		return
+	}
+
+	if e.cycle != nil {
+		io.WriteString(w, strings.Join(e.cycle, "\n"))
+		return
+	}
+
+	fmt.Fprintf(w, "%+v\ndependency path:", e.err)
+	indent := "\n\t"
+	for i, step := range e.steps {
+		io.WriteString(w, indent)
+		if i > 0 {
+			io.WriteString(w, "└─ ")
+		}
+		if step.Type != "" {
+			fmt.Fprintf(w, "%v: ", step.Type)
+		}
+		io.WriteString(w, step.Function)
+		if step.Module != "" {
+			fmt.Fprintf(w, " in module %q", step.Module)
+		}
+		if i > 0 {
+			indent += "   "
+		}
+	}
+	fmt.Fprintf(w, "%v└─ %v", indent, e.cause)
+	if len(e.suggestions) > 0 {
+		io.WriteString(w, "\nsuggestions:")
+		for _, s := range e.suggestions {
+			fmt.Fprintf(w, "\n\t- %v", s)
+		}
+	}
+}
+
+// cutAnyPrefix returns s without the first of prefixes that it starts with,
+// and whether it starts with any of them.
+func cutAnyPrefix(s string, prefixes []string) (string, bool) {
+	for _, prefix := range prefixes {
+		if rest, ok := strings.CutPrefix(s, prefix); ok {
+			return rest, true
+		}
+	}
+	return s, false
+}
+
+// funcLocation returns the "file:line" suffix of a function rendered by dig,
+// or an empty string if it doesn't have one.
+func funcLocation(fn string) string {
+	if m := _funcLocation.FindStringSubmatch(fn); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// targetLocation returns the "file:line" where the function behind
+// a target of fx.Provide, fx.Decorate, or fx.Invoke is defined,
+// or an empty string if the target isn't a function.
+func targetLocation(target interface{}) string {
+	switch t := target.(type) {
+	case annotated:
+		return targetLocation(t.Target)
+	case Annotated:
+		return targetLocation(t.Target)
+	}
+
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return ""
+	}
+	pc := v.Pointer()
+	f := runtime.FuncForPC(pc)
+	if f == nil {
+		return ""
+	}
+	file, line := f.FileLine(pc)
+	return fmt.Sprintf("%v:%v", file, line)
+}
+
+// moduleLocations maps the locations of the functions passed to m
+// and its descendants to the paths of the modules they were passed to.
+func (m *module) moduleLocations(locs map[string]string) map[string]string {
+	if locs == nil {
+		locs = make(map[string]string)
+	}
+	path := m.path()
+	for _, n := range m.graphNodes {
+		if n.Location != "" && path != "" {
+			locs[n.Location] = path
+		}
+	}
+	for _, mod := range m.modules {
+		mod.moduleLocations(locs)
+	}
+	return locs
+}
+
+// Prefixes of dig's error message for missing types.
+var _missingTypePrefixes = []string{"missing type: ", "missing types: "}
+
+// Maximum number of suggestions for each missing type.
+const _maxSuggestions = 3
+
+// withSuggestions adds suggestions to err, if it's a dependencyError for
+// missing types, for values provided to the application that may have
+// been meant instead: the same type provided privately to another
+// module, the same type with a different name, or a type with
+// a similar name.
+// dig already suggests pointers for values and the other way around,
+// and implementations of missing interfaces.
+func (app *App) withSuggestions(err error) error {
+	de, ok := err.(*dependencyError)
+	if !ok || de.cause == nil {
+		return err
+	}
+
+	var provided []providedValue
+	provided = app.root.providedValues(provided)
+	for _, missing := range missingValues(de.cause.Error()) {
+		var n int
+		for _, s := range suggestFor(missing, provided) {
+			if n == _maxSuggestions {
+				break
+			}
+			de.suggestions = append(de.suggestions, s)
+			n++
+		}
+	}
+	return de
+}
+
+// providedValue is a value provided to a module, other than to a group.
+type providedValue struct {
+	GraphValue
+
+	Module  string // path of the module
+	Private bool
+}
+
+// providedValues appends the values provided to m and its descendants
+// to vs.
+func (m *module) providedValues(vs []providedValue) []providedValue {
+	for _, n := range m.graphNodes {
+		if n.Kind != "provide" && n.Kind != "supply" {
+			continue
+		}
+		for _, o := range n.Outputs {
+			if o.Group == "" {
+				vs = append(vs, providedValue{GraphValue: o, Module: m.path(), Private: n.Private})
+			}
+		}
+	}
+	for _, mod := range m.modules {
+		vs = mod.providedValues(vs)
+	}
+	return vs
+}
+
+// missingValues parses the values named by dig's error for missing types,
+// like `missing types: *bytes.Buffer; io.Writer[name="out"]`.
+func missingValues(msg string) []GraphValue {
+	var list string
+	for _, prefix := range _missingTypePrefixes {
+		if l, ok := strings.CutPrefix(msg, prefix); ok {
+			list = l
+			break
+		}
+	}
+	if list == "" {
+		return nil
+	}
+
+	var values []GraphValue
+	for _, key := range strings.Split(list, "; ") {
+		// Drop dig's own suggestions.
+		if i := strings.Index(key, " (did you mean "); i >= 0 {
+			key = key[:i]
+		}
+		v := GraphValue{Type: key}
+		if i := strings.Index(key, "[name="); i >= 0 && strings.HasSuffix(key, "]") {
+			if name, err := strconv.Unquote(key[i+len("[name=") : len(key)-1]); err == nil {
+				v = GraphValue{Type: key[:i], Name: name}
+			}
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+// suggestFor returns suggestions for the missing value among
+// the provided ones.
+func suggestFor(missing GraphValue, provided []providedValue) []string {
+	var (
+		suggestions []string
+		seen        = make(map[string]struct{})
+	)
+	add := func(s string) {
+		if _, ok := seen[s]; !ok {
+			seen[s] = struct{}{}
+			suggestions = append(suggestions, s)
+		}
+	}
+
+	for _, p := range provided {
+		switch {
+		case p.Type == missing.Type && p.Name == missing.Name:
+			if p.Private && p.Module != "" {
+				add(fmt.Sprintf("%v is provided with fx.Private to module %q", valueKey(missing), p.Module))
+			}
+		case p.Type == missing.Type:
+			add(fmt.Sprintf("did you mean %v?", valueKey(p.GraphValue)))
+		case p.Name == missing.Name && similarTypes(p.Type, missing.Type):
+			add(fmt.Sprintf("did you mean %v?", valueKey(p.GraphValue)))
+		}
+	}
+	return suggestions
+}
+
+// valueKey renders v the way dig renders the values it misses.
+func valueKey(v GraphValue) string {
+	if v.Name != "" {
+		return fmt.Sprintf("%v[name=%q]", v.Type, v.Name)
+	}
+	return v.Type
+}
+
+// similarTypes reports whether the names of two types are alike enough
+// that one may have been meant for the other: they have the same name in
+// different packages, or their names differ by at most two characters.
+// Pointer and value types aren't similar; dig suggests those itself.
+func similarTypes(a, b string) bool {
+	if strings.TrimLeft(a, "*") == strings.TrimLeft(b, "*") {
+		return false
+	}
+
+	// Names in different packages, like *zap.Logger and *log.Logger.
+	prefixA, nameA := splitTypeName(a)
+	prefixB, nameB := splitTypeName(b)
+	if prefixA == prefixB && strings.EqualFold(nameA, nameB) {
+		return true
+	}
+
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	if len(a) < 5 || len(b) < 5 {
+		return false
+	}
+	return editDistance(a, b) <= 2
+}
+
+// splitTypeName splits a type name like "[]*pkg.Name" into the qualifiers
+// before its package, "[]*", and its unqualified name, "Name".
+func splitTypeName(t string) (prefix, name string) {
+	name = t
+	if i := strings.LastIndex(t, "."); i >= 0 {
+		name = t[i+1:]
+	}
+	prefix = t[:len(t)-len(strings.TrimLeft(t, "*[]"))]
+	return prefix, name
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if d := prev[j] + 1; d < cur[j] {
+				cur[j] = d
+			}
+			if d := cur[j-1] + 1; d < cur[j] {
+				cur[j] = d
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
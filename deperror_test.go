@@ -0,0 +1,268 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/fx"
+)
+
+func TestDependencyErrorPath(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+	type C struct{}
+	type D struct{}
+
+	t.Run("missing type", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			fx.Module("outer",
+				fx.Provide(func(B) A { return A{} }),
+				fx.Module("inner",
+					fx.Provide(
+						fx.Annotate(func(C) B { return B{} }, fx.ParamTags(``)),
+						func(D) C { return C{} },
+					),
+				),
+			),
+			fx.Invoke(func(A) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+
+		assert.NotContains(t, err.Error(), "dependency path")
+		assert.NotContains(t, fmt.Sprintf("%v", err), "dependency path")
+
+		msg := fmt.Sprintf("%+v", err)
+		assert.Contains(t, msg, "missing type:")
+		assert.Regexp(t, `\ndependency path:\n\t\S+ \(\S+deperror_test.go:\d+\)`+
+			`\n\t└─ fx_test.A: \S+ \(\S+deperror_test.go:\d+\) in module "outer"`+
+			`\n\t   └─ fx_test.B: \S+ \(\S+deperror_test.go:\d+\) in module "outer/inner"`+
+			`\n\t      └─ fx_test.C: \S+ \(\S+deperror_test.go:\d+\) in module "outer/inner"`+
+			`\n\t         └─ missing type: fx_test.D`, msg)
+	})
+
+	t.Run("constructor error", func(t *testing.T) {
+		t.Parallel()
+
+		sadness := errors.New("great sadness")
+		app := NewForTest(t,
+			fx.Module("child",
+				fx.Provide(func() (A, error) { return A{}, sadness }),
+			),
+			fx.Invoke(func(A) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.ErrorIs(t, err, sadness)
+
+		msg := fmt.Sprintf("%+v", err)
+		assert.Regexp(t, `\n\t└─ fx_test.A: \S+ \(\S+deperror_test.go:\d+\) in module "child"`+
+			`\n\t   └─ great sadness$`, msg)
+	})
+
+	t.Run("value group", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			fx.Provide(
+				fx.Annotate(func([]B) A { return A{} }, fx.ParamTags(`group:"bs"`)),
+				fx.Annotate(func() (B, error) { return B{}, errors.New("great sadness") },
+					fx.ResultTags(`group:"bs"`)),
+			),
+			fx.Invoke(func(A) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+
+		msg := fmt.Sprintf("%+v", err)
+		assert.Regexp(t, `\n\t└─ fx_test.A: \S+ \(\S+deperror_test.go:\d+\)`+
+			`\n\t   └─ fx_test.B\[group="bs"\]: \S+ \(\S+deperror_test.go:\d+\)`+
+			`\n\t      └─ great sadness$`, msg)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			fx.Module("outer",
+				fx.Provide(func(B) A { return A{} }),
+				fx.Module("inner",
+					fx.Provide(func(A) B { return B{} }),
+				),
+			),
+			fx.Invoke(func(A) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+
+		msg := fmt.Sprintf("%+v", err)
+		assert.Contains(t, msg, "cycle detected in dependency graph")
+		assert.Regexp(t, `provided by \S+ \(\S+deperror_test.go:\d+\) in module "outer"\n`, msg)
+		assert.Regexp(t, `depends on .* \(\S+deperror_test.go:\d+\) in module "outer/inner"\n`, msg)
+	})
+
+	t.Run("invoke error is unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		sadness := errors.New("great sadness")
+		app := NewForTest(t,
+			fx.Invoke(func() error { return sadness }),
+		)
+		assert.Equal(t, sadness, app.Err())
+	})
+}
+
+// TestDependencyErrorDigMessages pins the messages of dig's errors
+// that the dependency path is read from.
+// If it fails after upgrading dig, update the prefixes in deperror.go.
+func TestDependencyErrorDigMessages(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+	type C struct{}
+	type D struct{}
+	type groupParams struct {
+		dig.In
+
+		Cs []C `group:"cs"`
+	}
+	type groupResult struct {
+		dig.Out
+
+		C C `group:"cs"`
+	}
+
+	c := dig.New()
+	require.NoError(t, c.Provide(func(B) A { return A{} }))
+	require.NoError(t, c.Provide(func(groupParams) B { return B{} }))
+	require.NoError(t, c.Provide(func() (groupResult, error) {
+		return groupResult{}, errors.New("great sadness")
+	}))
+
+	err := c.Invoke(func(A) {})
+	require.Error(t, err)
+	fn := `\S+ \(\S+deperror_test.go:\d+\)`
+	assert.Regexp(t, `^could not build arguments for function `+fn+
+		`: failed to build fx_test.A`+
+		`: could not build arguments for function `+fn+
+		`: failed to build fx_test.B`+
+		`: could not build arguments for function `+fn+
+		`: could not build value group fx_test.C\[group="cs"\]`+
+		`: received non-nil error from function `+fn+
+		`: great sadness$`, err.Error())
+
+	err = c.Invoke(func(D) {})
+	require.Error(t, err)
+	assert.Regexp(t, `^missing dependencies for function `+fn+
+		`: missing type: fx_test.D$`, err.Error())
+}
+
+type suggestedLogger struct{}
+
+type suggestedLoger struct{}
+
+func TestDependencyErrorSuggestions(t *testing.T) {
+	t.Parallel()
+
+	type Config struct{}
+
+	tests := []struct {
+		desc    string
+		opts    []fx.Option
+		want    []string
+		notWant []string
+	}{
+		{
+			desc: "private to a module",
+			opts: []fx.Option{
+				fx.Module("db",
+					fx.Provide(func() *Config { return &Config{} }, fx.Private),
+				),
+				fx.Invoke(func(*Config) {}),
+			},
+			want: []string{`*fx_test.Config is provided with fx.Private to module "db"`},
+		},
+		{
+			desc: "named variant",
+			opts: []fx.Option{
+				fx.Provide(fx.Annotate(
+					func() *Config { return &Config{} },
+					fx.ResultTags(`name:"primary"`),
+				)),
+				fx.Invoke(func(*Config) {}),
+			},
+			want: []string{`did you mean *fx_test.Config[name="primary"]?`},
+		},
+		{
+			desc: "unnamed variant",
+			opts: []fx.Option{
+				fx.Provide(func() *Config { return &Config{} }),
+				fx.Invoke(fx.Annotate(func(*Config) {}, fx.ParamTags(`name:"primary"`))),
+			},
+			want: []string{`did you mean *fx_test.Config?`},
+		},
+		{
+			desc: "similar type name",
+			opts: []fx.Option{
+				fx.Provide(func() *suggestedLoger { return &suggestedLoger{} }),
+				fx.Invoke(func(*suggestedLogger) {}),
+			},
+			want: []string{`did you mean *fx_test.suggestedLoger?`},
+		},
+		{
+			desc: "nothing similar",
+			opts: []fx.Option{
+				fx.Provide(func() *suggestedLoger { return &suggestedLoger{} }),
+				fx.Invoke(func(*Config) {}),
+			},
+			notWant: []string{"did you mean", "fx.Private"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := NewForTest(t, tt.opts...).Err()
+			require.Error(t, err)
+
+			for _, s := range tt.want {
+				assert.Contains(t, err.Error(), s)
+				assert.Contains(t, fmt.Sprintf("%+v", err), "suggestions:\n\t- "+s)
+			}
+			for _, s := range tt.notWant {
+				assert.NotContains(t, err.Error(), s)
+			}
+		})
+	}
+}
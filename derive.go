@@ -0,0 +1,120 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// SupplyDerived provides a value computed from other values in the
+// container, such as a configuration assembled from several supplied
+// fragments.
+//
+//	fx.Supply(dbConfig, cacheConfig),
+//	fx.SupplyDerived(func(db DBConfig, cache CacheConfig) StorageConfig {
+//		return StorageConfig{DB: db, Cache: cache}
+//	}),
+//
+// The function must be a pure derivation that returns exactly one value,
+// optionally followed by an error. It is provided like a constructor
+// passed to [Provide], but it is reported as "derived" in Fx events.
+//
+// Unlike constructors, which are only called if a value they produce
+// is needed, derivations are evaluated eagerly: they run when the
+// application is built, after all constructors and decorators were
+// registered and before any [Invoke] runs.
+// A derivation that fails causes [New] to fail.
+func SupplyDerived(fn interface{}) Option {
+	return supplyDerivedOption{
+		Target: fn,
+		Stack:  fxreflect.CallerStack(1, 0),
+	}
+}
+
+type supplyDerivedOption struct {
+	Target interface{}
+	Stack  fxreflect.Stack
+}
+
+func (o supplyDerivedOption) apply(m *module) {
+	ft := reflect.TypeOf(o.Target)
+	switch {
+	case ft == nil || ft.Kind() != reflect.Func:
+		m.app.err = fmt.Errorf("fx.SupplyDerived(%v) from:\n%+vFailed: must be a function, got %T",
+			fxreflect.FuncName(o.Target), o.Stack, o.Target)
+		return
+	case ft.NumOut() == 0 || ft.NumOut() > 2 ||
+		ft.Out(0) == _typeOfError ||
+		(ft.NumOut() == 2 && ft.Out(1) != _typeOfError):
+		m.app.err = fmt.Errorf("fx.SupplyDerived(%v) from:\n%+vFailed: "+
+			"must return exactly one value, optionally followed by an error",
+			fxreflect.FuncName(o.Target), o.Stack)
+		return
+	}
+
+	m.provides = append(m.provides, provide{
+		Target:    o.Target,
+		Stack:     o.Stack,
+		IsDerived: true,
+	})
+}
+
+func (o supplyDerivedOption) String() string {
+	return fmt.Sprintf("fx.SupplyDerived(%v)", fxreflect.FuncName(o.Target))
+}
+
+// deriveAll evaluates the values provided with SupplyDerived
+// by m and its descendants.
+func (m *module) deriveAll() error {
+	for _, p := range m.provides {
+		if !p.IsDerived {
+			continue
+		}
+		if err := m.derive(p); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range m.modules {
+		if err := m.deriveAll(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// derive builds the value provided by p.
+func (m *module) derive(p provide) error {
+	typ := reflect.TypeOf(p.Target).Out(0)
+	fn := reflect.MakeFunc(
+		reflect.FuncOf([]reflect.Type{typ}, nil, false),
+		func([]reflect.Value) []reflect.Value { return nil },
+	)
+	if err := m.scope.Invoke(fn.Interface()); err != nil {
+		return fmt.Errorf("fx.SupplyDerived(%v) from:\n%+vFailed: %w",
+			fxreflect.FuncName(p.Target), p.Stack, err)
+	}
+	return nil
+}
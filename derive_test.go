@@ -0,0 +1,168 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestSupplyDerived(t *testing.T) {
+	t.Parallel()
+
+	type (
+		dbConfig      struct{ Addr string }
+		cacheConfig   struct{ Size int }
+		storageConfig struct {
+			DB    dbConfig
+			Cache cacheConfig
+		}
+	)
+
+	t.Run("derived from supplies", func(t *testing.T) {
+		t.Parallel()
+
+		var got storageConfig
+		app := fxtest.New(t,
+			fx.Supply(dbConfig{Addr: "localhost"}, cacheConfig{Size: 42}),
+			fx.SupplyDerived(func(db dbConfig, cache cacheConfig) storageConfig {
+				return storageConfig{DB: db, Cache: cache}
+			}),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, storageConfig{
+			DB:    dbConfig{Addr: "localhost"},
+			Cache: cacheConfig{Size: 42},
+		}, got)
+	})
+
+	t.Run("eager", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		app, spy := NewSpied(
+			fx.Supply(dbConfig{}),
+			fx.Module("child",
+				fx.SupplyDerived(func(db dbConfig) storageConfig {
+					calls = append(calls, "derive")
+					return storageConfig{DB: db}
+				}),
+			),
+			fx.Invoke(func() { calls = append(calls, "invoke") }),
+		)
+		require.NoError(t, app.Err())
+		assert.Equal(t, []string{"derive", "invoke"}, calls,
+			"derivation must run before invokes even if nothing consumes it")
+
+		provided := spy.Events().SelectByTypeName("Provided")
+		var derived []string
+		for _, e := range provided {
+			if e := e.(*fxevent.Provided); e.Derived {
+				derived = append(derived, e.OutputTypeNames...)
+			}
+		}
+		assert.Equal(t, []string{"fx_test.storageConfig"}, derived)
+
+		for _, e := range spy.Events().SelectByTypeName("Run") {
+			if e := e.(*fxevent.Run); e.ModuleName == "child" {
+				assert.Equal(t, "derive", e.Kind)
+			}
+		}
+	})
+
+	t.Run("derivation fails", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			fx.SupplyDerived(func() (storageConfig, error) {
+				return storageConfig{}, errors.New("great sadness")
+			}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.SupplyDerived(")
+		assert.Contains(t, err.Error(), "great sadness")
+	})
+
+	t.Run("missing dependency", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			fx.SupplyDerived(func(db dbConfig) storageConfig {
+				return storageConfig{DB: db}
+			}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing type: fx_test.dbConfig")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc string
+			give interface{}
+			want string
+		}{
+			{
+				desc: "not a function",
+				give: storageConfig{},
+				want: "must be a function, got fx_test.storageConfig",
+			},
+			{
+				desc: "no results",
+				give: func(dbConfig) {},
+				want: "must return exactly one value, optionally followed by an error",
+			},
+			{
+				desc: "only an error",
+				give: func(dbConfig) error { return nil },
+				want: "must return exactly one value, optionally followed by an error",
+			},
+			{
+				desc: "two values",
+				give: func() (dbConfig, cacheConfig) { return dbConfig{}, cacheConfig{} },
+				want: "must return exactly one value, optionally followed by an error",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := NewForTest(t, fx.SupplyDerived(tt.give))
+				err := app.Err()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.want)
+			})
+		}
+	})
+}
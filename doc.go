@@ -171,6 +171,16 @@
 //		// ...
 //	}
 //
+// A single optional dependency can also be declared as a parameter of type
+// fx.Optional, without a parameter struct.
+//
+//	func NewUserGateway(conn *sql.DB, cache fx.Optional[*redis.Client]) *UserGateway {
+//		if c, ok := cache.Get(); ok {
+//			// ...
+//		}
+//		// ...
+//	}
+//
 // Constructors that declare optional dependencies MUST gracefully handle
 // situations in which those dependencies are absent.
 //
@@ -335,6 +345,35 @@
 //		// Consumed as []Handler in ServerParams.
 //	}
 //
+// # Value group priorities
+//
+// For value groups whose order matters, like middleware chains,
+// a result tag may give the value a priority with the `,priority=N` option.
+// Consumers of such a group receive its values sorted by priority,
+// highest first, with values contributed without a priority
+// at priority 0. Values with the same priority are unordered,
+// unless the application is created with fx.StableGroups,
+// which orders them by the order their constructors were provided in.
+//
+//	type AuthResult struct {
+//		fx.Out
+//
+//		Middleware Middleware `group:"middleware,priority=10"`
+//	}
+//
+//	fx.Provide(
+//		NewAuth,
+//		fx.Annotate(NewRecover, fx.ResultTags(`group:"middleware,priority=-1"`)),
+//	)
+//
+// Values of a sorted group, including every group contributed to at the
+// top level with fx.StableGroups, must be provided to the top level of the
+// application: they can't be provided with fx.Private, to App.Try,
+// or to App.NewScope.
+// Fx sorts the group with a decorator at the top level of the
+// application, so such a group can't be decorated there too,
+// although modules may decorate it.
+//
 // # Unexported fields
 //
 // By default, a type that embeds fx.In may not have any unexported fields. The
@@ -0,0 +1,183 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// dotGraphWriter renders the modules of an application as a DOT graph.
+//
+// Modules are rendered as clusters containing the clusters of their
+// constructors, which contain the values the constructors produce.
+// Decorators are rendered as hexagons, and value groups as diamonds
+// connected to their members by edges labeled with the group name.
+//
+// Edges are written after all clusters so that Graphviz places each
+// value in the cluster of the constructor that produces it.
+type dotGraphWriter struct {
+	w io.Writer
+
+	ctors, decorators, modules int
+	groups                     []groupKey
+	groupMembers               map[groupKey]int
+	edges                      []string
+}
+
+type groupKey struct{ Type, Name string }
+
+func (k groupKey) id() string {
+	return fmt.Sprintf("[type=%v group=%v]", k.Type, k.Name)
+}
+
+func writeDotGraph(w io.Writer, root *module) {
+	gw := dotGraphWriter{w: w, groupMembers: make(map[groupKey]int)}
+	io.WriteString(w, "digraph {\n\trankdir=RL;\n\tgraph [compound=true];\n")
+	gw.writeModuleContents(root, "\t")
+	for _, g := range gw.groups {
+		fmt.Fprintf(w, "\t%q [shape=diamond label=<%v<BR /><FONT POINT-SIZE=\"10\">Group: %v</FONT>>];\n",
+			g.id(), html.EscapeString(g.Type), html.EscapeString(g.Name))
+	}
+	for _, e := range gw.edges {
+		fmt.Fprintf(w, "\t%v;\n", e)
+	}
+	io.WriteString(w, "}\n")
+}
+
+func (gw *dotGraphWriter) writeModuleContents(m *module, indent string) {
+	for _, n := range m.graphNodes {
+		switch n.Kind {
+		case "invoke":
+			// dig.Visualize doesn't render invoked functions either.
+		case "decorate", "replace":
+			gw.writeDecorator(n, indent)
+		default:
+			gw.writeConstructor(n, indent)
+		}
+	}
+	for _, mod := range m.modules {
+		if !mod.hasGraphNodes() {
+			continue
+		}
+		fmt.Fprintf(gw.w, "%vsubgraph cluster_module_%d {\n", indent, gw.modules)
+		gw.modules++
+		fmt.Fprintf(gw.w, "%v\tlabel = %q;\n%v\tstyle = dashed;\n", indent, mod.name, indent)
+		gw.writeModuleContents(mod, indent+"\t")
+		fmt.Fprintf(gw.w, "%v}\n", indent)
+	}
+}
+
+func (gw *dotGraphWriter) writeConstructor(n graphNode, indent string) {
+	index := gw.ctors
+	gw.ctors++
+	node := fmt.Sprintf("constructor_%d", index)
+
+	fmt.Fprintf(gw.w, "%vsubgraph cluster_%d {\n", indent, index)
+	fmt.Fprintf(gw.w, "%v\t%v [shape=plaintext label=%q];\n", indent, node, n.Name)
+	for _, o := range n.Outputs {
+		var id, label string
+		switch {
+		case len(o.Group) > 0:
+			g := gw.group(o.Type, o.Group)
+			id = fmt.Sprintf("%v[group=%v]%d", o.Type, o.Group, gw.groupMembers[g])
+			gw.groupMembers[g]++
+			label = fmt.Sprintf(`<%v<BR /><FONT POINT-SIZE="10">Group: %v</FONT>>`,
+				html.EscapeString(o.Type), html.EscapeString(o.Group))
+			gw.edges = append(gw.edges, fmt.Sprintf("%q -> %q [label=%q]", g.id(), id, o.Group))
+		case len(o.Name) > 0:
+			id = valueID(o)
+			label = fmt.Sprintf(`<%v<BR /><FONT POINT-SIZE="10">Name: %v</FONT>>`,
+				html.EscapeString(o.Type), html.EscapeString(o.Name))
+		default:
+			id = valueID(o)
+			label = fmt.Sprintf("<%v>", html.EscapeString(o.Type))
+		}
+		fmt.Fprintf(gw.w, "%v\t%q [label=%v];\n", indent, id, label)
+	}
+	fmt.Fprintf(gw.w, "%v}\n", indent)
+
+	for _, in := range n.Inputs {
+		attrs := fmt.Sprintf("ltail=cluster_%d", index)
+		if in.Optional {
+			attrs += " style=dashed"
+		}
+		gw.edges = append(gw.edges, gw.inputEdge(node, in, attrs))
+	}
+}
+
+func (gw *dotGraphWriter) writeDecorator(n graphNode, indent string) {
+	node := fmt.Sprintf("decorator_%d", gw.decorators)
+	gw.decorators++
+	fmt.Fprintf(gw.w, "%v%v [shape=hexagon label=%q];\n", indent, node, n.Name)
+
+	decorated := make(map[GraphValue]struct{}, len(n.Outputs))
+	for _, o := range n.Outputs {
+		decorated[o] = struct{}{}
+		gw.edges = append(gw.edges, gw.inputEdge(node, o, `style=bold label="decorates"`))
+	}
+	for _, in := range n.Inputs {
+		in.Optional = false
+		if _, ok := decorated[in]; ok {
+			continue
+		}
+		gw.edges = append(gw.edges, gw.inputEdge(node, in, ""))
+	}
+}
+
+// inputEdge returns an edge from node to the value it depends on.
+// Value groups are consumed as a slice of their element type.
+func (gw *dotGraphWriter) inputEdge(node string, in GraphValue, attrs string) string {
+	id := valueID(in)
+	if len(in.Group) > 0 {
+		g := gw.group(strings.TrimPrefix(in.Type, "[]"), in.Group)
+		id = g.id()
+		if !strings.Contains(attrs, "label=") {
+			attrs = strings.TrimSpace(fmt.Sprintf("%v label=%q", attrs, in.Group))
+		}
+	}
+	if len(attrs) == 0 {
+		return fmt.Sprintf("%v -> %q", node, id)
+	}
+	return fmt.Sprintf("%v -> %q [%v]", node, id, attrs)
+}
+
+// group returns the key of the given value group, recording it
+// to be rendered if it's the first time it's seen.
+func (gw *dotGraphWriter) group(typ, name string) groupKey {
+	g := groupKey{Type: typ, Name: name}
+	if _, ok := gw.groupMembers[g]; !ok {
+		gw.groupMembers[g] = 0
+		gw.groups = append(gw.groups, g)
+	}
+	return g
+}
+
+// valueID returns the ID of the node of a value that's not in a group,
+// matching the IDs used by dig.Visualize.
+func valueID(v GraphValue) string {
+	if len(v.Name) > 0 {
+		return fmt.Sprintf("%v[name=%v]", v.Type, v.Name)
+	}
+	return v.Type
+}
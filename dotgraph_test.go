@@ -0,0 +1,66 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDotGraphModules(t *testing.T) {
+	t.Parallel()
+
+	type config struct{}
+	type server struct{}
+
+	var g DotGraph
+	app := New(
+		NopLogger,
+		Provide(func() *config { return &config{} }),
+		Module("server",
+			Provide(
+				Annotate(func(*config) int { return 1 }, ResultTags(`group:"ports"`)),
+				Annotate(func([]int) *server { return &server{} }, ParamTags(`group:"ports"`)),
+			),
+			Decorate(func(c *config) *config { return c }),
+			Module("inner", Supply(Annotated{Name: "name", Target: "value"})),
+		),
+		Replace(&config{}),
+		Populate(&g),
+	)
+	require.NoError(t, app.Err())
+
+	for _, want := range []string{
+		"\tsubgraph cluster_module_0 {\n\t\tlabel = \"server\";\n\t\tstyle = dashed;\n",
+		"\t\tsubgraph cluster_module_1 {\n\t\t\tlabel = \"inner\";\n",
+		`"string[name=name]" [label=<string<BR /><FONT POINT-SIZE="10">Name: name</FONT>>];`,
+		`decorator_0 [shape=hexagon label="fx.Replace(*fx.config)"];`,
+		`decorator_0 -> "*fx.config" [style=bold label="decorates"];`,
+		`decorator_1 -> "*fx.config" [style=bold label="decorates"];`,
+		`"[type=int group=ports]" [shape=diamond label=<int<BR /><FONT POINT-SIZE="10">Group: ports</FONT>>];`,
+		`"[type=int group=ports]" -> "int[group=ports]0" [label="ports"];`,
+		`constructor_5 -> "[type=int group=ports]" [ltail=cluster_5 label="ports"];`,
+	} {
+		assert.Contains(t, string(g), want)
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/dig"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/internal/lifecycle"
+)
+
+// ProvideError describes the failure of a constructor:
+// it couldn't be provided,
+// or it returned an error when it was called to build
+// the dependencies of an invoked function.
+type ProvideError struct {
+	// Constructor is the name of the constructor.
+	Constructor string
+
+	// Module is the path of the module the constructor was provided to,
+	// if known, as in [ModuleError].
+	Module string
+
+	// Err is the error that occurred.
+	Err error
+}
+
+func (e *ProvideError) Error() string { return e.Err.Error() }
+
+// Unwrap returns the error that occurred.
+func (e *ProvideError) Unwrap() error { return e.Err }
+
+// Format implements fmt.Formatter by formatting the error that occurred.
+func (e *ProvideError) Format(w fmt.State, c rune) {
+	fmt.Fprintf(w, fmt.FormatString(w, c), e.Err)
+}
+
+// InvokeError describes the failure of a function passed to [Invoke]:
+// it returned an error, or its dependencies were missing.
+// Failures of the constructors it depends on are [ProvideError]s instead.
+type InvokeError struct {
+	// Function is the name of the invoked function.
+	Function string
+
+	// Module is the path of the module the function was passed to,
+	// as in [ModuleError].
+	Module string
+
+	// Err is the error that occurred.
+	Err error
+}
+
+func (e *InvokeError) Error() string { return e.Err.Error() }
+
+// Unwrap returns the error that occurred.
+func (e *InvokeError) Unwrap() error { return e.Err }
+
+// Format implements fmt.Formatter by formatting the error that occurred.
+func (e *InvokeError) Format(w fmt.State, c rune) {
+	fmt.Fprintf(w, fmt.FormatString(w, c), e.Err)
+}
+
+// HookError describes the failure of an OnStart or OnStop hook.
+// Its Hook field is "OnStart" or "OnStop",
+// Function names the hook's function, or the name given to the hook,
+// and Caller names the function that appended the hook.
+// Hooks that don't finish in time are [TimeoutError]s instead.
+type HookError = lifecycle.HookError
+
+// TimeoutError describes an OnStart or OnStop hook that didn't finish
+// within its timeout, that of its module, or the deadline of the context
+// given to [App.Start] or [App.Stop].
+// Its Hook, Function, and Caller fields name the hook as in [HookError],
+// and are empty if no hook was running.
+// Timeout is the timeout of the hook or of its module,
+// or zero for the deadline of the context.
+type TimeoutError = lifecycle.TimeoutError
+
+// newInvokeError describes the failure with err of the function fn,
+// invoked in mod.
+// If a constructor failed instead, it returns a ProvideError.
+func newInvokeError(fn string, mod *module, err error) error {
+	var de *dependencyError
+	if errors.As(err, &de) && de.constructorFailed && len(de.steps) > 1 {
+		step := de.steps[len(de.steps)-1]
+		return &ProvideError{
+			Constructor: strings.TrimSpace(_funcLocation.ReplaceAllString(step.Function, "")),
+			Module:      step.Module,
+			Err:         err,
+		}
+	}
+
+	var path string
+	if mod != nil {
+		path = mod.path()
+	}
+	return &InvokeError{Function: fn, Module: path, Err: err}
+}
+
+// withError returns a copy of failure, a ProvideError or an InvokeError,
+// that wraps err instead.
+// If failure is neither, withError returns err.
+func withError(failure, err error) error {
+	switch f := failure.(type) {
+	case *ProvideError:
+		f2 := *f
+		f2.Err = err
+		return &f2
+	case *InvokeError:
+		f2 := *f
+		f2.Err = err
+		return &f2
+	}
+	return err
+}
+
+// handleLifecycleError runs the error hooks for err,
+// the error of starting or stopping the application,
+// once for each hook that failed, or once with err if none did.
+// hook is "OnStart" or "OnStop", and ctx is the context of the attempt.
+func (app *App) handleLifecycleError(ctx context.Context, hook string, err error) {
+	failures := app.lifecycle.TakeFailures()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) && !containsTimeout(failures) {
+		failures = append(failures, app.lifecycle.TimedOut(hook, ctx.Err()))
+	}
+	if len(failures) == 0 {
+		failures = []error{err}
+	}
+	for _, f := range failures {
+		app.handleError(f, nil)
+	}
+}
+
+func containsTimeout(errs []error) bool {
+	for _, err := range errs {
+		var te *TimeoutError
+		if errors.As(err, &te) {
+			return true
+		}
+	}
+	return false
+}
+
+// PanicError is the error of [App.Err] when a function given to Fx
+// panicked and [RecoverFromPanics] recovered from it.
+// Its message is that of the error it wraps,
+// which also contains the [dig.PanicError] of the panic.
+type PanicError struct {
+	// Function is the name of the function that panicked.
+	Function string
+
+	// Value is the value the function panicked with.
+	Value interface{}
+
+	// Stack is the stack of the goroutine that panicked,
+	// as formatted by [runtime/debug.Stack], at the time of the panic.
+	// It's only captured for constructors,
+	// and is nil for decorators and invoked functions.
+	Stack []byte
+
+	// Err is the error that occurred.
+	Err error
+}
+
+func (e *PanicError) Error() string { return e.Err.Error() }
+
+// Unwrap returns the error that occurred.
+func (e *PanicError) Unwrap() error { return e.Err }
+
+// Format implements fmt.Formatter by formatting the error that occurred.
+func (e *PanicError) Format(w fmt.State, c rune) {
+	fmt.Fprintf(w, fmt.FormatString(w, c), e.Err)
+}
+
+// recoverPanic reports that the function name, passed to m with the given
+// kind of option, panicked, as dig reported with pe,
+// and remembers the panic for attachPanic.
+// stack is the stack of the panic, if known.
+func (m *module) recoverPanic(kind, name string, pe dig.PanicError, stack []byte) {
+	m.app.recovered = &PanicError{
+		Function: name,
+		Value:    pe.Panic,
+		Stack:    stack,
+		Err:      pe,
+	}
+	m.log.LogEvent(&fxevent.Panicked{
+		Name:        name,
+		Kind:        kind,
+		ModuleName:  m.name,
+		ModuleOwner: m.owner(),
+		Value:       pe.Panic,
+		Stack:       string(stack),
+		Err:         pe,
+	})
+}
+
+// attachPanic wraps err in a PanicError
+// if it was caused by the last panic recovered from.
+func (app *App) attachPanic(err error) error {
+	var pe dig.PanicError
+	if app.recovered == nil || !errors.As(err, &pe) {
+		return err
+	}
+
+	wrapped := *app.recovered
+	wrapped.Err = err
+	app.recovered = nil
+	return &wrapped
+}
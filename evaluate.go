@@ -0,0 +1,172 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// Evaluate registers functions that return options to apply to the
+// application, so that the options may depend on values in the container.
+// For example, an application may decide which backends to register
+// based on its configuration:
+//
+//	fx.Evaluate(func(cfg *Config) fx.Option {
+//		if cfg.Cache == "redis" {
+//			return fx.Provide(NewRedisCache)
+//		}
+//		return fx.Provide(NewMemoryCache)
+//	})
+//
+// Each function must return an [Option], and optionally an error.
+// Its parameters are built with the constructors registered by [Provide],
+// like those of functions passed to [Invoke].
+//
+// Evaluated functions run after every constructor of the application has
+// been provided, in the order they were registered, with those of a module
+// before those of the modules it includes. They run before decorators are
+// registered, so they receive the values built by constructors as they are.
+// The returned options are applied as if they had been passed alongside
+// Evaluate: constructors they provide and modules they include are
+// available to the rest of the application, and their decorators and
+// invocations run with the others.
+// Options that configure the application itself, like [WithLogger] or
+// [StartTimeout], should not be returned by Evaluate.
+//
+// With [ValidateApp], evaluated functions are not called,
+// so the options they'd return are not validated.
+func Evaluate(fns ...interface{}) Option {
+	return evaluateOption{
+		Targets: fns,
+		Stack:   fxreflect.CallerStack(1, 0),
+	}
+}
+
+type evaluateOption struct {
+	Targets []interface{}
+	Stack   fxreflect.Stack
+}
+
+func (o evaluateOption) apply(mod *module) {
+	for _, target := range o.Targets {
+		mod.evaluates = append(mod.evaluates, evaluate{
+			Target: target,
+			Stack:  o.Stack,
+		})
+	}
+}
+
+func (o evaluateOption) String() string {
+	items := make([]string, len(o.Targets))
+	for i, f := range o.Targets {
+		items[i] = fxreflect.FuncName(f)
+	}
+	return fmt.Sprintf("fx.Evaluate(%s)", strings.Join(items, ", "))
+}
+
+// evaluate is a function registered with Evaluate.
+type evaluate struct {
+	// Function that returns the options to apply.
+	Target interface{}
+
+	// Stack trace of where this function was registered.
+	Stack fxreflect.Stack
+}
+
+var _typeOfOption = reflect.TypeOf((*Option)(nil)).Elem()
+
+// evaluateAll runs the functions registered with Evaluate in m and the
+// modules it contains, including those added by the options they return.
+func (m *module) evaluateAll() error {
+	// The options returned by these functions may register more functions,
+	// and include more modules, so both lists may grow as we go.
+	for i := 0; i < len(m.evaluates); i++ {
+		if err := m.evaluate(m.evaluates[i]); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < len(m.modules); i++ {
+		if err := m.modules[i].evaluateAll(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *module) evaluate(e evaluate) error {
+	fnName := fxreflect.FuncName(e.Target)
+	fail := func(err error) error {
+		return fmt.Errorf("fx.Evaluate(%v) from:\n%+vFailed: %w", fnName, e.Stack, err)
+	}
+
+	if err := checkEvaluate(e.Target); err != nil {
+		return fail(err)
+	}
+	var opt Option
+	target, err := invokeInto(e.Target, []interface{}{&opt})
+	if err != nil {
+		return fail(err)
+	}
+	if err := runInvoke(m.app.transients.container(m.scope), invoke{Target: target, Stack: e.Stack}); err != nil {
+		return fail(err)
+	}
+	if opt == nil {
+		return nil
+	}
+	m.app.evaluated = true
+
+	provides, modules := len(m.provides), len(m.modules)
+	m.applyOption(opt)
+	if m.app.err != nil {
+		return m.app.err
+	}
+	for _, p := range m.provides[provides:] {
+		m.provide(p)
+	}
+	for _, mod := range m.modules[modules:] {
+		mod.build(m.app, m.scope)
+		mod.provideAll()
+	}
+	return m.app.err
+}
+
+// checkEvaluate reports an error if fn isn't a function that returns an
+// Option and optionally an error.
+func checkEvaluate(fn interface{}) error {
+	if ann, ok := fn.(annotated); ok {
+		fn = ann.Target
+	}
+	ft := reflect.TypeOf(fn)
+	if ft == nil || ft.Kind() != reflect.Func {
+		return fmt.Errorf("%v is not a function, got %T", fn, fn)
+	}
+	switch {
+	case ft.NumOut() == 1 && ft.Out(0) == _typeOfOption:
+	case ft.NumOut() == 2 && ft.Out(0) == _typeOfOption && ft.Out(1) == _typeOfError:
+	default:
+		return fmt.Errorf("must return fx.Option and optionally an error, got %v", ft)
+	}
+	return nil
+}
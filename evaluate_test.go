@@ -0,0 +1,159 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	type config struct{ cache string }
+	type cache interface{ name() string }
+
+	t.Run("options depend on values", func(t *testing.T) {
+		t.Parallel()
+
+		for _, name := range []string{"redis", "memory"} {
+			var got string
+			app := fxtest.New(t,
+				Supply(&config{cache: name}),
+				Evaluate(func(cfg *config) Option {
+					return Provide(func() string { return cfg.cache })
+				}),
+				Invoke(func(s string) { got = s }),
+			)
+			app.RequireStart().RequireStop()
+
+			assert.Equal(t, name, got)
+		}
+	})
+
+	t.Run("modules and nested evaluates", func(t *testing.T) {
+		t.Parallel()
+
+		var got, inner []string
+		app := fxtest.New(t,
+			Supply(&config{cache: "redis"}),
+			Evaluate(func(cfg *config) (Option, error) {
+				return Module("cache",
+					Provide(func() []string { return []string{cfg.cache} }),
+					Evaluate(func(s []string) Option {
+						return Decorate(func(s []string) []string {
+							return append(s, "decorated")
+						})
+					}),
+					Invoke(func(s []string) { inner = s }),
+				), nil
+			}),
+			Invoke(func(s []string) { got = s }),
+		)
+		app.RequireStart().RequireStop()
+
+		assert.Equal(t, []string{"redis", "decorated"}, inner)
+		assert.Equal(t, []string{"redis"}, got,
+			"decorators of a module only apply within it")
+	})
+
+	t.Run("nil option", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t, Evaluate(func() Option { return nil }))
+		app.RequireStart().RequireStop()
+	})
+
+	t.Run("within module", func(t *testing.T) {
+		t.Parallel()
+
+		var got string
+		app := fxtest.New(t,
+			Module("server",
+				Supply(&config{cache: "memory"}, Private),
+				Evaluate(func(cfg *config) Option {
+					return Provide(func() string { return cfg.cache })
+				}),
+			),
+			Invoke(func(s string) { got = s }),
+		)
+		app.RequireStart().RequireStop()
+
+		assert.Equal(t, "memory", got)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc    string
+			give    Option
+			wantErr string
+		}{
+			{
+				desc:    "not a function",
+				give:    Evaluate(42),
+				wantErr: "42 is not a function, got int",
+			},
+			{
+				desc:    "wrong results",
+				give:    Evaluate(func() string { return "" }),
+				wantErr: "must return fx.Option and optionally an error, got func() string",
+			},
+			{
+				desc: "function fails",
+				give: Evaluate(func() (Option, error) {
+					return nil, errors.New("great sadness")
+				}),
+				wantErr: "great sadness",
+			},
+			{
+				desc:    "missing dependency",
+				give:    Evaluate(func(cache) Option { return nil }),
+				wantErr: "missing type: fx_test.cache",
+			},
+			{
+				desc: "returned option fails",
+				give: Evaluate(func() Option {
+					return Provide(func() string { return "" }, func() string { return "" })
+				}),
+				wantErr: "already provided",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := NewForTest(t, tt.give)
+				err := app.Err()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+}
@@ -23,7 +23,16 @@ package fxevent
 import (
 	"fmt"
 	"io"
+	"regexp"
 	"strings"
+	"time"
+)
+
+// ANSI escape sequences used by ConsoleLogger.UseColor.
+const (
+	_ansiRed    = "\x1b[31m"
+	_ansiYellow = "\x1b[33m"
+	_ansiReset  = "\x1b[0m"
 )
 
 // ConsoleLogger is an Fx event logger that attempts to write human-readable
@@ -32,32 +41,103 @@ import (
 // Use this during development.
 type ConsoleLogger struct {
 	W io.Writer
+
+	verbosity Verbosity // default: Verbose
+	color     bool
+	compact   bool
+
+	// ANSI color of the event being logged, if any
+	style string
 }
 
 var _ Logger = (*ConsoleLogger)(nil)
 
+// UseVerbosity sets which events are logged to v.
+// Events that report an error are always logged.
+func (l *ConsoleLogger) UseVerbosity(v Verbosity) {
+	l.verbosity = v
+}
+
+// UseColor sets whether events that report an error or a warning
+// are highlighted with ANSI colors: red for errors, and yellow for warnings.
+// Only enable it when W is a terminal.
+func (l *ConsoleLogger) UseColor(color bool) {
+	l.color = color
+}
+
+// UseCompact sets whether each message is written on a single line,
+// with tabs collapsed to spaces and the lines of multi-line messages,
+// such as stack traces, joined with " | ".
+func (l *ConsoleLogger) UseCompact(compact bool) {
+	l.compact = compact
+}
+
+// _consoleTabs matches the tabs that align messages,
+// and the spaces around them.
+var _consoleTabs = regexp.MustCompile(` *\t[\t ]*`)
+
 func (l *ConsoleLogger) logf(msg string, args ...interface{}) {
-	fmt.Fprintf(l.W, "[Fx] "+msg+"\n", args...)
+	line := fmt.Sprintf("[Fx] "+msg, args...)
+	if l.compact {
+		line = compactLine(line)
+	}
+	if l.style != "" {
+		line = l.style + line + _ansiReset
+	}
+	fmt.Fprintln(l.W, line)
+}
+
+// compactLine joins the non-blank lines of s with " | "
+// and collapses its tabs to single spaces.
+func compactLine(s string) string {
+	lines := strings.Split(s, "\n")
+	parts := lines[:0]
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			parts = append(parts, _consoleTabs.ReplaceAllString(line, " "))
+		}
+	}
+	return strings.Join(parts, " | ")
 }
 
 // LogEvent logs the given event to the provided Zap logger.
 func (l *ConsoleLogger) LogEvent(event Event) {
+	if !l.verbosity.Allows(event) {
+		return
+	}
+
+	// Log with a copy that knows the event's color
+	// so that concurrent events don't share it.
+	el := *l
+	el.style = ""
+	if l.color {
+		switch {
+		case isError(event):
+			el.style = _ansiRed
+		case isWarning(event):
+			el.style = _ansiYellow
+		}
+	}
+	el.logEvent(event)
+}
+
+func (l *ConsoleLogger) logEvent(event Event) {
 	switch e := event.(type) {
 	case *OnStartExecuting:
 		l.logf("HOOK OnStart\t\t%s executing (caller: %s)", e.FunctionName, e.CallerName)
 	case *OnStartExecuted:
 		if e.Err != nil {
-			l.logf("HOOK OnStart\t\t%s called by %s failed in %s: %+v", e.FunctionName, e.CallerName, e.Runtime, e.Err)
+			l.logf("HOOK OnStart\t\t%s called by %s failed in %s%s: %+v", e.FunctionName, e.CallerName, e.Runtime, hookTimeout(e.Timeout), e.Err)
 		} else {
-			l.logf("HOOK OnStart\t\t%s called by %s ran successfully in %s", e.FunctionName, e.CallerName, e.Runtime)
+			l.logf("HOOK OnStart\t\t%s called by %s ran successfully in %s%s", e.FunctionName, e.CallerName, e.Runtime, hookTimeout(e.Timeout))
 		}
 	case *OnStopExecuting:
 		l.logf("HOOK OnStop\t\t%s executing (caller: %s)", e.FunctionName, e.CallerName)
 	case *OnStopExecuted:
 		if e.Err != nil {
-			l.logf("HOOK OnStop\t\t%s called by %s failed in %s: %+v", e.FunctionName, e.CallerName, e.Runtime, e.Err)
+			l.logf("HOOK OnStop\t\t%s called by %s failed in %s%s: %+v", e.FunctionName, e.CallerName, e.Runtime, hookTimeout(e.Timeout), e.Err)
 		} else {
-			l.logf("HOOK OnStop\t\t%s called by %s ran successfully in %s", e.FunctionName, e.CallerName, e.Runtime)
+			l.logf("HOOK OnStop\t\t%s called by %s ran successfully in %s%s", e.FunctionName, e.CallerName, e.Runtime, hookTimeout(e.Timeout))
 		}
 	case *Supplied:
 		if e.Err != nil {
@@ -68,15 +148,19 @@ func (l *ConsoleLogger) LogEvent(event Event) {
 			l.logf("SUPPLY\t%v", e.TypeName)
 		}
 	case *Provided:
+		verb := "PROVIDE"
+		if e.Derived {
+			verb = "DERIVE"
+		}
 		var privateStr string
 		if e.Private {
 			privateStr = " (PRIVATE)"
 		}
 		for _, rtype := range e.OutputTypeNames {
 			if e.ModuleName != "" {
-				l.logf("PROVIDE%v\t%v <= %v from module %q", privateStr, rtype, e.ConstructorName, e.ModuleName)
+				l.logf("%v%v\t%v <= %v from module %q", verb, privateStr, rtype, e.ConstructorName, e.ModuleName)
 			} else {
-				l.logf("PROVIDE%v\t%v <= %v", privateStr, rtype, e.ConstructorName)
+				l.logf("%v%v\t%v <= %v", verb, privateStr, rtype, e.ConstructorName)
 			}
 		}
 		if e.Err != nil {
@@ -104,6 +188,13 @@ func (l *ConsoleLogger) LogEvent(event Event) {
 		if e.Err != nil {
 			l.logf("Error after options were applied: %+v", e.Err)
 		}
+	case *AmbiguousDecoration:
+		var moduleStr string
+		if e.ModuleName != "" {
+			moduleStr = fmt.Sprintf(" in module %q", e.ModuleName)
+		}
+		l.logf("WARNING\t\t%v is both provided and decorated%v\n\tprovided at %v\n\tdecorated at %v",
+			e.TypeName, moduleStr, firstFrame(e.ProvideStackTrace), firstFrame(e.DecorateStackTrace))
 	case *Run:
 		var moduleStr string
 		if e.ModuleName != "" {
@@ -113,6 +204,15 @@ func (l *ConsoleLogger) LogEvent(event Event) {
 		if e.Err != nil {
 			l.logf("Error returned: %+v", e.Err)
 		}
+	case *Panicked:
+		var moduleStr string
+		if e.ModuleName != "" {
+			moduleStr = fmt.Sprintf(" from module %q", e.ModuleName)
+		}
+		l.logf("PANIC\t\t%v: %v%v panicked: %v", e.Kind, e.Name, moduleStr, e.Value)
+		if e.Stack != "" {
+			l.logf("%v", strings.TrimRight(e.Stack, "\n"))
+		}
 
 	case *Invoking:
 		if e.ModuleName != "" {
@@ -125,22 +225,52 @@ func (l *ConsoleLogger) LogEvent(event Event) {
 			l.logf("ERROR\t\tfx.Invoke(%v) called from:\n%+vFailed: %+v", e.FunctionName, e.Trace, e.Err)
 		}
 	case *Stopping:
-		l.logf("%v", strings.ToUpper(e.Signal.String()))
+		if e.Reason != nil {
+			l.logf("%v: %+v", strings.ToUpper(e.Signal.String()), e.Reason)
+		} else {
+			l.logf("%v", strings.ToUpper(e.Signal.String()))
+		}
 	case *Stopped:
 		if e.Err != nil {
 			l.logf("ERROR\t\tFailed to stop cleanly: %+v", e.Err)
 		}
 	case *RollingBack:
-		l.logf("ERROR\t\tStart failed, rolling back: %+v", e.StartErr)
+		if len(e.FunctionName) > 0 {
+			l.logf("ERROR\t\tStart failed in %v, rolling back: %+v", e.FunctionName, e.StartErr)
+		} else {
+			l.logf("ERROR\t\tStart failed, rolling back: %+v", e.StartErr)
+		}
 	case *RolledBack:
-		if e.Err != nil {
+		switch {
+		case e.Skipped:
+			l.logf("WARNING\t\tRollback skipped, started hooks left running")
+		case e.Err != nil && e.Ignored:
+			l.logf("ERROR\t\tCouldn't roll back cleanly, ignoring: %+v", e.Err)
+		case e.Err != nil:
 			l.logf("ERROR\t\tCouldn't roll back cleanly: %+v", e.Err)
 		}
 	case *Started:
 		if e.Err != nil {
 			l.logf("ERROR\t\tFailed to start: %+v", e.Err)
 		} else {
-			l.logf("RUNNING")
+			l.logf("RUNNING\t\tstarted in %s", e.Runtime)
+		}
+	case *Restarting:
+		l.logf("RESTARTING")
+	case *Restarted:
+		if e.Err != nil {
+			l.logf("ERROR\t\tFailed to restart: %+v", e.Err)
+		} else {
+			l.logf("RUNNING\t\trestarted in %s", e.Runtime)
+		}
+	case *Reloaded:
+		switch {
+		case e.Err != nil:
+			l.logf("ERROR\t\tFailed to reload: %+v", e.Err)
+		case e.Signal != nil:
+			l.logf("RELOADED\ton %v in %s", strings.ToUpper(e.Signal.String()), e.Runtime)
+		default:
+			l.logf("RELOADED\tin %s", e.Runtime)
 		}
 	case *LoggerInitialized:
 		if e.Err != nil {
@@ -150,3 +280,21 @@ func (l *ConsoleLogger) LogEvent(event Event) {
 		}
 	}
 }
+
+// firstFrame returns the innermost frame of a stack trace,
+// which is where an option was given to Fx.
+func firstFrame(stack []string) string {
+	if len(stack) == 0 {
+		return "unknown location"
+	}
+	return stack[0]
+}
+
+// hookTimeout describes the timeout of a hook,
+// or returns an empty string if it has none.
+func hookTimeout(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (timeout %s)", d)
+}
@@ -125,6 +125,27 @@ func TestConsoleLogger(t *testing.T) {
 			},
 			want: "[Fx] HOOK OnStart		hook.onStart1 called by bytes.NewBuffer ran successfully in 3ms\n",
 		},
+		{
+			name: "OnStartExecuted/Timeout",
+			give: &OnStartExecuted{
+				FunctionName: "hook.onStart1",
+				CallerName:   "bytes.NewBuffer",
+				Runtime:      time.Millisecond * 3,
+				Timeout:      time.Second,
+			},
+			want: "[Fx] HOOK OnStart		hook.onStart1 called by bytes.NewBuffer ran successfully in 3ms (timeout 1s)\n",
+		},
+		{
+			name: "OnStopExecutedError/Timeout",
+			give: &OnStopExecuted{
+				FunctionName: "hook.onStop1",
+				CallerName:   "bytes.NewBuffer",
+				Runtime:      time.Second,
+				Timeout:      time.Second,
+				Err:          errors.New("deadline exceeded"),
+			},
+			want: "[Fx] HOOK OnStop		hook.onStop1 called by bytes.NewBuffer failed in 1s (timeout 1s): deadline exceeded\n",
+		},
 		{
 			name: "ProvideError",
 			give: &Provided{Err: errors.New("some error")},
@@ -186,6 +207,17 @@ func TestConsoleLogger(t *testing.T) {
 			},
 			want: "[Fx] PROVIDE (PRIVATE)	*bytes.Buffer <= bytes.NewBuffer()\n",
 		},
+		{
+			name: "Provided derived",
+			give: &Provided{
+				ConstructorName: "main.newConfig()",
+				OutputTypeNames: []string{"main.Config"},
+				Derived:         true,
+				StackTrace:      []string{"main.main", "runtime.main"},
+				ModuleTrace:     []string{"main.main"},
+			},
+			want: "[Fx] DERIVE	main.Config <= main.newConfig()\n",
+		},
 		{
 			name: "Provided with module",
 			give: &Provided{
@@ -291,6 +323,25 @@ func TestConsoleLogger(t *testing.T) {
 				"[Fx] Error returned: terrible constructor error",
 			),
 		},
+		{
+			name: "Panicked",
+			give: &Panicked{
+				Name:       "bytes.NewBuffer()",
+				Kind:       "provide",
+				ModuleName: "myModule",
+				Value:      "great sadness",
+				Stack:      "goroutine 1 [running]:\nmain.main()\n",
+			},
+			want: joinLines(
+				"[Fx] PANIC\t\tprovide: bytes.NewBuffer() from module \"myModule\" panicked: great sadness",
+				"[Fx] goroutine 1 [running]:\nmain.main()",
+			),
+		},
+		{
+			name: "Panicked without stack",
+			give: &Panicked{Name: "bytes.NewBuffer()", Kind: "invoke", Value: 42},
+			want: "[Fx] PANIC\t\tinvoke: bytes.NewBuffer() panicked: 42\n",
+		},
 		{
 			name: "Invoking",
 			give: &Invoking{FunctionName: "bytes.NewBuffer()"},
@@ -347,6 +398,11 @@ func TestConsoleLogger(t *testing.T) {
 			give: &Stopping{Signal: os.Interrupt},
 			want: "[Fx] INTERRUPT\n",
 		},
+		{
+			name: "Stopping/Reason",
+			give: &Stopping{Signal: os.Interrupt, Reason: errors.New("some error")},
+			want: "[Fx] INTERRUPT: some error\n",
+		},
 		{
 			name: "Stopped",
 			give: &Stopped{Err: errors.New("some error")},
@@ -377,10 +433,67 @@ func TestConsoleLogger(t *testing.T) {
 			give: &RolledBack{Err: &richError{}},
 			want: "[Fx] ERROR		Couldn't roll back cleanly: rich error\n",
 		},
+		{
+			name: "RollingBack/Hook",
+			give: &RollingBack{StartErr: errors.New("some error"), FunctionName: "hook"},
+			want: "[Fx] ERROR		Start failed in hook, rolling back: some error\n",
+		},
+		{
+			name: "RolledBack/Ignored",
+			give: &RolledBack{Err: errors.New("some error"), Ignored: true},
+			want: "[Fx] ERROR		Couldn't roll back cleanly, ignoring: some error\n",
+		},
+		{
+			name: "RolledBack/Skipped",
+			give: &RolledBack{Skipped: true},
+			want: "[Fx] WARNING		Rollback skipped, started hooks left running\n",
+		},
 		{
 			name: "Started",
-			give: &Started{},
-			want: "[Fx] RUNNING\n",
+			give: &Started{Runtime: 1200 * time.Millisecond},
+			want: "[Fx] RUNNING\t\tstarted in 1.2s\n",
+		},
+		{
+			name: "Restarting",
+			give: &Restarting{},
+			want: "[Fx] RESTARTING\n",
+		},
+		{
+			name: "Restarted",
+			give: &Restarted{Runtime: 1200 * time.Millisecond},
+			want: "[Fx] RUNNING\t\trestarted in 1.2s\n",
+		},
+		{
+			name: "Restarted/Error",
+			give: &Restarted{Err: errors.New("some error")},
+			want: "[Fx] ERROR\t\tFailed to restart: some error\n",
+		},
+		{
+			name: "Reloaded",
+			give: &Reloaded{Runtime: 1200 * time.Millisecond},
+			want: "[Fx] RELOADED\tin 1.2s\n",
+		},
+		{
+			name: "Reloaded/Signal",
+			give: &Reloaded{Signal: os.Interrupt, Runtime: 1200 * time.Millisecond},
+			want: "[Fx] RELOADED\ton INTERRUPT in 1.2s\n",
+		},
+		{
+			name: "Reloaded/Error",
+			give: &Reloaded{Err: errors.New("some error")},
+			want: "[Fx] ERROR\t\tFailed to reload: some error\n",
+		},
+		{
+			name: "AmbiguousDecoration",
+			give: &AmbiguousDecoration{
+				TypeName:           "*bytes.Buffer",
+				ModuleName:         "myModule",
+				ProvideStackTrace:  []string{"main.provide (main.go:10)", "main.main"},
+				DecorateStackTrace: []string{"main.decorate (main.go:20)", "main.main"},
+			},
+			want: "[Fx] WARNING		*bytes.Buffer is both provided and decorated in module \"myModule\"\n" +
+				"	provided at main.provide (main.go:10)\n" +
+				"	decorated at main.decorate (main.go:20)\n",
 		},
 		{
 			name: "CustomLoggerError",
@@ -412,6 +525,83 @@ func TestConsoleLogger(t *testing.T) {
 	}
 }
 
+func TestConsoleLoggerOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		color   bool
+		compact bool
+		give    Event
+		want    string
+	}{
+		{
+			name:  "color/error",
+			color: true,
+			give:  &Started{Err: errors.New("great sadness")},
+			want:  "\x1b[31m[Fx] ERROR\t\tFailed to start: great sadness\x1b[0m\n",
+		},
+		{
+			name:  "color/warning",
+			color: true,
+			give: &AmbiguousDecoration{
+				TypeName:           "*bytes.Buffer",
+				ProvideStackTrace:  []string{"main.go:10"},
+				DecorateStackTrace: []string{"main.go:20"},
+			},
+			want: "\x1b[33m[Fx] WARNING\t\t*bytes.Buffer is both provided and decorated\n" +
+				"\tprovided at main.go:10\n" +
+				"\tdecorated at main.go:20\x1b[0m\n",
+		},
+		{
+			name:  "color/success",
+			color: true,
+			give:  &Started{Runtime: time.Second},
+			want:  "[Fx] RUNNING\t\tstarted in 1s\n",
+		},
+		{
+			name:    "compact",
+			compact: true,
+			give:    &Invoking{FunctionName: "bytes.NewBuffer()", ModuleName: "buffers"},
+			want:    "[Fx] INVOKE bytes.NewBuffer() from module \"buffers\"\n",
+		},
+		{
+			name:    "compact/multi-line",
+			compact: true,
+			give: &AmbiguousDecoration{
+				TypeName:           "*bytes.Buffer",
+				ProvideStackTrace:  []string{"main.go:10"},
+				DecorateStackTrace: []string{"main.go:20"},
+			},
+			want: "[Fx] WARNING *bytes.Buffer is both provided and decorated" +
+				" | provided at main.go:10 | decorated at main.go:20\n",
+		},
+		{
+			name:    "color and compact",
+			color:   true,
+			compact: true,
+			give:    &Panicked{Kind: "invoke", Name: "main.run()", Value: "oops", Stack: "main.run()\n\tmain.go:30\n"},
+			want: "\x1b[31m[Fx] PANIC invoke: main.run() panicked: oops\x1b[0m\n" +
+				"\x1b[31m[Fx] main.run() | main.go:30\x1b[0m\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buff bytes.Buffer
+			logger := &ConsoleLogger{W: &buff}
+			logger.UseColor(tt.color)
+			logger.UseCompact(tt.compact)
+			logger.LogEvent(tt.give)
+
+			assert.Equal(t, tt.want, buff.String())
+		})
+	}
+}
+
 func joinLines(lines ...string) string {
 	return strings.Join(lines, "\n") + "\n"
 }
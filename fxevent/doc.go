@@ -50,6 +50,43 @@
 //		),
 //	)
 //
+// Similarly, if you're using log/slog,
+// you can use the [SlogLogger] implementation of the interface.
+// It logs every event as a structured record,
+// at the levels set with [SlogLogger.UseLogLevel] and
+// [SlogLogger.UseErrorLevel].
+//
+//	fx.New(
+//		fx.Provide(
+//			slog.Default, // provide a *slog.Logger
+//		),
+//		fx.WithLogger(
+//			func(log *slog.Logger) fxevent.Logger {
+//				return &fxevent.SlogLogger{Logger: log}
+//			},
+//		),
+//	)
+//
+// In the controller-runtime and Kubernetes ecosystems,
+// use the Logger implementation of the go.uber.org/fx/fxevent/fxlogr module
+// with a logr.Logger.
+// It logs errors with Logger.Error and every other event with Logger.Info.
+//
+//	fx.WithLogger(
+//		func(log logr.Logger) fxevent.Logger {
+//			return &fxlogr.Logger{Logger: log}
+//		},
+//	)
+//
+// For zerolog, use the Logger implementation
+// of the go.uber.org/fx/fxevent/fxzerolog module.
+//
+//	fx.WithLogger(
+//		func(log zerolog.Logger) fxevent.Logger {
+//			return &fxzerolog.Logger{Logger: log}
+//		},
+//	)
+//
 // # Implementing a Custom Logger
 //
 // To implement a custom logger, you need to implement the [Logger] interface.
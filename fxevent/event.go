@@ -31,23 +31,28 @@ type Event interface {
 }
 
 // Passing events by type to make Event hashable in the future.
-func (*OnStartExecuting) event()  {}
-func (*OnStartExecuted) event()   {}
-func (*OnStopExecuting) event()   {}
-func (*OnStopExecuted) event()    {}
-func (*Supplied) event()          {}
-func (*Provided) event()          {}
-func (*Replaced) event()          {}
-func (*Decorated) event()         {}
-func (*Run) event()               {}
-func (*Invoking) event()          {}
-func (*Invoked) event()           {}
-func (*Stopping) event()          {}
-func (*Stopped) event()           {}
-func (*RollingBack) event()       {}
-func (*RolledBack) event()        {}
-func (*Started) event()           {}
-func (*LoggerInitialized) event() {}
+func (*OnStartExecuting) event()    {}
+func (*OnStartExecuted) event()     {}
+func (*OnStopExecuting) event()     {}
+func (*OnStopExecuted) event()      {}
+func (*Supplied) event()            {}
+func (*Provided) event()            {}
+func (*Replaced) event()            {}
+func (*Decorated) event()           {}
+func (*Run) event()                 {}
+func (*Panicked) event()            {}
+func (*Invoking) event()            {}
+func (*Invoked) event()             {}
+func (*Stopping) event()            {}
+func (*Stopped) event()             {}
+func (*RollingBack) event()         {}
+func (*RolledBack) event()          {}
+func (*Started) event()             {}
+func (*LoggerInitialized) event()   {}
+func (*AmbiguousDecoration) event() {}
+func (*Restarting) event()          {}
+func (*Restarted) event()           {}
+func (*Reloaded) event()            {}
 
 // OnStartExecuting is emitted before an OnStart hook is executed.
 type OnStartExecuting struct {
@@ -75,6 +80,10 @@ type OnStartExecuted struct {
 	// Runtime specifies how long it took to run this hook.
 	Runtime time.Duration
 
+	// Timeout is the timeout configured for this hook with fx.Hook.Timeout,
+	// or zero if it has none.
+	Timeout time.Duration
+
 	// Err is non-nil if the hook failed to execute.
 	Err error
 }
@@ -101,6 +110,10 @@ type OnStopExecuted struct {
 	// Runtime specifies how long it took to run this hook.
 	Runtime time.Duration
 
+	// Timeout is the timeout configured for this hook with fx.Hook.Timeout,
+	// or zero if it has none.
+	Timeout time.Duration
+
 	// Err is non-nil if the hook failed to execute.
 	Err error
 }
@@ -119,6 +132,10 @@ type Supplied struct {
 	// ModuleName is the name of the module in which the value was added to.
 	ModuleName string
 
+	// ModuleOwner is the owner given to that module with fx.ModuleInfo,
+	// or to the closest module that contains it, if any.
+	ModuleOwner string
+
 	// Err is non-nil if we failed to supply the value.
 	Err error
 }
@@ -143,11 +160,19 @@ type Provided struct {
 	// provided to.
 	ModuleName string
 
+	// ModuleOwner is the owner given to that module with fx.ModuleInfo,
+	// or to the closest module that contains it, if any.
+	ModuleOwner string
+
 	// Err is non-nil if we failed to provide this constructor.
 	Err error
 
 	// Private denotes whether the provided constructor is a [Private] constructor.
 	Private bool
+
+	// Derived denotes whether the constructor was provided with
+	// [go.uber.org/fx.SupplyDerived].
+	Derived bool
 }
 
 // Replaced is emitted when a value replaces a type in Fx.
@@ -164,6 +189,10 @@ type Replaced struct {
 	// ModuleName is the name of the module in which the value was added to.
 	ModuleName string
 
+	// ModuleOwner is the owner given to that module with fx.ModuleInfo,
+	// or to the closest module that contains it, if any.
+	ModuleOwner string
+
 	// Err is non-nil if we failed to supply the value.
 	Err error
 }
@@ -183,6 +212,10 @@ type Decorated struct {
 	// ModuleName is the name of the module in which the value was added to.
 	ModuleName string
 
+	// ModuleOwner is the owner given to that module with fx.ModuleInfo,
+	// or to the closest module that contains it, if any.
+	ModuleOwner string
+
 	// OutputTypeNames is a list of names of types that are decorated by
 	// this decorator.
 	OutputTypeNames []string
@@ -191,23 +224,85 @@ type Decorated struct {
 	Err error
 }
 
+// AmbiguousDecoration is emitted when a module decorates a type that the
+// same module also provides, if this check was requested with
+// fx.WarnAmbiguousDecorations.
+// This is usually a mistake: it is easy to misread which value the
+// decorator receives, and the module's own consumers never see the
+// value as provided.
+type AmbiguousDecoration struct {
+	// TypeName is the name of the type that is both provided and decorated.
+	TypeName string
+
+	// ModuleName is the name of the module that provides and decorates
+	// the type.
+	ModuleName string
+
+	// ProvideStackTrace is the stack trace of where the type was provided.
+	ProvideStackTrace []string
+
+	// DecorateStackTrace is the stack trace of where the type was decorated.
+	DecorateStackTrace []string
+}
+
 // Run is emitted after a constructor, decorator, or supply/replace stub is run by Fx.
 type Run struct {
 	// Name is the name of the function that was run.
 	Name string
 
 	// Kind indicates which Fx option was used to pass along the function.
-	// It is either "provide", "decorate", "supply", or "replace".
+	// It is either "provide", "decorate", "supply", "replace", or "derive".
 	Kind string
 
 	// ModuleName is the name of the module in which the function belongs.
 	ModuleName string
 
+	// ModuleOwner is the owner given to that module with fx.ModuleInfo,
+	// or to the closest module that contains it, if any.
+	ModuleOwner string
+
+	// Runtime specifies how long the function took to run,
+	// not counting the functions that built its dependencies.
+	// It is only reported for constructors: functions passed to
+	// fx.Provide, and the constructors of fx.SupplyDerived,
+	// and only if the application uses fx.TimeConstructors.
+	Runtime time.Duration
+
 	// Err is non-nil if the function returned an error.
 	// If fx.RecoverFromPanics is used, this will include panics.
 	Err error
 }
 
+// Panicked is emitted when fx.RecoverFromPanics recovers from a panic
+// in a constructor, decorator, or invoked function.
+type Panicked struct {
+	// Name is the name of the function that panicked.
+	Name string
+
+	// Kind indicates which Fx option was used to pass along the function.
+	// It is either "provide", "decorate", or "invoke".
+	Kind string
+
+	// ModuleName is the name of the module in which the function belongs.
+	ModuleName string
+
+	// ModuleOwner is the owner given to that module with fx.ModuleInfo,
+	// or to the closest module that contains it, if any.
+	ModuleOwner string
+
+	// Value is the value the function panicked with.
+	Value interface{}
+
+	// Stack is the stack of the goroutine that panicked,
+	// as formatted by runtime/debug.Stack, at the time of the panic.
+	// It is only captured for constructors,
+	// and is empty for decorators and invoked functions.
+	Stack string
+
+	// Err is the error that Fx reports for the panic.
+	Err error
+}
+
 // Invoking is emitted before we invoke a function specified with fx.Invoke.
 type Invoking struct {
 	// FunctionName is the name of the function that will be invoked.
@@ -215,6 +310,10 @@ type Invoking struct {
 
 	// ModuleName is the name of the module in which the value was added to.
 	ModuleName string
+
+	// ModuleOwner is the owner given to that module with fx.ModuleInfo,
+	// or to the closest module that contains it, if any.
+	ModuleOwner string
 }
 
 // Invoked is emitted after we invoke a function specified with fx.Invoke,
@@ -226,6 +325,10 @@ type Invoked struct {
 	// ModuleName is the name of the module in which the value was added to.
 	ModuleName string
 
+	// ModuleOwner is the owner given to that module with fx.ModuleInfo,
+	// or to the closest module that contains it, if any.
+	ModuleOwner string
+
 	// Err is non-nil if the function failed to execute.
 	Err error
 
@@ -237,6 +340,9 @@ type Invoked struct {
 // Started is emitted when an application is started successfully and/or it
 // errored.
 type Started struct {
+	// Runtime specifies how long it took to run all OnStart hooks.
+	Runtime time.Duration
+
 	// Err is non-nil if the application failed to start successfully.
 	Err error
 }
@@ -247,11 +353,18 @@ type Started struct {
 type Stopping struct {
 	// Signal is the signal that caused this shutdown.
 	Signal os.Signal
+
+	// Reason is the error passed to fx.ShutdownReason
+	// by the caller of Shutdown, if any.
+	Reason error
 }
 
 // Stopped is emitted when the application has finished shutting down, whether
 // successfully or not.
 type Stopped struct {
+	// Runtime specifies how long it took to run all OnStop hooks.
+	Runtime time.Duration
+
 	// Err is non-nil if errors were encountered during shutdown.
 	Err error
 }
@@ -261,6 +374,19 @@ type Stopped struct {
 type RollingBack struct {
 	// StartErr is the error that caused this rollback.
 	StartErr error
+
+	// FunctionName is the name of the OnStart hook that failed,
+	// or empty if the failure didn't come from a hook,
+	// e.g. because the start timed out.
+	FunctionName string
+
+	// CallerName is the name of the function that appended the hook
+	// that failed.
+	CallerName string
+
+	// Policy is the name of the rollback policy in use,
+	// as set with fx.OnStartFailure.
+	Policy string
 }
 
 // RolledBack is emitted after a service has been rolled back, whether it
@@ -268,6 +394,49 @@ type RollingBack struct {
 type RolledBack struct {
 	// Err is non-nil if the rollback failed.
 	Err error
+
+	// Hooks are the names of the OnStop hooks run to roll back,
+	// in the order they ran.
+	Hooks []string
+
+	// Failed are the names of the hooks among Hooks that failed.
+	Failed []string
+
+	// Ignored is true if Err is not reported by App.Start,
+	// because rollback errors are ignored.
+	Ignored bool
+
+	// Skipped is true if the hooks that had started were left running
+	// instead of being rolled back.
+	Skipped bool
+}
+
+// Restarting is emitted when the application begins restarting with
+// App.Restart, before it is stopped.
+type Restarting struct{}
+
+// Restarted is emitted when App.Restart finishes, whether the application
+// restarted successfully or not.
+type Restarted struct {
+	// Runtime specifies how long it took to stop and start the application.
+	Runtime time.Duration
+
+	// Err is non-nil if the application failed to stop or start.
+	Err error
+}
+
+// Reloaded is emitted after the application has run its OnReload hooks,
+// whether they succeeded or not.
+type Reloaded struct {
+	// Signal is the signal that caused the reload,
+	// or nil if it was requested with App.Reload.
+	Signal os.Signal
+
+	// Runtime specifies how long it took to run all OnReload hooks.
+	Runtime time.Duration
+
+	// Err is non-nil if any OnReload hook failed.
+	Err error
 }
 
 // LoggerInitialized is emitted when a logger supplied with fx.WithLogger is
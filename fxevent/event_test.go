@@ -40,6 +40,7 @@ func TestForCoverage(t *testing.T) {
 		&Replaced{},
 		&Decorated{},
 		&Run{},
+		&Panicked{},
 		&Invoking{},
 		&Invoked{},
 		&Stopping{},
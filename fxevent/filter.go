@@ -0,0 +1,103 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxevent
+
+import "reflect"
+
+// Filter returns an Fx event logger that passes events to logger,
+// except those selected by the given options.
+// Events that report an error or a warning are always passed to logger.
+//
+// For example, the following drops the events reported for every
+// constructor, and logs invoked functions at the debug level.
+//
+//	fx.WithLogger(func(log *zap.Logger) fxevent.Logger {
+//		debug := &fxevent.ZapLogger{Logger: log}
+//		debug.UseLogLevel(zapcore.DebugLevel)
+//		return fxevent.Filter(&fxevent.ZapLogger{Logger: log},
+//			fxevent.DropEvents(&fxevent.Provided{}, &fxevent.Supplied{}, &fxevent.Run{}),
+//			fxevent.RedirectEvents(debug, &fxevent.Invoking{}, &fxevent.Invoked{}),
+//		)
+//	})
+//
+// See also [Verbosity] for the levels of detail supported
+// by the loggers in this package.
+func Filter(logger Logger, opts ...FilterOption) Logger {
+	f := &filterLogger{
+		logger: logger,
+		routes: make(map[reflect.Type]Logger),
+	}
+	for _, opt := range opts {
+		opt.apply(f)
+	}
+	return f
+}
+
+// FilterOption selects events for [Filter].
+type FilterOption interface {
+	apply(*filterLogger)
+}
+
+// DropEvents drops events of the same types as the given events,
+// such as &fxevent.Provided{}, unless they report an error or a warning.
+func DropEvents(events ...Event) FilterOption {
+	return redirectEventsOption{Events: events}
+}
+
+// RedirectEvents passes events of the same types as the given events
+// to logger instead, unless they report an error or a warning.
+// Use it to log selected events at a different level.
+func RedirectEvents(logger Logger, events ...Event) FilterOption {
+	return redirectEventsOption{Logger: logger, Events: events}
+}
+
+type redirectEventsOption struct {
+	Logger Logger // nil to drop events
+	Events []Event
+}
+
+func (o redirectEventsOption) apply(f *filterLogger) {
+	for _, e := range o.Events {
+		f.routes[reflect.TypeOf(e)] = o.Logger
+	}
+}
+
+type filterLogger struct {
+	logger Logger
+
+	// Loggers that events are passed to instead of logger, by event type.
+	// Events mapped to nil are dropped.
+	routes map[reflect.Type]Logger
+}
+
+var _ Logger = (*filterLogger)(nil)
+
+func (f *filterLogger) LogEvent(event Event) {
+	logger := f.logger
+	if !isError(event) && !isWarning(event) {
+		if l, ok := f.routes[reflect.TypeOf(event)]; ok {
+			logger = l
+		}
+	}
+	if logger != nil {
+		logger.LogEvent(event)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxevent
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	someError := errors.New("some error")
+
+	tests := []struct {
+		name         string
+		give         Event
+		wantLogged   bool
+		wantRedirect bool
+	}{
+		{name: "Provided", give: &Provided{}},
+		{name: "Supplied", give: &Supplied{}},
+		{name: "Provided/Error", give: &Provided{Err: someError}, wantLogged: true},
+		{name: "Invoking", give: &Invoking{}, wantRedirect: true},
+		{name: "Invoked/Error", give: &Invoked{Err: someError}, wantLogged: true},
+		{name: "AmbiguousDecoration", give: &AmbiguousDecoration{}, wantLogged: true},
+		{name: "Started", give: &Started{}, wantLogged: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var logged, redirected recordingLogger
+			logger := Filter(&logged,
+				DropEvents(&Provided{}, &Supplied{}, &AmbiguousDecoration{}),
+				RedirectEvents(&redirected, &Invoking{}, &Invoked{}),
+			)
+			logger.LogEvent(tt.give)
+
+			if tt.wantLogged {
+				assert.Equal(t, recordingLogger{tt.give}, logged)
+			} else {
+				assert.Empty(t, logged)
+			}
+			if tt.wantRedirect {
+				assert.Equal(t, recordingLogger{tt.give}, redirected)
+			} else {
+				assert.Empty(t, redirected)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+module go.uber.org/fx/fxevent/fxlogr
+
+go 1.20
+
+require (
+	github.com/go-logr/logr v1.4.2
+	github.com/stretchr/testify v1.8.1
+	go.uber.org/fx v1.19.2
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	go.uber.org/zap v1.26.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace go.uber.org/fx => ../..
@@ -0,0 +1,378 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package fxlogr provides an Fx event logger that logs to a logr.Logger,
+// the logging interface used by controller-runtime and Kubernetes.
+//
+//	fx.WithLogger(
+//		func(log logr.Logger) fxevent.Logger {
+//			return &fxlogr.Logger{Logger: log}
+//		},
+//	)
+//
+// It's a module of its own,
+// so that applications that don't use logr don't depend on it.
+package fxlogr
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/fx/fxevent"
+)
+
+var _ fxevent.Logger = (*Logger)(nil)
+
+// Logger is an Fx event logger that logs events to a logr.Logger,
+// the logging interface used by controller-runtime and Kubernetes.
+// Like [fxevent.ZapLogger], it logs each event as a message with structured
+// key-value pairs, such as "constructor" or "error".
+//
+// Events that report an error are logged with Logger.Error,
+// and all other events with Logger.Info.
+type Logger struct {
+	Logger logr.Logger
+
+	logLevel  int               // default: 0
+	verbosity fxevent.Verbosity // default: fxevent.Verbose
+}
+
+// UseLogLevel sets the verbosity level, as passed to Logger.V,
+// of non-error logs emitted by Fx to level.
+// Warnings are always logged at level 0.
+func (l *Logger) UseLogLevel(level int) {
+	l.logLevel = level
+}
+
+// UseVerbosity sets which events are logged to v.
+// Events that report an error are always logged.
+func (l *Logger) UseVerbosity(v fxevent.Verbosity) {
+	l.verbosity = v
+}
+
+// logrField is a key-value pair logged by Logger,
+// or nothing if skip is set.
+type logrField struct {
+	key   string
+	value interface{}
+	skip  bool
+}
+
+func logrString(key, value string) logrField {
+	return logrField{key: key, value: value}
+}
+
+func logrStrings(key string, values []string) logrField {
+	return logrField{key: key, value: values}
+}
+
+// logrKeysAndValues flattens fields into the key-value pairs
+// expected by logr.
+func logrKeysAndValues(fields []logrField) []interface{} {
+	kvs := make([]interface{}, 0, 2*len(fields))
+	for _, f := range fields {
+		if !f.skip {
+			kvs = append(kvs, f.key, f.value)
+		}
+	}
+	return kvs
+}
+
+func (l *Logger) logEvent(msg string, fields ...logrField) {
+	l.Logger.V(l.logLevel).Info(msg, logrKeysAndValues(fields)...)
+}
+
+func (l *Logger) logWarning(msg string, fields ...logrField) {
+	l.Logger.Info(msg, logrKeysAndValues(fields)...)
+}
+
+func (l *Logger) logError(err error, msg string, fields ...logrField) {
+	l.Logger.Error(err, msg, logrKeysAndValues(fields)...)
+}
+
+// LogEvent logs the given event to the provided logr logger.
+func (l *Logger) LogEvent(event fxevent.Event) {
+	if !l.verbosity.Allows(event) {
+		return
+	}
+
+	switch e := event.(type) {
+	case *fxevent.OnStartExecuting:
+		l.logEvent("OnStart hook executing",
+			logrString("callee", e.FunctionName),
+			logrString("caller", e.CallerName),
+		)
+	case *fxevent.OnStartExecuted:
+		if e.Err != nil {
+			l.logError(e.Err, "OnStart hook failed",
+				logrString("callee", e.FunctionName),
+				logrString("caller", e.CallerName),
+				logrMaybeDuration("timeout", e.Timeout),
+			)
+		} else {
+			l.logEvent("OnStart hook executed",
+				logrString("callee", e.FunctionName),
+				logrString("caller", e.CallerName),
+				logrString("runtime", e.Runtime.String()),
+				logrMaybeDuration("timeout", e.Timeout),
+			)
+		}
+	case *fxevent.OnStopExecuting:
+		l.logEvent("OnStop hook executing",
+			logrString("callee", e.FunctionName),
+			logrString("caller", e.CallerName),
+		)
+	case *fxevent.OnStopExecuted:
+		if e.Err != nil {
+			l.logError(e.Err, "OnStop hook failed",
+				logrString("callee", e.FunctionName),
+				logrString("caller", e.CallerName),
+				logrMaybeDuration("timeout", e.Timeout),
+			)
+		} else {
+			l.logEvent("OnStop hook executed",
+				logrString("callee", e.FunctionName),
+				logrString("caller", e.CallerName),
+				logrString("runtime", e.Runtime.String()),
+				logrMaybeDuration("timeout", e.Timeout),
+			)
+		}
+	case *fxevent.Supplied:
+		if e.Err != nil {
+			l.logError(e.Err, "error encountered while applying options",
+				logrString("type", e.TypeName),
+				logrStrings("stacktrace", e.StackTrace),
+				logrStrings("moduletrace", e.ModuleTrace),
+				logrMaybeModuleField(e.ModuleName),
+				logrMaybeOwnerField(e.ModuleOwner),
+			)
+		} else {
+			l.logEvent("supplied",
+				logrString("type", e.TypeName),
+				logrStrings("stacktrace", e.StackTrace),
+				logrStrings("moduletrace", e.ModuleTrace),
+				logrMaybeModuleField(e.ModuleName),
+				logrMaybeOwnerField(e.ModuleOwner),
+			)
+		}
+	case *fxevent.Provided:
+		for _, rtype := range e.OutputTypeNames {
+			l.logEvent("provided",
+				logrString("constructor", e.ConstructorName),
+				logrStrings("stacktrace", e.StackTrace),
+				logrStrings("moduletrace", e.ModuleTrace),
+				logrMaybeModuleField(e.ModuleName),
+				logrMaybeOwnerField(e.ModuleOwner),
+				logrString("type", rtype),
+				logrMaybeBool("private", e.Private),
+				logrMaybeBool("derived", e.Derived),
+			)
+		}
+		if e.Err != nil {
+			l.logError(e.Err, "error encountered while applying options",
+				logrMaybeModuleField(e.ModuleName),
+				logrMaybeOwnerField(e.ModuleOwner),
+				logrStrings("stacktrace", e.StackTrace),
+				logrStrings("moduletrace", e.ModuleTrace),
+			)
+		}
+	case *fxevent.Replaced:
+		for _, rtype := range e.OutputTypeNames {
+			l.logEvent("replaced",
+				logrStrings("stacktrace", e.StackTrace),
+				logrStrings("moduletrace", e.ModuleTrace),
+				logrMaybeModuleField(e.ModuleName),
+				logrMaybeOwnerField(e.ModuleOwner),
+				logrString("type", rtype),
+			)
+		}
+		if e.Err != nil {
+			l.logError(e.Err, "error encountered while replacing",
+				logrStrings("stacktrace", e.StackTrace),
+				logrStrings("moduletrace", e.ModuleTrace),
+				logrMaybeModuleField(e.ModuleName),
+				logrMaybeOwnerField(e.ModuleOwner),
+			)
+		}
+	case *fxevent.Decorated:
+		for _, rtype := range e.OutputTypeNames {
+			l.logEvent("decorated",
+				logrString("decorator", e.DecoratorName),
+				logrStrings("stacktrace", e.StackTrace),
+				logrStrings("moduletrace", e.ModuleTrace),
+				logrMaybeModuleField(e.ModuleName),
+				logrMaybeOwnerField(e.ModuleOwner),
+				logrString("type", rtype),
+			)
+		}
+		if e.Err != nil {
+			l.logError(e.Err, "error encountered while applying options",
+				logrStrings("stacktrace", e.StackTrace),
+				logrStrings("moduletrace", e.ModuleTrace),
+				logrMaybeModuleField(e.ModuleName),
+				logrMaybeOwnerField(e.ModuleOwner),
+			)
+		}
+	case *fxevent.AmbiguousDecoration:
+		l.logWarning("type is both provided and decorated in the same module",
+			logrString("type", e.TypeName),
+			logrMaybeModuleField(e.ModuleName),
+			logrStrings("providestacktrace", e.ProvideStackTrace),
+			logrStrings("decoratestacktrace", e.DecorateStackTrace),
+		)
+	case *fxevent.Run:
+		if e.Err != nil {
+			l.logError(e.Err, "error returned",
+				logrString("name", e.Name),
+				logrString("kind", e.Kind),
+				logrMaybeModuleField(e.ModuleName),
+				logrMaybeOwnerField(e.ModuleOwner),
+			)
+		} else {
+			l.logEvent("run",
+				logrString("name", e.Name),
+				logrString("kind", e.Kind),
+				logrMaybeModuleField(e.ModuleName),
+				logrMaybeOwnerField(e.ModuleOwner),
+			)
+		}
+	case *fxevent.Panicked:
+		l.logError(e.Err, "panic recovered",
+			logrString("name", e.Name),
+			logrString("kind", e.Kind),
+			logrMaybeModuleField(e.ModuleName),
+			logrMaybeOwnerField(e.ModuleOwner),
+			logrField{key: "value", value: e.Value},
+			logrMaybeString("stack", e.Stack),
+		)
+	case *fxevent.Invoking:
+		// Do not log stack as it will make logs hard to read.
+		l.logEvent("invoking",
+			logrString("function", e.FunctionName),
+			logrMaybeModuleField(e.ModuleName),
+			logrMaybeOwnerField(e.ModuleOwner),
+		)
+	case *fxevent.Invoked:
+		if e.Err != nil {
+			l.logError(e.Err, "invoke failed",
+				logrString("stack", e.Trace),
+				logrString("function", e.FunctionName),
+				logrMaybeModuleField(e.ModuleName),
+				logrMaybeOwnerField(e.ModuleOwner),
+			)
+		}
+	case *fxevent.Stopping:
+		l.logEvent("received signal",
+			logrString("signal", strings.ToUpper(e.Signal.String())),
+			logrMaybeErr("reason", e.Reason))
+	case *fxevent.Stopped:
+		if e.Err != nil {
+			l.logError(e.Err, "stop failed")
+		}
+	case *fxevent.RollingBack:
+		l.logError(e.StartErr, "start failed, rolling back",
+			logrMaybeString("callee", e.FunctionName),
+			logrMaybeString("caller", e.CallerName),
+			logrMaybeString("policy", e.Policy),
+		)
+	case *fxevent.RolledBack:
+		switch {
+		case e.Skipped:
+			l.logWarning("rollback skipped, started hooks left running")
+		case e.Err != nil:
+			l.logError(e.Err, "rollback failed",
+				logrMaybeStrings("hooks", e.Hooks),
+				logrMaybeStrings("failed", e.Failed),
+				logrMaybeBool("ignored", e.Ignored),
+			)
+		case len(e.Hooks) > 0:
+			l.logEvent("rolled back", logrStrings("hooks", e.Hooks))
+		}
+	case *fxevent.Started:
+		if e.Err != nil {
+			l.logError(e.Err, "start failed")
+		} else {
+			l.logEvent("started", logrString("runtime", e.Runtime.String()))
+		}
+	case *fxevent.Restarting:
+		l.logEvent("restarting")
+	case *fxevent.Restarted:
+		if e.Err != nil {
+			l.logError(e.Err, "restart failed")
+		} else {
+			l.logEvent("restarted", logrString("runtime", e.Runtime.String()))
+		}
+	case *fxevent.Reloaded:
+		if e.Err != nil {
+			l.logError(e.Err, "reload failed", logrMaybeSignal(e.Signal))
+		} else {
+			l.logEvent("reloaded", logrMaybeSignal(e.Signal), logrString("runtime", e.Runtime.String()))
+		}
+	case *fxevent.LoggerInitialized:
+		if e.Err != nil {
+			l.logError(e.Err, "custom logger initialization failed")
+		} else {
+			l.logEvent("initialized custom fxevent.Logger", logrString("function", e.ConstructorName))
+		}
+	}
+}
+
+func logrMaybeString(key, value string) logrField {
+	return logrField{key: key, value: value, skip: len(value) == 0}
+}
+
+func logrMaybeStrings(key string, values []string) logrField {
+	return logrField{key: key, value: values, skip: len(values) == 0}
+}
+
+func logrMaybeModuleField(name string) logrField {
+	return logrMaybeString("module", name)
+}
+
+func logrMaybeOwnerField(owner string) logrField {
+	return logrMaybeString("moduleOwner", owner)
+}
+
+func logrMaybeBool(key string, b bool) logrField {
+	return logrField{key: key, value: true, skip: !b}
+}
+
+func logrMaybeDuration(key string, d time.Duration) logrField {
+	if d <= 0 {
+		return logrField{key: key, skip: true}
+	}
+	return logrString(key, d.String())
+}
+
+func logrMaybeSignal(sig os.Signal) logrField {
+	if sig == nil {
+		return logrField{key: "signal", skip: true}
+	}
+	return logrString("signal", strings.ToUpper(sig.String()))
+}
+
+func logrMaybeErr(key string, err error) logrField {
+	if err == nil {
+		return logrField{key: key, skip: true}
+	}
+	return logrString(key, err.Error())
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxlogr
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxevent"
+)
+
+// logrEntry is a message logged to a logrObserver.
+type logrEntry struct {
+	Level  int // -1 for errors
+	Msg    string
+	Err    error
+	Fields map[string]interface{}
+}
+
+// logrObserver is a logr.LogSink that records what's logged to it.
+type logrObserver struct {
+	verbosity int
+	entries   []logrEntry
+}
+
+var _ logr.LogSink = (*logrObserver)(nil)
+
+func (o *logrObserver) Init(logr.RuntimeInfo) {}
+
+func (o *logrObserver) Enabled(level int) bool { return level <= o.verbosity }
+
+func (o *logrObserver) Info(level int, msg string, kvs ...interface{}) {
+	o.entries = append(o.entries, logrEntry{Level: level, Msg: msg, Fields: logrFieldMap(kvs)})
+}
+
+func (o *logrObserver) Error(err error, msg string, kvs ...interface{}) {
+	o.entries = append(o.entries, logrEntry{Level: -1, Msg: msg, Err: err, Fields: logrFieldMap(kvs)})
+}
+
+func (o *logrObserver) WithValues(...interface{}) logr.LogSink { return o }
+
+func (o *logrObserver) WithName(string) logr.LogSink { return o }
+
+func logrFieldMap(kvs []interface{}) map[string]interface{} {
+	fields := make(map[string]interface{})
+	for i := 0; i+1 < len(kvs); i += 2 {
+		fields[kvs[i].(string)] = kvs[i+1]
+	}
+	return fields
+}
+
+func TestLogrLogger(t *testing.T) {
+	t.Parallel()
+
+	someError := errors.New("some error")
+
+	tests := []struct {
+		name string
+		give fxevent.Event
+		want logrEntry
+	}{
+		{
+			name: "OnStartExecuting",
+			give: &fxevent.OnStartExecuting{FunctionName: "hook.onStart", CallerName: "bytes.NewBuffer"},
+			want: logrEntry{
+				Msg:    "OnStart hook executing",
+				Fields: map[string]interface{}{"callee": "hook.onStart", "caller": "bytes.NewBuffer"},
+			},
+		},
+		{
+			name: "OnStopExecuted/Error",
+			give: &fxevent.OnStopExecuted{
+				FunctionName: "hook.onStop",
+				CallerName:   "bytes.NewBuffer",
+				Err:          someError,
+				Timeout:      time.Second,
+			},
+			want: logrEntry{
+				Level: -1,
+				Msg:   "OnStop hook failed",
+				Err:   someError,
+				Fields: map[string]interface{}{
+					"callee":  "hook.onStop",
+					"caller":  "bytes.NewBuffer",
+					"timeout": "1s",
+				},
+			},
+		},
+		{
+			name: "Provided",
+			give: &fxevent.Provided{
+				ConstructorName: "bytes.NewBuffer()",
+				OutputTypeNames: []string{"*bytes.Buffer"},
+				ModuleName:      "myModule",
+				StackTrace:      []string{"main.main"},
+				ModuleTrace:     []string{"main.main"},
+				Private:         true,
+			},
+			want: logrEntry{
+				Msg: "provided",
+				Fields: map[string]interface{}{
+					"constructor": "bytes.NewBuffer()",
+					"type":        "*bytes.Buffer",
+					"module":      "myModule",
+					"stacktrace":  []string{"main.main"},
+					"moduletrace": []string{"main.main"},
+					"private":     true,
+				},
+			},
+		},
+		{
+			name: "AmbiguousDecoration",
+			give: &fxevent.AmbiguousDecoration{
+				TypeName:           "*bytes.Buffer",
+				ProvideStackTrace:  []string{"main.provide"},
+				DecorateStackTrace: []string{"main.decorate"},
+			},
+			want: logrEntry{
+				Msg: "type is both provided and decorated in the same module",
+				Fields: map[string]interface{}{
+					"type":               "*bytes.Buffer",
+					"providestacktrace":  []string{"main.provide"},
+					"decoratestacktrace": []string{"main.decorate"},
+				},
+			},
+		},
+		{
+			name: "Stopping",
+			give: &fxevent.Stopping{Signal: os.Interrupt},
+			want: logrEntry{
+				Msg:    "received signal",
+				Fields: map[string]interface{}{"signal": "INTERRUPT"},
+			},
+		},
+		{
+			name: "Started/Error",
+			give: &fxevent.Started{Err: someError},
+			want: logrEntry{
+				Level:  -1,
+				Msg:    "start failed",
+				Err:    someError,
+				Fields: map[string]interface{}{},
+			},
+		},
+		{
+			name: "LoggerInitialized",
+			give: &fxevent.LoggerInitialized{ConstructorName: "bytes.NewBuffer()"},
+			want: logrEntry{
+				Msg:    "initialized custom fxevent.Logger",
+				Fields: map[string]interface{}{"function": "bytes.NewBuffer()"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sink := &logrObserver{}
+			(&Logger{Logger: logr.New(sink)}).LogEvent(tt.give)
+
+			require.Len(t, sink.entries, 1)
+			assert.Equal(t, tt.want, sink.entries[0])
+		})
+	}
+
+	t.Run("log level", func(t *testing.T) {
+		t.Parallel()
+
+		sink := &logrObserver{verbosity: 1}
+		l := &Logger{Logger: logr.New(sink)}
+		l.UseLogLevel(1)
+		l.LogEvent(&fxevent.Started{})
+		l.LogEvent(&fxevent.AmbiguousDecoration{TypeName: "*bytes.Buffer"})
+		l.LogEvent(&fxevent.Started{Err: someError})
+
+		require.Len(t, sink.entries, 3)
+		assert.Equal(t, 1, sink.entries[0].Level)
+		assert.Equal(t, 0, sink.entries[1].Level, "warnings must not use the log level")
+		assert.Equal(t, -1, sink.entries[2].Level)
+	})
+
+	t.Run("verbosity", func(t *testing.T) {
+		t.Parallel()
+
+		sink := &logrObserver{}
+		l := &Logger{Logger: logr.New(sink)}
+		l.UseVerbosity(fxevent.ErrorsOnly)
+		l.LogEvent(&fxevent.Started{})
+		l.LogEvent(&fxevent.Started{Err: someError})
+
+		require.Len(t, sink.entries, 1)
+		assert.Equal(t, "start failed", sink.entries[0].Msg)
+	})
+}
@@ -0,0 +1,22 @@
+module go.uber.org/fx/fxevent/fxzerolog
+
+go 1.20
+
+require (
+	github.com/rs/zerolog v1.33.0
+	github.com/stretchr/testify v1.8.1
+	go.uber.org/fx v1.19.2
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.19 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	go.uber.org/zap v1.26.0 // indirect
+	golang.org/x/sys v0.12.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace go.uber.org/fx => ../..
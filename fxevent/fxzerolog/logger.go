@@ -0,0 +1,405 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package fxzerolog provides an Fx event logger that logs to zerolog.
+// It logs at the levels set with [Logger.UseLogLevel]
+// and [Logger.UseErrorLevel].
+//
+//	fx.WithLogger(
+//		func(log zerolog.Logger) fxevent.Logger {
+//			return &fxzerolog.Logger{Logger: log}
+//		},
+//	)
+//
+// It's a module of its own,
+// so that applications that don't use zerolog don't depend on it.
+package fxzerolog
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/fx/fxevent"
+)
+
+var _ fxevent.Logger = (*Logger)(nil)
+
+// Logger is an Fx event logger that logs events to zerolog.
+// Like [fxevent.ZapLogger], it logs each event as a message with structured
+// fields, such as "constructor" or "error".
+type Logger struct {
+	Logger zerolog.Logger
+
+	logLevel   *zerolog.Level // default: zerolog.InfoLevel
+	errorLevel *zerolog.Level
+	verbosity  fxevent.Verbosity // default: fxevent.Verbose
+}
+
+// UseErrorLevel sets the level of error logs emitted by Fx to level.
+func (l *Logger) UseErrorLevel(level zerolog.Level) {
+	l.errorLevel = &level
+}
+
+// UseLogLevel sets the level of non-error logs emitted by Fx to level.
+func (l *Logger) UseLogLevel(level zerolog.Level) {
+	l.logLevel = &level
+}
+
+// UseVerbosity sets which events are logged to v.
+// Events that report an error are always logged.
+func (l *Logger) UseVerbosity(v fxevent.Verbosity) {
+	l.verbosity = v
+}
+
+// zerologField adds a field to a zerolog event.
+type zerologField func(*zerolog.Event)
+
+func (l *Logger) log(level zerolog.Level, msg string, fields []zerologField) {
+	e := l.Logger.WithLevel(level)
+	if e == nil {
+		return
+	}
+	for _, f := range fields {
+		f(e)
+	}
+	e.Msg(msg)
+}
+
+func (l *Logger) logEvent(msg string, fields ...zerologField) {
+	// The zero zerolog.Level is DebugLevel, so the default is set here.
+	lvl := zerolog.InfoLevel
+	if l.logLevel != nil {
+		lvl = *l.logLevel
+	}
+	l.log(lvl, msg, fields)
+}
+
+func (l *Logger) logWarning(msg string, fields ...zerologField) {
+	l.log(zerolog.WarnLevel, msg, fields)
+}
+
+func (l *Logger) logError(err error, msg string, fields ...zerologField) {
+	lvl := zerolog.ErrorLevel
+	if l.errorLevel != nil {
+		lvl = *l.errorLevel
+	}
+	l.log(lvl, msg, append(fields, zerologErr(err)))
+}
+
+// LogEvent logs the given event to the provided zerolog logger.
+func (l *Logger) LogEvent(event fxevent.Event) {
+	if !l.verbosity.Allows(event) {
+		return
+	}
+
+	switch e := event.(type) {
+	case *fxevent.OnStartExecuting:
+		l.logEvent("OnStart hook executing",
+			zerologString("callee", e.FunctionName),
+			zerologString("caller", e.CallerName),
+		)
+	case *fxevent.OnStartExecuted:
+		if e.Err != nil {
+			l.logError(e.Err, "OnStart hook failed",
+				zerologString("callee", e.FunctionName),
+				zerologString("caller", e.CallerName),
+				zerologMaybeDuration("timeout", e.Timeout),
+			)
+		} else {
+			l.logEvent("OnStart hook executed",
+				zerologString("callee", e.FunctionName),
+				zerologString("caller", e.CallerName),
+				zerologString("runtime", e.Runtime.String()),
+				zerologMaybeDuration("timeout", e.Timeout),
+			)
+		}
+	case *fxevent.OnStopExecuting:
+		l.logEvent("OnStop hook executing",
+			zerologString("callee", e.FunctionName),
+			zerologString("caller", e.CallerName),
+		)
+	case *fxevent.OnStopExecuted:
+		if e.Err != nil {
+			l.logError(e.Err, "OnStop hook failed",
+				zerologString("callee", e.FunctionName),
+				zerologString("caller", e.CallerName),
+				zerologMaybeDuration("timeout", e.Timeout),
+			)
+		} else {
+			l.logEvent("OnStop hook executed",
+				zerologString("callee", e.FunctionName),
+				zerologString("caller", e.CallerName),
+				zerologString("runtime", e.Runtime.String()),
+				zerologMaybeDuration("timeout", e.Timeout),
+			)
+		}
+	case *fxevent.Supplied:
+		if e.Err != nil {
+			l.logError(e.Err, "error encountered while applying options",
+				zerologString("type", e.TypeName),
+				zerologStrings("stacktrace", e.StackTrace),
+				zerologStrings("moduletrace", e.ModuleTrace),
+				zerologMaybeModuleField(e.ModuleName),
+				zerologMaybeOwnerField(e.ModuleOwner),
+			)
+		} else {
+			l.logEvent("supplied",
+				zerologString("type", e.TypeName),
+				zerologStrings("stacktrace", e.StackTrace),
+				zerologStrings("moduletrace", e.ModuleTrace),
+				zerologMaybeModuleField(e.ModuleName),
+				zerologMaybeOwnerField(e.ModuleOwner),
+			)
+		}
+	case *fxevent.Provided:
+		for _, rtype := range e.OutputTypeNames {
+			l.logEvent("provided",
+				zerologString("constructor", e.ConstructorName),
+				zerologStrings("stacktrace", e.StackTrace),
+				zerologStrings("moduletrace", e.ModuleTrace),
+				zerologMaybeModuleField(e.ModuleName),
+				zerologMaybeOwnerField(e.ModuleOwner),
+				zerologString("type", rtype),
+				zerologMaybeBool("private", e.Private),
+				zerologMaybeBool("derived", e.Derived),
+			)
+		}
+		if e.Err != nil {
+			l.logError(e.Err, "error encountered while applying options",
+				zerologMaybeModuleField(e.ModuleName),
+				zerologMaybeOwnerField(e.ModuleOwner),
+				zerologStrings("stacktrace", e.StackTrace),
+				zerologStrings("moduletrace", e.ModuleTrace),
+			)
+		}
+	case *fxevent.Replaced:
+		for _, rtype := range e.OutputTypeNames {
+			l.logEvent("replaced",
+				zerologStrings("stacktrace", e.StackTrace),
+				zerologStrings("moduletrace", e.ModuleTrace),
+				zerologMaybeModuleField(e.ModuleName),
+				zerologMaybeOwnerField(e.ModuleOwner),
+				zerologString("type", rtype),
+			)
+		}
+		if e.Err != nil {
+			l.logError(e.Err, "error encountered while replacing",
+				zerologStrings("stacktrace", e.StackTrace),
+				zerologStrings("moduletrace", e.ModuleTrace),
+				zerologMaybeModuleField(e.ModuleName),
+				zerologMaybeOwnerField(e.ModuleOwner),
+			)
+		}
+	case *fxevent.Decorated:
+		for _, rtype := range e.OutputTypeNames {
+			l.logEvent("decorated",
+				zerologString("decorator", e.DecoratorName),
+				zerologStrings("stacktrace", e.StackTrace),
+				zerologStrings("moduletrace", e.ModuleTrace),
+				zerologMaybeModuleField(e.ModuleName),
+				zerologMaybeOwnerField(e.ModuleOwner),
+				zerologString("type", rtype),
+			)
+		}
+		if e.Err != nil {
+			l.logError(e.Err, "error encountered while applying options",
+				zerologStrings("stacktrace", e.StackTrace),
+				zerologStrings("moduletrace", e.ModuleTrace),
+				zerologMaybeModuleField(e.ModuleName),
+				zerologMaybeOwnerField(e.ModuleOwner),
+			)
+		}
+	case *fxevent.AmbiguousDecoration:
+		l.logWarning("type is both provided and decorated in the same module",
+			zerologString("type", e.TypeName),
+			zerologMaybeModuleField(e.ModuleName),
+			zerologStrings("providestacktrace", e.ProvideStackTrace),
+			zerologStrings("decoratestacktrace", e.DecorateStackTrace),
+		)
+	case *fxevent.Run:
+		if e.Err != nil {
+			l.logError(e.Err, "error returned",
+				zerologString("name", e.Name),
+				zerologString("kind", e.Kind),
+				zerologMaybeModuleField(e.ModuleName),
+				zerologMaybeOwnerField(e.ModuleOwner),
+			)
+		} else {
+			l.logEvent("run",
+				zerologString("name", e.Name),
+				zerologString("kind", e.Kind),
+				zerologMaybeModuleField(e.ModuleName),
+				zerologMaybeOwnerField(e.ModuleOwner),
+			)
+		}
+	case *fxevent.Panicked:
+		l.logError(e.Err, "panic recovered",
+			zerologString("name", e.Name),
+			zerologString("kind", e.Kind),
+			zerologMaybeModuleField(e.ModuleName),
+			zerologMaybeOwnerField(e.ModuleOwner),
+			zerologAny("value", e.Value),
+			zerologMaybeString("stack", e.Stack),
+		)
+	case *fxevent.Invoking:
+		// Do not log stack as it will make logs hard to read.
+		l.logEvent("invoking",
+			zerologString("function", e.FunctionName),
+			zerologMaybeModuleField(e.ModuleName),
+			zerologMaybeOwnerField(e.ModuleOwner),
+		)
+	case *fxevent.Invoked:
+		if e.Err != nil {
+			l.logError(e.Err, "invoke failed",
+				zerologString("stack", e.Trace),
+				zerologString("function", e.FunctionName),
+				zerologMaybeModuleField(e.ModuleName),
+				zerologMaybeOwnerField(e.ModuleOwner),
+			)
+		}
+	case *fxevent.Stopping:
+		l.logEvent("received signal",
+			zerologString("signal", strings.ToUpper(e.Signal.String())),
+			zerologMaybeErr("reason", e.Reason))
+	case *fxevent.Stopped:
+		if e.Err != nil {
+			l.logError(e.Err, "stop failed")
+		}
+	case *fxevent.RollingBack:
+		l.logError(e.StartErr, "start failed, rolling back",
+			zerologMaybeString("callee", e.FunctionName),
+			zerologMaybeString("caller", e.CallerName),
+			zerologMaybeString("policy", e.Policy),
+		)
+	case *fxevent.RolledBack:
+		switch {
+		case e.Skipped:
+			l.logWarning("rollback skipped, started hooks left running")
+		case e.Err != nil:
+			l.logError(e.Err, "rollback failed",
+				zerologMaybeStrings("hooks", e.Hooks),
+				zerologMaybeStrings("failed", e.Failed),
+				zerologMaybeBool("ignored", e.Ignored),
+			)
+		case len(e.Hooks) > 0:
+			l.logEvent("rolled back", zerologStrings("hooks", e.Hooks))
+		}
+	case *fxevent.Started:
+		if e.Err != nil {
+			l.logError(e.Err, "start failed")
+		} else {
+			l.logEvent("started", zerologString("runtime", e.Runtime.String()))
+		}
+	case *fxevent.Restarting:
+		l.logEvent("restarting")
+	case *fxevent.Restarted:
+		if e.Err != nil {
+			l.logError(e.Err, "restart failed")
+		} else {
+			l.logEvent("restarted", zerologString("runtime", e.Runtime.String()))
+		}
+	case *fxevent.Reloaded:
+		if e.Err != nil {
+			l.logError(e.Err, "reload failed", zerologMaybeSignal(e.Signal))
+		} else {
+			l.logEvent("reloaded", zerologMaybeSignal(e.Signal), zerologString("runtime", e.Runtime.String()))
+		}
+	case *fxevent.LoggerInitialized:
+		if e.Err != nil {
+			l.logError(e.Err, "custom logger initialization failed")
+		} else {
+			l.logEvent("initialized custom fxevent.Logger", zerologString("function", e.ConstructorName))
+		}
+	}
+}
+
+func zerologString(key, value string) zerologField {
+	return func(e *zerolog.Event) { e.Str(key, value) }
+}
+
+func zerologStrings(key string, values []string) zerologField {
+	return func(e *zerolog.Event) { e.Strs(key, values) }
+}
+
+func zerologAny(key string, value interface{}) zerologField {
+	return func(e *zerolog.Event) { e.Interface(key, value) }
+}
+
+func zerologErr(err error) zerologField {
+	return func(e *zerolog.Event) {
+		if err != nil {
+			e.Err(err)
+		}
+	}
+}
+
+func zerologSkip(*zerolog.Event) {}
+
+func zerologMaybeString(key, value string) zerologField {
+	if len(value) == 0 {
+		return zerologSkip
+	}
+	return zerologString(key, value)
+}
+
+func zerologMaybeStrings(key string, values []string) zerologField {
+	if len(values) == 0 {
+		return zerologSkip
+	}
+	return zerologStrings(key, values)
+}
+
+func zerologMaybeModuleField(name string) zerologField {
+	return zerologMaybeString("module", name)
+}
+
+func zerologMaybeOwnerField(owner string) zerologField {
+	return zerologMaybeString("moduleOwner", owner)
+}
+
+func zerologMaybeBool(key string, b bool) zerologField {
+	if !b {
+		return zerologSkip
+	}
+	return func(e *zerolog.Event) { e.Bool(key, true) }
+}
+
+func zerologMaybeDuration(key string, d time.Duration) zerologField {
+	if d <= 0 {
+		return zerologSkip
+	}
+	return zerologString(key, d.String())
+}
+
+func zerologMaybeSignal(sig os.Signal) zerologField {
+	if sig == nil {
+		return zerologSkip
+	}
+	return zerologString("signal", strings.ToUpper(sig.String()))
+}
+
+func zerologMaybeErr(key string, err error) zerologField {
+	if err == nil {
+		return zerologSkip
+	}
+	return zerologString(key, err.Error())
+}
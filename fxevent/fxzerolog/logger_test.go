@@ -0,0 +1,208 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxzerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxevent"
+)
+
+// zerologEntries decodes the JSON lines written by zerolog.
+func zerologEntries(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestZerologLogger(t *testing.T) {
+	t.Parallel()
+
+	someError := errors.New("some error")
+
+	tests := []struct {
+		name string
+		give fxevent.Event
+		want map[string]interface{}
+	}{
+		{
+			name: "OnStartExecuting",
+			give: &fxevent.OnStartExecuting{FunctionName: "hook.onStart", CallerName: "bytes.NewBuffer"},
+			want: map[string]interface{}{
+				"level":   "info",
+				"message": "OnStart hook executing",
+				"callee":  "hook.onStart",
+				"caller":  "bytes.NewBuffer",
+			},
+		},
+		{
+			name: "OnStopExecuted/Error",
+			give: &fxevent.OnStopExecuted{
+				FunctionName: "hook.onStop",
+				CallerName:   "bytes.NewBuffer",
+				Err:          someError,
+				Timeout:      time.Second,
+			},
+			want: map[string]interface{}{
+				"level":   "error",
+				"message": "OnStop hook failed",
+				"callee":  "hook.onStop",
+				"caller":  "bytes.NewBuffer",
+				"timeout": "1s",
+				"error":   "some error",
+			},
+		},
+		{
+			name: "Provided",
+			give: &fxevent.Provided{
+				ConstructorName: "bytes.NewBuffer()",
+				OutputTypeNames: []string{"*bytes.Buffer"},
+				ModuleName:      "myModule",
+				StackTrace:      []string{"main.main"},
+				ModuleTrace:     []string{"main.main"},
+				Private:         true,
+			},
+			want: map[string]interface{}{
+				"level":       "info",
+				"message":     "provided",
+				"constructor": "bytes.NewBuffer()",
+				"type":        "*bytes.Buffer",
+				"module":      "myModule",
+				"stacktrace":  []interface{}{"main.main"},
+				"moduletrace": []interface{}{"main.main"},
+				"private":     true,
+			},
+		},
+		{
+			name: "AmbiguousDecoration",
+			give: &fxevent.AmbiguousDecoration{
+				TypeName:           "*bytes.Buffer",
+				ProvideStackTrace:  []string{"main.provide"},
+				DecorateStackTrace: []string{"main.decorate"},
+			},
+			want: map[string]interface{}{
+				"level":              "warn",
+				"message":            "type is both provided and decorated in the same module",
+				"type":               "*bytes.Buffer",
+				"providestacktrace":  []interface{}{"main.provide"},
+				"decoratestacktrace": []interface{}{"main.decorate"},
+			},
+		},
+		{
+			name: "Panicked",
+			give: &fxevent.Panicked{Kind: "invoke", Name: "main.run()", Value: "oops"},
+			want: map[string]interface{}{
+				"level":   "error",
+				"message": "panic recovered",
+				"kind":    "invoke",
+				"name":    "main.run()",
+				"value":   "oops",
+			},
+		},
+		{
+			name: "Stopping",
+			give: &fxevent.Stopping{Signal: os.Interrupt},
+			want: map[string]interface{}{
+				"level":   "info",
+				"message": "received signal",
+				"signal":  "INTERRUPT",
+			},
+		},
+		{
+			name: "Started/Error",
+			give: &fxevent.Started{Err: someError},
+			want: map[string]interface{}{
+				"level":   "error",
+				"message": "start failed",
+				"error":   "some error",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			(&Logger{Logger: zerolog.New(&buf)}).LogEvent(tt.give)
+
+			entries := zerologEntries(t, &buf)
+			require.Len(t, entries, 1)
+			assert.Equal(t, tt.want, entries[0])
+		})
+	}
+
+	t.Run("levels", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		l := &Logger{Logger: zerolog.New(&buf)}
+		l.UseLogLevel(zerolog.DebugLevel)
+		l.UseErrorLevel(zerolog.WarnLevel)
+		l.LogEvent(&fxevent.Started{})
+		l.LogEvent(&fxevent.Started{Err: someError})
+
+		entries := zerologEntries(t, &buf)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "debug", entries[0]["level"])
+		assert.Equal(t, "warn", entries[1]["level"])
+	})
+
+	t.Run("verbosity", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		l := &Logger{Logger: zerolog.New(&buf)}
+		l.UseVerbosity(fxevent.ErrorsOnly)
+		l.LogEvent(&fxevent.Started{})
+		l.LogEvent(&fxevent.Started{Err: someError})
+
+		entries := zerologEntries(t, &buf)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "start failed", entries[0]["message"])
+	})
+
+	t.Run("disabled level", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		l := &Logger{Logger: zerolog.New(&buf).Level(zerolog.WarnLevel)}
+		l.LogEvent(&fxevent.Started{})
+		assert.Empty(t, buf.String())
+	})
+}
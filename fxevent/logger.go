@@ -36,3 +36,56 @@ var _ Logger = nopLogger{}
 func (nopLogger) LogEvent(Event) {}
 
 func (nopLogger) String() string { return "NopLogger" }
+
+// Tee returns an Fx event logger that passes every event to all the given
+// loggers, in order. Nil loggers are ignored.
+//
+//	fx.WithLogger(func(log *zap.Logger, m fxevent.Metrics) fxevent.Logger {
+//		return fxevent.Tee(
+//			&fxevent.ZapLogger{Logger: log},
+//			&fxevent.MetricsLogger{Metrics: m},
+//		)
+//	})
+//
+// A logger that panics doesn't prevent the others from receiving the
+// event; the first panic is re-raised once all loggers have been called.
+func Tee(loggers ...Logger) Logger {
+	var tee teeLogger
+	for _, l := range loggers {
+		switch l := l.(type) {
+		case nil:
+		case teeLogger:
+			tee = append(tee, l...)
+		default:
+			tee = append(tee, l)
+		}
+	}
+	if len(tee) == 1 {
+		return tee[0]
+	}
+	return tee
+}
+
+type teeLogger []Logger
+
+var _ Logger = teeLogger(nil)
+
+func (t teeLogger) LogEvent(event Event) {
+	var (
+		panicked  bool
+		recovered any
+	)
+	for _, l := range t {
+		func() {
+			defer func() {
+				if r := recover(); r != nil && !panicked {
+					panicked, recovered = true, r
+				}
+			}()
+			l.LogEvent(event)
+		}()
+	}
+	if panicked {
+		panic(recovered)
+	}
+}
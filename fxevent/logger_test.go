@@ -0,0 +1,83 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxevent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type panickingLogger string
+
+func (l panickingLogger) LogEvent(Event) { panic(string(l)) }
+
+func TestTee(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fans out", func(t *testing.T) {
+		t.Parallel()
+
+		var a, b recordingLogger
+		logger := Tee(&a, nil, &b)
+		logger.LogEvent(&Started{})
+		logger.LogEvent(&Stopped{})
+
+		want := recordingLogger{&Started{}, &Stopped{}}
+		assert.Equal(t, want, a)
+		assert.Equal(t, want, b)
+	})
+
+	t.Run("flattens", func(t *testing.T) {
+		t.Parallel()
+
+		var a, b, c recordingLogger
+		logger := Tee(Tee(&a, &b), &c)
+		assert.Len(t, logger, 3)
+	})
+
+	t.Run("single logger", func(t *testing.T) {
+		t.Parallel()
+
+		var a recordingLogger
+		assert.Same(t, &a, Tee(nil, &a))
+	})
+
+	t.Run("no loggers", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NotPanics(t, func() {
+			Tee().LogEvent(&Started{})
+		})
+	})
+
+	t.Run("panics", func(t *testing.T) {
+		t.Parallel()
+
+		var a recordingLogger
+		logger := Tee(panickingLogger("great sadness"), &a, panickingLogger("more sadness"))
+		assert.PanicsWithValue(t, "great sadness", func() {
+			logger.LogEvent(&Started{})
+		})
+		assert.Equal(t, recordingLogger{&Started{}}, a,
+			"loggers after a panicking logger must still be called")
+	})
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxevent
+
+import "time"
+
+// Metrics receives measurements of the startup and shutdown of an Fx
+// application from a [MetricsLogger].
+//
+// Implement it with the metrics library of your choice.
+// For example, with Prometheus, record durations in histograms labeled
+// by the kind of function or hook, and count failures with a counter
+// incremented when Err is non-nil.
+type Metrics interface {
+	// ObserveConstructor is called after a constructor runs.
+	ObserveConstructor(ConstructorMeasurement)
+
+	// ObserveHook is called after an OnStart or OnStop hook runs.
+	ObserveHook(HookMeasurement)
+
+	// ObserveLifecycle is called after the application starts or stops,
+	// whether successfully or not.
+	ObserveLifecycle(LifecycleMeasurement)
+}
+
+// ConstructorMeasurement describes a run of a constructor.
+type ConstructorMeasurement struct {
+	// Name is the name of the constructor.
+	Name string
+
+	// Kind is "provide" or "derive", as reported in the [Run] event.
+	Kind string
+
+	// ModuleName is the name of the module the constructor was provided to.
+	ModuleName string
+
+	// Runtime is how long the constructor took to run.
+	// It is zero unless the application uses fx.TimeConstructors.
+	Runtime time.Duration
+
+	// Err is non-nil if the constructor failed.
+	Err error
+}
+
+// HookMeasurement describes a run of a lifecycle hook.
+type HookMeasurement struct {
+	// Kind is "OnStart" or "OnStop".
+	Kind string
+
+	// FunctionName is the name of the hook function.
+	FunctionName string
+
+	// CallerName is the name of the function that appended the hook.
+	CallerName string
+
+	// Runtime is how long the hook took to run.
+	Runtime time.Duration
+
+	// Err is non-nil if the hook failed.
+	Err error
+}
+
+// LifecycleMeasurement describes the start or stop of an application.
+type LifecycleMeasurement struct {
+	// Kind is "start" or "stop".
+	Kind string
+
+	// Runtime is how long it took to run all OnStart or OnStop hooks.
+	Runtime time.Duration
+
+	// Err is non-nil if the application failed to start or stop.
+	Err error
+}
+
+// MetricsLogger is an Fx event logger that records constructor durations,
+// hook durations, and the outcome of starting and stopping the application
+// with Metrics.
+// Every event is also passed to Logger, if set,
+// so that metrics can be recorded alongside regular logs.
+//
+// Constructor durations are only measured if the application
+// uses fx.TimeConstructors.
+//
+//	fx.WithLogger(func(log *zap.Logger, m fxevent.Metrics) fxevent.Logger {
+//		return &fxevent.MetricsLogger{
+//			Metrics: m,
+//			Logger:  &fxevent.ZapLogger{Logger: log},
+//		}
+//	})
+type MetricsLogger struct {
+	Metrics Metrics
+	Logger  Logger
+}
+
+var _ Logger = (*MetricsLogger)(nil)
+
+// LogEvent records metrics for the given event, then passes it to Logger.
+func (l *MetricsLogger) LogEvent(event Event) {
+	switch e := event.(type) {
+	case *Run:
+		if e.Kind == "provide" || e.Kind == "derive" {
+			l.Metrics.ObserveConstructor(ConstructorMeasurement{
+				Name:       e.Name,
+				Kind:       e.Kind,
+				ModuleName: e.ModuleName,
+				Runtime:    e.Runtime,
+				Err:        e.Err,
+			})
+		}
+	case *OnStartExecuted:
+		l.Metrics.ObserveHook(HookMeasurement{
+			Kind:         "OnStart",
+			FunctionName: e.FunctionName,
+			CallerName:   e.CallerName,
+			Runtime:      e.Runtime,
+			Err:          e.Err,
+		})
+	case *OnStopExecuted:
+		l.Metrics.ObserveHook(HookMeasurement{
+			Kind:         "OnStop",
+			FunctionName: e.FunctionName,
+			CallerName:   e.CallerName,
+			Runtime:      e.Runtime,
+			Err:          e.Err,
+		})
+	case *Started:
+		l.Metrics.ObserveLifecycle(LifecycleMeasurement{
+			Kind:    "start",
+			Runtime: e.Runtime,
+			Err:     e.Err,
+		})
+	case *Stopped:
+		l.Metrics.ObserveLifecycle(LifecycleMeasurement{
+			Kind:    "stop",
+			Runtime: e.Runtime,
+			Err:     e.Err,
+		})
+	}
+
+	if l.Logger != nil {
+		l.Logger.LogEvent(event)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxevent
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingMetrics struct {
+	constructors []ConstructorMeasurement
+	hooks        []HookMeasurement
+	lifecycle    []LifecycleMeasurement
+}
+
+func (m *recordingMetrics) ObserveConstructor(c ConstructorMeasurement) {
+	m.constructors = append(m.constructors, c)
+}
+
+func (m *recordingMetrics) ObserveHook(h HookMeasurement) {
+	m.hooks = append(m.hooks, h)
+}
+
+func (m *recordingMetrics) ObserveLifecycle(l LifecycleMeasurement) {
+	m.lifecycle = append(m.lifecycle, l)
+}
+
+type recordingLogger []Event
+
+func (l *recordingLogger) LogEvent(e Event) { *l = append(*l, e) }
+
+func TestMetricsLogger(t *testing.T) {
+	t.Parallel()
+
+	someError := errors.New("some error")
+
+	tests := []struct {
+		name string
+		give Event
+		want recordingMetrics
+	}{
+		{
+			name: "Run/provide",
+			give: &Run{Name: "bytes.NewBuffer()", Kind: "provide", ModuleName: "buf", Runtime: time.Second},
+			want: recordingMetrics{constructors: []ConstructorMeasurement{{
+				Name:       "bytes.NewBuffer()",
+				Kind:       "provide",
+				ModuleName: "buf",
+				Runtime:    time.Second,
+			}}},
+		},
+		{
+			name: "Run/derive/Error",
+			give: &Run{Name: "bytes.NewBuffer()", Kind: "derive", Err: someError},
+			want: recordingMetrics{constructors: []ConstructorMeasurement{{
+				Name: "bytes.NewBuffer()",
+				Kind: "derive",
+				Err:  someError,
+			}}},
+		},
+		{
+			name: "Run/decorate",
+			give: &Run{Name: "bytes.NewBuffer()", Kind: "decorate"},
+		},
+		{
+			name: "OnStartExecuted",
+			give: &OnStartExecuted{FunctionName: "hook.onStart", CallerName: "bytes.NewBuffer", Runtime: time.Second},
+			want: recordingMetrics{hooks: []HookMeasurement{{
+				Kind:         "OnStart",
+				FunctionName: "hook.onStart",
+				CallerName:   "bytes.NewBuffer",
+				Runtime:      time.Second,
+			}}},
+		},
+		{
+			name: "OnStopExecuted/Error",
+			give: &OnStopExecuted{FunctionName: "hook.onStop", CallerName: "bytes.NewBuffer", Err: someError},
+			want: recordingMetrics{hooks: []HookMeasurement{{
+				Kind:         "OnStop",
+				FunctionName: "hook.onStop",
+				CallerName:   "bytes.NewBuffer",
+				Err:          someError,
+			}}},
+		},
+		{
+			name: "Started",
+			give: &Started{Runtime: time.Second},
+			want: recordingMetrics{lifecycle: []LifecycleMeasurement{{Kind: "start", Runtime: time.Second}}},
+		},
+		{
+			name: "Stopped/Error",
+			give: &Stopped{Err: someError},
+			want: recordingMetrics{lifecycle: []LifecycleMeasurement{{Kind: "stop", Err: someError}}},
+		},
+		{
+			name: "Invoked",
+			give: &Invoked{FunctionName: "bytes.NewBuffer()"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				metrics recordingMetrics
+				logged  recordingLogger
+			)
+			(&MetricsLogger{Metrics: &metrics, Logger: &logged}).LogEvent(tt.give)
+			assert.Equal(t, tt.want, metrics)
+			assert.Equal(t, recordingLogger{tt.give}, logged)
+		})
+	}
+
+	t.Run("without Logger", func(t *testing.T) {
+		t.Parallel()
+
+		var metrics recordingMetrics
+		(&MetricsLogger{Metrics: &metrics}).LogEvent(&Started{})
+		assert.Len(t, metrics.lifecycle, 1)
+	})
+}
@@ -25,19 +25,24 @@ package fxevent
 import (
 	"context"
 	"log/slog"
+	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 var _ Logger = (*SlogLogger)(nil)
 
-// SlogLogger an Fx event logger that logs events using a slog logger.
+// SlogLogger is an Fx event logger that logs events using a slog logger.
+// Like [ZapLogger], it logs each event as a message with structured
+// attributes, such as "constructor" or "error".
 type SlogLogger struct {
 	Logger *slog.Logger
 
 	ctx        context.Context
 	logLevel   slog.Level
 	errorLevel *slog.Level
+	verbosity  Verbosity
 }
 
 // UseContext sets the context that will be used when logging to slog.
@@ -55,6 +60,12 @@ func (l *SlogLogger) UseErrorLevel(level slog.Level) {
 	l.errorLevel = &level
 }
 
+// UseVerbosity sets which events are logged to v.
+// Events that report an error are always logged.
+func (l *SlogLogger) UseVerbosity(v Verbosity) {
+	l.verbosity = v
+}
+
 func (l *SlogLogger) filter(fields []any) []any {
 	filtered := []any{}
 
@@ -75,6 +86,10 @@ func (l *SlogLogger) logEvent(msg string, fields ...any) {
 	l.Logger.Log(l.ctx, l.logLevel, msg, l.filter(fields)...)
 }
 
+func (l *SlogLogger) logWarning(msg string, fields ...any) {
+	l.Logger.Log(l.ctx, slog.LevelWarn, msg, l.filter(fields)...)
+}
+
 func (l *SlogLogger) logError(msg string, fields ...any) {
 	lvl := slog.LevelError
 	if l.errorLevel != nil {
@@ -84,8 +99,12 @@ func (l *SlogLogger) logError(msg string, fields ...any) {
 	l.Logger.Log(l.ctx, lvl, msg, l.filter(fields)...)
 }
 
-// LogEvent logs the given event to the provided Zap logger.
+// LogEvent logs the given event to the provided slog logger.
 func (l *SlogLogger) LogEvent(event Event) {
+	if !l.verbosity.Allows(event) {
+		return
+	}
+
 	switch e := event.(type) {
 	case *OnStartExecuting:
 		l.logEvent("OnStart hook executing",
@@ -97,6 +116,7 @@ func (l *SlogLogger) LogEvent(event Event) {
 			l.logError("OnStart hook failed",
 				slog.String("callee", e.FunctionName),
 				slog.String("caller", e.CallerName),
+				slogMaybeDuration("timeout", e.Timeout),
 				slogErr(e.Err),
 			)
 		} else {
@@ -104,6 +124,7 @@ func (l *SlogLogger) LogEvent(event Event) {
 				slog.String("callee", e.FunctionName),
 				slog.String("caller", e.CallerName),
 				slog.String("runtime", e.Runtime.String()),
+				slogMaybeDuration("timeout", e.Timeout),
 			)
 		}
 	case *OnStopExecuting:
@@ -116,6 +137,7 @@ func (l *SlogLogger) LogEvent(event Event) {
 			l.logError("OnStop hook failed",
 				slog.String("callee", e.FunctionName),
 				slog.String("caller", e.CallerName),
+				slogMaybeDuration("timeout", e.Timeout),
 				slogErr(e.Err),
 			)
 		} else {
@@ -123,6 +145,7 @@ func (l *SlogLogger) LogEvent(event Event) {
 				slog.String("callee", e.FunctionName),
 				slog.String("caller", e.CallerName),
 				slog.String("runtime", e.Runtime.String()),
+				slogMaybeDuration("timeout", e.Timeout),
 			)
 		}
 	case *Supplied:
@@ -132,6 +155,7 @@ func (l *SlogLogger) LogEvent(event Event) {
 				slogStrings("moduletrace", e.ModuleTrace),
 				slogStrings("stacktrace", e.StackTrace),
 				slogMaybeModuleField(e.ModuleName),
+				slogMaybeOwnerField(e.ModuleOwner),
 				slogErr(e.Err))
 		} else {
 			l.logEvent("supplied",
@@ -139,6 +163,7 @@ func (l *SlogLogger) LogEvent(event Event) {
 				slogStrings("stacktrace", e.StackTrace),
 				slogStrings("moduletrace", e.ModuleTrace),
 				slogMaybeModuleField(e.ModuleName),
+				slogMaybeOwnerField(e.ModuleOwner),
 			)
 		}
 	case *Provided:
@@ -148,13 +173,16 @@ func (l *SlogLogger) LogEvent(event Event) {
 				slogStrings("stacktrace", e.StackTrace),
 				slogStrings("moduletrace", e.ModuleTrace),
 				slogMaybeModuleField(e.ModuleName),
+				slogMaybeOwnerField(e.ModuleOwner),
 				slog.String("type", rtype),
 				slogMaybeBool("private", e.Private),
+				slogMaybeBool("derived", e.Derived),
 			)
 		}
 		if e.Err != nil {
 			l.logError("error encountered while applying options",
 				slogMaybeModuleField(e.ModuleName),
+				slogMaybeOwnerField(e.ModuleOwner),
 				slogStrings("stacktrace", e.StackTrace),
 				slogStrings("moduletrace", e.ModuleTrace),
 				slogErr(e.Err))
@@ -165,6 +193,7 @@ func (l *SlogLogger) LogEvent(event Event) {
 				slogStrings("stacktrace", e.StackTrace),
 				slogStrings("moduletrace", e.ModuleTrace),
 				slogMaybeModuleField(e.ModuleName),
+				slogMaybeOwnerField(e.ModuleOwner),
 				slog.String("type", rtype),
 			)
 		}
@@ -173,6 +202,7 @@ func (l *SlogLogger) LogEvent(event Event) {
 				slogStrings("stacktrace", e.StackTrace),
 				slogStrings("moduletrace", e.ModuleTrace),
 				slogMaybeModuleField(e.ModuleName),
+				slogMaybeOwnerField(e.ModuleOwner),
 				slogErr(e.Err))
 		}
 	case *Decorated:
@@ -182,6 +212,7 @@ func (l *SlogLogger) LogEvent(event Event) {
 				slogStrings("stacktrace", e.StackTrace),
 				slogStrings("moduletrace", e.ModuleTrace),
 				slogMaybeModuleField(e.ModuleName),
+				slogMaybeOwnerField(e.ModuleOwner),
 				slog.String("type", rtype),
 			)
 		}
@@ -190,14 +221,23 @@ func (l *SlogLogger) LogEvent(event Event) {
 				slogStrings("stacktrace", e.StackTrace),
 				slogStrings("moduletrace", e.ModuleTrace),
 				slogMaybeModuleField(e.ModuleName),
+				slogMaybeOwnerField(e.ModuleOwner),
 				slogErr(e.Err))
 		}
+	case *AmbiguousDecoration:
+		l.logWarning("type is both provided and decorated in the same module",
+			slog.String("type", e.TypeName),
+			slogMaybeModuleField(e.ModuleName),
+			slogStrings("providestacktrace", e.ProvideStackTrace),
+			slogStrings("decoratestacktrace", e.DecorateStackTrace),
+		)
 	case *Run:
 		if e.Err != nil {
 			l.logError("error returned",
 				slog.String("name", e.Name),
 				slog.String("kind", e.Kind),
 				slogMaybeModuleField(e.ModuleName),
+				slogMaybeOwnerField(e.ModuleOwner),
 				slogErr(e.Err),
 			)
 		} else {
@@ -205,13 +245,25 @@ func (l *SlogLogger) LogEvent(event Event) {
 				slog.String("name", e.Name),
 				slog.String("kind", e.Kind),
 				slogMaybeModuleField(e.ModuleName),
+				slogMaybeOwnerField(e.ModuleOwner),
 			)
 		}
+	case *Panicked:
+		l.logError("panic recovered",
+			slog.String("name", e.Name),
+			slog.String("kind", e.Kind),
+			slogMaybeModuleField(e.ModuleName),
+			slogMaybeOwnerField(e.ModuleOwner),
+			slog.Any("value", e.Value),
+			slogMaybeStack(e.Stack),
+			slogErr(e.Err),
+		)
 	case *Invoking:
 		// Do not log stack as it will make logs hard to read.
 		l.logEvent("invoking",
 			slog.String("function", e.FunctionName),
 			slogMaybeModuleField(e.ModuleName),
+			slogMaybeOwnerField(e.ModuleOwner),
 		)
 	case *Invoked:
 		if e.Err != nil {
@@ -220,26 +272,57 @@ func (l *SlogLogger) LogEvent(event Event) {
 				slog.String("stack", e.Trace),
 				slog.String("function", e.FunctionName),
 				slogMaybeModuleField(e.ModuleName),
+				slogMaybeOwnerField(e.ModuleOwner),
 			)
 		}
 	case *Stopping:
 		l.logEvent("received signal",
-			slog.String("signal", strings.ToUpper(e.Signal.String())))
+			slog.String("signal", strings.ToUpper(e.Signal.String())),
+			slogMaybeErr("reason", e.Reason))
 	case *Stopped:
 		if e.Err != nil {
 			l.logError("stop failed", slogErr(e.Err))
 		}
 	case *RollingBack:
-		l.logError("start failed, rolling back", slogErr(e.StartErr))
+		l.logError("start failed, rolling back",
+			slogErr(e.StartErr),
+			slogMaybeString("callee", e.FunctionName),
+			slogMaybeString("caller", e.CallerName),
+			slogMaybeString("policy", e.Policy),
+		)
 	case *RolledBack:
-		if e.Err != nil {
-			l.logError("rollback failed", slogErr(e.Err))
+		switch {
+		case e.Skipped:
+			l.logWarning("rollback skipped, started hooks left running")
+		case e.Err != nil:
+			l.logError("rollback failed",
+				slogErr(e.Err),
+				slogMaybeStrings("hooks", e.Hooks),
+				slogMaybeStrings("failed", e.Failed),
+				slogMaybeBool("ignored", e.Ignored),
+			)
+		case len(e.Hooks) > 0:
+			l.logEvent("rolled back", slogStrings("hooks", e.Hooks))
 		}
 	case *Started:
 		if e.Err != nil {
 			l.logError("start failed", slogErr(e.Err))
 		} else {
-			l.logEvent("started")
+			l.logEvent("started", slog.String("runtime", e.Runtime.String()))
+		}
+	case *Restarting:
+		l.logEvent("restarting")
+	case *Restarted:
+		if e.Err != nil {
+			l.logError("restart failed", slogErr(e.Err))
+		} else {
+			l.logEvent("restarted", slog.String("runtime", e.Runtime.String()))
+		}
+	case *Reloaded:
+		if e.Err != nil {
+			l.logError("reload failed", slogMaybeSignal(e.Signal), slogErr(e.Err))
+		} else {
+			l.logEvent("reloaded", slogMaybeSignal(e.Signal), slog.String("runtime", e.Runtime.String()))
 		}
 	case *LoggerInitialized:
 		if e.Err != nil {
@@ -252,6 +335,13 @@ func (l *SlogLogger) LogEvent(event Event) {
 
 type slogFieldSkip struct{}
 
+func slogMaybeStack(stack string) slog.Attr {
+	if len(stack) == 0 {
+		return slog.Any("stack", slogFieldSkip{})
+	}
+	return slog.String("stack", stack)
+}
+
 func slogMaybeModuleField(name string) slog.Attr {
 	if len(name) == 0 {
 		return slog.Any("module", slogFieldSkip{})
@@ -259,6 +349,27 @@ func slogMaybeModuleField(name string) slog.Attr {
 	return slog.String("module", name)
 }
 
+func slogMaybeOwnerField(owner string) slog.Attr {
+	if len(owner) == 0 {
+		return slog.Any("moduleOwner", slogFieldSkip{})
+	}
+	return slog.String("moduleOwner", owner)
+}
+
+func slogMaybeString(name, value string) slog.Attr {
+	if len(value) == 0 {
+		return slog.Any(name, slogFieldSkip{})
+	}
+	return slog.String(name, value)
+}
+
+func slogMaybeStrings(name string, values []string) slog.Attr {
+	if len(values) == 0 {
+		return slog.Any(name, slogFieldSkip{})
+	}
+	return slogStrings(name, values)
+}
+
 func slogMaybeBool(name string, b bool) slog.Attr {
 	if !b {
 		return slog.Any(name, slogFieldSkip{})
@@ -266,6 +377,27 @@ func slogMaybeBool(name string, b bool) slog.Attr {
 	return slog.Bool(name, true)
 }
 
+func slogMaybeDuration(name string, d time.Duration) slog.Attr {
+	if d <= 0 {
+		return slog.Any(name, slogFieldSkip{})
+	}
+	return slog.String(name, d.String())
+}
+
+func slogMaybeSignal(sig os.Signal) slog.Attr {
+	if sig == nil {
+		return slog.Any("signal", slogFieldSkip{})
+	}
+	return slog.String("signal", strings.ToUpper(sig.String()))
+}
+
+func slogMaybeErr(name string, err error) slog.Attr {
+	if err == nil {
+		return slog.Any(name, slogFieldSkip{})
+	}
+	return slog.String(name, err.Error())
+}
+
 func slogErr(err error) slog.Attr {
 	return slog.String("error", err.Error())
 }
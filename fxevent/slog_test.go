@@ -194,6 +194,22 @@ func TestSlogLogger(t *testing.T) {
 				"runtime": "3ms",
 			},
 		},
+		{
+			name: "OnStartExecuted/Timeout",
+			give: &OnStartExecuted{
+				FunctionName: "hook.onStart1",
+				CallerName:   "bytes.NewBuffer",
+				Runtime:      time.Millisecond * 3,
+				Timeout:      time.Second,
+			},
+			wantMessage: "OnStart hook executed",
+			wantFields: map[string]interface{}{
+				"caller":  "bytes.NewBuffer",
+				"callee":  "hook.onStart1",
+				"runtime": "3ms",
+				"timeout": "1s",
+			},
+		},
 		{
 			name: "Supplied",
 			give: &Supplied{
@@ -263,6 +279,24 @@ func TestSlogLogger(t *testing.T) {
 				"private":     true,
 			},
 		},
+		{
+			name: "Provided/Derived",
+			give: &Provided{
+				ConstructorName: "main.newConfig()",
+				StackTrace:      []string{"main.main", "runtime.main"},
+				ModuleTrace:     []string{"main.main"},
+				OutputTypeNames: []string{"main.Config"},
+				Derived:         true,
+			},
+			wantMessage: "provided",
+			wantFields: map[string]interface{}{
+				"constructor": "main.newConfig()",
+				"stacktrace":  []interface{}{"main.main", "runtime.main"},
+				"moduletrace": []interface{}{"main.main"},
+				"type":        "main.Config",
+				"derived":     true,
+			},
+		},
 		{
 			name: "Provide/Error",
 			give: &Provided{
@@ -363,6 +397,22 @@ func TestSlogLogger(t *testing.T) {
 				"module": "myModule",
 			},
 		},
+		{
+			name: "Run with module owner",
+			give: &Run{
+				Name:        "bytes.NewBuffer()",
+				Kind:        "constructor",
+				ModuleName:  "myModule",
+				ModuleOwner: "myTeam",
+			},
+			wantMessage: "run",
+			wantFields: map[string]interface{}{
+				"name":        "bytes.NewBuffer()",
+				"kind":        "constructor",
+				"module":      "myModule",
+				"moduleOwner": "myTeam",
+			},
+		},
 		{
 			name: "Run/Error",
 			give: &Run{
@@ -377,6 +427,42 @@ func TestSlogLogger(t *testing.T) {
 				"error": "some error",
 			},
 		},
+		{
+			name: "Panicked/Error",
+			give: &Panicked{
+				Name:       "bytes.NewBuffer()",
+				Kind:       "provide",
+				ModuleName: "myModule",
+				Value:      "great sadness",
+				Stack:      "goroutine 1 [running]:",
+				Err:        someError,
+			},
+			wantMessage: "panic recovered",
+			wantFields: map[string]interface{}{
+				"name":   "bytes.NewBuffer()",
+				"kind":   "provide",
+				"module": "myModule",
+				"value":  "great sadness",
+				"stack":  "goroutine 1 [running]:",
+				"error":  "some error",
+			},
+		},
+		{
+			name: "Panicked/NoStack/Error",
+			give: &Panicked{
+				Name:  "bytes.NewBuffer()",
+				Kind:  "invoke",
+				Value: "great sadness",
+				Err:   someError,
+			},
+			wantMessage: "panic recovered",
+			wantFields: map[string]interface{}{
+				"name":  "bytes.NewBuffer()",
+				"kind":  "invoke",
+				"value": "great sadness",
+				"error": "some error",
+			},
+		},
 		{
 			name:        "Invoking/Success",
 			give:        &Invoking{ModuleName: "myModule", FunctionName: "bytes.NewBuffer()"},
@@ -412,6 +498,15 @@ func TestSlogLogger(t *testing.T) {
 				"signal": "INTERRUPT",
 			},
 		},
+		{
+			name:        "Stopping/Reason",
+			give:        &Stopping{Signal: os.Interrupt, Reason: someError},
+			wantMessage: "received signal",
+			wantFields: map[string]interface{}{
+				"signal": "INTERRUPT",
+				"reason": "some error",
+			},
+		},
 		{
 			name:        "Stopped/Error",
 			give:        &Stopped{Err: someError},
@@ -436,12 +531,101 @@ func TestSlogLogger(t *testing.T) {
 				"error": "some error",
 			},
 		},
+		{
+			name: "RollingBack/Hook/Error",
+			give: &RollingBack{
+				StartErr:     someError,
+				FunctionName: "hook",
+				CallerName:   "bytes.NewBuffer",
+				Policy:       "RollbackStarted",
+			},
+			wantMessage: "start failed, rolling back",
+			wantFields: map[string]interface{}{
+				"error":  "some error",
+				"callee": "hook",
+				"caller": "bytes.NewBuffer",
+				"policy": "RollbackStarted",
+			},
+		},
+		{
+			name: "RolledBack/Hooks/Error",
+			give: &RolledBack{
+				Err:     someError,
+				Hooks:   []string{"second", "first"},
+				Failed:  []string{"second"},
+				Ignored: true,
+			},
+			wantMessage: "rollback failed",
+			wantFields: map[string]interface{}{
+				"error":   "some error",
+				"hooks":   []interface{}{"second", "first"},
+				"failed":  []interface{}{"second"},
+				"ignored": true,
+			},
+		},
+		{
+			name:        "RolledBack",
+			give:        &RolledBack{Hooks: []string{"first"}},
+			wantMessage: "rolled back",
+			wantFields: map[string]interface{}{
+				"hooks": []interface{}{"first"},
+			},
+		},
 		{
 			name:        "Started",
-			give:        &Started{},
+			give:        &Started{Runtime: 1200 * time.Millisecond},
 			wantMessage: "started",
+			wantFields: map[string]interface{}{
+				"runtime": "1.2s",
+			},
+		},
+		{
+			name:        "Restarting",
+			give:        &Restarting{},
+			wantMessage: "restarting",
 			wantFields:  map[string]interface{}{},
 		},
+		{
+			name:        "Restarted",
+			give:        &Restarted{Runtime: 1200 * time.Millisecond},
+			wantMessage: "restarted",
+			wantFields: map[string]interface{}{
+				"runtime": "1.2s",
+			},
+		},
+		{
+			name:        "Restarted/Error",
+			give:        &Restarted{Err: someError},
+			wantMessage: "restart failed",
+			wantFields: map[string]interface{}{
+				"error": "some error",
+			},
+		},
+		{
+			name:        "Reloaded",
+			give:        &Reloaded{Runtime: 1200 * time.Millisecond},
+			wantMessage: "reloaded",
+			wantFields: map[string]interface{}{
+				"runtime": "1.2s",
+			},
+		},
+		{
+			name:        "Reloaded/Signal",
+			give:        &Reloaded{Signal: os.Interrupt, Runtime: 1200 * time.Millisecond},
+			wantMessage: "reloaded",
+			wantFields: map[string]interface{}{
+				"signal":  "INTERRUPT",
+				"runtime": "1.2s",
+			},
+		},
+		{
+			name:        "Reloaded/Error",
+			give:        &Reloaded{Err: someError},
+			wantMessage: "reload failed",
+			wantFields: map[string]interface{}{
+				"error": "some error",
+			},
+		},
 		{
 			name:        "LoggerInitialized/Error",
 			give:        &LoggerInitialized{Err: someError},
@@ -575,3 +759,51 @@ func TestSlogLogger(t *testing.T) {
 		}
 	})
 }
+
+func TestSlogLoggerVerbosity(t *testing.T) {
+	t.Parallel()
+
+	logger, observedLogs := newSlogObservableLogger(slog.LevelDebug)
+	sl := &SlogLogger{Logger: logger}
+	sl.UseVerbosity(LifecycleOnly)
+
+	sl.LogEvent(&Provided{OutputTypeNames: []string{"*bytes.Buffer"}})
+	sl.LogEvent(&Provided{Err: errors.New("some error")})
+	sl.LogEvent(&Started{})
+
+	entries := observedLogs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "error encountered while applying options", entries[0].record.Message)
+	assert.Equal(t, "started", entries[1].record.Message)
+}
+
+func TestSlogLoggerWarnings(t *testing.T) {
+	t.Parallel()
+
+	logger, observedLogs := newSlogObservableLogger(slog.LevelInfo)
+	sl := &SlogLogger{Logger: logger}
+	sl.UseLogLevel(slog.LevelDebug)
+	sl.LogEvent(&AmbiguousDecoration{
+		TypeName:           "*bytes.Buffer",
+		ModuleName:         "myModule",
+		ProvideStackTrace:  []string{"main.provide"},
+		DecorateStackTrace: []string{"main.decorate"},
+	})
+
+	entries := observedLogs.TakeAll()
+	require.Len(t, entries, 1, "warnings must not use the log level")
+	assert.Equal(t, slog.LevelWarn, entries[0].record.Level)
+	assert.Equal(t, "type is both provided and decorated in the same module", entries[0].record.Message)
+	assert.Equal(t, map[string]interface{}{
+		"type":               "*bytes.Buffer",
+		"module":             "myModule",
+		"providestacktrace":  []interface{}{"main.provide"},
+		"decoratestacktrace": []interface{}{"main.decorate"},
+	}, entries[0].ContextMap())
+
+	sl.LogEvent(&RolledBack{Skipped: true})
+	entries = observedLogs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Equal(t, slog.LevelWarn, entries[1].record.Level)
+	assert.Equal(t, "rollback skipped, started hooks left running", entries[1].record.Message)
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxevent
+
+import "strconv"
+
+// Verbosity controls which events are logged by the loggers in this package.
+// Events that report an error or a warning are logged at every verbosity.
+type Verbosity int
+
+const (
+	// Verbose logs all events. This is the default.
+	Verbose Verbosity = iota
+
+	// LifecycleOnly logs events about starting and stopping
+	// the application, including its hooks.
+	// Events about building the dependency graph,
+	// such as [Provided] and [Supplied], are only logged on failure.
+	LifecycleOnly
+
+	// ErrorsOnly logs only events that report an error.
+	ErrorsOnly
+)
+
+// String returns the name of the verbosity.
+func (v Verbosity) String() string {
+	switch v {
+	case Verbose:
+		return "Verbose"
+	case LifecycleOnly:
+		return "LifecycleOnly"
+	case ErrorsOnly:
+		return "ErrorsOnly"
+	default:
+		return "Verbosity(" + strconv.Itoa(int(v)) + ")"
+	}
+}
+
+// Allows reports whether event should be logged at this verbosity.
+// Loggers implemented outside this package use it to support Verbosity.
+func (v Verbosity) Allows(event Event) bool {
+	switch {
+	case v == Verbose, isError(event), isWarning(event):
+		return true
+	case v == LifecycleOnly:
+		return isLifecycle(event)
+	default:
+		return false
+	}
+}
+
+// isError reports whether event reports a failure.
+func isError(event Event) bool {
+	switch e := event.(type) {
+	case *OnStartExecuted:
+		return e.Err != nil
+	case *OnStopExecuted:
+		return e.Err != nil
+	case *Supplied:
+		return e.Err != nil
+	case *Provided:
+		return e.Err != nil
+	case *Replaced:
+		return e.Err != nil
+	case *Decorated:
+		return e.Err != nil
+	case *Run:
+		return e.Err != nil
+	case *Panicked:
+		return true
+	case *Invoked:
+		return e.Err != nil
+	case *Stopped:
+		return e.Err != nil
+	case *RollingBack:
+		return true
+	case *RolledBack:
+		return e.Err != nil || e.Skipped
+	case *Started:
+		return e.Err != nil
+	case *Restarted:
+		return e.Err != nil
+	case *Reloaded:
+		return e.Err != nil
+	case *LoggerInitialized:
+		return e.Err != nil
+	default:
+		return false
+	}
+}
+
+// isWarning reports whether event warns about a likely mistake.
+func isWarning(event Event) bool {
+	_, ok := event.(*AmbiguousDecoration)
+	return ok
+}
+
+// isLifecycle reports whether event is about starting or stopping
+// the application.
+func isLifecycle(event Event) bool {
+	switch event.(type) {
+	case *OnStartExecuting, *OnStartExecuted,
+		*OnStopExecuting, *OnStopExecuted,
+		*Started, *Stopping, *Stopped,
+		*RollingBack, *RolledBack,
+		*Restarting, *Restarted, *Reloaded:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxevent
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestVerbosity(t *testing.T) {
+	t.Parallel()
+
+	someError := errors.New("some error")
+
+	tests := []struct {
+		name string
+		give Event
+
+		// Whether the event is logged at each verbosity.
+		wantLifecycle bool
+		wantErrors    bool
+	}{
+		{name: "Provided", give: &Provided{OutputTypeNames: []string{"*bytes.Buffer"}}},
+		{
+			name:          "Provided/Error",
+			give:          &Provided{Err: someError},
+			wantLifecycle: true,
+			wantErrors:    true,
+		},
+		{name: "Supplied", give: &Supplied{TypeName: "*bytes.Buffer"}},
+		{
+			name:          "Supplied/Error",
+			give:          &Supplied{TypeName: "*bytes.Buffer", Err: someError},
+			wantLifecycle: true,
+			wantErrors:    true,
+		},
+		{name: "Decorated", give: &Decorated{OutputTypeNames: []string{"*bytes.Buffer"}}},
+		{name: "Invoking", give: &Invoking{FunctionName: "bytes.NewBuffer"}},
+		{name: "LoggerInitialized", give: &LoggerInitialized{ConstructorName: "bytes.NewBuffer"}},
+		{
+			name:          "OnStartExecuting",
+			give:          &OnStartExecuting{FunctionName: "hook.onStart"},
+			wantLifecycle: true,
+		},
+		{
+			name:          "OnStopExecuted/Error",
+			give:          &OnStopExecuted{FunctionName: "hook.onStop", Err: someError},
+			wantLifecycle: true,
+			wantErrors:    true,
+		},
+		{name: "Started", give: &Started{}, wantLifecycle: true},
+		{name: "Restarting", give: &Restarting{}, wantLifecycle: true},
+		{name: "Reloaded", give: &Reloaded{}, wantLifecycle: true},
+		{
+			name:          "Reloaded/Error",
+			give:          &Reloaded{Err: someError},
+			wantLifecycle: true,
+			wantErrors:    true,
+		},
+		{
+			name:          "Restarted/Error",
+			give:          &Restarted{Err: someError},
+			wantLifecycle: true,
+			wantErrors:    true,
+		},
+		{
+			name:          "Started/Error",
+			give:          &Started{Err: someError},
+			wantLifecycle: true,
+			wantErrors:    true,
+		},
+		{
+			name: "AmbiguousDecoration",
+			give: &AmbiguousDecoration{
+				TypeName:           "*bytes.Buffer",
+				ProvideStackTrace:  []string{"main.provide"},
+				DecorateStackTrace: []string{"main.decorate"},
+			},
+			wantLifecycle: true,
+			wantErrors:    true,
+		},
+		{
+			name:          "RollingBack",
+			give:          &RollingBack{StartErr: someError},
+			wantLifecycle: true,
+			wantErrors:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			for _, v := range []struct {
+				verbosity Verbosity
+				want      bool
+			}{
+				{Verbose, true},
+				{LifecycleOnly, tt.wantLifecycle},
+				{ErrorsOnly, tt.wantErrors},
+			} {
+				t.Run(v.verbosity.String(), func(t *testing.T) {
+					var buf bytes.Buffer
+					console := &ConsoleLogger{W: &buf}
+					console.UseVerbosity(v.verbosity)
+					console.LogEvent(tt.give)
+
+					core, observedLogs := observer.New(zap.DebugLevel)
+					zl := &ZapLogger{Logger: zap.New(core)}
+					zl.UseVerbosity(v.verbosity)
+					zl.LogEvent(tt.give)
+
+					if v.want {
+						assert.NotEmpty(t, buf.String(), "console: event must be logged")
+						assert.NotZero(t, observedLogs.Len(), "zap: event must be logged")
+					} else {
+						assert.Empty(t, buf.String(), "console: event must not be logged")
+						assert.Zero(t, observedLogs.Len(), "zap: event must not be logged")
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestVerbosityString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Verbose", Verbose.String())
+	assert.Equal(t, "LifecycleOnly", LifecycleOnly.String())
+	assert.Equal(t, "ErrorsOnly", ErrorsOnly.String())
+	assert.Equal(t, "Verbosity(42)", Verbosity(42).String())
+}
@@ -21,7 +21,10 @@
 package fxevent
 
 import (
+	"os"
+	"reflect"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -33,6 +36,10 @@ type ZapLogger struct {
 
 	logLevel   zapcore.Level // default: zapcore.InfoLevel
 	errorLevel *zapcore.Level
+	verbosity  Verbosity // default: Verbose
+
+	// levels of non-error logs for specific event types
+	eventLevels map[reflect.Type]zapcore.Level
 }
 
 var _ Logger = (*ZapLogger)(nil)
@@ -47,10 +54,34 @@ func (l *ZapLogger) UseLogLevel(level zapcore.Level) {
 	l.logLevel = level
 }
 
+// UseVerbosity sets which events are logged to v.
+// Events that report an error are always logged.
+func (l *ZapLogger) UseVerbosity(v Verbosity) {
+	l.verbosity = v
+}
+
+// UseEventLevel sets the level of non-error logs emitted by Fx for events
+// of the same type as event to level, overriding [ZapLogger.UseLogLevel].
+// Errors reported by those events are still logged at the level set by
+// [ZapLogger.UseErrorLevel].
+//
+//	logger.UseEventLevel(&fxevent.Provided{}, zapcore.DebugLevel)
+//	logger.UseEventLevel(&fxevent.OnStartExecuted{}, zapcore.InfoLevel)
+func (l *ZapLogger) UseEventLevel(event Event, level zapcore.Level) {
+	if l.eventLevels == nil {
+		l.eventLevels = make(map[reflect.Type]zapcore.Level)
+	}
+	l.eventLevels[reflect.TypeOf(event)] = level
+}
+
 func (l *ZapLogger) logEvent(msg string, fields ...zap.Field) {
 	l.Logger.Log(l.logLevel, msg, fields...)
 }
 
+func (l *ZapLogger) logWarning(msg string, fields ...zap.Field) {
+	l.Logger.Log(zapcore.WarnLevel, msg, fields...)
+}
+
 func (l *ZapLogger) logError(msg string, fields ...zap.Field) {
 	lvl := zapcore.ErrorLevel
 	if l.errorLevel != nil {
@@ -61,6 +92,21 @@ func (l *ZapLogger) logError(msg string, fields ...zap.Field) {
 
 // LogEvent logs the given event to the provided Zap logger.
 func (l *ZapLogger) LogEvent(event Event) {
+	if !l.verbosity.Allows(event) {
+		return
+	}
+
+	if lvl, ok := l.eventLevels[reflect.TypeOf(event)]; ok {
+		// Log with a copy so that concurrent events don't share the level.
+		el := *l
+		el.logLevel = lvl
+		el.log(event)
+		return
+	}
+	l.log(event)
+}
+
+func (l *ZapLogger) log(event Event) {
 	switch e := event.(type) {
 	case *OnStartExecuting:
 		l.logEvent("OnStart hook executing",
@@ -72,6 +118,7 @@ func (l *ZapLogger) LogEvent(event Event) {
 			l.logError("OnStart hook failed",
 				zap.String("callee", e.FunctionName),
 				zap.String("caller", e.CallerName),
+				maybeDuration("timeout", e.Timeout),
 				zap.Error(e.Err),
 			)
 		} else {
@@ -79,6 +126,7 @@ func (l *ZapLogger) LogEvent(event Event) {
 				zap.String("callee", e.FunctionName),
 				zap.String("caller", e.CallerName),
 				zap.String("runtime", e.Runtime.String()),
+				maybeDuration("timeout", e.Timeout),
 			)
 		}
 	case *OnStopExecuting:
@@ -91,6 +139,7 @@ func (l *ZapLogger) LogEvent(event Event) {
 			l.logError("OnStop hook failed",
 				zap.String("callee", e.FunctionName),
 				zap.String("caller", e.CallerName),
+				maybeDuration("timeout", e.Timeout),
 				zap.Error(e.Err),
 			)
 		} else {
@@ -98,6 +147,7 @@ func (l *ZapLogger) LogEvent(event Event) {
 				zap.String("callee", e.FunctionName),
 				zap.String("caller", e.CallerName),
 				zap.String("runtime", e.Runtime.String()),
+				maybeDuration("timeout", e.Timeout),
 			)
 		}
 	case *Supplied:
@@ -107,6 +157,7 @@ func (l *ZapLogger) LogEvent(event Event) {
 				zap.Strings("stacktrace", e.StackTrace),
 				zap.Strings("moduletrace", e.ModuleTrace),
 				moduleField(e.ModuleName),
+				ownerField(e.ModuleOwner),
 				zap.Error(e.Err))
 		} else {
 			l.logEvent("supplied",
@@ -114,6 +165,7 @@ func (l *ZapLogger) LogEvent(event Event) {
 				zap.Strings("stacktrace", e.StackTrace),
 				zap.Strings("moduletrace", e.ModuleTrace),
 				moduleField(e.ModuleName),
+				ownerField(e.ModuleOwner),
 			)
 		}
 	case *Provided:
@@ -123,13 +175,16 @@ func (l *ZapLogger) LogEvent(event Event) {
 				zap.Strings("stacktrace", e.StackTrace),
 				zap.Strings("moduletrace", e.ModuleTrace),
 				moduleField(e.ModuleName),
+				ownerField(e.ModuleOwner),
 				zap.String("type", rtype),
 				maybeBool("private", e.Private),
+				maybeBool("derived", e.Derived),
 			)
 		}
 		if e.Err != nil {
 			l.logError("error encountered while applying options",
 				moduleField(e.ModuleName),
+				ownerField(e.ModuleOwner),
 				zap.Strings("stacktrace", e.StackTrace),
 				zap.Strings("moduletrace", e.ModuleTrace),
 				zap.Error(e.Err))
@@ -140,6 +195,7 @@ func (l *ZapLogger) LogEvent(event Event) {
 				zap.Strings("stacktrace", e.StackTrace),
 				zap.Strings("moduletrace", e.ModuleTrace),
 				moduleField(e.ModuleName),
+				ownerField(e.ModuleOwner),
 				zap.String("type", rtype),
 			)
 		}
@@ -148,6 +204,7 @@ func (l *ZapLogger) LogEvent(event Event) {
 				zap.Strings("stacktrace", e.StackTrace),
 				zap.Strings("moduletrace", e.ModuleTrace),
 				moduleField(e.ModuleName),
+				ownerField(e.ModuleOwner),
 				zap.Error(e.Err))
 		}
 	case *Decorated:
@@ -157,6 +214,7 @@ func (l *ZapLogger) LogEvent(event Event) {
 				zap.Strings("stacktrace", e.StackTrace),
 				zap.Strings("moduletrace", e.ModuleTrace),
 				moduleField(e.ModuleName),
+				ownerField(e.ModuleOwner),
 				zap.String("type", rtype),
 			)
 		}
@@ -165,14 +223,23 @@ func (l *ZapLogger) LogEvent(event Event) {
 				zap.Strings("stacktrace", e.StackTrace),
 				zap.Strings("moduletrace", e.ModuleTrace),
 				moduleField(e.ModuleName),
+				ownerField(e.ModuleOwner),
 				zap.Error(e.Err))
 		}
+	case *AmbiguousDecoration:
+		l.logWarning("type is both provided and decorated in the same module",
+			zap.String("type", e.TypeName),
+			moduleField(e.ModuleName),
+			zap.Strings("providestacktrace", e.ProvideStackTrace),
+			zap.Strings("decoratestacktrace", e.DecorateStackTrace),
+		)
 	case *Run:
 		if e.Err != nil {
 			l.logError("error returned",
 				zap.String("name", e.Name),
 				zap.String("kind", e.Kind),
 				moduleField(e.ModuleName),
+				ownerField(e.ModuleOwner),
 				zap.Error(e.Err),
 			)
 		} else {
@@ -180,13 +247,25 @@ func (l *ZapLogger) LogEvent(event Event) {
 				zap.String("name", e.Name),
 				zap.String("kind", e.Kind),
 				moduleField(e.ModuleName),
+				ownerField(e.ModuleOwner),
 			)
 		}
+	case *Panicked:
+		l.logError("panic recovered",
+			zap.String("name", e.Name),
+			zap.String("kind", e.Kind),
+			moduleField(e.ModuleName),
+			ownerField(e.ModuleOwner),
+			zap.Any("value", e.Value),
+			maybeStack(e.Stack),
+			zap.Error(e.Err),
+		)
 	case *Invoking:
 		// Do not log stack as it will make logs hard to read.
 		l.logEvent("invoking",
 			zap.String("function", e.FunctionName),
 			moduleField(e.ModuleName),
+			ownerField(e.ModuleOwner),
 		)
 	case *Invoked:
 		if e.Err != nil {
@@ -195,26 +274,57 @@ func (l *ZapLogger) LogEvent(event Event) {
 				zap.String("stack", e.Trace),
 				zap.String("function", e.FunctionName),
 				moduleField(e.ModuleName),
+				ownerField(e.ModuleOwner),
 			)
 		}
 	case *Stopping:
 		l.logEvent("received signal",
-			zap.String("signal", strings.ToUpper(e.Signal.String())))
+			zap.String("signal", strings.ToUpper(e.Signal.String())),
+			zap.NamedError("reason", e.Reason))
 	case *Stopped:
 		if e.Err != nil {
 			l.logError("stop failed", zap.Error(e.Err))
 		}
 	case *RollingBack:
-		l.logError("start failed, rolling back", zap.Error(e.StartErr))
+		l.logError("start failed, rolling back",
+			zap.Error(e.StartErr),
+			maybeString("callee", e.FunctionName),
+			maybeString("caller", e.CallerName),
+			maybeString("policy", e.Policy),
+		)
 	case *RolledBack:
-		if e.Err != nil {
-			l.logError("rollback failed", zap.Error(e.Err))
+		switch {
+		case e.Skipped:
+			l.logWarning("rollback skipped, started hooks left running")
+		case e.Err != nil:
+			l.logError("rollback failed",
+				zap.Error(e.Err),
+				maybeStrings("hooks", e.Hooks),
+				maybeStrings("failed", e.Failed),
+				maybeBool("ignored", e.Ignored),
+			)
+		case len(e.Hooks) > 0:
+			l.logEvent("rolled back", zap.Strings("hooks", e.Hooks))
 		}
 	case *Started:
 		if e.Err != nil {
 			l.logError("start failed", zap.Error(e.Err))
 		} else {
-			l.logEvent("started")
+			l.logEvent("started", zap.String("runtime", e.Runtime.String()))
+		}
+	case *Restarting:
+		l.logEvent("restarting")
+	case *Restarted:
+		if e.Err != nil {
+			l.logError("restart failed", zap.Error(e.Err))
+		} else {
+			l.logEvent("restarted", zap.String("runtime", e.Runtime.String()))
+		}
+	case *Reloaded:
+		if e.Err != nil {
+			l.logError("reload failed", maybeSignal(e.Signal), zap.Error(e.Err))
+		} else {
+			l.logEvent("reloaded", maybeSignal(e.Signal), zap.String("runtime", e.Runtime.String()))
 		}
 	case *LoggerInitialized:
 		if e.Err != nil {
@@ -225,6 +335,13 @@ func (l *ZapLogger) LogEvent(event Event) {
 	}
 }
 
+func maybeStack(stack string) zap.Field {
+	if len(stack) == 0 {
+		return zap.Skip()
+	}
+	return zap.String("stack", stack)
+}
+
 func moduleField(name string) zap.Field {
 	if len(name) == 0 {
 		return zap.Skip()
@@ -232,9 +349,44 @@ func moduleField(name string) zap.Field {
 	return zap.String("module", name)
 }
 
+func ownerField(owner string) zap.Field {
+	if len(owner) == 0 {
+		return zap.Skip()
+	}
+	return zap.String("moduleOwner", owner)
+}
+
+func maybeString(name, value string) zap.Field {
+	if len(value) > 0 {
+		return zap.String(name, value)
+	}
+	return zap.Skip()
+}
+
+func maybeStrings(name string, values []string) zap.Field {
+	if len(values) > 0 {
+		return zap.Strings(name, values)
+	}
+	return zap.Skip()
+}
+
 func maybeBool(name string, b bool) zap.Field {
 	if b {
 		return zap.Bool(name, true)
 	}
 	return zap.Skip()
 }
+
+func maybeDuration(name string, d time.Duration) zap.Field {
+	if d > 0 {
+		return zap.String(name, d.String())
+	}
+	return zap.Skip()
+}
+
+func maybeSignal(sig os.Signal) zap.Field {
+	if sig != nil {
+		return zap.String("signal", strings.ToUpper(sig.String()))
+	}
+	return zap.Skip()
+}
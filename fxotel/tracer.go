@@ -0,0 +1,66 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package fxotel records how Fx builds, starts, and stops applications
+// as OpenTelemetry spans.
+//
+//	fx.New(
+//		fx.WithTracer(&fxotel.Tracer{
+//			Tracer: otel.Tracer("go.uber.org/fx"),
+//		}),
+//		...
+//	)
+//
+// Constructors and lifecycle hooks are recorded as children of "fx.New",
+// "fx.Start", and "fx.Stop" spans; see [fx.WithTracer].
+//
+// It's a module of its own,
+// so that applications that don't use OpenTelemetry don't depend on it.
+package fxotel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+)
+
+var _ fx.Tracer = (*Tracer)(nil)
+
+// Tracer is an [fx.Tracer] that records spans with an OpenTelemetry tracer.
+//
+// Spans of steps that fail record their error as an event,
+// and have an error status.
+type Tracer struct {
+	Tracer trace.Tracer
+}
+
+// StartSpan starts a span named name as a child of the span of ctx, if any.
+func (t *Tracer) StartSpan(ctx context.Context, name string) (context.Context, func(error)) {
+	ctx, span := t.Tracer.Start(ctx, name)
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+type server struct{}
+
+func newServer(lc fx.Lifecycle) *server {
+	lc.Append(fx.StartStopHook(startServer, stopServer))
+	return &server{}
+}
+
+func startServer(context.Context) error { return nil }
+
+func stopServer(context.Context) error { return errors.New("great sadness") }
+
+func newRecordingTracer() (*Tracer, *tracetest.SpanRecorder) {
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	return &Tracer{Tracer: tp.Tracer("fxotel_test")}, rec
+}
+
+// endedSpans returns the spans rec recorded the end of, by name.
+func endedSpans(rec *tracetest.SpanRecorder) map[string]sdktrace.ReadOnlySpan {
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, s := range rec.Ended() {
+		spans[s.Name()] = s
+	}
+	return spans
+}
+
+func TestTracer(t *testing.T) {
+	t.Parallel()
+
+	tracer, rec := newRecordingTracer()
+	app := fxtest.New(t,
+		fx.WithTracer(tracer),
+		fx.Provide(newServer),
+		fx.Invoke(func(*server) {}),
+	)
+	app.RequireStart()
+	require.Error(t, app.Stop(context.Background()))
+
+	spans := endedSpans(rec)
+	for name, parent := range map[string]string{
+		"fx.Provide: go.uber.org/fx/fxotel.newServer()":   "fx.New",
+		"fx.OnStart: go.uber.org/fx/fxotel.startServer()": "fx.Start",
+		"fx.OnStop: go.uber.org/fx/fxotel.stopServer()":   "fx.Stop",
+	} {
+		s, ok := spans[name]
+		require.True(t, ok, "span %q must be recorded", name)
+		p, ok := spans[parent]
+		require.True(t, ok, "span %q must be recorded", parent)
+
+		assert.Equal(t, p.SpanContext().SpanID(), s.Parent().SpanID(),
+			"span %q must be a child of %q", name, parent)
+		assert.Equal(t, p.SpanContext().TraceID(), s.SpanContext().TraceID(),
+			"span %q must belong to the trace of %q", name, parent)
+		assert.False(t, s.StartTime().Before(p.StartTime()),
+			"span %q must not start before %q", name, parent)
+		assert.False(t, s.EndTime().After(p.EndTime()),
+			"span %q must not end after %q", name, parent)
+	}
+
+	stop := spans["fx.OnStop: go.uber.org/fx/fxotel.stopServer()"]
+	assert.Equal(t, codes.Error, stop.Status().Code)
+	assert.Equal(t, "great sadness", stop.Status().Description)
+	if assert.Len(t, stop.Events(), 1) {
+		assert.Equal(t, "exception", stop.Events()[0].Name)
+	}
+	assert.Equal(t, codes.Error, spans["fx.Stop"].Status().Code)
+	assert.Equal(t, codes.Unset, spans["fx.Start"].Status().Code)
+}
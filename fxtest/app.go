@@ -22,6 +22,9 @@ package fxtest
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"go.uber.org/fx"
 )
@@ -74,3 +77,87 @@ func (app *App) RequireStop() {
 		app.tb.FailNow()
 	}
 }
+
+// RequireStartWithin calls Start with a deadline of d, failing the test if
+// an error is encountered or if the application takes longer than d
+// to start. On failure, it reports how long each OnStart hook ran.
+//
+// Use it to guard against slow hooks creeping into an application:
+//
+//	app := fxtest.New(t, server.Module).RequireStartWithin(time.Second)
+//	defer app.RequireStopWithin(time.Second)
+func (app *App) RequireStartWithin(d time.Duration) *App {
+	startCtx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	begin := time.Now()
+	err := app.Start(startCtx)
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil:
+		app.tb.Errorf("application didn't start cleanly within %v: %v\n%s",
+			d, err, startTimings(app.RegisteredHooks()))
+		app.tb.FailNow()
+	case elapsed > d:
+		app.tb.Errorf("application took %v to start, longer than %v\n%s",
+			elapsed, d, startTimings(app.RegisteredHooks()))
+		app.tb.FailNow()
+	}
+	return app
+}
+
+// RequireStopWithin calls Stop with a deadline of d, failing the test if
+// an error is encountered or if the application takes longer than d
+// to stop. On failure, it reports how long each OnStop hook ran.
+func (app *App) RequireStopWithin(d time.Duration) {
+	stopCtx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	begin := time.Now()
+	err := app.Stop(stopCtx)
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil:
+		app.tb.Errorf("application didn't stop cleanly within %v: %v\n%s",
+			d, err, stopTimings(app.RegisteredHooks()))
+		app.tb.FailNow()
+	case elapsed > d:
+		app.tb.Errorf("application took %v to stop, longer than %v\n%s",
+			elapsed, d, stopTimings(app.RegisteredHooks()))
+		app.tb.FailNow()
+	}
+}
+
+// startTimings describes how long the OnStart function of each hook ran.
+// Hooks that were still running or never ran are reported as pending.
+func startTimings(hooks []fx.HookInfo) string {
+	var sb strings.Builder
+	sb.WriteString("OnStart hook timings:")
+	for _, h := range hooks {
+		if h.OnStart == "" {
+			continue
+		}
+		writeTiming(&sb, h.Name, h.OnStart, h.StartRuntime, h.Status)
+	}
+	return sb.String()
+}
+
+// stopTimings describes how long the OnStop function of each hook ran.
+func stopTimings(hooks []fx.HookInfo) string {
+	var sb strings.Builder
+	sb.WriteString("OnStop hook timings:")
+	for _, h := range hooks {
+		if h.OnStop == "" {
+			continue
+		}
+		writeTiming(&sb, h.Name, h.OnStop, h.StopRuntime, h.Status)
+	}
+	return sb.String()
+}
+
+func writeTiming(sb *strings.Builder, name, funcName string, runtime time.Duration, status fx.HookStatus) {
+	if name == "" {
+		name = funcName
+	}
+	fmt.Fprintf(sb, "\n\t%v\t%v (%v)", runtime, name, status)
+}
@@ -24,6 +24,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/fx"
@@ -96,4 +97,70 @@ func TestApp(t *testing.T) {
 		assert.Equal(t, 1, spy.failures, "Expected Stop to fail.")
 		assert.Contains(t, spy.errors.String(), "didn't stop cleanly", "Expected to write errors to TB.")
 	})
+
+	t.Run("StartAndStopWithin", func(t *testing.T) {
+		t.Parallel()
+
+		spy := newTB()
+
+		New(
+			spy,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.Hook{
+					OnStart: func(context.Context) error { return nil },
+					OnStop:  func(context.Context) error { return nil },
+				})
+			}),
+		).RequireStartWithin(time.Minute).RequireStopWithin(time.Minute)
+
+		assert.Zero(t, spy.failures, "App didn't start and stop in time.")
+	})
+
+	t.Run("StartWithinSlowHook", func(t *testing.T) {
+		t.Parallel()
+
+		spy := newTB()
+
+		New(
+			spy,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.Hook{
+					Name: "slow-start",
+					OnStart: func(ctx context.Context) error {
+						<-ctx.Done()
+						return ctx.Err()
+					},
+				})
+			}),
+		).RequireStartWithin(10 * time.Millisecond)
+
+		assert.Equal(t, 1, spy.failures, "Expected app to miss the start deadline.")
+		assert.Contains(t, spy.errors.String(), "didn't start cleanly within 10ms")
+		assert.Contains(t, spy.errors.String(), "OnStart hook timings:")
+		assert.Contains(t, spy.errors.String(), "slow-start")
+	})
+
+	t.Run("StopWithinSlowHook", func(t *testing.T) {
+		t.Parallel()
+
+		spy := newTB()
+
+		New(
+			spy,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.Hook{
+					Name: "slow-stop",
+					OnStop: func(ctx context.Context) error {
+						<-ctx.Done()
+						return ctx.Err()
+					},
+				})
+			}),
+		).RequireStart().RequireStopWithin(10 * time.Millisecond)
+
+		assert.Equal(t, 1, spy.failures, "Expected app to miss the stop deadline.")
+		assert.Contains(t, spy.errors.String(), "didn't stop cleanly within 10ms")
+		assert.Contains(t, spy.errors.String(), "OnStop hook timings:")
+		assert.Contains(t, spy.errors.String(), "slow-stop")
+	})
 }
@@ -0,0 +1,93 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxtest
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/fx/internal/fxclock"
+)
+
+// Clock is a fake [fx.Clock] for tests.
+// Time only passes on it when the test advances it with [Clock.Add],
+// so that timeouts and retries can be exercised deterministically.
+//
+//	clock := fxtest.NewClock()
+//	app := fxtest.New(t,
+//		fx.WithClock(clock),
+//		fx.Invoke(func(lc fx.Lifecycle) {
+//			lc.Append(fx.Hook{OnStart: slowStart, Timeout: time.Second})
+//		}),
+//	)
+//	errc := make(chan error, 1)
+//	go func() { errc <- app.Start(context.Background()) }()
+//	clock.AwaitScheduled(1) // wait for the hook's timeout to be scheduled
+//	clock.Add(time.Second)
+//	err := <-errc // the hook timed out
+type Clock struct {
+	mock *fxclock.Mock
+}
+
+var _ fx.Clock = (*Clock)(nil)
+
+// NewClock builds a new fake clock,
+// using the current actual time as the initial time.
+func NewClock() *Clock {
+	return &Clock{mock: fxclock.NewMock()}
+}
+
+// Now reports the current time.
+func (c *Clock) Now() time.Time {
+	return c.mock.Now()
+}
+
+// Since reports the time elapsed since t.
+func (c *Clock) Since(t time.Time) time.Duration {
+	return c.mock.Since(t)
+}
+
+// Sleep blocks until the clock is advanced past d with [Clock.Add].
+func (c *Clock) Sleep(d time.Duration) {
+	c.mock.Sleep(d)
+}
+
+// WithTimeout returns a context that expires
+// once the clock is advanced past d with [Clock.Add].
+func (c *Clock) WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	return c.mock.WithTimeout(ctx, d)
+}
+
+// Add advances the clock by d,
+// expiring the timeouts and waking up the sleepers that are due.
+// It panics if d is negative.
+func (c *Clock) Add(d time.Duration) {
+	c.mock.Add(d)
+}
+
+// AwaitScheduled blocks until at least n timeouts or sleeps
+// are waiting for the clock to advance.
+// Use it to make sure the application is waiting on the clock
+// before calling [Clock.Add].
+func (c *Clock) AwaitScheduled(n int) {
+	c.mock.AwaitScheduled(n)
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+func TestClock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Since", func(t *testing.T) {
+		t.Parallel()
+
+		clock := NewClock()
+		start := clock.Now()
+		clock.Add(3 * time.Second)
+		assert.Equal(t, 3*time.Second, clock.Since(start))
+	})
+
+	t.Run("hook timeout", func(t *testing.T) {
+		t.Parallel()
+
+		clock := NewClock()
+		app := New(t,
+			fx.WithClock(clock),
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.Hook{
+					OnStart: func(ctx context.Context) error {
+						<-ctx.Done()
+						return ctx.Err()
+					},
+					Timeout: time.Second,
+				})
+			}),
+		)
+
+		errc := make(chan error, 1)
+		go func() { errc <- app.Start(context.Background()) }()
+
+		clock.AwaitScheduled(1)
+		clock.Add(time.Second)
+		assert.ErrorIs(t, <-errc, context.DeadlineExceeded)
+	})
+
+	t.Run("hook retry backoff", func(t *testing.T) {
+		t.Parallel()
+
+		var attempts int
+		clock := NewClock()
+		app := New(t,
+			fx.WithClock(clock),
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.Hook{
+					OnStart: func(context.Context) error {
+						attempts++
+						if attempts == 1 {
+							return errors.New("not yet")
+						}
+						return nil
+					},
+					Retry: fx.RetryPolicy{
+						Attempts: 2,
+						Backoff: func(int) time.Duration {
+							return time.Minute
+						},
+					},
+				})
+			}),
+		)
+
+		errc := make(chan error, 1)
+		go func() { errc <- app.Start(context.Background()) }()
+
+		clock.AwaitScheduled(1)
+		clock.Add(time.Minute)
+		require.NoError(t, <-errc)
+		assert.Equal(t, 2, attempts)
+		require.NoError(t, app.Stop(context.Background()))
+	})
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxtest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.uber.org/fx"
+)
+
+// UpdateGoldenEnv is the environment variable that makes [VerifyGraph]
+// write the golden file instead of comparing against it.
+//
+//	FXTEST_UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnv = "FXTEST_UPDATE_GOLDEN"
+
+// _fxPrefix prefixes the names of the constructors Fx provides itself.
+const _fxPrefix = "go.uber.org/fx."
+
+// VerifyGraph compares the dependency graph of app against the golden file
+// at path, failing the test with a line diff if they differ.
+// Use it to catch architectural drift, such as a new dependency
+// into a module that should stay isolated.
+//
+//	func TestGraph(t *testing.T) {
+//		app := fx.New(server.Module, fx.NopLogger)
+//		fxtest.VerifyGraph(t, app, "testdata/graph.golden")
+//	}
+//
+// The graph is written in a deterministic, line-oriented text format
+// that lists each module with the functions passed to it,
+// followed by the dependencies between those functions.
+// Constructors Fx provides itself, such as the one for [fx.Lifecycle],
+// are left out so that upgrading Fx doesn't change the golden file.
+//
+// Run the tests with the environment variable named by [UpdateGoldenEnv]
+// set to a non-empty value to create or update golden files.
+func VerifyGraph(t TB, app *fx.App, path string) {
+	got := FormatGraph(app.Graph())
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("fxtest.VerifyGraph: %v", err)
+			t.FailNow()
+			return
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Errorf("fxtest.VerifyGraph: %v", err)
+			t.FailNow()
+			return
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("fxtest.VerifyGraph: %v\n"+
+			"Set %v=1 to create the golden file.", err, UpdateGoldenEnv)
+		t.FailNow()
+		return
+	}
+
+	if !bytes.Equal(want, got) {
+		t.Errorf("dependency graph doesn't match %v (-want +got):\n%s\n"+
+			"Set %v=1 to update the golden file.",
+			path, lineDiff(string(want), string(got)), UpdateGoldenEnv)
+		t.FailNow()
+	}
+}
+
+// FormatGraph renders g in the text format used by [VerifyGraph].
+func FormatGraph(g fx.Graph) []byte {
+	var buf bytes.Buffer
+	names := make(map[int]string) // node ID => qualified name
+	writeGraphModule(&buf, g.Root, "", 0, names)
+
+	var edges []string
+	for _, e := range g.Edges {
+		from, ok := names[e.From]
+		if !ok {
+			continue
+		}
+		to, ok := names[e.To]
+		if !ok {
+			continue
+		}
+		edges = append(edges, fmt.Sprintf("%v -> %v: %v", from, to, formatGraphValue(e.Value)))
+	}
+	sort.Strings(edges)
+
+	buf.WriteString("edges\n")
+	for _, e := range edges {
+		fmt.Fprintf(&buf, "  %v\n", e)
+	}
+	return buf.Bytes()
+}
+
+func writeGraphModule(buf *bytes.Buffer, m fx.GraphModule, path string, depth int, names map[int]string) {
+	indent := strings.Repeat("  ", depth)
+	if depth == 0 {
+		buf.WriteString("module (root)\n")
+	} else {
+		path += m.Name + "/"
+		fmt.Fprintf(buf, "%vmodule %q\n", indent, m.Name)
+	}
+
+	for _, n := range m.Nodes {
+		if depth == 0 && strings.HasPrefix(n.Name, _fxPrefix) {
+			continue
+		}
+		names[n.ID] = path + n.Name
+
+		fmt.Fprintf(buf, "%v  %v %v", indent, n.Kind, n.Name)
+		if n.Private {
+			buf.WriteString(" (private)")
+		}
+		buf.WriteString("\n")
+		for _, v := range n.Inputs {
+			fmt.Fprintf(buf, "%v    in: %v\n", indent, formatGraphValue(v))
+		}
+		for _, v := range n.Outputs {
+			fmt.Fprintf(buf, "%v    out: %v\n", indent, formatGraphValue(v))
+		}
+	}
+
+	for _, sub := range m.Modules {
+		writeGraphModule(buf, sub, path, depth+1, names)
+	}
+}
+
+func formatGraphValue(v fx.GraphValue) string {
+	s := v.Type
+	if v.Name != "" {
+		s += fmt.Sprintf(" name:%q", v.Name)
+	}
+	if v.Group != "" {
+		s += fmt.Sprintf(" group:%q", v.Group)
+	}
+	if v.Optional {
+		s += " optional"
+	}
+	return s
+}
+
+// lineDiff returns the lines removed from want and added in got,
+// prefixed with "-" and "+", with the lines they share prefixed with " ".
+func lineDiff(want, got string) string {
+	a := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence
+	// of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			fmt.Fprintf(&sb, " %v\n", a[i])
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(&sb, "-%v\n", a[i])
+			i++
+		default:
+			fmt.Fprintf(&sb, "+%v\n", b[j])
+			j++
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+type (
+	graphConfig struct{}
+	graphServer struct{}
+)
+
+func newGraphConfig() *graphConfig { return &graphConfig{} }
+
+func newGraphPort(*graphConfig) int { return 8080 }
+
+func newGraphServer([]int) *graphServer { return &graphServer{} }
+
+func runGraphServer(*graphServer, fx.Lifecycle) {}
+
+func newGraphApp(opts ...fx.Option) *fx.App {
+	return fx.New(
+		fx.NopLogger,
+		fx.Provide(newGraphConfig),
+		fx.Module("server",
+			fx.Provide(
+				fx.Private,
+				fx.Annotate(newGraphPort, fx.ResultTags(`group:"ports"`)),
+			),
+			fx.Provide(fx.Annotate(newGraphServer, fx.ParamTags(`group:"ports"`))),
+			fx.Invoke(runGraphServer),
+		),
+		fx.Options(opts...),
+	)
+}
+
+func TestVerifyGraph(t *testing.T) {
+	t.Parallel()
+
+	t.Run("matches", func(t *testing.T) {
+		t.Parallel()
+
+		spy := newTB()
+		VerifyGraph(spy, newGraphApp(), "testdata/graph.golden")
+		assert.Zero(t, spy.failures, spy.errors.String())
+	})
+
+	t.Run("drift", func(t *testing.T) {
+		t.Parallel()
+
+		spy := newTB()
+		app := newGraphApp(fx.Invoke(func(*graphServer) {}))
+		VerifyGraph(spy, app, "testdata/graph.golden")
+		assert.Equal(t, 1, spy.failures)
+		assert.Contains(t, spy.errors.String(), "dependency graph doesn't match testdata/graph.golden")
+		assert.Contains(t, spy.errors.String(), "+  invoke go.uber.org/fx/fxtest.TestVerifyGraph.func2.1()")
+	})
+
+	t.Run("missing golden file", func(t *testing.T) {
+		t.Parallel()
+
+		spy := newTB()
+		VerifyGraph(spy, newGraphApp(), "testdata/missing.golden")
+		assert.Equal(t, 1, spy.failures)
+		assert.Contains(t, spy.errors.String(), UpdateGoldenEnv)
+	})
+}
+
+func TestVerifyGraphUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "graph.golden")
+	t.Setenv(UpdateGoldenEnv, "1")
+
+	spy := newTB()
+	VerifyGraph(spy, newGraphApp(), path)
+	require.Zero(t, spy.failures, spy.errors.String())
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(FormatGraph(newGraphApp().Graph())), string(got))
+}
+
+func TestLineDiff(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, " a\n-b\n+c\n d\n", lineDiff("a\nb\nd\n", "a\nc\nd\n"))
+	assert.Equal(t, " a\n+b\n", lineDiff("a\n", "a\nb\n"))
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxtest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/fx"
+)
+
+// GraphDiff is the difference between two dependency graphs,
+// as reported by [DiffGraphs].
+type GraphDiff struct {
+	// Nodes added to and removed from the graph.
+	AddedNodes   []GraphDiffNode
+	RemovedNodes []GraphDiffNode
+
+	// Dependencies added to and removed from the graph.
+	AddedEdges   []GraphDiffEdge
+	RemovedEdges []GraphDiffEdge
+}
+
+// GraphDiffNode identifies a function in a dependency graph
+// by the module it was passed to, how, and its name.
+type GraphDiffNode struct {
+	// Module is the path of the module the function was passed to,
+	// like "server/http", or empty for the top-level module.
+	Module string
+
+	// Kind is the option the function was passed to,
+	// like "provide" or "invoke". See [fx.GraphNode].
+	Kind string
+
+	// Name of the function.
+	Name string
+}
+
+func (n GraphDiffNode) String() string {
+	if n.Module == "" {
+		return fmt.Sprintf("%v %v", n.Kind, n.Name)
+	}
+	return fmt.Sprintf("%v %v in %q", n.Kind, n.Name, n.Module)
+}
+
+// GraphDiffEdge is a dependency of one function on another
+// in a dependency graph.
+type GraphDiffEdge struct {
+	// From depends on Value, which To provides or decorates.
+	From  GraphDiffNode
+	To    GraphDiffNode
+	Value fx.GraphValue
+}
+
+func (e GraphDiffEdge) String() string {
+	return fmt.Sprintf("%v -> %v: %v", e.From, e.To, formatGraphValue(e.Value))
+}
+
+// Empty reports whether the graphs compared were the same.
+func (d GraphDiff) Empty() bool {
+	return len(d.AddedNodes) == 0 && len(d.RemovedNodes) == 0 &&
+		len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0
+}
+
+// String renders the difference with one line for each node or edge,
+// prefixed with "+" if it was added and "-" if it was removed.
+func (d GraphDiff) String() string {
+	var sb strings.Builder
+	for _, n := range d.RemovedNodes {
+		fmt.Fprintf(&sb, "-%v\n", n)
+	}
+	for _, n := range d.AddedNodes {
+		fmt.Fprintf(&sb, "+%v\n", n)
+	}
+	for _, e := range d.RemovedEdges {
+		fmt.Fprintf(&sb, "-%v\n", e)
+	}
+	for _, e := range d.AddedEdges {
+		fmt.Fprintf(&sb, "+%v\n", e)
+	}
+	return sb.String()
+}
+
+// DiffGraphs reports the functions and dependencies
+// that were added to or removed from the dependency graph before
+// to produce after.
+// Functions are matched by module, kind, and name,
+// so IDs needn't agree between the two graphs.
+// Use it to flag changes to how an application is wired,
+// such as a new dependency of one module on another.
+//
+//	diff := fxtest.DiffGraphs(baseline.Graph(), app.Graph())
+//	for _, e := range diff.AddedEdges {
+//		if e.From.Module == "billing" && e.To.Module == "users" {
+//			t.Errorf("billing must not depend on users: %v", e)
+//		}
+//	}
+//
+// Since [fx.Graph] can be encoded as JSON, before may also be a baseline
+// decoded from a file checked in alongside the test.
+// As with [VerifyGraph], constructors Fx provides itself are left out.
+func DiffGraphs(before, after fx.Graph) GraphDiff {
+	beforeNodes, beforeEdges := graphDiffItems(before)
+	afterNodes, afterEdges := graphDiffItems(after)
+
+	var d GraphDiff
+	d.AddedNodes, d.RemovedNodes = diffItems(beforeNodes, afterNodes)
+	d.AddedEdges, d.RemovedEdges = diffItems(beforeEdges, afterEdges)
+	return d
+}
+
+// graphDiffItems returns the nodes and edges of g.
+func graphDiffItems(g fx.Graph) ([]GraphDiffNode, []GraphDiffEdge) {
+	var nodes []GraphDiffNode
+	byID := make(map[int]GraphDiffNode)
+	collectGraphDiffNodes(g.Root, "", 0, byID, &nodes)
+
+	var edges []GraphDiffEdge
+	for _, e := range g.Edges {
+		from, ok := byID[e.From]
+		if !ok {
+			continue
+		}
+		to, ok := byID[e.To]
+		if !ok {
+			continue
+		}
+		edges = append(edges, GraphDiffEdge{From: from, To: to, Value: e.Value})
+	}
+	return nodes, edges
+}
+
+func collectGraphDiffNodes(m fx.GraphModule, path string, depth int, byID map[int]GraphDiffNode, nodes *[]GraphDiffNode) {
+	if depth > 0 {
+		if path != "" {
+			path += "/"
+		}
+		path += m.Name
+	}
+
+	for _, n := range m.Nodes {
+		if depth == 0 && strings.HasPrefix(n.Name, _fxPrefix) {
+			continue
+		}
+		node := GraphDiffNode{Module: path, Kind: n.Kind, Name: n.Name}
+		byID[n.ID] = node
+		*nodes = append(*nodes, node)
+	}
+
+	for _, sub := range m.Modules {
+		collectGraphDiffNodes(sub, path, depth+1, byID, nodes)
+	}
+}
+
+// diffItems returns the items of after missing from before, and those of
+// before missing from after, each sorted by their string form.
+// Items that appear more than once are matched up one for one.
+func diffItems[T comparable](before, after []T) (added, removed []T) {
+	counts := make(map[T]int)
+	for _, x := range before {
+		counts[x]++
+	}
+	for _, x := range after {
+		if counts[x] > 0 {
+			counts[x]--
+			continue
+		}
+		added = append(added, x)
+	}
+	for _, x := range before {
+		if counts[x] > 0 {
+			counts[x]--
+			removed = append(removed, x)
+		}
+	}
+	sortByString(added)
+	sortByString(removed)
+	return added, removed
+}
+
+func sortByString[T any](xs []T) {
+	sort.SliceStable(xs, func(i, j int) bool {
+		return fmt.Sprint(xs[i]) < fmt.Sprint(xs[j])
+	})
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fxtest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+type graphClient struct{}
+
+func newGraphClient(*graphConfig) *graphClient { return &graphClient{} }
+
+func runGraphClient(*graphClient) {}
+
+func TestDiffGraphs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("same", func(t *testing.T) {
+		t.Parallel()
+
+		diff := DiffGraphs(newGraphApp().Graph(), newGraphApp().Graph())
+		assert.True(t, diff.Empty(), diff.String())
+		assert.Empty(t, diff.String())
+	})
+
+	t.Run("added module", func(t *testing.T) {
+		t.Parallel()
+
+		diff := DiffGraphs(newGraphApp().Graph(), newGraphApp(
+			fx.Module("client",
+				fx.Provide(newGraphClient),
+				fx.Invoke(runGraphClient),
+			),
+		).Graph())
+		require.False(t, diff.Empty())
+
+		client := GraphDiffNode{
+			Module: "client",
+			Kind:   "provide",
+			Name:   "go.uber.org/fx/fxtest.newGraphClient()",
+		}
+		run := GraphDiffNode{
+			Module: "client",
+			Kind:   "invoke",
+			Name:   "go.uber.org/fx/fxtest.runGraphClient()",
+		}
+		config := GraphDiffNode{
+			Kind: "provide",
+			Name: "go.uber.org/fx/fxtest.newGraphConfig()",
+		}
+		assert.Equal(t, []GraphDiffNode{run, client}, diff.AddedNodes)
+		assert.Empty(t, diff.RemovedNodes)
+		assert.Equal(t, []GraphDiffEdge{
+			{From: run, To: client, Value: fx.GraphValue{Type: "*fxtest.graphClient"}},
+			{From: client, To: config, Value: fx.GraphValue{Type: "*fxtest.graphConfig"}},
+		}, diff.AddedEdges)
+		assert.Empty(t, diff.RemovedEdges)
+
+		assert.Contains(t, diff.String(),
+			`+provide go.uber.org/fx/fxtest.newGraphClient() in "client" -> `+
+				`provide go.uber.org/fx/fxtest.newGraphConfig(): *fxtest.graphConfig`)
+	})
+
+	t.Run("removed dependency", func(t *testing.T) {
+		t.Parallel()
+
+		before := newGraphApp(fx.Invoke(func(*graphServer) {})).Graph()
+		diff := DiffGraphs(before, newGraphApp().Graph())
+
+		require.Len(t, diff.RemovedNodes, 1)
+		assert.Equal(t, "invoke", diff.RemovedNodes[0].Kind)
+		require.Len(t, diff.RemovedEdges, 1)
+		assert.Equal(t, "server", diff.RemovedEdges[0].To.Module)
+		assert.Empty(t, diff.AddedNodes)
+		assert.Empty(t, diff.AddedEdges)
+		assert.Contains(t, diff.String(), "-invoke ")
+	})
+
+	t.Run("serialized baseline", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := json.Marshal(newGraphApp().Graph())
+		require.NoError(t, err)
+
+		var baseline fx.Graph
+		require.NoError(t, json.Unmarshal(b, &baseline))
+
+		diff := DiffGraphs(baseline, newGraphApp().Graph())
+		assert.True(t, diff.Empty(), diff.String())
+	})
+}
@@ -0,0 +1,352 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/dig"
+)
+
+// Graph is a machine-readable description of an application's dependency
+// graph: the functions passed to each module with [Provide], [Supply],
+// [Decorate], [Replace], and [Invoke], and the dependencies between them.
+// It is designed to be encoded as JSON.
+//
+//	json.NewEncoder(os.Stdout).Encode(app.Graph())
+type Graph struct {
+	// Root is the top-level module of the application.
+	Root GraphModule `json:"root"`
+
+	// Edges connects each function to the functions that provide
+	// or decorate the values it depends on.
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphModule is a module in a [Graph].
+type GraphModule struct {
+	// Name of the module, or empty for the top-level module.
+	Name string `json:"name,omitempty"`
+
+	// Info is the [ModuleInfo] passed to the module, if any.
+	Info *ModuleInfo `json:"info,omitempty"`
+
+	// Nodes are the functions passed to the module,
+	// in the order they were registered with the container.
+	Nodes []GraphNode `json:"nodes,omitempty"`
+
+	// Modules are the modules nested in this one.
+	Modules []GraphModule `json:"modules,omitempty"`
+}
+
+// GraphNode is a function passed to a module in a [Graph].
+type GraphNode struct {
+	// ID identifies the node in the edges of the graph.
+	ID int `json:"id"`
+
+	// Kind is the option the function was passed to:
+	// "provide", "supply", "decorate", "replace", or "invoke".
+	// Constructors provided with SupplyDerived are "derive".
+	Kind string `json:"kind"`
+
+	// Name of the function.
+	Name string `json:"name"`
+
+	// Private reports whether the function was provided with [Private].
+	Private bool `json:"private,omitempty"`
+
+	// Inputs and Outputs are the values the function
+	// depends on and produces.
+	Inputs  []GraphValue `json:"inputs,omitempty"`
+	Outputs []GraphValue `json:"outputs,omitempty"`
+
+	// Location is where the function is defined, as "file:line".
+	// It's empty for values passed to Supply and Replace,
+	// which aren't functions.
+	Location string `json:"location,omitempty"`
+}
+
+// GraphValue is a value consumed or produced by a [GraphNode].
+type GraphValue struct {
+	// Type of the value, as rendered by reflect.Type.String.
+	// Value groups are consumed as a slice of their element type.
+	Type string `json:"type"`
+
+	// Name is the name of a named value.
+	Name string `json:"name,omitempty"`
+
+	// Group is the name of the value group the value belongs to.
+	Group string `json:"group,omitempty"`
+
+	// Optional reports whether an input is optional.
+	Optional bool `json:"optional,omitempty"`
+}
+
+// GraphEdge is a dependency of one [GraphNode] on another.
+type GraphEdge struct {
+	// From is the ID of the node that depends on Value.
+	From int `json:"from"`
+
+	// To is the ID of a node that provides or decorates Value.
+	To int `json:"to"`
+
+	// Value is the input of From that To provides or decorates.
+	Value GraphValue `json:"value"`
+}
+
+// Graph returns the dependency graph of the application.
+// It includes functions passed to [Invoke] only once they have run,
+// which happens in [New].
+//
+// Graph is the application's introspection API:
+// it lists every module and the constructors, decorators,
+// and invoked functions passed to it, along with where they're defined
+// and the types they consume and produce.
+// Serve it from an admin endpoint to inspect a running application.
+//
+//	mux.HandleFunc("/debug/fx", func(w http.ResponseWriter, r *http.Request) {
+//	  json.NewEncoder(w).Encode(app.Graph())
+//	})
+func (app *App) Graph() Graph {
+	g, _ := app.graph()
+	return g
+}
+
+// graph returns the dependency graph of the application
+// and its nodes, indexed by ID.
+func (app *App) graph() (Graph, []graphNodeRef) {
+	var (
+		g     Graph
+		nodes []graphNodeRef
+	)
+	g.Root = app.root.graphModule(&nodes)
+
+	for _, consumer := range nodes {
+		for _, in := range consumer.Inputs {
+			key := in.key()
+			for _, producer := range nodes {
+				if producer.ID == consumer.ID || !producer.serves(consumer.mod, key) {
+					continue
+				}
+				g.Edges = append(g.Edges, GraphEdge{
+					From:  consumer.ID,
+					To:    producer.ID,
+					Value: in,
+				})
+			}
+		}
+	}
+	return g, nodes
+}
+
+// graphNodeRef is a GraphNode and the module it was passed to.
+type graphNodeRef struct {
+	GraphNode
+
+	mod *module
+
+	// Whether the constructor has run, and how long it took.
+	ran     bool
+	runtime time.Duration
+}
+
+// serves reports whether n provides or decorates the value with the given
+// key for functions of module m.
+func (n graphNodeRef) serves(m *module, key GraphValue) bool {
+	var scoped bool
+	switch n.Kind {
+	case "invoke":
+		return false
+	case "decorate", "replace":
+		// Decorations apply to the module and its descendants.
+		scoped = true
+	default:
+		scoped = n.Private
+	}
+	if scoped && !n.mod.isAncestorOf(m) {
+		return false
+	}
+	for _, o := range n.Outputs {
+		if o.key() == key {
+			return true
+		}
+	}
+	return false
+}
+
+// isAncestorOf reports whether m is other or one of its ancestors.
+func (m *module) isAncestorOf(other *module) bool {
+	for mod := other; mod != nil; mod = mod.parent {
+		if mod == m {
+			return true
+		}
+	}
+	return false
+}
+
+// key identifies the value regardless of how it's consumed:
+// value groups by their element type and name.
+func (v GraphValue) key() GraphValue {
+	key := GraphValue{Type: v.Type, Name: v.Name, Group: v.Group}
+	if len(key.Group) > 0 {
+		key.Type = strings.TrimPrefix(key.Type, "[]")
+	}
+	return key
+}
+
+// graphModule returns the GraphModule for m, numbering its nodes
+// after those already in nodes and appending them to it.
+func (m *module) graphModule(nodes *[]graphNodeRef) GraphModule {
+	gm := GraphModule{Name: m.name}
+	if m.hasInfo {
+		info := m.info
+		gm.Info = &info
+	}
+	for _, n := range m.graphNodes {
+		node := GraphNode{
+			ID:       len(*nodes),
+			Kind:     n.Kind,
+			Name:     n.Name,
+			Private:  n.Private,
+			Inputs:   n.Inputs,
+			Outputs:  n.Outputs,
+			Location: n.Location,
+		}
+		gm.Nodes = append(gm.Nodes, node)
+		*nodes = append(*nodes, graphNodeRef{
+			GraphNode: node,
+			mod:       m,
+			ran:       n.Ran,
+			runtime:   n.Runtime,
+		})
+	}
+	for _, mod := range m.modules {
+		gm.Modules = append(gm.Modules, mod.graphModule(nodes))
+	}
+	return gm
+}
+
+// graphNode is a function registered with the container of a module,
+// as reported by App.Graph and rendered in the application's DotGraph.
+type graphNode struct {
+	Kind    string // provide, supply, decorate, replace, or invoke
+	Name    string
+	Private bool
+	Inputs  []GraphValue
+	Outputs []GraphValue
+
+	// Location of the function, as file:line, if known.
+	Location string
+
+	// Whether the constructor has run, and how long it took.
+	Ran     bool
+	Runtime time.Duration
+}
+
+// parseGraphValue parses a dig.Input or dig.Output rendered as a string,
+// such as `T`, `T[name = "foo"]`, or `[]T[optional, group = "bar"]`.
+func parseGraphValue(s string) GraphValue {
+	// Go type names don't contain any of these, so the first match
+	// is the start of dig's annotations.
+	i := -1
+	for _, prefix := range []string{"[optional", "[name = ", "[group = "} {
+		if j := strings.Index(s, prefix); j >= 0 && (i < 0 || j < i) {
+			i = j
+		}
+	}
+	if i < 0 || !strings.HasSuffix(s, "]") {
+		return GraphValue{Type: s}
+	}
+
+	v := GraphValue{Type: s[:i]}
+	rest := s[i+1 : len(s)-1]
+	for len(rest) > 0 {
+		rest = strings.TrimPrefix(rest, ", ")
+		switch {
+		case strings.HasPrefix(rest, "optional"):
+			v.Optional = true
+			rest = strings.TrimPrefix(rest, "optional")
+		case strings.HasPrefix(rest, "name = "):
+			v.Name, rest = unquotePrefix(strings.TrimPrefix(rest, "name = "))
+		case strings.HasPrefix(rest, "group = "):
+			v.Group, rest = unquotePrefix(strings.TrimPrefix(rest, "group = "))
+		default:
+			// Unknown annotation: treat the whole string as the type.
+			return GraphValue{Type: s}
+		}
+	}
+	return v
+}
+
+// unquotePrefix unquotes the quoted string at the start of s,
+// returning it and the remainder of s.
+func unquotePrefix(s string) (string, string) {
+	q, err := strconv.QuotedPrefix(s)
+	if err != nil {
+		return s, ""
+	}
+	v, _ := strconv.Unquote(q)
+	return v, s[len(q):]
+}
+
+func inputGraphValues(inputs []*dig.Input) []GraphValue {
+	if len(inputs) == 0 {
+		return nil
+	}
+	vs := make([]GraphValue, len(inputs))
+	for i, in := range inputs {
+		vs[i] = parseGraphValue(in.String())
+	}
+	return vs
+}
+
+func outputGraphValues(outputs []*dig.Output) []GraphValue {
+	if len(outputs) == 0 {
+		return nil
+	}
+	vs := make([]GraphValue, len(outputs))
+	for i, o := range outputs {
+		vs[i] = parseGraphValue(o.String())
+	}
+	return vs
+}
+
+// recordGraphNode records a function registered with this module's
+// container for the application's DotGraph.
+func (m *module) recordGraphNode(n graphNode) {
+	m.graphNodes = append(m.graphNodes, n)
+}
+
+// hasGraphNodes reports whether m or any of its descendants
+// registered a function with its container.
+func (m *module) hasGraphNodes() bool {
+	if len(m.graphNodes) > 0 {
+		return true
+	}
+	for _, mod := range m.modules {
+		if mod.hasGraphNodes() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGraphValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		give string
+		want GraphValue
+	}{
+		{give: "int", want: GraphValue{Type: "int"}},
+		{give: "map[string]int", want: GraphValue{Type: "map[string]int"}},
+		{give: `int[name = "foo"]`, want: GraphValue{Type: "int", Name: "foo"}},
+		{
+			give: `[]int[optional, group = "a, b"]`,
+			want: GraphValue{Type: "[]int", Group: "a, b", Optional: true},
+		},
+		{
+			give: `*bytes.Buffer[optional, name = "with \"quotes\""]`,
+			want: GraphValue{Type: "*bytes.Buffer", Name: `with "quotes"`, Optional: true},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.give, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, parseGraphValue(tt.give))
+		})
+	}
+}
+
+func TestGraph(t *testing.T) {
+	t.Parallel()
+
+	type config struct{}
+	type server struct{}
+
+	app := New(
+		NopLogger,
+		Provide(func() *config { return &config{} }),
+		Module("server",
+			Provide(
+				Private,
+				Annotate(func(*config) int { return 1 }, ResultTags(`group:"ports"`)),
+			),
+			Provide(Annotate(func([]int) *server { return &server{} }, ParamTags(`group:"ports"`))),
+			Decorate(func(c *config) *config { return c }),
+			Invoke(func(*server, *config) {}),
+		),
+		Invoke(func(*config) {}),
+	)
+	require.NoError(t, app.Err())
+
+	g := app.Graph()
+	// Skip the types Fx provides.
+	builtins := len(g.Root.Nodes) - 2
+	nodes := g.Root.Nodes[builtins:]
+	require.Len(t, g.Root.Modules, 1)
+	mod := g.Root.Modules[0]
+	assert.Equal(t, "server", mod.Name)
+
+	assert.Equal(t, "provide", nodes[0].Kind)
+	assert.Equal(t, []GraphValue{{Type: "*fx.config"}}, nodes[0].Outputs)
+	assert.Equal(t, "invoke", nodes[1].Kind)
+	assert.Equal(t, []GraphValue{{Type: "*fx.config"}}, nodes[1].Inputs)
+
+	require.Len(t, mod.Nodes, 4)
+	ports, srv, decorator, invoke := mod.Nodes[0], mod.Nodes[1], mod.Nodes[2], mod.Nodes[3]
+	assert.True(t, ports.Private)
+	assert.Equal(t, []GraphValue{{Type: "int", Group: "ports"}}, ports.Outputs)
+	assert.Equal(t, []GraphValue{{Type: "[]int", Group: "ports"}}, srv.Inputs)
+	assert.Equal(t, "decorate", decorator.Kind)
+	assert.Equal(t, "invoke", invoke.Kind)
+	for _, n := range []GraphNode{nodes[0], nodes[1], ports, srv, decorator, invoke} {
+		assert.Regexp(t, `/graph_test.go:\d+$`, n.Location, "location of %v", n.Name)
+	}
+
+	edge := func(from, to GraphNode, v GraphValue) GraphEdge {
+		return GraphEdge{From: from.ID, To: to.ID, Value: v}
+	}
+	cfg := GraphValue{Type: "*fx.config"}
+	assert.ElementsMatch(t, []GraphEdge{
+		edge(nodes[1], nodes[0], cfg),
+		edge(ports, nodes[0], cfg),
+		edge(ports, decorator, cfg),
+		edge(srv, ports, GraphValue{Type: "[]int", Group: "ports"}),
+		edge(decorator, nodes[0], cfg),
+		edge(invoke, srv, GraphValue{Type: "*fx.server"}),
+		edge(invoke, nodes[0], cfg),
+		edge(invoke, decorator, cfg),
+	}, g.Edges)
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := json.Marshal(g)
+		require.NoError(t, err)
+
+		var got Graph
+		require.NoError(t, json.Unmarshal(b, &got))
+		assert.Equal(t, g, got)
+		assert.Contains(t, string(b), `"group":"ports"`)
+	})
+}
@@ -0,0 +1,507 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/dig"
+)
+
+// StableGroups makes value groups deliver their values in the order their
+// constructors were provided, rather than in an unspecified order.
+// Values of a group with a priority still come before those with a lower
+// priority; StableGroups orders the values that have the same priority.
+// The constructors of a module are provided before those of the modules
+// it includes, and values that a constructor contributes as a flattened
+// slice are kept in the order of the slice.
+//
+//	app := fx.New(
+//		fx.StableGroups(),
+//		fx.Provide(
+//			AsRoute(NewEchoHandler),  // first
+//			AsRoute(NewHelloHandler), // second
+//		),
+//	)
+//
+// This has the same limitations as value group priorities: the values
+// must be exported to the top level of the application, and the groups
+// can't be decorated at the top level.
+// See the package documentation for value group priorities.
+func StableGroups() Option {
+	return stableGroupsOption{}
+}
+
+type stableGroupsOption struct{}
+
+func (stableGroupsOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.StableGroups Option should be passed to top-level App, " +
+			"not to fx.Module")
+	} else {
+		m.app.priorities.stable = true
+	}
+}
+
+func (stableGroupsOption) String() string {
+	return "fx.StableGroups()"
+}
+
+// groupPriorities holds the value groups that constructors contribute
+// values with a priority to, so that they can be sorted by priority.
+type groupPriorities struct {
+	groups []prioritizedGroup
+	seen   map[prioritizedGroup]struct{}
+
+	// private holds the value groups that constructors contribute values
+	// to without exporting them to the top level.
+	private map[prioritizedGroup]struct{}
+
+	// stable is set by StableGroups. If set, every value exported to
+	// a value group is passed to it along with its priority,
+	// and values with the same priority are ordered by order.
+	stable bool
+	order  int
+}
+
+// prioritizedGroup identifies a value group by its name and element type.
+type prioritizedGroup struct {
+	name string
+	typ  reflect.Type
+}
+
+// container returns a container that records the value groups constructors
+// provided to c contribute to with a priority, and that passes their values
+// to the group along with their priority.
+// Values provided with a priority must be exported to the top level
+// for the group to be sorted; export reports whether they are.
+func (gp *groupPriorities) container(c container, export bool) container {
+	return priorityContainer{container: c, priorities: gp, export: export}
+}
+
+func (gp *groupPriorities) add(g prioritizedGroup) {
+	if _, ok := gp.seen[g]; ok {
+		return
+	}
+	if gp.seen == nil {
+		gp.seen = make(map[prioritizedGroup]struct{})
+	}
+	gp.seen[g] = struct{}{}
+	gp.groups = append(gp.groups, g)
+}
+
+// addPrivate records a value group that a constructor contributes values to
+// without exporting them. It fails if the group is sorted, as sorting it
+// at the top level would hide those values.
+func (gp *groupPriorities) addPrivate(g prioritizedGroup) error {
+	if gp.private == nil {
+		gp.private = make(map[prioritizedGroup]struct{})
+	}
+	gp.private[g] = struct{}{}
+	if _, ok := gp.seen[g]; ok {
+		return privateSortedGroupError(g)
+	}
+	return nil
+}
+
+func privateSortedGroupError(g prioritizedGroup) error {
+	return fmt.Errorf("values of sorted value group %q of %v "+
+		"must be provided to the top level of the application, "+
+		"without fx.Private", g.name, g.typ)
+}
+
+// annotateGroup converts an fx.Annotated that contributes its value to
+// a value group into the equivalent fx.Annotate if the groups are stable,
+// so that the value is returned in a result struct and can be ordered.
+func (gp *groupPriorities) annotateGroup(target interface{}) interface{} {
+	ann, ok := target.(Annotated)
+	if !ok || !gp.stable || len(ann.Group) == 0 || len(ann.Name) > 0 {
+		return target
+	}
+	ft := reflect.TypeOf(ann.Target)
+	if ft == nil || ft.Kind() != reflect.Func {
+		return target
+	}
+
+	ctor := ann.Target
+	if idx := cleanupResult(ft); idx >= 0 {
+		ctor = withCleanup(ctor, idx)
+		ft = reflect.TypeOf(ctor)
+	}
+	results := ft.NumOut()
+	if results > 0 && ft.Out(results-1) == _typeOfError {
+		results--
+	}
+	if results != 1 {
+		return target
+	}
+
+	a, ok := Annotate(ctor, ResultTags(fmt.Sprintf("group:%q", ann.Group))).(annotated)
+	if !ok {
+		return target
+	}
+	a.FuncPtr = reflect.ValueOf(ann.Target).Pointer()
+	return a
+}
+
+// outputName returns the name of an output of a constructor as rendered by
+// dig, with the values it contributes with a priority named after their
+// own type.
+func (gp *groupPriorities) outputName(name string) string {
+	for _, g := range gp.groups {
+		if typeName := prioritizedType(g.typ).String(); strings.HasPrefix(name, typeName+"[") {
+			return g.typ.String() + strings.TrimPrefix(name, typeName)
+		}
+	}
+	return name
+}
+
+// sortGroups decorates each value group that was contributed to
+// with a priority, within s, so that it's sorted by priority.
+func (gp *groupPriorities) sortGroups(s container) error {
+	for _, g := range gp.groups {
+		if _, ok := gp.private[g]; ok {
+			return privateSortedGroupError(g)
+		}
+		if err := s.Decorate(newGroupSorter(g, gp.stable)); err != nil {
+			return fmt.Errorf("cannot sort value group %q of %v by priority: %w", g.name, g.typ, err)
+		}
+	}
+	return nil
+}
+
+// prioritizedType returns the type of the values passed to the group of
+// values of typ along with their priority, the order their constructor
+// was provided in, and their index within a flattened slice.
+func prioritizedType(typ reflect.Type) reflect.Type {
+	return reflect.StructOf([]reflect.StructField{
+		{Name: "Priority", Type: reflect.TypeOf(0)},
+		{Name: "Order", Type: reflect.TypeOf(0)},
+		{Name: "Index", Type: reflect.TypeOf(0)},
+		{Name: "Value", Type: typ},
+	})
+}
+
+// newGroupSorter returns a decorator for the value group g that merges
+// the values contributed to it with and without a priority,
+// highest priority first.
+// If stable is set, values with the same priority are ordered by the order
+// their constructors were provided in, followed by values passed to the
+// group without one.
+func newGroupSorter(g prioritizedGroup, stable bool) interface{} {
+	sliceType := reflect.SliceOf(g.typ)
+	prioritized := prioritizedType(g.typ)
+	groupTag := reflect.StructTag(fmt.Sprintf("group:%q", g.name))
+
+	paramType := reflect.StructOf([]reflect.StructField{
+		{Name: "In", Type: reflect.TypeOf(In{}), Anonymous: true},
+		{Name: "Values", Type: sliceType, Tag: groupTag},
+		{Name: "Prioritized", Type: reflect.SliceOf(prioritized), Tag: groupTag},
+	})
+	resultType := reflect.StructOf([]reflect.StructField{
+		{Name: "Out", Type: reflect.TypeOf(Out{}), Anonymous: true},
+		{Name: "Values", Type: sliceType, Tag: groupTag},
+	})
+
+	ft := reflect.FuncOf([]reflect.Type{paramType}, []reflect.Type{resultType}, false)
+	return reflect.MakeFunc(ft, func(args []reflect.Value) []reflect.Value {
+		values, withPriority := args[0].Field(1), args[0].Field(2)
+
+		type entry struct {
+			priority int
+			order    int
+			index    int
+			value    reflect.Value
+		}
+		entries := make([]entry, 0, values.Len()+withPriority.Len())
+		for i := 0; i < values.Len(); i++ {
+			entries = append(entries, entry{order: math.MaxInt, value: values.Index(i)})
+		}
+		for i := 0; i < withPriority.Len(); i++ {
+			e := withPriority.Index(i)
+			entries = append(entries, entry{
+				priority: int(e.Field(0).Int()),
+				order:    int(e.Field(1).Int()),
+				index:    int(e.Field(2).Int()),
+				value:    e.Field(3),
+			})
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			a, b := entries[i], entries[j]
+			switch {
+			case a.priority != b.priority:
+				return a.priority > b.priority
+			case !stable:
+				return false
+			case a.order != b.order:
+				return a.order < b.order
+			default:
+				return a.index < b.index
+			}
+		})
+
+		sorted := reflect.MakeSlice(sliceType, len(entries), len(entries))
+		for i, e := range entries {
+			sorted.Index(i).Set(e.value)
+		}
+		result := reflect.New(resultType).Elem()
+		result.Field(1).Set(sorted)
+		return []reflect.Value{result}
+	}).Interface()
+}
+
+// parseGroupPriority returns the group tag of a result field without its
+// priority option, and the priority it specifies.
+// ok is false if the tag has no priority option.
+func parseGroupPriority(tag string) (group string, priority int, ok bool, err error) {
+	components := strings.Split(tag, ",")
+	kept := components[:1]
+	for _, c := range components[1:] {
+		p, found := strings.CutPrefix(c, "priority=")
+		if !found {
+			kept = append(kept, c)
+			continue
+		}
+		if ok {
+			return "", 0, false, fmt.Errorf("value group %q has more than one priority", components[0])
+		}
+		priority, err = strconv.Atoi(p)
+		if err != nil {
+			return "", 0, false, fmt.Errorf("invalid priority %q for value group %q", p, components[0])
+		}
+		ok = true
+	}
+	return strings.Join(kept, ","), priority, ok, nil
+}
+
+// priorityContainer wraps constructors provided to it whose result structs
+// contribute to value groups with a priority, so that those values are
+// passed to the group along with their priority.
+type priorityContainer struct {
+	container
+
+	priorities *groupPriorities
+	export     bool
+}
+
+// prioritizedField describes a field of a result struct that contributes
+// to a value group with a priority.
+type prioritizedField struct {
+	index    int
+	priority int
+	flatten  bool
+}
+
+func (c priorityContainer) Provide(ctor interface{}, opts ...dig.ProvideOption) error {
+	fv := reflect.ValueOf(ctor)
+	if fv.Kind() != reflect.Func {
+		return c.container.Provide(ctor, opts...)
+	}
+	ft := fv.Type()
+	if !c.export {
+		if err := c.addPrivate(ft); err != nil {
+			return fmt.Errorf("cannot provide function %v: %w", ft, err)
+		}
+	}
+
+	var (
+		changed bool
+		groups  []prioritizedGroup
+	)
+	outs := make([]reflect.Type, ft.NumOut())
+	fields := make([][]prioritizedField, ft.NumOut())
+	for i := range outs {
+		out := ft.Out(i)
+		outs[i] = out
+		if !dig.IsOut(out) || out.Kind() != reflect.Struct {
+			continue
+		}
+
+		newOut, pfs, gs, err := prioritizeOut(out, c.export && c.priorities.stable)
+		if err != nil {
+			return fmt.Errorf("cannot provide function %v: %w", ft, err)
+		}
+		if len(pfs) == 0 {
+			continue
+		}
+		outs[i], fields[i], changed = newOut, pfs, true
+		groups = append(groups, gs...)
+	}
+	if !changed {
+		return c.container.Provide(ctor, opts...)
+	}
+	for _, g := range groups {
+		c.priorities.add(g)
+	}
+	order := c.priorities.order
+	c.priorities.order++
+
+	ins := make([]reflect.Type, ft.NumIn())
+	for i := range ins {
+		ins[i] = ft.In(i)
+	}
+	wt := reflect.FuncOf(ins, outs, ft.IsVariadic())
+	return provideWrapper(c.container, fv, wt, func(args []reflect.Value) []reflect.Value {
+		results := callFunc(fv, args)
+		for i, pfs := range fields {
+			if len(pfs) > 0 {
+				results[i] = prioritizeResult(results[i], outs[i], pfs, order)
+			}
+		}
+		return results
+	}, opts)
+}
+
+// addPrivate records the value groups that the result structs of
+// a constructor of type ft contribute to, for a constructor whose values
+// aren't exported to the top level.
+func (c priorityContainer) addPrivate(ft reflect.Type) error {
+	for i := 0; i < ft.NumOut(); i++ {
+		out := ft.Out(i)
+		if !dig.IsOut(out) || out.Kind() != reflect.Struct {
+			continue
+		}
+		for j := 0; j < out.NumField(); j++ {
+			f := out.Field(j)
+			tag, ok := f.Tag.Lookup("group")
+			if !ok {
+				continue
+			}
+			group, _, ok, err := parseGroupPriority(tag)
+			if err != nil {
+				return err
+			}
+			if ok {
+				return errors.New("values with a priority " +
+					"must be provided to the top level of the application, " +
+					"without fx.Private")
+			}
+			typ := f.Type
+			if strings.Contains(group, ",flatten") && typ.Kind() == reflect.Slice {
+				typ = typ.Elem()
+			}
+			g := prioritizedGroup{name: strings.Split(group, ",")[0], typ: typ}
+			if err := c.priorities.addPrivate(g); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// prioritizeOut returns a copy of the result struct type out, with each
+// field that contributes to a value group with a priority replaced by one
+// that contributes its value along with the priority.
+// If all is set, every field that contributes to a value group is
+// replaced, with a priority of zero if it doesn't specify one.
+func prioritizeOut(out reflect.Type, all bool) (reflect.Type, []prioritizedField, []prioritizedGroup, error) {
+	var (
+		pfs    []prioritizedField
+		groups []prioritizedGroup
+	)
+	newFields := make([]reflect.StructField, out.NumField())
+	for i := range newFields {
+		f := out.Field(i)
+		newFields[i] = f
+
+		tag, ok := f.Tag.Lookup("group")
+		if !ok {
+			continue
+		}
+		group, priority, ok, err := parseGroupPriority(tag)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if !ok && !all {
+			continue
+		}
+		flatten := strings.Contains(group, ",flatten")
+		typ := f.Type
+		if flatten {
+			if typ.Kind() != reflect.Slice {
+				return nil, nil, nil, fmt.Errorf("flatten can be applied to slices only: field %q of %v is %v",
+					f.Name, out, typ)
+			}
+			typ = typ.Elem()
+		}
+		newType := prioritizedType(typ)
+		if flatten {
+			newType = reflect.SliceOf(newType)
+		}
+
+		newFields[i].Type = newType
+		newFields[i].Tag = reflect.StructTag(strings.Replace(string(f.Tag),
+			fmt.Sprintf("group:%q", tag), fmt.Sprintf("group:%q", group), 1))
+		pfs = append(pfs, prioritizedField{index: i, priority: priority, flatten: flatten})
+		groups = append(groups, prioritizedGroup{
+			name: strings.Split(group, ",")[0],
+			typ:  typ,
+		})
+	}
+	if len(pfs) == 0 {
+		return out, nil, nil, nil
+	}
+	for _, f := range newFields {
+		if len(f.PkgPath) > 0 {
+			return nil, nil, nil, fmt.Errorf("unexported fields not allowed in %v", out)
+		}
+	}
+	return reflect.StructOf(newFields), pfs, groups, nil
+}
+
+// prioritizeResult converts a result struct to type out, pairing the
+// fields in pfs with their priority and the order of their constructor.
+func prioritizeResult(result reflect.Value, out reflect.Type, pfs []prioritizedField, order int) reflect.Value {
+	converted := reflect.New(out).Elem()
+	for i := 0; i < out.NumField(); i++ {
+		if out.Field(i).Type == result.Type().Field(i).Type {
+			converted.Field(i).Set(result.Field(i))
+		}
+	}
+
+	set := func(entry, value reflect.Value, priority, index int) {
+		entry.Field(0).SetInt(int64(priority))
+		entry.Field(1).SetInt(int64(order))
+		entry.Field(2).SetInt(int64(index))
+		entry.Field(3).Set(value)
+	}
+	for _, pf := range pfs {
+		field := converted.Field(pf.index)
+		value := result.Field(pf.index)
+		if !pf.flatten {
+			set(field, value, pf.priority, 0)
+			continue
+		}
+
+		entries := reflect.MakeSlice(field.Type(), value.Len(), value.Len())
+		for j := 0; j < value.Len(); j++ {
+			set(entries.Index(j), value.Index(j), pf.priority, j)
+		}
+		field.Set(entries)
+	}
+	return converted
+}
@@ -0,0 +1,361 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestGroupPriority(t *testing.T) {
+	t.Parallel()
+
+	type middleware struct{ name string }
+
+	type params struct {
+		In
+
+		Middleware []middleware `group:"middleware"`
+	}
+
+	names := func(ms []middleware) []string {
+		got := make([]string, len(ms))
+		for i, m := range ms {
+			got[i] = m.name
+		}
+		return got
+	}
+
+	t.Run("sorted by priority", func(t *testing.T) {
+		t.Parallel()
+
+		type result struct {
+			Out
+
+			Auth    middleware   `group:"middleware,priority=10"`
+			Tracing []middleware `group:"middleware,flatten,priority=20"`
+		}
+
+		var got []string
+		app := fxtest.New(t,
+			Provide(
+				func() result {
+					return result{
+						Auth:    middleware{"auth"},
+						Tracing: []middleware{{"trace"}, {"span"}},
+					}
+				},
+				Annotate(
+					func() middleware { return middleware{"recover"} },
+					ResultTags(`group:"middleware,priority=-1"`),
+				),
+				Annotate(
+					func() middleware { return middleware{"log"} },
+					ResultTags(`group:"middleware"`),
+				),
+			),
+			Module("server",
+				Invoke(func(p params) { got = names(p.Middleware) }),
+			),
+		)
+		defer app.RequireStart().RequireStop()
+
+		require.Len(t, got, 5)
+		// Values with the same priority are in unspecified order.
+		assert.ElementsMatch(t, []string{"trace", "span"}, got[:2])
+		assert.Equal(t, []string{"auth", "log", "recover"}, got[2:])
+	})
+
+	t.Run("provided event", func(t *testing.T) {
+		t.Parallel()
+
+		app, spy := NewSpied(Provide(Annotate(
+			func() middleware { return middleware{} },
+			ResultTags(`group:"middleware,priority=1"`),
+		)))
+		require.NoError(t, app.Err())
+
+		provided := spy.Events().SelectByTypeName("Provided")
+		require.NotEmpty(t, provided)
+		last := provided[len(provided)-1].(*fxevent.Provided)
+		assert.Equal(t, []string{`fx_test.middleware[group = "middleware"]`}, last.OutputTypeNames)
+	})
+
+	t.Run("other groups untouched", func(t *testing.T) {
+		t.Parallel()
+
+		type other struct {
+			In
+
+			Middleware []middleware `group:"other"`
+		}
+
+		var got []string
+		app := fxtest.New(t,
+			Provide(
+				Annotate(
+					func() middleware { return middleware{"auth"} },
+					ResultTags(`group:"middleware,priority=1"`),
+				),
+				Annotate(
+					func() middleware { return middleware{"log"} },
+					ResultTags(`group:"other"`),
+				),
+			),
+			Invoke(func(p other) { got = names(p.Middleware) }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, []string{"log"}, got)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc    string
+			give    Option
+			wantErr string
+		}{
+			{
+				desc: "invalid priority",
+				give: Provide(Annotate(
+					func() middleware { return middleware{} },
+					ResultTags(`group:"middleware,priority=high"`),
+				)),
+				wantErr: `invalid priority "high" for value group "middleware"`,
+			},
+			{
+				desc: "several priorities",
+				give: Provide(Annotate(
+					func() middleware { return middleware{} },
+					ResultTags(`group:"middleware,priority=1,priority=2"`),
+				)),
+				wantErr: `value group "middleware" has more than one priority`,
+			},
+			{
+				desc: "private",
+				give: Module("server", Provide(Annotate(
+					func() middleware { return middleware{} },
+					ResultTags(`group:"middleware,priority=1"`),
+				), Private)),
+				wantErr: "values with a priority must be provided to the top level " +
+					"of the application, without fx.Private",
+			},
+			{
+				desc: "private value in sorted group",
+				give: Options(
+					Provide(Annotate(
+						func() middleware { return middleware{} },
+						ResultTags(`group:"middleware,priority=1"`),
+					)),
+					Module("server", Provide(Annotate(
+						func() middleware { return middleware{} },
+						ResultTags(`group:"middleware"`),
+					), Private)),
+				),
+				wantErr: `values of sorted value group "middleware" of fx_test.middleware ` +
+					"must be provided to the top level of the application",
+			},
+			{
+				desc: "decorated at top level",
+				give: Options(
+					Provide(Annotate(
+						func() middleware { return middleware{} },
+						ResultTags(`group:"middleware,priority=1"`),
+					)),
+					Decorate(Annotate(
+						func(ms []middleware) []middleware { return ms },
+						ParamTags(`group:"middleware"`),
+						ResultTags(`group:"middleware"`),
+					)),
+				),
+				wantErr: "already decorated",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := NewForTest(t, tt.give, Invoke(func(params) {}))
+				err := app.Err()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+}
+
+func TestStableGroups(t *testing.T) {
+	t.Parallel()
+
+	type handler struct{ name string }
+
+	type params struct {
+		In
+
+		Handlers []handler `group:"handlers"`
+	}
+
+	names := func(hs []handler) []string {
+		got := make([]string, len(hs))
+		for i, h := range hs {
+			got[i] = h.name
+		}
+		return got
+	}
+
+	newHandler := func(name string) interface{} {
+		return Annotate(
+			func() handler { return handler{name} },
+			ResultTags(`group:"handlers"`),
+		)
+	}
+
+	t.Run("registration order", func(t *testing.T) {
+		t.Parallel()
+
+		type result struct {
+			Out
+
+			Handlers []handler `group:"handlers,flatten"`
+		}
+
+		var (
+			want []string
+			opts []Option
+		)
+		for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+			want = append(want, name)
+			opts = append(opts, Provide(newHandler(name)))
+		}
+		// Constructors of a module are provided before those of
+		// the modules it includes.
+		want = append(want, "annotated", "supplied", "x", "y", "z")
+		opts = append(opts,
+			Module("routes",
+				Provide(func() result {
+					return result{Handlers: []handler{{"x"}, {"y"}, {"z"}}}
+				}),
+			),
+			Provide(Annotated{
+				Group:  "handlers",
+				Target: func() handler { return handler{"annotated"} },
+			}),
+			Supply(Annotated{Group: "handlers", Target: handler{"supplied"}}),
+		)
+
+		var got []string
+		app := fxtest.New(t,
+			StableGroups(),
+			Options(opts...),
+			Invoke(func(p params) { got = names(p.Handlers) }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("priority first", func(t *testing.T) {
+		t.Parallel()
+
+		var got []string
+		app := fxtest.New(t,
+			StableGroups(),
+			Provide(
+				newHandler("a"),
+				newHandler("b"),
+				Annotate(
+					func() handler { return handler{"urgent"} },
+					ResultTags(`group:"handlers,priority=1"`),
+				),
+				newHandler("c"),
+			),
+			Invoke(func(p params) { got = names(p.Handlers) }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, []string{"urgent", "a", "b", "c"}, got)
+	})
+
+	t.Run("private group", func(t *testing.T) {
+		t.Parallel()
+
+		var got []string
+		app := fxtest.New(t,
+			StableGroups(),
+			Module("server",
+				Provide(newHandler("a"), Private),
+				Invoke(func(p params) { got = names(p.Handlers) }),
+			),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, []string{"a"}, got)
+	})
+
+	t.Run("private value in sorted group", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			StableGroups(),
+			Provide(newHandler("a")),
+			Module("server", Provide(newHandler("b"), Private)),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `values of sorted value group "handlers"`)
+	})
+
+	t.Run("group presence", func(t *testing.T) {
+		t.Parallel()
+
+		var present bool
+		app := fxtest.New(t,
+			StableGroups(),
+			Provide(newHandler("a")),
+			Invoke(Annotate(
+				func(hs []handler, ok bool) { present = ok },
+				ParamTags(`group:"handlers"`),
+				GroupPresence(),
+			)),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.True(t, present)
+	})
+
+	t.Run("not top level", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, Module("server", StableGroups()))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.StableGroups Option should be passed to top-level App")
+	})
+}
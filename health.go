@@ -0,0 +1,123 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// HealthChecker is a component of the application whose health can be
+// checked with [App.HealthCheck].
+//
+// Contribute HealthCheckers to the "health" value group to have them
+// checked. For example,
+//
+//	fx.Provide(
+//		fx.Annotate(
+//			NewDBHealthChecker,
+//			fx.As(new(fx.HealthChecker)),
+//			fx.ResultTags(`group:"health"`),
+//		),
+//	)
+type HealthChecker interface {
+	// Name identifies the component in a HealthReport.
+	Name() string
+
+	// CheckHealth reports whether the component is healthy
+	// by returning nil, or why it isn't.
+	CheckHealth(context.Context) error
+}
+
+// HealthReport is the result of [App.HealthCheck].
+type HealthReport struct {
+	// Components holds the health of each HealthChecker,
+	// in no particular order.
+	Components []ComponentHealth
+}
+
+// Healthy reports whether every component in the report is healthy.
+func (r HealthReport) Healthy() bool {
+	for _, c := range r.Components {
+		if !c.Healthy() {
+			return false
+		}
+	}
+	return true
+}
+
+// ComponentHealth is the health of a single [HealthChecker].
+type ComponentHealth struct {
+	// Name of the component, as reported by HealthChecker.Name.
+	Name string
+
+	// Err is the error returned by HealthChecker.CheckHealth,
+	// or nil if the component is healthy.
+	Err error
+
+	// Latency is how long HealthChecker.CheckHealth took.
+	Latency time.Duration
+}
+
+// Healthy reports whether the component is healthy.
+func (c ComponentHealth) Healthy() bool {
+	return c.Err == nil
+}
+
+type healthParams struct {
+	In
+
+	Checkers []HealthChecker `group:"health"`
+}
+
+// HealthCheck checks the health of every [HealthChecker] in the "health"
+// value group concurrently, and aggregates the results into a report.
+// The checks receive the given context, and should return when it's done.
+//
+// HealthCheck returns an error if the HealthCheckers could not be built.
+// To serve the report as a liveness probe, use [HealthProbes]
+// with [ProbeHealthChecks].
+func (app *App) HealthCheck(ctx context.Context) (HealthReport, error) {
+	var p healthParams
+	err := app.containerLock().Do(func() error {
+		return app.root.scope.Invoke(func(params healthParams) { p = params })
+	})
+	if err != nil {
+		return HealthReport{}, err
+	}
+
+	report := HealthReport{Components: make([]ComponentHealth, len(p.Checkers))}
+	var wg sync.WaitGroup
+	for i, hc := range p.Checkers {
+		wg.Add(1)
+		go func(c *ComponentHealth, hc HealthChecker) {
+			defer wg.Done()
+
+			begin := app.clock.Now()
+			c.Name = hc.Name()
+			c.Err = hc.CheckHealth(ctx)
+			c.Latency = app.clock.Since(begin)
+		}(&report.Components[i], hc)
+	}
+	wg.Wait()
+	return report, nil
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+	"go.uber.org/fx/internal/fxclock"
+)
+
+type healthCheckerFunc struct {
+	name  string
+	check func(context.Context) error
+}
+
+func (h healthCheckerFunc) Name() string                          { return h.name }
+func (h healthCheckerFunc) CheckHealth(ctx context.Context) error { return h.check(ctx) }
+
+func provideHealthChecker(name string, check func(context.Context) error) Option {
+	return Provide(Annotate(
+		func() healthCheckerFunc { return healthCheckerFunc{name: name, check: check} },
+		As(new(HealthChecker)),
+		ResultTags(`group:"health"`),
+	))
+}
+
+func TestHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	healthy := func(context.Context) error { return nil }
+
+	t.Run("aggregates components", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			provideHealthChecker("db", healthy),
+			provideHealthChecker("cache", func(context.Context) error {
+				return errors.New("connection refused")
+			}),
+			Module("child", provideHealthChecker("queue", healthy)),
+		)
+		require.NoError(t, app.Err())
+
+		report, err := app.HealthCheck(context.Background())
+		require.NoError(t, err)
+		assert.False(t, report.Healthy())
+
+		components := report.Components
+		sort.Slice(components, func(i, j int) bool { return components[i].Name < components[j].Name })
+		require.Len(t, components, 3)
+
+		assert.Equal(t, "cache", components[0].Name)
+		assert.False(t, components[0].Healthy())
+		assert.EqualError(t, components[0].Err, "connection refused")
+
+		assert.Equal(t, "db", components[1].Name)
+		assert.True(t, components[1].Healthy())
+
+		assert.Equal(t, "queue", components[2].Name)
+		assert.True(t, components[2].Healthy())
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		app := NewForTest(t,
+			WithClock(clock),
+			provideHealthChecker("db", func(context.Context) error {
+				clock.Add(50 * time.Millisecond)
+				return nil
+			}),
+		)
+		require.NoError(t, app.Err())
+
+		report, err := app.HealthCheck(context.Background())
+		require.NoError(t, err)
+		assert.True(t, report.Healthy())
+		require.Len(t, report.Components, 1)
+		assert.Equal(t, 50*time.Millisecond, report.Components[0].Latency)
+	})
+
+	t.Run("context", func(t *testing.T) {
+		t.Parallel()
+
+		type key struct{}
+		app := NewForTest(t,
+			provideHealthChecker("db", func(ctx context.Context) error {
+				assert.Equal(t, "value", ctx.Value(key{}))
+				return nil
+			}),
+		)
+		require.NoError(t, app.Err())
+
+		ctx := context.WithValue(context.Background(), key{}, "value")
+		_, err := app.HealthCheck(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("no components", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t)
+		require.NoError(t, app.Err())
+
+		report, err := app.HealthCheck(context.Background())
+		require.NoError(t, err)
+		assert.True(t, report.Healthy())
+		assert.Empty(t, report.Components)
+	})
+
+	t.Run("checker fails to build", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			Provide(Annotate(
+				func() (HealthChecker, error) { return nil, errors.New("great sadness") },
+				ResultTags(`group:"health"`),
+			)),
+		)
+		require.NoError(t, app.Err())
+
+		_, err := app.HealthCheck(context.Background())
+		assert.ErrorContains(t, err, "great sadness")
+	})
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// _httpReadHeaderTimeout bounds how long the servers wait for the headers
+// of a request, so that slow clients can't hold connections open.
+const _httpReadHeaderTimeout = 10 * time.Second
+
+// httpServer serves HTTP requests in the background
+// on behalf of an option such as HealthProbes.
+type httpServer struct {
+	name    string // option that enabled the server, for errors
+	addr    string
+	handler http.Handler
+
+	mu     sync.Mutex
+	ln     net.Listener // non-nil while serving
+	server *http.Server
+	served chan struct{} // closed once server has returned
+}
+
+// start begins serving requests if it isn't doing so already.
+func (s *httpServer) start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ln != nil {
+		return nil
+	}
+
+	ln, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("%v: %w", s.name, err)
+	}
+
+	s.ln = ln
+	s.server = &http.Server{
+		Handler:           s.handler,
+		ReadHeaderTimeout: _httpReadHeaderTimeout,
+	}
+	s.served = make(chan struct{})
+	go func(server *http.Server, served chan<- struct{}) {
+		defer close(served)
+		_ = server.Serve(ln)
+	}(s.server, s.served)
+	return nil
+}
+
+// listenAddr returns the address the server is listening on,
+// or nil if it isn't serving.
+func (s *httpServer) listenAddr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ln == nil {
+		return nil
+	}
+	return s.ln.Addr()
+}
+
+// stop stops serving requests, waiting for in-flight requests
+// until ctx is done.
+func (s *httpServer) stop(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ln == nil {
+		return nil
+	}
+
+	err := s.server.Shutdown(ctx)
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		err = s.server.Close()
+	}
+	<-s.served
+	s.ln, s.server, s.served = nil, nil, nil
+	return err
+}
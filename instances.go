@@ -0,0 +1,182 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"go.uber.org/dig"
+)
+
+// TrackInstances records every value built by a constructor so that it
+// can be inspected with [App.ConstructedInstances].
+// It is intended for debugging, such as to back a debug endpoint
+// that dumps live application state.
+//
+// Tracking keeps every constructed value reachable for the lifetime of
+// the App, and adds a small overhead to each constructor call.
+func TrackInstances() Option {
+	return trackInstancesOption{}
+}
+
+type trackInstancesOption struct{}
+
+func (trackInstancesOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.TrackInstances Option should be passed to top-level App, " +
+			"not to fx.Module")
+	} else {
+		m.app.instances = &instanceTracker{
+			values: make(map[reflect.Type]interface{}),
+		}
+	}
+}
+
+func (trackInstancesOption) String() string {
+	return "fx.TrackInstances()"
+}
+
+// ConstructedInstances returns the values built so far by the
+// application's constructors, keyed by their type.
+// It requires the [TrackInstances] option, and returns nil without it.
+//
+// Only values that were already built are reported;
+// calling ConstructedInstances never causes a constructor to run.
+// Values are reported as returned by their constructors,
+// before any [Decorate] is applied.
+// Named values and values in value groups are not included.
+// If a type is provided by more than one module,
+// the value built most recently is reported.
+//
+// The returned map is a copy and may be modified freely.
+func (app *App) ConstructedInstances() map[reflect.Type]interface{} {
+	if app.instances == nil {
+		return nil
+	}
+	return app.instances.snapshot()
+}
+
+// instanceTracker holds the values built by constructors
+// when the application is run with TrackInstances.
+type instanceTracker struct {
+	mu     sync.Mutex
+	values map[reflect.Type]interface{}
+}
+
+// container returns a container that records values built by constructors
+// provided to c with the given target.
+// It returns c unchanged if tracking is disabled or the target only
+// produces named or grouped values.
+func (t *instanceTracker) container(c container, target interface{}) container {
+	if t == nil {
+		return c
+	}
+	if ann, ok := target.(Annotated); ok && (len(ann.Name) > 0 || len(ann.Group) > 0) {
+		return c
+	}
+	return trackingContainer{container: c, tracker: t}
+}
+
+// get returns the value of type typ built most recently, if any.
+func (t *instanceTracker) get(typ reflect.Type) (interface{}, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	v, ok := t.values[typ]
+	return v, ok
+}
+
+func (t *instanceTracker) snapshot() map[reflect.Type]interface{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	values := make(map[reflect.Type]interface{}, len(t.values))
+	for typ, v := range t.values {
+		values[typ] = v
+	}
+	return values
+}
+
+// wrap returns the body of a function with the same signature as the
+// constructor fv that records the values returned by fv unless it fails.
+func (t *instanceTracker) wrap(fv reflect.Value) func([]reflect.Value) []reflect.Value {
+	ft := fv.Type()
+	return func(args []reflect.Value) []reflect.Value {
+		results := callFunc(fv, args)
+		if n := len(results); n > 0 && ft.Out(n-1) == _typeOfError && !results[n-1].IsNil() {
+			return results
+		}
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		for _, v := range results {
+			t.record(v)
+		}
+		return results
+	}
+}
+
+// record stores v, or the unnamed fields of v if it is an fx.Out struct.
+// t.mu must be held.
+func (t *instanceTracker) record(v reflect.Value) {
+	typ := v.Type()
+	switch {
+	case typ == _typeOfError:
+		return
+	case !dig.IsOut(typ):
+		t.values[typ] = v.Interface()
+		return
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		switch {
+		case f.Type == _typeOfOut:
+		case dig.IsOut(f.Type):
+			t.record(v.Field(i))
+		case len(f.PkgPath) > 0:
+			// Unexported fields are not provided.
+		case len(f.Tag.Get("name")) > 0 || len(f.Tag.Get("group")) > 0:
+		default:
+			t.values[f.Type] = v.Field(i).Interface()
+		}
+	}
+}
+
+var _typeOfOut = reflect.TypeOf(Out{})
+
+// trackingContainer wraps constructors provided to it so that the values
+// they build are recorded by an instanceTracker.
+type trackingContainer struct {
+	container
+
+	tracker *instanceTracker
+}
+
+func (c trackingContainer) Provide(ctor interface{}, opts ...dig.ProvideOption) error {
+	fv := reflect.ValueOf(ctor)
+	if fv.Kind() != reflect.Func {
+		return c.container.Provide(ctor, opts...)
+	}
+	return provideWrapper(c.container, fv, fv.Type(), c.tracker.wrap(fv), opts)
+}
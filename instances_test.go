@@ -0,0 +1,140 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestConstructedInstances(t *testing.T) {
+	t.Parallel()
+
+	type built struct{ name string }
+	type unbuilt struct{}
+
+	t.Run("only built instances", func(t *testing.T) {
+		t.Parallel()
+
+		b := &built{name: "foo"}
+		app := fxtest.New(t,
+			TrackInstances(),
+			Provide(
+				func() *built { return b },
+				func() *unbuilt {
+					t.Error("unbuilt must not be constructed")
+					return nil
+				},
+			),
+			Invoke(func(*built) {}),
+		)
+		defer app.RequireStart().RequireStop()
+
+		got := app.ConstructedInstances()
+		assert.Same(t, b, got[reflect.TypeOf(b)])
+		assert.NotContains(t, got, reflect.TypeOf(&unbuilt{}))
+	})
+
+	t.Run("without TrackInstances", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			Provide(func() *built { return &built{} }),
+			Invoke(func(*built) {}),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Nil(t, app.ConstructedInstances())
+	})
+
+	t.Run("result structs and supplied values", func(t *testing.T) {
+		t.Parallel()
+
+		type result struct {
+			Out
+
+			Built   *built
+			Named   *unbuilt `name:"named"`
+			Grouped string   `group:"strings"`
+		}
+
+		app := fxtest.New(t,
+			TrackInstances(),
+			Supply(42),
+			Provide(func() result {
+				return result{Built: &built{name: "bar"}, Named: &unbuilt{}, Grouped: "baz"}
+			}),
+			Provide(Annotated{Name: "other", Target: func() string { return "qux" }}),
+			Invoke(func(int, *built) {}),
+			Invoke(Annotate(func(string) {}, ParamTags(`name:"other"`))),
+		)
+		defer app.RequireStart().RequireStop()
+
+		got := app.ConstructedInstances()
+		assert.Equal(t, map[reflect.Type]interface{}{
+			reflect.TypeOf(0):        42,
+			reflect.TypeOf(&built{}): &built{name: "bar"},
+		}, got)
+	})
+
+	t.Run("failed constructor", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			TrackInstances(),
+			Provide(func() (*built, error) {
+				return &built{}, errors.New("great sadness")
+			}),
+			Invoke(func(*built) {}),
+		)
+		require.Error(t, app.Err())
+		assert.Empty(t, app.ConstructedInstances())
+	})
+
+	t.Run("errors name original constructor", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			TrackInstances(),
+			Provide(func(*unbuilt) *built { return &built{} }),
+			Invoke(func(*built) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "makeFuncStub")
+	})
+
+	t.Run("in module", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, Module("child", TrackInstances()))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			"fx.TrackInstances Option should be passed to top-level App, not to fx.Module")
+	})
+}
@@ -52,14 +52,56 @@ func Caller() string {
 }
 
 // FuncName returns a funcs formatted name
+//
+// All instantiations of a generic function share a name that elides
+// their type arguments, like "pkg.NewRepository[...]".
+// To tell them apart, the names of generic functions include their
+// parameter and result types, like "pkg.NewRepository[...]() *pkg.Repository[pkg.User]".
 func FuncName(fn interface{}) string {
 	fnV := reflect.ValueOf(fn)
 	if fnV.Kind() != reflect.Func {
 		return fmt.Sprint(fn)
 	}
 
-	function := runtime.FuncForPC(fnV.Pointer()).Name()
-	return fmt.Sprintf("%s()", sanitize(function))
+	function := sanitize(runtime.FuncForPC(fnV.Pointer()).Name())
+	if strings.Contains(function, "[...]") {
+		return function + signature(fnV.Type())
+	}
+	return fmt.Sprintf("%s()", function)
+}
+
+// signature renders the parameter and result types of a function type
+// the way they appear in Go source, like "(int, ...string) (T, error)".
+func signature(t reflect.Type) string {
+	var sb strings.Builder
+	sb.WriteByte('(')
+	for i := 0; i < t.NumIn(); i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		if in := t.In(i); t.IsVariadic() && i == t.NumIn()-1 {
+			sb.WriteString("..." + in.Elem().String())
+		} else {
+			sb.WriteString(in.String())
+		}
+	}
+	sb.WriteByte(')')
+
+	switch t.NumOut() {
+	case 0:
+	case 1:
+		sb.WriteString(" " + t.Out(0).String())
+	default:
+		sb.WriteString(" (")
+		for i := 0; i < t.NumOut(); i++ {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(t.Out(i).String())
+		}
+		sb.WriteByte(')')
+	}
+	return sb.String()
 }
 
 // Ascend the call stack until we leave the Fx production code. This allows us
@@ -35,6 +35,14 @@ func TestCaller(t *testing.T) {
 
 func someFunc() {}
 
+type box[T any] struct{ v T }
+
+func newBox[T any](v T) *box[T] { return &box[T]{v} }
+
+func joinBoxes[T any](sep string, boxes ...*box[T]) (*box[T], error) { return nil, nil }
+
+func emptyBox[T any]() {}
+
 func TestFuncName(t *testing.T) {
 	t.Parallel()
 
@@ -48,6 +56,26 @@ func TestFuncName(t *testing.T) {
 			give: someFunc,
 			want: "go.uber.org/fx/internal/fxreflect.someFunc()",
 		},
+		{
+			desc: "generic function",
+			give: newBox[int],
+			want: "go.uber.org/fx/internal/fxreflect.newBox[...](int) *fxreflect.box[int]",
+		},
+		{
+			desc: "generic function/other instantiation",
+			give: newBox[string],
+			want: "go.uber.org/fx/internal/fxreflect.newBox[...](string) *fxreflect.box[string]",
+		},
+		{
+			desc: "generic function/variadic",
+			give: joinBoxes[int],
+			want: "go.uber.org/fx/internal/fxreflect.joinBoxes[...](string, ...*fxreflect.box[int]) (*fxreflect.box[int], error)",
+		},
+		{
+			desc: "generic function/no results",
+			give: emptyBox[int],
+			want: "go.uber.org/fx/internal/fxreflect.emptyBox[...]()",
+		},
 		{
 			desc: "not a function",
 			give: 42,
@@ -26,6 +26,10 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"runtime/debug"
+	"runtime/pprof"
+	"runtime/trace"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -115,14 +119,119 @@ func Wrap[T Callable](x T) (ContextErrorFunc, string) {
 // A Hook is a pair of start and stop callbacks, either of which can be nil,
 // plus a string identifying the supplier of the hook.
 type Hook struct {
+	Name        string
 	OnStart     func(context.Context) error
 	OnStop      func(context.Context) error
+	OnDrain     func(context.Context) error
+	OnReload    func(context.Context) error
 	OnStartName string
 	OnStopName  string
+	Timeout     time.Duration
+	Phase       string
+
+	// Names of the hooks this hook starts after, and before.
+	RunAfter  []string
+	RunBefore []string
+
+	// Maximum number of times OnStart is run, and how long to wait
+	// before each retry.
+	Attempts int
+	Backoff  func(retry int) time.Duration
+
+	// Budget shared with other hooks, if any.
+	Budget *Budget
 
 	callerFrame fxreflect.Frame
 }
 
+// A Budget bounds the total time that a group of hooks,
+// such as those of a module, may take to start and to stop.
+type Budget struct {
+	// Name identifies the group of hooks in errors.
+	Name string
+
+	// Timeouts for the group's OnStart and OnStop hooks.
+	// The group isn't bounded in a direction whose timeout isn't positive.
+	StartTimeout time.Duration
+	StopTimeout  time.Duration
+
+	// The time charged to the group in this Start or Stop is used,
+	// plus the time elapsed since since while running > 0 of its hooks
+	// are running, so that hooks running at once are charged for their
+	// overlap once.
+	used    time.Duration
+	running int
+	since   time.Time
+}
+
+// elapsed returns the time charged to b as of now.
+// This must be called with l.mu held.
+func (b *Budget) elapsed(now time.Time) time.Duration {
+	if b.running == 0 {
+		return b.used
+	}
+	return b.used + now.Sub(b.since)
+}
+
+// StartName returns the name of the hook's OnStart function,
+// or an empty string if it has none.
+func (h Hook) StartName() string {
+	if h.OnStart == nil {
+		return ""
+	}
+	if len(h.OnStartName) > 0 {
+		return h.OnStartName
+	}
+	return fxreflect.FuncName(h.OnStart)
+}
+
+// StopName returns the name of the hook's OnStop function,
+// or an empty string if it has none.
+func (h Hook) StopName() string {
+	if h.OnStop == nil {
+		return ""
+	}
+	if len(h.OnStopName) > 0 {
+		return h.OnStopName
+	}
+	return fxreflect.FuncName(h.OnStop)
+}
+
+// startEventName returns the name identifying the hook's OnStart function
+// in events: the hook's name if it has one.
+func (h Hook) startEventName() string {
+	if len(h.Name) > 0 {
+		return h.Name
+	}
+	return h.StartName()
+}
+
+// stopEventName returns the name identifying the hook's OnStop function
+// in events: the hook's name if it has one.
+func (h Hook) stopEventName() string {
+	if len(h.Name) > 0 {
+		return h.Name
+	}
+	return h.StopName()
+}
+
+// CallerName returns the name of the function that appended the hook.
+func (h Hook) CallerName() string {
+	return h.callerFrame.Function
+}
+
+// HookStatus is the execution status of a hook.
+type HookStatus int
+
+// Hook statuses, from a hook's registration until it's stopped.
+const (
+	HookPending     HookStatus = iota // not started
+	HookStarted                       // OnStart succeeded, or there's none
+	HookStartFailed                   // OnStart failed
+	HookStopped                       // OnStop ran and succeeded, or there's none
+	HookStopFailed                    // OnStop failed
+)
+
 type appState int
 
 const (
@@ -150,31 +259,421 @@ func (as appState) String() string {
 	}
 }
 
+// ErrAlreadyStarted is returned by Start if the lifecycle is already
+// starting or started.
+var ErrAlreadyStarted = errors.New("already started")
+
 // Lifecycle coordinates application lifecycle hooks.
 type Lifecycle struct {
 	clock        fxclock.Clock
 	logger       fxevent.Logger
 	state        appState
 	hooks        []Hook
+	statuses     []HookStatus  // status of each hook
+	runtimes     []HookRuntime // runtimes of each hook
+	phases       []string
+	order        []int // indexes of hooks in the order they're started
 	numStarted   int
+	failedStart  int   // index of the first OnStart hook that failed, or -1
+	stopped      []int // indexes of hooks whose OnStop ran in the last Stop
 	startRecords HookRecords
 	stopRecords  HookRecords
 	runningHook  Hook
-	mu           sync.Mutex
+	failures     []error // of hooks, since the last TakeFailures
+	traceRegions bool
+	pprofLabels  bool
+
+	// starts a span for each hook, if set
+	startSpan func(context.Context, string) (context.Context, func(error))
+
+	// called before each OnStart hook runs, if set
+	startProgress func(StartProgress)
+
+	// If workers > 1, the number of OnStart hooks that may run at once,
+	// and whether the hook at index i must start after the one at index j.
+	workers   int
+	dependsOn func(i, j int) bool
+
+	recoverStopPanics bool
+	abortOnStopPanic  bool
+	mu                sync.Mutex
 }
 
 // New constructs a new Lifecycle.
 func New(logger fxevent.Logger, clock fxclock.Clock) *Lifecycle {
-	return &Lifecycle{logger: logger, clock: clock}
+	return &Lifecycle{logger: logger, clock: clock, failedStart: -1}
+}
+
+// TraceRegions makes the lifecycle run each hook in a runtime/trace region.
+func (l *Lifecycle) TraceRegions() {
+	l.traceRegions = true
+}
+
+// TraceSpans makes the lifecycle run each hook in a span started with
+// startSpan, named like its trace region.
+// Hooks receive the context returned by startSpan.
+func (l *Lifecycle) TraceSpans(startSpan func(ctx context.Context, name string) (context.Context, func(error))) {
+	l.startSpan = startSpan
+}
+
+// ProfileLabels makes the lifecycle run each hook with a pprof label
+// named "fx", set to the name of its trace region.
+func (l *Lifecycle) ProfileLabels() {
+	l.pprofLabels = true
+}
+
+// RecoverStopPanics makes the lifecycle recover from panics in OnStop
+// hooks, reporting them as errors.
+// With abort, Stop returns as soon as a hook panics,
+// without running the remaining OnStop hooks.
+func (l *Lifecycle) RecoverStopPanics(abort bool) {
+	l.recoverStopPanics = true
+	l.abortOnStopPanic = abort
+}
+
+// StartProgress describes an OnStart hook that's about to run.
+type StartProgress struct {
+	Index    int // of the hook, in the order it was appended
+	Position int // among the OnStart hooks run by Start, from 1
+	Total    int // number of OnStart hooks run by Start
+
+	FunctionName string
+	CallerName   string
+}
+
+// OnStartProgress sets a function that's called before each OnStart hook
+// runs.
+func (l *Lifecycle) OnStartProgress(f func(StartProgress)) {
+	l.startProgress = f
+}
+
+// SetPhases sets the names of the phases hooks may be registered into,
+// in the order they run.
+func (l *Lifecycle) SetPhases(phases []string) {
+	l.phases = phases
+}
+
+// startOrder returns the indexes of the hooks in the order they're started:
+// hooks without a phase first, then the hooks of each phase in turn.
+// Within a phase, hooks start after the hooks named in their RunAfter
+// and before those named in their RunBefore.
+// Of the hooks free to start, the one appended first starts first.
+// This must be called with l.mu held.
+func (l *Lifecycle) startOrder() ([]int, error) {
+	rank := make(map[string]int, len(l.phases)+1)
+	rank[""] = 0
+	for i, phase := range l.phases {
+		rank[phase] = i + 1
+	}
+
+	order := make([]int, len(l.hooks))
+	for i, hook := range l.hooks {
+		if _, ok := rank[hook.Phase]; !ok {
+			return nil, fmt.Errorf("hook appended by %v uses unknown lifecycle phase %q",
+				hook.callerFrame.Function, hook.Phase)
+		}
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return rank[l.hooks[order[i]].Phase] < rank[l.hooks[order[j]].Phase]
+	})
+	return l.constrainOrder(order, rank)
+}
+
+// constrainOrder reorders hooks, given in phase order,
+// to satisfy their RunAfter and RunBefore constraints.
+// It returns an error if a constraint names an unknown hook,
+// or if the constraints and phases can't all be satisfied.
+func (l *Lifecycle) constrainOrder(order []int, rank map[string]int) ([]int, error) {
+	byName := make(map[string][]int)
+	constrained := false
+	for i, hook := range l.hooks {
+		if len(hook.Name) > 0 {
+			byName[hook.Name] = append(byName[hook.Name], i)
+		}
+		if len(hook.RunAfter) > 0 || len(hook.RunBefore) > 0 {
+			constrained = true
+		}
+	}
+	if !constrained {
+		return order, nil
+	}
+
+	// after[i] holds the hooks that hook i starts after.
+	after := make([]map[int]struct{}, len(l.hooks))
+	for i := range after {
+		after[i] = make(map[int]struct{})
+	}
+	for i, hook := range l.hooks {
+		for _, name := range hook.RunAfter {
+			others, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("hook %v appended by %v must run after unknown hook %q",
+					hook.startEventName(), hook.callerFrame.Function, name)
+			}
+			for _, j := range others {
+				after[i][j] = struct{}{}
+			}
+		}
+		for _, name := range hook.RunBefore {
+			others, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("hook %v appended by %v must run before unknown hook %q",
+					hook.startEventName(), hook.callerFrame.Function, name)
+			}
+			for _, j := range others {
+				after[j][i] = struct{}{}
+			}
+		}
+	}
+
+	// Hooks of a phase start once every hook of earlier phases has.
+	pending := make(map[int]int) // phase rank => hooks not yet ordered
+	for _, hook := range l.hooks {
+		pending[rank[hook.Phase]]++
+	}
+	sorted := make([]int, 0, len(order))
+	done := make([]bool, len(l.hooks))
+	for len(sorted) < len(order) {
+		next := -1
+	candidates:
+		for _, i := range order {
+			if done[i] {
+				continue
+			}
+			for j := range after[i] {
+				if !done[j] {
+					continue candidates
+				}
+			}
+			for r, n := range pending {
+				if r < rank[l.hooks[i].Phase] && n > 0 {
+					continue candidates
+				}
+			}
+			next = i
+			break
+		}
+		if next < 0 {
+			var names []string
+			for _, i := range order {
+				if !done[i] {
+					names = append(names, l.hooks[i].startEventName())
+				}
+			}
+			return nil, fmt.Errorf("ordering constraints of lifecycle hooks can't be satisfied: %v",
+				strings.Join(names, ", "))
+		}
+		done[next] = true
+		pending[rank[l.hooks[next].Phase]]--
+		sorted = append(sorted, next)
+	}
+	return sorted, nil
+}
+
+// runHook calls f with ctx, in a runtime/trace region, with pprof labels,
+// and in a span if requested.
+func (l *Lifecycle) runHook(ctx context.Context, regionType string, f func(context.Context) error) (err error) {
+	if l.startSpan != nil {
+		var end func(error)
+		ctx, end = l.startSpan(ctx, regionType)
+		defer func() { end(err) }()
+	}
+	if l.pprofLabels {
+		inner := f
+		f = func(ctx context.Context) (err error) {
+			pprof.Do(ctx, pprof.Labels("fx", regionType), func(ctx context.Context) {
+				err = inner(ctx)
+			})
+			return err
+		}
+	}
+	if !l.traceRegions {
+		return f(ctx)
+	}
+	trace.WithRegion(ctx, regionType, func() {
+		err = f(ctx)
+	})
+	return err
+}
+
+// runHookTimeout is runHook for hooks with their own timeout.
+// It returns once the timeout elapses, even if f hasn't returned.
+func (l *Lifecycle) runHookTimeout(ctx context.Context, timeout time.Duration, regionType string, f func(context.Context) error) error {
+	if timeout <= 0 {
+		return l.runHook(ctx, regionType, f)
+	}
+
+	return l.runWithin(ctx, timeout, func(ctx context.Context) error {
+		return l.runHook(ctx, regionType, f)
+	}, func(err error) error {
+		return &timeoutError{
+			timeout: timeout,
+			err:     fmt.Errorf("hook did not finish within its %v timeout: %w", timeout, err),
+		}
+	})
+}
+
+// runInBudget runs f within what remains of timeout for the hooks of b,
+// if b is non-nil, and charges b for the time f takes.
+// Time during which several of the hooks of b run at once,
+// as they may with StartConcurrently, is charged once.
+// verb describes what the hooks do in errors: "start" or "stop".
+func (l *Lifecycle) runInBudget(ctx context.Context, b *Budget, timeout time.Duration, verb string, f func(context.Context) error) error {
+	if b == nil || timeout <= 0 {
+		return f(ctx)
+	}
+
+	l.mu.Lock()
+	now := l.clock.Now()
+	remaining := timeout - b.elapsed(now)
+	if b.running == 0 {
+		b.since = now
+	}
+	b.running++
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		b.running--
+		if b.running == 0 {
+			b.used += l.clock.Since(b.since)
+		}
+		l.mu.Unlock()
+	}()
+
+	budgetErr := func(err error) error {
+		return &timeoutError{
+			timeout: timeout,
+			err:     fmt.Errorf("%v did not %v within its %v timeout: %w", b.Name, verb, timeout, err),
+		}
+	}
+	if remaining <= 0 {
+		return budgetErr(context.DeadlineExceeded)
+	}
+	return l.runWithin(ctx, remaining, f, budgetErr)
 }
 
+// runWithin runs f with a context that's canceled once timeout elapses.
+// It returns once the timeout elapses, even if f hasn't returned,
+// with the context's error wrapped by wrapErr.
+func (l *Lifecycle) runWithin(ctx context.Context, timeout time.Duration, f func(context.Context) error, wrapErr func(error) error) error {
+	parent := ctx
+	ctx, cancel := l.clock.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Distinguish this deadline from the caller's.
+	timeoutErr := func() error {
+		if parent.Err() != nil {
+			return parent.Err()
+		}
+		return wrapErr(ctx.Err())
+	}
+
+	c := make(chan error, 1)
+	go func() {
+		// If f calls runtime.Goexit, report it instead of waiting
+		// for the timeout to elapse.
+		exited := false
+		defer func() {
+			if !exited {
+				c <- errHookExited
+			}
+		}()
+
+		c <- f(ctx)
+		exited = true
+	}()
+
+	select {
+	case <-ctx.Done():
+		return timeoutErr()
+	case err := <-c:
+		// Prefer the context error if both are ready.
+		if ctx.Err() != nil {
+			return timeoutErr()
+		}
+		return err
+	}
+}
+
+var errHookExited = errors.New("goroutine exited without returning")
+
 // Append adds a Hook to the lifecycle.
 func (l *Lifecycle) Append(hook Hook) {
 	// Save the caller's stack frame to report file/line number.
 	if f := fxreflect.CallerStack(2, 0); len(f) > 0 {
 		hook.callerFrame = f[0]
 	}
+	l.mu.Lock()
 	l.hooks = append(l.hooks, hook)
+	l.statuses = append(l.statuses, HookPending)
+	l.runtimes = append(l.runtimes, HookRuntime{})
+	l.mu.Unlock()
+}
+
+// Hooks returns the hooks appended to the lifecycle, in order.
+func (l *Lifecycle) Hooks() []Hook {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Hook(nil), l.hooks...)
+}
+
+// Statuses returns the execution status of each hook appended to the
+// lifecycle, in the order they were appended.
+func (l *Lifecycle) Statuses() []HookStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]HookStatus(nil), l.statuses...)
+}
+
+// setStatus records the execution status of the hook at index i.
+func (l *Lifecycle) setStatus(i int, status HookStatus) {
+	l.mu.Lock()
+	l.statuses[i] = status
+	l.mu.Unlock()
+}
+
+// HookRuntime reports how long the callbacks of a hook ran.
+type HookRuntime struct {
+	// Start is how long OnStart ran, including retries,
+	// the last time the lifecycle was started.
+	Start time.Duration
+
+	// Stop is how long OnStop ran
+	// the last time the lifecycle was stopped.
+	Stop time.Duration
+}
+
+// Runtimes returns the runtimes of each hook appended to the lifecycle,
+// in the order they were appended.
+func (l *Lifecycle) Runtimes() []HookRuntime {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]HookRuntime(nil), l.runtimes...)
+}
+
+// SetBudget makes the hook at index i share b with other hooks.
+func (l *Lifecycle) SetBudget(i int, b *Budget) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks[i].Budget = b
+}
+
+// resetBudgets clears the time charged to the budgets of all hooks.
+// This must be called with l.mu held.
+func (l *Lifecycle) resetBudgets() {
+	for _, h := range l.hooks {
+		if h.Budget != nil {
+			h.Budget.used = 0
+		}
+	}
+}
+
+// HookCount returns the number of hooks appended to the lifecycle.
+func (l *Lifecycle) HookCount() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.hooks)
 }
 
 // Start runs all OnStart hooks, returning immediately if it encounters an
@@ -185,12 +684,26 @@ func (l *Lifecycle) Start(ctx context.Context) error {
 	}
 
 	l.mu.Lock()
-	if l.state != stopped {
+	switch l.state {
+	case stopped:
+	case starting, started:
+		defer l.mu.Unlock()
+		return fmt.Errorf("attempted to start lifecycle when in state: %v: %w", l.state, ErrAlreadyStarted)
+	default:
 		defer l.mu.Unlock()
 		return fmt.Errorf("attempted to start lifecycle when in state: %v", l.state)
 	}
+	order, err := l.startOrder()
+	if err != nil {
+		defer l.mu.Unlock()
+		return err
+	}
+	l.order = order
 	l.numStarted = 0
+	l.failedStart = -1
 	l.state = starting
+	l.runningHook = Hook{}
+	l.resetBudgets()
 
 	l.startRecords = make(HookRecords, 0, len(l.hooks))
 	l.mu.Unlock()
@@ -202,29 +715,42 @@ func (l *Lifecycle) Start(ctx context.Context) error {
 		l.mu.Unlock()
 	}()
 
-	for _, hook := range l.hooks {
+	var total, position int
+	for _, i := range order {
+		if l.hooks[i].OnStart != nil {
+			total++
+		}
+	}
+
+	if l.workers > 1 {
+		if err := l.startConcurrently(ctx, order, total); err != nil {
+			return err
+		}
+		returnState = started
+		return nil
+	}
+
+	for _, i := range order {
+		hook := l.hooks[i]
 		// if ctx has cancelled, bail out of the loop.
 		if err := ctx.Err(); err != nil {
 			return err
 		}
 
 		if hook.OnStart != nil {
+			position++
+			l.reportStartProgress(i, position, total)
+
 			l.mu.Lock()
 			l.runningHook = hook
 			l.mu.Unlock()
 
 			runtime, err := l.runStartHook(ctx, hook)
-			if err != nil {
+			if err := l.recordStart(i, runtime, err); err != nil {
 				return err
 			}
-
-			l.mu.Lock()
-			l.startRecords = append(l.startRecords, HookRecord{
-				CallerFrame: hook.callerFrame,
-				Func:        hook.OnStart,
-				Runtime:     runtime,
-			})
-			l.mu.Unlock()
+		} else {
+			l.setStatus(i, HookStarted)
 		}
 		l.numStarted++
 	}
@@ -233,11 +759,201 @@ func (l *Lifecycle) Start(ctx context.Context) error {
 	return nil
 }
 
-func (l *Lifecycle) runStartHook(ctx context.Context, hook Hook) (runtime time.Duration, err error) {
-	funcName := hook.OnStartName
-	if len(funcName) == 0 {
-		funcName = fxreflect.FuncName(hook.OnStart)
+// reportStartProgress reports that the OnStart hook at index i,
+// at the given position among those run by Start, is about to run.
+func (l *Lifecycle) reportStartProgress(i, position, total int) {
+	if l.startProgress == nil {
+		return
+	}
+	hook := l.hooks[i]
+	l.startProgress(StartProgress{
+		Index:        i,
+		Position:     position,
+		Total:        total,
+		FunctionName: hook.startEventName(),
+		CallerName:   hook.callerFrame.Function,
+	})
+}
+
+// recordStart records the outcome of the OnStart hook at index i,
+// returning err.
+func (l *Lifecycle) recordStart(i int, runtime time.Duration, err error) error {
+	hook := l.hooks[i]
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.runtimes[i].Start = runtime
+	if err != nil {
+		l.statuses[i] = HookStartFailed
+		if l.failedStart < 0 {
+			l.failedStart = i
+		}
+		return err
 	}
+	l.statuses[i] = HookStarted
+	l.startRecords = append(l.startRecords, HookRecord{
+		CallerFrame: hook.callerFrame,
+		Func:        hook.OnStart,
+		Runtime:     runtime,
+	})
+	return nil
+}
+
+// StartConcurrently makes Start run up to workers OnStart hooks at once.
+// A hook starts only once the hooks it depends on have started:
+// those of earlier phases, those its ordering constraints name,
+// and earlier hooks for which dependsOn(i, j) reports true,
+// where i and j are the indexes of the hooks in the order they were
+// appended.
+// With a nil dependsOn, every hook depends on the hooks before it.
+func (l *Lifecycle) StartConcurrently(workers int, dependsOn func(i, j int) bool) {
+	l.workers = workers
+	l.dependsOn = dependsOn
+}
+
+// waitsFor reports whether the hook at index i must start after the hook
+// at index j, given that j comes before i in the start order.
+func (l *Lifecycle) waitsFor(i, j int, rank map[string]int) bool {
+	a, b := l.hooks[i], l.hooks[j]
+	if rank[a.Phase] != rank[b.Phase] {
+		return true
+	}
+	if len(b.Name) > 0 {
+		for _, name := range a.RunAfter {
+			if name == b.Name {
+				return true
+			}
+		}
+	}
+	if len(a.Name) > 0 {
+		for _, name := range b.RunBefore {
+			if name == a.Name {
+				return true
+			}
+		}
+	}
+	return l.dependsOn == nil || l.dependsOn(i, j)
+}
+
+// startConcurrently runs the OnStart hooks of the hooks in order,
+// up to l.workers at a time, each once the hooks it waits for have started.
+// Once a hook fails or ctx is done, no more hooks are started,
+// and startConcurrently returns the error once running hooks return.
+// The start order is then updated to the order in which hooks started,
+// so that Stop runs OnStop hooks in reverse.
+func (l *Lifecycle) startConcurrently(ctx context.Context, order []int, total int) error {
+	rank := make(map[string]int, len(l.phases)+1)
+	for i, phase := range l.phases {
+		rank[phase] = i + 1
+	}
+
+	// waiting[p] is the number of hooks that the hook at order[p]
+	// waits for, and next[p] the positions of the hooks waiting for it.
+	waiting := make([]int, len(order))
+	next := make([][]int, len(order))
+	for p, i := range order {
+		for q := 0; q < p; q++ {
+			if l.waitsFor(i, order[q], rank) {
+				waiting[p]++
+				next[q] = append(next[q], p)
+			}
+		}
+	}
+
+	var ready []int // positions of hooks free to start, in start order
+	for p := range order {
+		if waiting[p] == 0 {
+			ready = append(ready, p)
+		}
+	}
+	started := make([]int, 0, len(order)) // hook indexes, in start order
+	done := func(p int) {
+		started = append(started, order[p])
+		for _, q := range next[p] {
+			if waiting[q]--; waiting[q] == 0 {
+				ready = append(ready, q)
+			}
+		}
+		sort.Ints(ready)
+	}
+
+	type result struct {
+		pos     int
+		runtime time.Duration
+		err     error
+	}
+	results := make(chan result)
+
+	var (
+		err      error
+		running  int
+		position int
+	)
+	for {
+		for err == nil && running < l.workers && len(ready) > 0 {
+			if err = ctx.Err(); err != nil {
+				break
+			}
+
+			p := ready[0]
+			ready = ready[1:]
+			i := order[p]
+			hook := l.hooks[i]
+			if hook.OnStart == nil {
+				l.setStatus(i, HookStarted)
+				done(p)
+				continue
+			}
+
+			position++
+			l.reportStartProgress(i, position, total)
+			l.mu.Lock()
+			l.runningHook = hook
+			l.mu.Unlock()
+
+			running++
+			go func() {
+				runtime, err := l.runStartHook(ctx, hook)
+				results <- result{pos: p, runtime: runtime, err: err}
+			}()
+		}
+		if running == 0 {
+			break
+		}
+
+		r := <-results
+		running--
+		if startErr := l.recordStart(order[r.pos], r.runtime, r.err); startErr != nil {
+			if err == nil {
+				err = startErr
+			}
+			continue
+		}
+		done(r.pos)
+	}
+
+	// Hooks that didn't start go last, in their original order.
+	newOrder := append([]int(nil), started...)
+	isStarted := make(map[int]bool, len(started))
+	for _, i := range started {
+		isStarted[i] = true
+	}
+	for _, i := range order {
+		if !isStarted[i] {
+			newOrder = append(newOrder, i)
+		}
+	}
+
+	l.mu.Lock()
+	l.order = newOrder
+	l.numStarted = len(started)
+	l.mu.Unlock()
+	return err
+}
+
+func (l *Lifecycle) runStartHook(ctx context.Context, hook Hook) (runtime time.Duration, err error) {
+	funcName := hook.startEventName()
 
 	l.logger.LogEvent(&fxevent.OnStartExecuting{
 		CallerName:   hook.callerFrame.Function,
@@ -248,28 +964,103 @@ func (l *Lifecycle) runStartHook(ctx context.Context, hook Hook) (runtime time.D
 			CallerName:   hook.callerFrame.Function,
 			FunctionName: funcName,
 			Runtime:      runtime,
+			Timeout:      hook.Timeout,
 			Err:          err,
 		})
 	}()
 
+	var startTimeout time.Duration
+	if hook.Budget != nil {
+		startTimeout = hook.Budget.StartTimeout
+	}
+
 	begin := l.clock.Now()
-	err = hook.OnStart(ctx)
+	err = l.runInBudget(ctx, hook.Budget, startTimeout, "start", func(ctx context.Context) (err error) {
+		for retry := 1; ; retry++ {
+			err = l.runHookTimeout(ctx, hook.Timeout, "fx.OnStart: "+funcName, hook.OnStart)
+			if err == nil || retry >= hook.Attempts {
+				return err
+			}
+			// An attempt that timed out may still be running:
+			// retrying would run OnStart concurrently with it.
+			var te *timeoutError
+			if errors.As(err, &te) {
+				return err
+			}
+
+			var backoff time.Duration
+			if hook.Backoff != nil {
+				backoff = hook.Backoff(retry)
+			}
+			if ctxErr := l.sleep(ctx, backoff); ctxErr != nil {
+				return multierr.Append(err, ctxErr)
+			}
+		}
+	})
+	if err != nil {
+		l.recordFailure(hookError(ctx, "OnStart", funcName, hook, err))
+	}
 	return l.clock.Since(begin), err
 }
 
+// sleep waits for d to elapse on the lifecycle's clock,
+// returning early with an error if ctx is done first.
+func (l *Lifecycle) sleep(ctx context.Context, d time.Duration) error {
+	if d > 0 {
+		sleepCtx, cancel := l.clock.WithTimeout(ctx, d)
+		defer cancel()
+		select {
+		case <-sleepCtx.Done():
+		case <-ctx.Done():
+		}
+	}
+	return ctx.Err()
+}
+
+// Running reports whether the lifecycle has been started and not yet
+// stopped, in which case Stop has hooks to run.
+func (l *Lifecycle) Running() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.running()
+}
+
+// State describes the state of the lifecycle,
+// such as "starting" or "stopped".
+func (l *Lifecycle) State() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.state.String()
+}
+
+// Started reports whether the lifecycle has finished starting successfully
+// and hasn't begun stopping.
+func (l *Lifecycle) Started() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.state == started
+}
+
+// running is Running for callers that hold l.mu.
+func (l *Lifecycle) running() bool {
+	return l.state == started || l.state == incompleteStart || l.state == starting
+}
+
 // Stop runs any OnStop hooks whose OnStart counterpart succeeded. OnStop
-// hooks run in reverse order.
+// hooks run in reverse order. Stop is a no-op if the lifecycle is not
+// running.
 func (l *Lifecycle) Stop(ctx context.Context) error {
 	if ctx == nil {
 		return errors.New("called OnStop with nil context")
 	}
 
 	l.mu.Lock()
-	if l.state != started && l.state != incompleteStart && l.state != starting {
+	if !l.running() {
 		defer l.mu.Unlock()
 		return nil
 	}
 	l.state = stopping
+	l.runningHook = Hook{}
 	l.mu.Unlock()
 
 	defer func() {
@@ -280,8 +1071,11 @@ func (l *Lifecycle) Stop(ctx context.Context) error {
 
 	l.mu.Lock()
 	l.stopRecords = make(HookRecords, 0, l.numStarted)
+	l.stopped = l.stopped[:0]
+	l.resetBudgets()
 	// Take a snapshot of hook state to avoid races.
 	allHooks := l.hooks[:]
+	order := l.order
 	numStarted := l.numStarted
 	l.mu.Unlock()
 
@@ -291,8 +1085,10 @@ func (l *Lifecycle) Stop(ctx context.Context) error {
 		if err := ctx.Err(); err != nil {
 			return err
 		}
-		hook := allHooks[numStarted-1]
+		i := order[numStarted-1]
+		hook := allHooks[i]
 		if hook.OnStop == nil {
+			l.setStatus(i, HookStopped)
 			continue
 		}
 
@@ -301,9 +1097,16 @@ func (l *Lifecycle) Stop(ctx context.Context) error {
 		l.mu.Unlock()
 
 		runtime, err := l.runStopHook(ctx, hook)
+		l.mu.Lock()
+		l.runtimes[i].Stop = runtime
+		l.stopped = append(l.stopped, i)
+		l.mu.Unlock()
 		if err != nil {
 			// For best-effort cleanup, keep going after errors.
 			errs = append(errs, err)
+			l.setStatus(i, HookStopFailed)
+		} else {
+			l.setStatus(i, HookStopped)
 		}
 
 		l.mu.Lock()
@@ -313,16 +1116,109 @@ func (l *Lifecycle) Stop(ctx context.Context) error {
 			Runtime:     runtime,
 		})
 		l.mu.Unlock()
+
+		var panicErr *hookPanicError
+		if l.abortOnStopPanic && errors.As(err, &panicErr) {
+			break
+		}
 	}
 
 	return multierr.Combine(errs...)
 }
 
-func (l *Lifecycle) runStopHook(ctx context.Context, hook Hook) (runtime time.Duration, err error) {
-	funcName := hook.OnStopName
-	if len(funcName) == 0 {
-		funcName = fxreflect.FuncName(hook.OnStop)
+// FailedStart returns the name of the first OnStart hook that failed
+// during the last Start, and the name of the function that appended it.
+// Both are empty if no OnStart hook failed.
+func (l *Lifecycle) FailedStart() (name, caller string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.failedStart < 0 {
+		return "", ""
+	}
+	hook := l.hooks[l.failedStart]
+	return hook.startEventName(), hook.CallerName()
+}
+
+// StoppedHooks returns the names of the OnStop hooks run by the last
+// Stop, in the order they ran, and the names of those that failed.
+func (l *Lifecycle) StoppedHooks() (ran, failed []string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, i := range l.stopped {
+		name := l.hooks[i].stopEventName()
+		ran = append(ran, name)
+		if l.statuses[i] == HookStopFailed {
+			failed = append(failed, name)
+		}
 	}
+	return ran, failed
+}
+
+// Reload runs the OnReload hooks of the hooks whose OnStart succeeded,
+// in the order they were started.
+// Reload is a no-op if the lifecycle is not running.
+func (l *Lifecycle) Reload(ctx context.Context) error {
+	l.mu.Lock()
+	if l.state != started {
+		l.mu.Unlock()
+		return nil
+	}
+	// Take a snapshot of hook state to avoid races.
+	allHooks := l.hooks[:]
+	order := l.order
+	numStarted := l.numStarted
+	l.mu.Unlock()
+
+	// Reload as much as possible, even after errors.
+	var errs []error
+	for _, i := range order[:numStarted] {
+		hook := allHooks[i]
+		if hook.OnReload == nil {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := hook.OnReload(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return multierr.Combine(errs...)
+}
+
+// Drain runs the OnDrain hooks of the hooks whose OnStart succeeded,
+// in reverse order, as OnStop hooks would be.
+// It stops early, without an error, once ctx is done.
+// Drain is a no-op if the lifecycle is not running.
+func (l *Lifecycle) Drain(ctx context.Context) error {
+	l.mu.Lock()
+	if !l.running() {
+		l.mu.Unlock()
+		return nil
+	}
+	// Take a snapshot of hook state to avoid races.
+	allHooks := l.hooks[:]
+	order := l.order
+	numStarted := l.numStarted
+	l.mu.Unlock()
+
+	// For best-effort draining, keep going after errors.
+	var errs []error
+	for ; numStarted > 0 && ctx.Err() == nil; numStarted-- {
+		hook := allHooks[order[numStarted-1]]
+		if hook.OnDrain == nil {
+			continue
+		}
+		if err := hook.OnDrain(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return multierr.Combine(errs...)
+}
+
+func (l *Lifecycle) runStopHook(ctx context.Context, hook Hook) (runtime time.Duration, err error) {
+	funcName := hook.stopEventName()
 
 	l.logger.LogEvent(&fxevent.OnStopExecuting{
 		CallerName:   hook.callerFrame.Function,
@@ -333,12 +1229,28 @@ func (l *Lifecycle) runStopHook(ctx context.Context, hook Hook) (runtime time.Du
 			CallerName:   hook.callerFrame.Function,
 			FunctionName: funcName,
 			Runtime:      runtime,
+			Timeout:      hook.Timeout,
 			Err:          err,
 		})
 	}()
 
+	var stopTimeout time.Duration
+	if hook.Budget != nil {
+		stopTimeout = hook.Budget.StopTimeout
+	}
+
+	onStop := hook.OnStop
+	if l.recoverStopPanics {
+		onStop = recoverHookPanics(onStop)
+	}
+
 	begin := l.clock.Now()
-	err = hook.OnStop(ctx)
+	err = l.runInBudget(ctx, hook.Budget, stopTimeout, "stop", func(ctx context.Context) error {
+		return l.runHookTimeout(ctx, hook.Timeout, "fx.OnStop: "+funcName, onStop)
+	})
+	if err != nil {
+		l.recordFailure(hookError(ctx, "OnStop", funcName, hook, err))
+	}
 	return l.clock.Since(begin), err
 }
 
@@ -350,6 +1262,173 @@ func (l *Lifecycle) RunningHookCaller() string {
 	return l.runningHook.callerFrame.Function
 }
 
+// TimedOut returns a TimeoutError for err, the error of a Start or Stop
+// whose context ended before it finished,
+// naming the hook that was running at the time, if any.
+// hook is "OnStart" or "OnStop".
+func (l *Lifecycle) TimedOut(hook string, err error) *TimeoutError {
+	l.mu.Lock()
+	running := l.runningHook
+	l.mu.Unlock()
+
+	te := &TimeoutError{Hook: hook, Caller: running.callerFrame.Function, Err: err}
+	switch {
+	case hook == "OnStart" && running.OnStart != nil:
+		te.Function = running.startEventName()
+	case hook == "OnStop" && running.OnStop != nil:
+		te.Function = running.stopEventName()
+	}
+	return te
+}
+
+// recordFailure records the failure of a hook for TakeFailures.
+func (l *Lifecycle) recordFailure(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.failures = append(l.failures, err)
+}
+
+// TakeFailures returns the failures of the hooks that ran since
+// it was last called, as HookErrors and TimeoutErrors, and forgets them.
+func (l *Lifecycle) TakeFailures() []error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	failures := l.failures
+	l.failures = nil
+	return failures
+}
+
+// HookError describes the failure of a hook during Start or Stop.
+type HookError struct {
+	// Hook is the hook that failed: "OnStart" or "OnStop".
+	Hook string
+
+	// Function is the name of the hook's function,
+	// or the name given to the hook.
+	Function string
+
+	// Caller is the name of the function that appended the hook.
+	Caller string
+
+	// Err is the error returned by the hook.
+	Err error
+}
+
+func (e *HookError) Error() string { return e.Err.Error() }
+
+// Unwrap returns the error returned by the hook.
+func (e *HookError) Unwrap() error { return e.Err }
+
+// Format implements fmt.Formatter by formatting the hook's error.
+func (e *HookError) Format(w fmt.State, c rune) {
+	fmt.Fprintf(w, fmt.FormatString(w, c), e.Err)
+}
+
+// TimeoutError describes a hook, or all of them, not finishing in time
+// during Start or Stop.
+type TimeoutError struct {
+	// Hook is the hook that was running: "OnStart" or "OnStop".
+	Hook string
+
+	// Function is the name of the hook's function,
+	// or the name given to the hook.
+	// It's empty if no hook was running.
+	Function string
+
+	// Caller is the name of the function that appended the hook.
+	Caller string
+
+	// Timeout is the timeout of the hook or of its module that elapsed.
+	// It's zero if the context given to Start or Stop ended instead.
+	Timeout time.Duration
+
+	// Err is the error that occurred.
+	Err error
+}
+
+func (e *TimeoutError) Error() string { return e.Err.Error() }
+
+// Unwrap returns the error that occurred.
+func (e *TimeoutError) Unwrap() error { return e.Err }
+
+// Format implements fmt.Formatter by formatting the error that occurred.
+func (e *TimeoutError) Format(w fmt.State, c rune) {
+	fmt.Fprintf(w, fmt.FormatString(w, c), e.Err)
+}
+
+// hookPanicError is the error of a hook that panicked.
+type hookPanicError struct {
+	value interface{}
+	stack []byte // as formatted by debug.Stack
+}
+
+func (e *hookPanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.value)
+}
+
+// Format implements fmt.Formatter.
+// With "%+v", it adds the stack of the panic.
+func (e *hookPanicError) Format(w fmt.State, c rune) {
+	io.WriteString(w, e.Error())
+	if c == 'v' && w.Flag('+') {
+		fmt.Fprintf(w, "\n%s", e.stack)
+	}
+}
+
+// recoverHookPanics returns a function that runs f,
+// and reports its panics as a hookPanicError.
+func recoverHookPanics(f func(context.Context) error) func(context.Context) error {
+	return func(ctx context.Context) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = &hookPanicError{value: p, stack: debug.Stack()}
+			}
+		}()
+		return f(ctx)
+	}
+}
+
+// timeoutError is the error of a hook that didn't finish
+// within its timeout or that of its module.
+type timeoutError struct {
+	timeout time.Duration
+	err     error
+}
+
+func (e *timeoutError) Error() string { return e.err.Error() }
+
+func (e *timeoutError) Unwrap() error { return e.err }
+
+// hookError describes the failure of the given hook with err,
+// run with ctx: a TimeoutError if it ran out of time,
+// or a HookError otherwise.
+func hookError(ctx context.Context, hookName, funcName string, hook Hook, err error) error {
+	var te *timeoutError
+	if errors.As(err, &te) {
+		return &TimeoutError{
+			Hook:     hookName,
+			Function: funcName,
+			Caller:   hook.callerFrame.Function,
+			Timeout:  te.timeout,
+			Err:      err,
+		}
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) && errors.Is(err, context.DeadlineExceeded) {
+		return &TimeoutError{
+			Hook:     hookName,
+			Function: funcName,
+			Caller:   hook.callerFrame.Function,
+			Err:      err,
+		}
+	}
+	return &HookError{
+		Hook:     hookName,
+		Function: funcName,
+		Caller:   hook.callerFrame.Function,
+		Err:      err,
+	}
+}
+
 // HookRecord keeps track of each Hook's execution time, the caller that appended the Hook, and function that ran as the Hook.
 type HookRecord struct {
 	CallerFrame fxreflect.Frame             // stack frame of the caller
@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -72,6 +73,77 @@ func TestLifecycleStart(t *testing.T) {
 		assert.Equal(t, 2, count)
 	})
 
+	t.Run("Progress", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(testLogger(t), fxclock.System)
+		noop := func(context.Context) error { return nil }
+		l.Append(Hook{Name: "first", OnStart: noop})
+		l.Append(Hook{OnStop: noop})
+		l.Append(Hook{Name: "second", OnStart: noop})
+
+		var got []StartProgress
+		l.OnStartProgress(func(p StartProgress) {
+			p.CallerName = ""
+			got = append(got, p)
+		})
+		require.NoError(t, l.Start(context.Background()))
+		assert.Equal(t, []StartProgress{
+			{Index: 0, Position: 1, Total: 2, FunctionName: "first"},
+			{Index: 2, Position: 2, Total: 2, FunctionName: "second"},
+		}, got)
+	})
+
+	t.Run("Concurrently", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(testLogger(t), fxclock.System)
+		l.StartConcurrently(2, func(i, j int) bool { return false })
+
+		// Both hooks must be running at the same time to return.
+		started := make(chan struct{}, 2)
+		both := func(context.Context) error {
+			started <- struct{}{}
+			for len(started) < 2 {
+				time.Sleep(time.Millisecond)
+			}
+			return nil
+		}
+		l.Append(Hook{OnStart: both})
+		l.Append(Hook{OnStart: both})
+
+		done := make(chan error)
+		go func() { done <- l.Start(context.Background()) }()
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("hooks did not run concurrently")
+		}
+	})
+
+	t.Run("ConcurrentlyRespectsDependencies", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(testLogger(t), fxclock.System)
+		l.StartConcurrently(4, nil)
+
+		count := 0
+		for i := 1; i <= 3; i++ {
+			want := i
+			l.Append(Hook{
+				OnStart: func(context.Context) error {
+					count++
+					assert.Equal(t, want, count, "hooks without a dependency function run in order")
+					return nil
+				},
+			})
+		}
+
+		require.NoError(t, l.Start(context.Background()))
+		assert.Equal(t, 3, count)
+	})
+
 	t.Run("ErrHaltsChainAndRollsBack", func(t *testing.T) {
 		t.Parallel()
 
@@ -153,9 +225,247 @@ func TestLifecycleStart(t *testing.T) {
 		err := l.Start(context.Background())
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "attempted to start lifecycle when in state: started")
+		assert.ErrorIs(t, err, ErrAlreadyStarted)
 		assert.NoError(t, l.Stop(context.Background()))
 		assert.NoError(t, l.Start(context.Background()))
 	})
+	t.Run("Timeout", func(t *testing.T) {
+		t.Parallel()
+
+		var spy fxlog.Spy
+		l := New(&spy, fxclock.System)
+		release := make(chan struct{})
+		defer close(release)
+		l.Append(Hook{
+			OnStart: func(ctx context.Context) error {
+				assert.NotNil(t, ctx.Done(), "expected a deadline on the hook context")
+				<-release // ignores ctx
+				return nil
+			},
+			Timeout: 10 * time.Millisecond,
+		})
+
+		err := l.Start(context.Background())
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Contains(t, err.Error(), "hook did not finish within its 10ms timeout")
+
+		executed := spy.Events().SelectByTypeName("OnStartExecuted")
+		require.Len(t, executed, 1)
+		assert.Equal(t, 10*time.Millisecond, executed[0].(*fxevent.OnStartExecuted).Timeout)
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		l := New(testLogger(t), clock)
+		var attempts int
+		var backoffs []int
+		l.Append(Hook{
+			OnStart: func(context.Context) error {
+				attempts++
+				if attempts < 3 {
+					return errors.New("not yet")
+				}
+				return nil
+			},
+			Attempts: 5,
+			Backoff: func(retry int) time.Duration {
+				backoffs = append(backoffs, retry)
+				return time.Duration(retry) * time.Second
+			},
+		})
+
+		done := make(chan error)
+		go func() { done <- l.Start(context.Background()) }()
+		clock.AwaitScheduled(1)
+		clock.Add(time.Second)
+		clock.AwaitScheduled(1)
+		clock.Add(2 * time.Second)
+
+		require.NoError(t, <-done)
+		assert.Equal(t, 3, attempts)
+		assert.Equal(t, []int{1, 2}, backoffs)
+		require.Len(t, l.startRecords, 1)
+		assert.Equal(t, 3*time.Second, l.startRecords[0].Runtime)
+	})
+
+	t.Run("RetryExhausted", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(testLogger(t), fxclock.System)
+		var attempts int
+		l.Append(Hook{
+			OnStart: func(context.Context) error {
+				attempts++
+				return fmt.Errorf("attempt %d failed", attempts)
+			},
+			Attempts: 3,
+		})
+
+		err := l.Start(context.Background())
+		assert.EqualError(t, err, "attempt 3 failed")
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("RetryCanceledDuringBackoff", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		l := New(testLogger(t), clock)
+		var attempts int
+		l.Append(Hook{
+			OnStart: func(context.Context) error {
+				attempts++
+				return errors.New("great sadness")
+			},
+			Attempts: 3,
+			Backoff:  func(int) time.Duration { return time.Minute },
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- l.Start(ctx) }()
+		clock.AwaitScheduled(1)
+		cancel()
+
+		err := <-done
+		assert.ErrorContains(t, err, "great sadness")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("RetryAfterTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(testLogger(t), fxclock.System)
+		var attempts atomic.Int32
+		release := make(chan struct{})
+		defer close(release)
+		l.Append(Hook{
+			OnStart: func(context.Context) error {
+				attempts.Add(1)
+				<-release
+				return nil
+			},
+			Timeout:  10 * time.Millisecond,
+			Attempts: 3,
+		})
+
+		err := l.Start(context.Background())
+		assert.ErrorContains(t, err, "hook did not finish within its 10ms timeout")
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+
+	t.Run("TimeoutNotReached", func(t *testing.T) {
+		t.Parallel()
+
+		var spy fxlog.Spy
+		l := New(&spy, fxclock.System)
+		l.Append(Hook{
+			OnStart: func(ctx context.Context) error {
+				_, ok := ctx.Deadline()
+				assert.True(t, ok, "expected a deadline on the hook context")
+				return nil
+			},
+			Timeout: time.Minute,
+		})
+
+		require.NoError(t, l.Start(context.Background()))
+		executed := spy.Events().SelectByTypeName("OnStartExecuted")
+		require.Len(t, executed, 1)
+		assert.Equal(t, time.Minute, executed[0].(*fxevent.OnStartExecuted).Timeout)
+		require.NoError(t, l.Stop(context.Background()))
+	})
+
+	t.Run("Budget", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(testLogger(t), fxclock.System)
+		release := make(chan struct{})
+		defer close(release)
+		budget := &Budget{Name: `module "db"`, StartTimeout: 10 * time.Millisecond}
+		l.Append(Hook{
+			OnStart: func(ctx context.Context) error {
+				_, ok := ctx.Deadline()
+				assert.True(t, ok, "expected a deadline on the hook context")
+				return nil
+			},
+			Budget: budget,
+		})
+		l.Append(Hook{
+			OnStart: func(context.Context) error {
+				<-release // ignores ctx
+				return nil
+			},
+			Budget: budget,
+		})
+
+		err := l.Start(context.Background())
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Contains(t, err.Error(), `module "db" did not start within its 10ms timeout`)
+		assert.Equal(t, []HookStatus{HookStarted, HookStartFailed}, l.Statuses())
+	})
+
+	t.Run("BudgetConcurrently", func(t *testing.T) {
+		t.Parallel()
+
+		clock := fxclock.NewMock()
+		l := New(testLogger(t), clock)
+		// The third hook waits for the first two, which run at once.
+		l.StartConcurrently(2, func(i, j int) bool { return i == 2 })
+		budget := &Budget{Name: `module "db"`, StartTimeout: 12 * time.Millisecond}
+
+		started := make(chan struct{})
+		ran := make(chan struct{})
+		l.Append(Hook{
+			OnStart: func(context.Context) error {
+				<-started
+				clock.Add(8 * time.Millisecond)
+				close(ran)
+				return nil
+			},
+			Budget: budget,
+		})
+		l.Append(Hook{
+			OnStart: func(context.Context) error {
+				close(started)
+				<-ran
+				return nil
+			},
+			Budget: budget,
+		})
+		var third bool
+		l.Append(Hook{
+			OnStart: func(context.Context) error {
+				third = true
+				return nil
+			},
+			Budget: budget,
+		})
+
+		require.NoError(t, l.Start(context.Background()),
+			"time the hooks ran at once must be charged once")
+		assert.True(t, third)
+	})
+
+	t.Run("BudgetWithoutStartTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(testLogger(t), fxclock.System)
+		l.Append(Hook{
+			OnStart: func(ctx context.Context) error {
+				_, ok := ctx.Deadline()
+				assert.False(t, ok, "expected no deadline on the hook context")
+				return nil
+			},
+			Budget: &Budget{Name: `module "db"`, StopTimeout: time.Minute},
+		})
+
+		require.NoError(t, l.Start(context.Background()))
+	})
 }
 
 func TestLifecycleStop(t *testing.T) {
@@ -306,6 +616,86 @@ func TestLifecycleStop(t *testing.T) {
 		require.Error(t, l.Stop(ctx))
 	})
 
+	t.Run("Timeout", func(t *testing.T) {
+		t.Parallel()
+
+		var spy fxlog.Spy
+		l := New(&spy, fxclock.System)
+		release := make(chan struct{})
+		defer close(release)
+		stopped := false
+		l.Append(Hook{
+			OnStop: func(context.Context) error {
+				stopped = true
+				return nil
+			},
+		})
+		l.Append(Hook{
+			OnStop: func(ctx context.Context) error {
+				<-release // ignores ctx
+				return nil
+			},
+			Timeout: 10 * time.Millisecond,
+		})
+		require.NoError(t, l.Start(context.Background()))
+
+		err := l.Stop(context.Background())
+		assert.ErrorContains(t, err, "hook did not finish within its 10ms timeout")
+		assert.True(t, stopped, "expected the remaining hooks to run")
+
+		executed := spy.Events().SelectByTypeName("OnStopExecuted")
+		require.Len(t, executed, 2)
+		assert.Equal(t, 10*time.Millisecond, executed[0].(*fxevent.OnStopExecuted).Timeout)
+		assert.Zero(t, executed[1].(*fxevent.OnStopExecuted).Timeout)
+	})
+
+	t.Run("Budget", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(testLogger(t), fxclock.System)
+		release := make(chan struct{})
+		defer close(release)
+		budget := &Budget{Name: `module "db"`, StopTimeout: 10 * time.Millisecond}
+		var stopped []string
+		l.Append(Hook{
+			OnStop: func(context.Context) error {
+				stopped = append(stopped, "other")
+				return nil
+			},
+		})
+		l.Append(Hook{
+			OnStop: func(context.Context) error {
+				stopped = append(stopped, "db")
+				return nil
+			},
+			Budget: budget,
+		})
+		l.Append(Hook{
+			OnStop: func(context.Context) error {
+				<-release // ignores ctx
+				return nil
+			},
+			Budget: budget,
+		})
+		require.NoError(t, l.Start(context.Background()))
+
+		err := l.Stop(context.Background())
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Len(t, multierr.Errors(err), 2, "expected both db hooks to fail")
+		assert.Contains(t, err.Error(), `module "db" did not stop within its 10ms timeout`)
+		assert.Equal(t, []string{"other"}, stopped,
+			"expected only the hook outside the exhausted budget to run")
+		assert.Equal(t, []HookStatus{HookStopped, HookStopFailed, HookStopFailed}, l.Statuses())
+
+		// The budget is replenished for the next run.
+		release <- struct{}{}
+		l.hooks[2].OnStop = func(context.Context) error { return nil }
+		require.NoError(t, l.Start(context.Background()))
+		require.NoError(t, l.Stop(context.Background()))
+		assert.Equal(t, []string{"other", "db", "other"}, stopped)
+	})
+
 	t.Run("nil ctx", func(t *testing.T) {
 		t.Parallel()
 
@@ -328,6 +718,101 @@ func TestLifecycleStop(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "called OnStop with nil context")
 	})
+
+	t.Run("Running", func(t *testing.T) {
+		t.Parallel()
+
+		var stops int
+		l := New(testLogger(t), fxclock.System)
+		l.Append(Hook{OnStop: func(context.Context) error {
+			stops++
+			return nil
+		}})
+		assert.False(t, l.Running())
+
+		require.NoError(t, l.Start(context.Background()))
+		assert.True(t, l.Running())
+
+		require.NoError(t, l.Stop(context.Background()))
+		assert.False(t, l.Running())
+
+		require.NoError(t, l.Stop(context.Background()))
+		assert.Equal(t, 1, stops, "second Stop must not run hooks")
+	})
+}
+
+func TestLifecycleDrain(t *testing.T) {
+	t.Parallel()
+
+	t.Run("DoesNothingWhenNotStarted", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(testLogger(t), fxclock.System)
+		l.Append(Hook{
+			OnDrain: func(context.Context) error {
+				t.Error("this hook should not run")
+				return nil
+			},
+		})
+		assert.NoError(t, l.Drain(context.Background()))
+	})
+
+	t.Run("ExecutesInReverseOrder", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(testLogger(t), fxclock.System)
+		var calls []int
+		for i := 0; i < 3; i++ {
+			i := i
+			l.Append(Hook{
+				OnDrain: func(context.Context) error {
+					calls = append(calls, i)
+					return nil
+				},
+			})
+		}
+
+		require.NoError(t, l.Start(context.Background()))
+		require.NoError(t, l.Drain(context.Background()))
+		assert.Equal(t, []int{2, 1, 0}, calls)
+		require.NoError(t, l.Stop(context.Background()))
+	})
+
+	t.Run("GathersAllErrs", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(testLogger(t), fxclock.System)
+		err1, err2 := errors.New("drain 1"), errors.New("drain 2")
+		l.Append(Hook{OnDrain: func(context.Context) error { return err1 }})
+		l.Append(Hook{OnDrain: func(context.Context) error { return err2 }})
+
+		require.NoError(t, l.Start(context.Background()))
+		assert.Equal(t, multierr.Combine(err2, err1), l.Drain(context.Background()))
+		require.NoError(t, l.Stop(context.Background()))
+	})
+
+	t.Run("StopsWhenCtxDone", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(testLogger(t), fxclock.System)
+		ctx, cancel := context.WithCancel(context.Background())
+		l.Append(Hook{
+			OnDrain: func(context.Context) error {
+				t.Error("this hook should not run")
+				return nil
+			},
+		})
+		l.Append(Hook{
+			OnDrain: func(context.Context) error {
+				cancel()
+				return nil
+			},
+		})
+
+		require.NoError(t, l.Start(context.Background()))
+		assert.NoError(t, l.Drain(ctx))
+		require.NoError(t, l.Stop(context.Background()))
+	})
 }
 
 func TestHookRecordsFormat(t *testing.T) {
@@ -378,3 +863,189 @@ func TestHookRecordsFormat(t *testing.T) {
 func TestMain(m *testing.M) {
 	goleak.VerifyTestMain(m)
 }
+
+func TestLifecycleReload(t *testing.T) {
+	t.Parallel()
+
+	t.Run("DoesNothingWhenNotStarted", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(testLogger(t), fxclock.System)
+		l.Append(Hook{
+			OnReload: func(context.Context) error {
+				t.Error("this hook should not run")
+				return nil
+			},
+		})
+		assert.NoError(t, l.Reload(context.Background()))
+	})
+
+	t.Run("ExecutesInStartOrder", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(testLogger(t), fxclock.System)
+		var calls []int
+		for i := 0; i < 3; i++ {
+			i := i
+			l.Append(Hook{
+				OnReload: func(context.Context) error {
+					calls = append(calls, i)
+					return nil
+				},
+			})
+		}
+
+		require.NoError(t, l.Start(context.Background()))
+		require.NoError(t, l.Reload(context.Background()))
+		assert.Equal(t, []int{0, 1, 2}, calls)
+		require.NoError(t, l.Stop(context.Background()))
+	})
+
+	t.Run("GathersAllErrs", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(testLogger(t), fxclock.System)
+		err1, err2 := errors.New("reload 1"), errors.New("reload 2")
+		l.Append(Hook{OnReload: func(context.Context) error { return err1 }})
+		l.Append(Hook{OnReload: func(context.Context) error { return err2 }})
+
+		require.NoError(t, l.Start(context.Background()))
+		assert.Equal(t, multierr.Combine(err1, err2), l.Reload(context.Background()))
+		require.NoError(t, l.Stop(context.Background()))
+	})
+}
+
+func TestLifecycleStatuses(t *testing.T) {
+	t.Parallel()
+
+	l := New(testLogger(t), fxclock.System)
+	l.Append(Hook{OnStart: func(context.Context) error { return nil }})
+	l.Append(Hook{OnStop: func(context.Context) error { return errors.New("stop") }})
+	l.Append(Hook{OnStart: func(context.Context) error { return errors.New("start") }})
+	l.Append(Hook{})
+	assert.Equal(t, []HookStatus{HookPending, HookPending, HookPending, HookPending}, l.Statuses())
+
+	require.Error(t, l.Start(context.Background()))
+	assert.Equal(t, []HookStatus{HookStarted, HookStarted, HookStartFailed, HookPending}, l.Statuses())
+
+	require.Error(t, l.Stop(context.Background()))
+	assert.Equal(t, []HookStatus{HookStopped, HookStopFailed, HookStartFailed, HookPending}, l.Statuses())
+}
+
+func TestLifecycleRuntimes(t *testing.T) {
+	t.Parallel()
+
+	clock := fxclock.NewMock()
+	l := New(testLogger(t), clock)
+	l.Append(Hook{
+		OnStart: func(context.Context) error {
+			clock.Add(time.Second)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			clock.Add(2 * time.Second)
+			return nil
+		},
+	})
+	l.Append(Hook{
+		OnStart: func(context.Context) error {
+			clock.Add(3 * time.Second)
+			return errors.New("start")
+		},
+	})
+	assert.Equal(t, []HookRuntime{{}, {}}, l.Runtimes())
+
+	require.Error(t, l.Start(context.Background()))
+	assert.Equal(t, []HookRuntime{
+		{Start: time.Second},
+		{Start: 3 * time.Second},
+	}, l.Runtimes())
+
+	require.NoError(t, l.Stop(context.Background()))
+	assert.Equal(t, []HookRuntime{
+		{Start: time.Second, Stop: 2 * time.Second},
+		{Start: 3 * time.Second},
+	}, l.Runtimes())
+}
+
+func TestLifecycleFailedStartAndStoppedHooks(t *testing.T) {
+	t.Parallel()
+
+	noop := func(context.Context) error { return nil }
+	l := New(testLogger(t), fxclock.System)
+	l.Append(Hook{Name: "first", OnStart: noop, OnStop: noop})
+	l.Append(Hook{OnStart: noop})
+	l.Append(Hook{
+		Name:    "third",
+		OnStart: noop,
+		OnStop:  func(context.Context) error { return errors.New("stop") },
+	})
+	l.Append(Hook{
+		Name:    "fourth",
+		OnStart: func(context.Context) error { return errors.New("start") },
+		OnStop:  noop,
+	})
+
+	name, caller := l.FailedStart()
+	assert.Empty(t, name)
+	assert.Empty(t, caller)
+
+	require.Error(t, l.Start(context.Background()))
+	name, caller = l.FailedStart()
+	assert.Equal(t, "fourth", name)
+	assert.Equal(t, "testing.tRunner", caller)
+
+	require.Error(t, l.Stop(context.Background()))
+	ran, failed := l.StoppedHooks()
+	assert.Equal(t, []string{"third", "first"}, ran)
+	assert.Equal(t, []string{"third"}, failed)
+}
+
+func TestLifecycleFailures(t *testing.T) {
+	t.Parallel()
+
+	startErr := errors.New("start")
+	stopErr := errors.New("stop")
+
+	l := New(testLogger(t), fxclock.System)
+	l.Append(Hook{
+		Name:    "stopper",
+		OnStop:  func(context.Context) error { return stopErr },
+		OnStart: func(context.Context) error { return nil },
+	})
+	l.Append(Hook{
+		OnStart: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		Timeout: time.Millisecond,
+	})
+	l.Append(Hook{OnStart: func(context.Context) error { return startErr }})
+	assert.Empty(t, l.TakeFailures())
+
+	err := l.Start(context.Background())
+	require.Error(t, err)
+	var te *timeoutError
+	assert.True(t, errors.As(err, &te), "Start must return the hook's error unchanged")
+	require.ErrorIs(t, l.Stop(context.Background()), stopErr)
+
+	failures := l.TakeFailures()
+	require.Len(t, failures, 2)
+
+	var timeoutErr *TimeoutError
+	require.ErrorAs(t, failures[0], &timeoutErr)
+	assert.Equal(t, "OnStart", timeoutErr.Hook)
+	assert.Equal(t, time.Millisecond, timeoutErr.Timeout)
+	assert.ErrorIs(t, timeoutErr, context.DeadlineExceeded)
+
+	var hookErr *HookError
+	require.ErrorAs(t, failures[1], &hookErr)
+	assert.Equal(t, HookError{
+		Hook:     "OnStop",
+		Function: "stopper",
+		Caller:   timeoutErr.Caller,
+		Err:      stopErr,
+	}, *hookErr)
+
+	assert.Empty(t, l.TakeFailures(), "failures must be forgotten once taken")
+}
@@ -22,8 +22,10 @@ package fx
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 
+	"go.uber.org/dig"
 	"go.uber.org/fx/internal/fxreflect"
 )
 
@@ -90,7 +92,102 @@ func (o invokeOption) String() string {
 	return fmt.Sprintf("fx.Invoke(%s)", strings.Join(items, ", "))
 }
 
-func runInvoke(c container, i invoke) error {
+// InvokeInto is like [Invoke], but rather than discarding the values
+// returned by the invoked function, it sets targets to them.
+// Each target must be a pointer that the corresponding return value,
+// other than a final error, can be assigned to.
+// Targets are left unchanged if the function returns an error.
+//
+// This is the counterpart of [Populate] for functions that assemble
+// an object from values in the container.
+//
+//	var srv *http.Server
+//	fx.InvokeInto(func(h http.Handler, cfg Config) *http.Server {
+//		return &http.Server{Addr: cfg.Addr, Handler: h}
+//	}, &srv)
+//
+// The function may be annotated with [Annotate], with annotations
+// that apply to its parameters.
+func InvokeInto(fn interface{}, targets ...interface{}) Option {
+	target, err := invokeInto(fn, targets)
+	if err != nil {
+		return Error(fmt.Errorf("failed to InvokeInto: %w", err))
+	}
+	return invokeOption{
+		Targets: []interface{}{target},
+		Stack:   fxreflect.CallerStack(1, 0),
+	}
+}
+
+// invokeInto returns a function to invoke in place of fn,
+// which sets targets to the values returned by fn.
+func invokeInto(fn interface{}, targets []interface{}) (interface{}, error) {
+	target := fn
+	switch f := fn.(type) {
+	case annotationError:
+		return nil, f.err
+	case annotated:
+		target = f.Target
+	}
+
+	fv := reflect.ValueOf(target)
+	if fv.Kind() != reflect.Func {
+		return nil, fmt.Errorf("%v is not a function, got %T", fn, fn)
+	}
+	ft := fv.Type()
+
+	results := make([]reflect.Type, 0, ft.NumOut())
+	for i := 0; i < ft.NumOut(); i++ {
+		results = append(results, ft.Out(i))
+	}
+	var outs []reflect.Type
+	if n := len(results); n > 0 && results[n-1] == _typeOfError {
+		results, outs = results[:n-1], []reflect.Type{_typeOfError}
+	}
+	if len(targets) != len(results) {
+		return nil, fmt.Errorf("%v returns %d values, got %d targets",
+			fxreflect.FuncName(target), len(results), len(targets))
+	}
+	for i, t := range targets {
+		rt := reflect.TypeOf(t)
+		if rt == nil || rt.Kind() != reflect.Ptr || reflect.ValueOf(t).IsNil() {
+			return nil, fmt.Errorf("target %v is not a non-nil pointer, got %T", i+1, t)
+		}
+		if !results[i].AssignableTo(rt.Elem()) {
+			return nil, fmt.Errorf("target %v: cannot assign %v to %v", i+1, results[i], rt.Elem())
+		}
+	}
+
+	ins := make([]reflect.Type, ft.NumIn())
+	for i := range ins {
+		ins[i] = ft.In(i)
+	}
+	var wrapped interface{} = reflect.MakeFunc(reflect.FuncOf(ins, outs, ft.IsVariadic()), func(args []reflect.Value) []reflect.Value {
+		values := callFunc(fv, args)
+		if len(outs) > 0 {
+			err := values[len(values)-1]
+			if !err.IsNil() {
+				return []reflect.Value{err}
+			}
+			values = values[:len(values)-1]
+		}
+		for i, v := range values {
+			reflect.ValueOf(targets[i]).Elem().Set(v)
+		}
+		if len(outs) > 0 {
+			return []reflect.Value{_nilError}
+		}
+		return nil
+	}).Interface()
+
+	if ann, ok := fn.(annotated); ok {
+		ann.Target = wrapped
+		wrapped = ann
+	}
+	return wrapped, nil
+}
+
+func runInvoke(c container, i invoke, opts ...dig.InvokeOption) error {
 	fn := i.Target
 	switch fn := fn.(type) {
 	case Option:
@@ -104,8 +201,8 @@ func runInvoke(c container, i invoke) error {
 			return err
 		}
 
-		return c.Invoke(af)
+		return c.Invoke(af, opts...)
 	default:
-		return c.Invoke(fn)
+		return c.Invoke(fn, opts...)
 	}
 }
@@ -22,6 +22,8 @@ package fx
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"go.uber.org/fx/internal/lifecycle"
 )
@@ -43,9 +45,72 @@ type Lifecycle interface {
 // failure short-circuited application startup), its OnStop callback won't be
 // executed.
 type Hook struct {
+	// Name, if set, identifies the hook in events and in [HookInfo],
+	// instead of the names of its OnStart and OnStop functions.
+	Name string
+
 	OnStart func(context.Context) error
 	OnStop  func(context.Context) error
 
+	// OnDrain, if set, runs when the application is stopping,
+	// after BeforeStop functions and before any OnStop callback.
+	// Use it to stop accepting new work while in-flight work completes.
+	// OnDrain callbacks run in the same order as OnStop callbacks,
+	// and only for hooks whose OnStart callback succeeded.
+	//
+	// Draining is bounded by the stop timeout, and by the timeout given to
+	// [DrainTimeout] if the application was shut down with it.
+	// Once the drain timeout elapses, the context given to OnDrain is
+	// canceled, remaining OnDrain callbacks are skipped,
+	// and the OnStop callbacks run.
+	// Errors returned by OnDrain are reported by [App.Stop],
+	// but they don't prevent OnStop callbacks from running.
+	OnDrain func(context.Context) error
+
+	// OnReload, if set, runs when the application is reloaded with
+	// [App.Reload], or by a signal given to [ReloadOnSignal],
+	// while it's running.
+	// OnReload callbacks run in the same order as OnStart callbacks,
+	// and only for hooks whose OnStart callback succeeded.
+	OnReload func(context.Context) error
+
+	// Timeout, if positive, limits how long each of OnStart and OnStop
+	// may run, within the StartTimeout and StopTimeout of the application
+	// and of the module that appended the hook.
+	// The callback's context is canceled once the timeout elapses,
+	// and the callback fails with context.DeadlineExceeded
+	// even if it doesn't return.
+	Timeout time.Duration
+
+	// Retry controls whether OnStart is run again if it fails.
+	// By default, it's run once.
+	Retry RetryPolicy
+
+	// Phase is the name of the lifecycle phase the hook runs in,
+	// as declared with [LifecyclePhases].
+	// Hooks without a phase run before those of every phase.
+	Phase string
+
+	// RunAfter and RunBefore name hooks, by their [Hook.Name],
+	// whose OnStart functions this hook's OnStart runs after and before.
+	// OnStop runs in the reverse order.
+	// Use them for operational ordering requirements that aren't
+	// expressed by dependencies between constructors.
+	//
+	//	lc.Append(fx.Hook{
+	//		Name:     "consumer",
+	//		OnStart:  consumer.Start,
+	//		RunAfter: []string{"db-migrations"},
+	//	})
+	//
+	// Phases still apply: a hook can't run before a hook of an earlier
+	// phase, or after one of a later phase.
+	// Starting the application fails if a constraint names a hook
+	// that wasn't appended, or if the constraints contradict each other
+	// or the phases of the hooks.
+	RunAfter  []string
+	RunBefore []string
+
 	onStartName string
 	onStopName  string
 }
@@ -139,9 +204,404 @@ type lifecycleWrapper struct {
 
 func (l *lifecycleWrapper) Append(h Hook) {
 	l.Lifecycle.Append(lifecycle.Hook{
+		Name:        h.Name,
 		OnStart:     h.OnStart,
 		OnStop:      h.OnStop,
+		OnDrain:     h.OnDrain,
+		OnReload:    h.OnReload,
 		OnStartName: h.onStartName,
 		OnStopName:  h.onStopName,
+		Timeout:     h.Timeout,
+		Attempts:    h.Retry.Attempts,
+		Backoff:     h.Retry.Backoff,
+		Phase:       h.Phase,
+		RunAfter:    h.RunAfter,
+		RunBefore:   h.RunBefore,
 	})
 }
+
+// RetryPolicy specifies how a failing [Hook.OnStart] is retried.
+// For example, the following runs OnStart up to 3 times,
+// waiting 1 second, then 2 seconds between attempts.
+//
+//	lc.Append(fx.Hook{
+//		OnStart: db.Ping,
+//		Retry: fx.RetryPolicy{
+//			Attempts: 3,
+//			Backoff: func(retry int) time.Duration {
+//				return time.Duration(retry) * time.Second
+//			},
+//		},
+//	})
+//
+// Retries stop once the context given to OnStart is done.
+// An attempt that exceeds [Hook.Timeout] is not retried,
+// because it may still be running after the timeout elapses.
+// If every attempt fails, the error from the last attempt is reported.
+type RetryPolicy struct {
+	// Attempts is the maximum number of times OnStart is run,
+	// including the first. Values below 2 disable retries.
+	Attempts int
+
+	// Backoff returns how long to wait before the given retry,
+	// starting at 1 for the first retry.
+	// If nil, retries happen immediately.
+	Backoff func(retry int) time.Duration
+}
+
+// HookInfo describes a hook appended to an application's [Lifecycle].
+type HookInfo struct {
+	// Name is the name given to the hook with [Hook.Name], if any.
+	Name string
+
+	// OnStart is the name of the hook's OnStart function,
+	// or empty if the hook has none.
+	OnStart string
+
+	// OnStop is the name of the hook's OnStop function,
+	// or empty if the hook has none.
+	OnStop string
+
+	// Caller is the name of the function that appended the hook.
+	Caller string
+
+	// Phase is the lifecycle phase the hook runs in,
+	// or empty if it has none.
+	Phase string
+
+	// Module is the name of the module whose constructor, decorator,
+	// or invoked function appended the hook.
+	// It is empty for the top-level application,
+	// and for hooks appended outside of those functions.
+	Module string
+
+	// Status is the execution status of the hook.
+	Status HookStatus
+
+	// StartRuntime is how long the hook's OnStart function ran,
+	// including retries, the last time the application was started.
+	// It is zero if OnStart hasn't returned yet.
+	StartRuntime time.Duration
+
+	// StopRuntime is how long the hook's OnStop function ran
+	// the last time the application was stopped.
+	// It is zero if OnStop hasn't returned yet.
+	StopRuntime time.Duration
+}
+
+// HookStatus is the execution status of a hook appended to an
+// application's [Lifecycle].
+type HookStatus int
+
+// Statuses of a hook, from the time it's appended until it's stopped.
+// A hook without an OnStart or OnStop function is considered
+// started or stopped once the application gets to it.
+const (
+	// HookPending is the status of a hook that has not been started.
+	HookPending HookStatus = iota
+
+	// HookStarted is the status of a hook whose OnStart succeeded.
+	HookStarted
+
+	// HookStartFailed is the status of a hook whose OnStart failed.
+	HookStartFailed
+
+	// HookStopped is the status of a hook whose OnStop succeeded.
+	HookStopped
+
+	// HookStopFailed is the status of a hook whose OnStop failed.
+	HookStopFailed
+)
+
+func (s HookStatus) String() string {
+	switch s {
+	case HookPending:
+		return "pending"
+	case HookStarted:
+		return "started"
+	case HookStartFailed:
+		return "start failed"
+	case HookStopped:
+		return "stopped"
+	case HookStopFailed:
+		return "stop failed"
+	default:
+		return fmt.Sprintf("HookStatus(%d)", int(s))
+	}
+}
+
+// hookStatus converts a status reported by the internal lifecycle.
+func hookStatus(s lifecycle.HookStatus) HookStatus {
+	switch s {
+	case lifecycle.HookStarted:
+		return HookStarted
+	case lifecycle.HookStartFailed:
+		return HookStartFailed
+	case lifecycle.HookStopped:
+		return HookStopped
+	case lifecycle.HookStopFailed:
+		return HookStopFailed
+	default:
+		return HookPending
+	}
+}
+
+// LifecycleInspector is a read-only view of the hooks appended to an
+// application's [Lifecycle]. Fx provides one to every application
+// so that diagnostics tooling can list hooks and their status.
+//
+//	fx.Invoke(func(li fx.LifecycleInspector, mux *http.ServeMux) {
+//		mux.HandleFunc("/debug/hooks", func(w http.ResponseWriter, r *http.Request) {
+//			for _, h := range li.Hooks() {
+//				fmt.Fprintf(w, "%v (%v): %v\n", h.Name, h.Caller, h.Status)
+//			}
+//		})
+//	})
+type LifecycleInspector interface {
+	// Hooks returns the hooks appended to the application's Lifecycle
+	// so far, as reported by [App.RegisteredHooks].
+	Hooks() []HookInfo
+}
+
+type lifecycleInspector struct{ app *App }
+
+func (li lifecycleInspector) Hooks() []HookInfo {
+	return li.app.RegisteredHooks()
+}
+
+// RegisteredHooks returns the hooks appended to the application's
+// [Lifecycle] so far, in the order they were appended.
+// This is the order in which their OnStart functions run;
+// OnStop functions run in reverse.
+// With [LifecyclePhases], hooks run grouped by phase instead,
+// and [Hook.RunAfter] and [Hook.RunBefore] reorder hooks within a phase.
+// With [ConcurrentStart], hooks are still listed in the order they were
+// appended, but independent OnStart functions may run at once or in
+// another order, and OnStop functions run in the reverse of the order
+// in which their OnStart functions completed.
+//
+// Use it after [New] and before [App.Start]
+// to verify the hooks registered by a composition of modules,
+// or while the application runs to report the status of each hook.
+// Constructors can get the same information from [LifecycleInspector].
+func (app *App) RegisteredHooks() []HookInfo {
+	hooks := app.lifecycle.Hooks()
+	statuses := app.lifecycle.Statuses()
+	runtimes := app.lifecycle.Runtimes()
+	infos := make([]HookInfo, len(hooks))
+	for i, h := range hooks {
+		infos[i] = HookInfo{
+			Name:    h.Name,
+			OnStart: h.StartName(),
+			OnStop:  h.StopName(),
+			Caller:  h.CallerName(),
+			Phase:   h.Phase,
+		}
+		if i < len(statuses) {
+			infos[i].Status = hookStatus(statuses[i])
+		}
+		if i < len(runtimes) {
+			infos[i].StartRuntime = runtimes[i].Start
+			infos[i].StopRuntime = runtimes[i].Stop
+		}
+		if i < len(app.hookModules) {
+			infos[i].Module = app.hookModules[i]
+		}
+	}
+	return infos
+}
+
+// LifecyclePhases declares named phases that lifecycle hooks may be
+// registered into with [Hook.Phase], in the order they run.
+// OnStart hooks of a phase run only after the OnStart hooks of all earlier
+// phases have completed, regardless of the order in which the hooks were
+// appended. OnStop hooks run in the reverse order.
+// Hooks without a phase run before the hooks of every phase.
+//
+// For example, the following runs database migrations before serving
+// traffic, and announces the server to service discovery only after that.
+//
+//	fx.New(
+//		fx.LifecyclePhases("migrate", "serve", "announce"),
+//		fx.Invoke(func(lc fx.Lifecycle, d *discovery.Client) {
+//			lc.Append(fx.Hook{OnStart: d.Register, Phase: "announce"})
+//		}),
+//		fx.Invoke(func(lc fx.Lifecycle, s *http.Server) {
+//			lc.Append(fx.Hook{OnStart: startServer(s), Phase: "serve"})
+//		}),
+//		fx.Invoke(func(lc fx.Lifecycle, m *Migrator) {
+//			lc.Append(fx.Hook{OnStart: m.Run, Phase: "migrate"})
+//		}),
+//	)
+//
+// Starting the application fails if a hook uses a phase
+// that was not declared.
+func LifecyclePhases(phases ...string) Option {
+	return lifecyclePhasesOption(phases)
+}
+
+type lifecyclePhasesOption []string
+
+func (o lifecyclePhasesOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.LifecyclePhases Option should be passed to top-level App, " +
+			"not to fx.Module")
+		return
+	}
+
+	seen := make(map[string]struct{}, len(m.app.lifecyclePhases)+len(o))
+	for _, phase := range m.app.lifecyclePhases {
+		seen[phase] = struct{}{}
+	}
+	for _, phase := range o {
+		if len(phase) == 0 {
+			m.app.err = fmt.Errorf("fx.LifecyclePhases: phase names must not be empty")
+			return
+		}
+		if _, ok := seen[phase]; ok {
+			m.app.err = fmt.Errorf("fx.LifecyclePhases: phase %q declared more than once", phase)
+			return
+		}
+		seen[phase] = struct{}{}
+	}
+	m.app.lifecyclePhases = append(m.app.lifecyclePhases, o...)
+}
+
+func (o lifecyclePhasesOption) String() string {
+	return fmt.Sprintf("fx.LifecyclePhases(%q)", []string(o))
+}
+
+// StopPanicPolicy specifies what happens when an OnStop hook panics,
+// as set with [OnStopPanic].
+type StopPanicPolicy int
+
+const (
+	// ContinueOnStopPanic runs the remaining OnStop hooks after one panics,
+	// and reports the panic among the errors of [App.Stop].
+	ContinueOnStopPanic StopPanicPolicy = iota + 1
+
+	// AbortOnStopPanic makes [App.Stop] return as soon as an OnStop hook
+	// panics, with the panic as its error,
+	// without running the remaining OnStop hooks.
+	AbortOnStopPanic
+)
+
+// String returns the name of the policy.
+func (p StopPanicPolicy) String() string {
+	switch p {
+	case ContinueOnStopPanic:
+		return "ContinueOnStopPanic"
+	case AbortOnStopPanic:
+		return "AbortOnStopPanic"
+	default:
+		return fmt.Sprintf("StopPanicPolicy(%d)", int(p))
+	}
+}
+
+// OnStopPanic makes the application recover from panics in OnStop hooks,
+// reporting them as errors of [App.Stop], and sets what happens next.
+// With [ContinueOnStopPanic], a single panicking hook doesn't keep the
+// others from releasing their resources.
+//
+//	fx.New(
+//		fx.OnStopPanic(fx.ContinueOnStopPanic),
+//		...
+//	)
+//
+// The errors describe the value the hook panicked with,
+// and formatting them with "%+v" adds the stack of the panic.
+//
+// Without OnStopPanic, panics in OnStop hooks are not recovered from.
+func OnStopPanic(policy StopPanicPolicy) Option {
+	return onStopPanicOption(policy)
+}
+
+type onStopPanicOption StopPanicPolicy
+
+func (o onStopPanicOption) apply(m *module) {
+	policy := StopPanicPolicy(o)
+	switch {
+	case m.parent != nil:
+		m.app.err = fmt.Errorf("fx.OnStopPanic Option should be passed to top-level App, " +
+			"not to fx.Module")
+	case policy != ContinueOnStopPanic && policy != AbortOnStopPanic:
+		m.app.err = fmt.Errorf("fx.OnStopPanic: unknown policy %v", policy)
+	default:
+		m.app.stopPanicPolicy = policy
+	}
+}
+
+func (o onStopPanicOption) String() string {
+	return fmt.Sprintf("fx.OnStopPanic(%v)", StopPanicPolicy(o))
+}
+
+// RollbackPolicy specifies what [App.Start] does with the hooks that
+// started when an OnStart hook fails, as set with [OnStartFailure].
+type RollbackPolicy int
+
+const (
+	// RollbackStarted runs the OnStop hooks of all hooks that started,
+	// and reports their failures among the errors of [App.Start].
+	// This is the default.
+	RollbackStarted RollbackPolicy = iota + 1
+
+	// RollbackIgnoringErrors runs the OnStop hooks of all hooks that
+	// started, but only logs their failures:
+	// [App.Start] returns the error that caused the rollback.
+	RollbackIgnoringErrors
+
+	// NoRollback leaves the hooks that started running,
+	// along with the health probes and debug server,
+	// so that the application can be inspected.
+	// Call [App.Stop] to stop them.
+	NoRollback
+)
+
+// String returns the name of the policy.
+func (p RollbackPolicy) String() string {
+	switch p {
+	case RollbackStarted:
+		return "RollbackStarted"
+	case RollbackIgnoringErrors:
+		return "RollbackIgnoringErrors"
+	case NoRollback:
+		return "NoRollback"
+	default:
+		return fmt.Sprintf("RollbackPolicy(%d)", int(p))
+	}
+}
+
+// OnStartFailure sets what [App.Start] does with the hooks that started
+// when an OnStart hook fails.
+//
+//	fx.New(
+//		fx.OnStartFailure(fx.NoRollback),
+//		...
+//	)
+//
+// Whatever the policy, the RollingBack and RolledBack events name the hook
+// that failed and the OnStop hooks that were run to roll back.
+//
+// Without OnStartFailure, the policy is [RollbackStarted].
+func OnStartFailure(policy RollbackPolicy) Option {
+	return onStartFailureOption(policy)
+}
+
+type onStartFailureOption RollbackPolicy
+
+func (o onStartFailureOption) apply(m *module) {
+	policy := RollbackPolicy(o)
+	switch {
+	case m.parent != nil:
+		m.app.err = fmt.Errorf("fx.OnStartFailure Option should be passed to top-level App, " +
+			"not to fx.Module")
+	case policy < RollbackStarted || policy > NoRollback:
+		m.app.err = fmt.Errorf("fx.OnStartFailure: unknown policy %v", policy)
+	default:
+		m.app.rollbackPolicy = policy
+	}
+}
+
+func (o onStartFailureOption) String() string {
+	return fmt.Sprintf("fx.OnStartFailure(%v)", RollbackPolicy(o))
+}
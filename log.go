@@ -21,6 +21,8 @@
 package fx
 
 import (
+	"reflect"
+
 	"go.uber.org/fx/fxevent"
 )
 
@@ -29,15 +31,49 @@ import (
 type logBuffer struct {
 	events []fxevent.Event
 	logger fxevent.Logger
+
+	// limit is the maximum estimated size in bytes of the buffered
+	// events. If zero, the buffer is unbounded.
+	limit int
+	size  int   // estimated size of events currently buffered
+	sizes []int // sizes[i] is the estimated size of events[i]
+
+	// dropped is the number of events evicted from the buffer
+	// to stay within the limit.
+	dropped int
 }
 
 // LogEvent buffers or logs an event.
 func (l *logBuffer) LogEvent(event fxevent.Event) {
-	if l.logger == nil {
-		l.events = append(l.events, event)
-	} else {
+	if l.logger != nil {
 		l.logger.LogEvent(event)
+		return
+	}
+
+	if l.limit <= 0 {
+		l.events = append(l.events, event)
+		return
+	}
+
+	size := eventSize(event)
+	if size > l.limit {
+		// The event will never fit. Drop it rather than
+		// evicting everything else.
+		l.dropped++
+		return
 	}
+
+	// Evict the oldest events until the new one fits.
+	for len(l.events) > 0 && l.size+size > l.limit {
+		l.size -= l.sizes[0]
+		l.events[0] = nil // allow GC
+		l.events, l.sizes = l.events[1:], l.sizes[1:]
+		l.dropped++
+	}
+
+	l.events = append(l.events, event)
+	l.sizes = append(l.sizes, size)
+	l.size += size
 }
 
 // Connect flushes out all buffered events to a logger and resets them.
@@ -47,4 +83,33 @@ func (l *logBuffer) Connect(logger fxevent.Logger) {
 		logger.LogEvent(e)
 	}
 	l.events = nil
+	l.sizes = nil
+	l.size = 0
+}
+
+var _stringHeaderSize = int(reflect.TypeOf("").Size())
+
+// eventSize estimates the number of bytes retained by an event:
+// the event struct itself plus the contents of its string fields.
+func eventSize(event fxevent.Event) int {
+	v := reflect.Indirect(reflect.ValueOf(event))
+	size := int(v.Type().Size())
+	if v.Kind() != reflect.Struct {
+		return size
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		switch f := v.Field(i); f.Kind() {
+		case reflect.String:
+			size += f.Len()
+		case reflect.Slice:
+			if f.Type().Elem().Kind() != reflect.String {
+				continue
+			}
+			for j := 0; j < f.Len(); j++ {
+				size += _stringHeaderSize + f.Index(j).Len()
+			}
+		}
+	}
+	return size
 }
@@ -61,6 +61,69 @@ func TestLogBufferLog(t *testing.T) {
 	assert.Equal(t, fxlog.Events{event}, spy.Events())
 }
 
+func TestLogBufferLimit(t *testing.T) {
+	t.Parallel()
+
+	events := []fxevent.Event{
+		&fxevent.Invoking{FunctionName: "foo"},
+		&fxevent.Invoking{FunctionName: "bar"},
+		&fxevent.Invoking{FunctionName: "baz"},
+	}
+
+	t.Run("evicts oldest", func(t *testing.T) {
+		t.Parallel()
+
+		// Room for exactly two events.
+		lb := &logBuffer{limit: 2 * eventSize(events[0])}
+		for _, e := range events {
+			lb.LogEvent(e)
+		}
+		assert.Equal(t, 1, lb.dropped)
+
+		spy := new(fxlog.Spy)
+		lb.Connect(spy)
+		assert.Equal(t, fxlog.Events{events[1], events[2]}, spy.Events())
+	})
+
+	t.Run("drops events larger than limit", func(t *testing.T) {
+		t.Parallel()
+
+		lb := &logBuffer{limit: eventSize(events[0])}
+		lb.LogEvent(events[0])
+		lb.LogEvent(&fxevent.Invoking{FunctionName: "a much longer function name"})
+		assert.Equal(t, 1, lb.dropped)
+
+		spy := new(fxlog.Spy)
+		lb.Connect(spy)
+		assert.Equal(t, fxlog.Events{events[0]}, spy.Events())
+	})
+
+	t.Run("does not drop after connect", func(t *testing.T) {
+		t.Parallel()
+
+		spy := new(fxlog.Spy)
+		lb := &logBuffer{limit: 1}
+		lb.Connect(spy)
+		for _, e := range events {
+			lb.LogEvent(e)
+		}
+		assert.Zero(t, lb.dropped)
+		assert.Equal(t, fxlog.Events(events), spy.Events())
+	})
+}
+
+func TestEventSize(t *testing.T) {
+	t.Parallel()
+
+	short := eventSize(&fxevent.Provided{ConstructorName: "a"})
+	long := eventSize(&fxevent.Provided{
+		ConstructorName: "a",
+		StackTrace:      []string{"foo", "bar"},
+	})
+	assert.Greater(t, short, 0)
+	assert.Greater(t, long, short+len("foobar"))
+}
+
 func TestWithLoggerDecorate(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,214 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.uber.org/dig"
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// MapResult applies a pure transformation to every value of the type
+// pointed to by target as it is provided, in any module.
+// The mapper must be a function that accepts and returns that type.
+//
+// For example, the following fills in defaults for every *Config
+// that any constructor provides:
+//
+//	fx.MapResult(new(*Config), func(c *Config) *Config {
+//		if c.Timeout == 0 {
+//			c.Timeout = time.Second
+//		}
+//		return c
+//	})
+//
+// Mappers apply to values of exactly that type returned by constructors
+// or supplied with [Supply], including named values, values in value
+// groups, and fields of [Out] structs.
+// They do not apply to values provided as a different type with [As].
+//
+// Unlike [Decorate], which applies to values consumed within a module,
+// a mapper transforms the value once, when it is constructed.
+// Mappers therefore run before any decorator sees the value.
+// When several mappers target the same type,
+// they are applied in the order they were given.
+//
+// MapResult may only be passed to the top-level App.
+func MapResult(target interface{}, mapper interface{}) Option {
+	return mapResultOption{
+		Target: target,
+		Mapper: mapper,
+		Stack:  fxreflect.CallerStack(1, 0),
+	}
+}
+
+type mapResultOption struct {
+	Target interface{}
+	Mapper interface{}
+	Stack  fxreflect.Stack
+}
+
+func (o mapResultOption) apply(m *module) {
+	if m.rejectInTrial("fx.MapResult") {
+		return
+	}
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.MapResult Option should be passed to top-level App, " +
+			"not to fx.Module")
+		return
+	}
+
+	typ := reflect.TypeOf(o.Target)
+	if typ == nil || typ.Kind() != reflect.Ptr {
+		m.app.err = fmt.Errorf("fx.MapResult: target must be a pointer to a type, got %T", o.Target)
+		return
+	}
+	typ = typ.Elem()
+
+	want := reflect.FuncOf([]reflect.Type{typ}, []reflect.Type{typ}, false)
+	mt := reflect.TypeOf(o.Mapper)
+	if mt == nil || !mt.AssignableTo(want) {
+		m.app.err = fmt.Errorf("fx.MapResult(%v): mapper must be a %v, got %T from:\n%+v",
+			typ, want, o.Mapper, o.Stack)
+		return
+	}
+
+	m.app.resultMappers = append(m.app.resultMappers, resultMapper{
+		Type: typ,
+		Func: reflect.ValueOf(o.Mapper).Convert(want),
+	})
+}
+
+func (o mapResultOption) String() string {
+	typ := reflect.TypeOf(o.Target)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return fmt.Sprintf("fx.MapResult(%v, %v)", typ, fxreflect.FuncName(o.Mapper))
+}
+
+// resultMapper is a function registered with MapResult.
+type resultMapper struct {
+	Type reflect.Type
+	Func reflect.Value // func(Type) Type
+}
+
+// providerContainer returns the container that constructors provided to
+// c with the given target are registered with.
+// export reports whether their values are exported to the top level,
+// and raw records the values they build before decoration, if set.
+func (app *App) providerContainer(c container, target interface{}, export bool, raw *instanceTracker) container {
+	c = app.transients.container(c)
+	c = app.priorities.container(c, export)
+	c = raw.container(c, target)
+	c = app.instances.container(c, target)
+	return app.mapResults(c)
+}
+
+// mapResults returns a container that applies the application's result
+// mappers to values built by constructors provided to c.
+func (app *App) mapResults(c container) container {
+	if len(app.resultMappers) == 0 {
+		return c
+	}
+	return mappingContainer{container: c, mappers: app.resultMappers}
+}
+
+// mappingContainer wraps constructors provided to it so that their
+// results are transformed by result mappers.
+type mappingContainer struct {
+	container
+
+	mappers []resultMapper
+}
+
+func (c mappingContainer) Provide(ctor interface{}, opts ...dig.ProvideOption) error {
+	fv := reflect.ValueOf(ctor)
+	if fv.Kind() != reflect.Func || !c.produces(fv.Type()) {
+		return c.container.Provide(ctor, opts...)
+	}
+
+	ft := fv.Type()
+	return provideWrapper(c.container, fv, ft, func(args []reflect.Value) []reflect.Value {
+		results := callFunc(fv, args)
+		if n := len(results); n > 0 && ft.Out(n-1) == _typeOfError && !results[n-1].IsNil() {
+			return results
+		}
+		for i, v := range results {
+			results[i] = c.mapValue(v)
+		}
+		return results
+	}, opts)
+}
+
+// produces reports whether a constructor of type ft returns a value that
+// a mapper applies to.
+func (c mappingContainer) produces(ft reflect.Type) bool {
+	for i := 0; i < ft.NumOut(); i++ {
+		if c.applies(ft.Out(i)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c mappingContainer) applies(t reflect.Type) bool {
+	if dig.IsOut(t) {
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); len(f.PkgPath) == 0 && c.applies(f.Type) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, m := range c.mappers {
+		if m.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// mapValue applies the mappers for v's type to v,
+// or to the fields of v if it is an fx.Out struct.
+func (c mappingContainer) mapValue(v reflect.Value) reflect.Value {
+	typ := v.Type()
+	if dig.IsOut(typ) {
+		out := reflect.New(typ).Elem()
+		out.Set(v)
+		for i := 0; i < typ.NumField(); i++ {
+			if f := typ.Field(i); len(f.PkgPath) == 0 && f.Type != _typeOfOut {
+				out.Field(i).Set(c.mapValue(out.Field(i)))
+			}
+		}
+		return out
+	}
+
+	for _, m := range c.mappers {
+		if m.Type == typ {
+			v = m.Func.Call([]reflect.Value{v})[0]
+		}
+	}
+	return v
+}
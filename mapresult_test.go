@@ -0,0 +1,170 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestMapResult(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Name    string
+		Timeout int
+	}
+
+	withDefaults := func(c *config) *config {
+		if c.Timeout == 0 {
+			c.Timeout = 5
+		}
+		return c
+	}
+
+	t.Run("consumers see mapped value", func(t *testing.T) {
+		t.Parallel()
+
+		var got *config
+		app := fxtest.New(t,
+			MapResult(new(*config), withDefaults),
+			Provide(func() *config { return &config{Name: "foo"} }),
+			Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, &config{Name: "foo", Timeout: 5}, got)
+	})
+
+	t.Run("applies in every module", func(t *testing.T) {
+		t.Parallel()
+
+		type result struct {
+			Out
+
+			Named   *config `name:"named"`
+			Grouped *config `group:"configs"`
+		}
+		type params struct {
+			In
+
+			Private *config
+			Named   *config   `name:"named"`
+			Grouped []*config `group:"configs"`
+		}
+
+		app := fxtest.New(t,
+			MapResult(new(*config), withDefaults),
+			Provide(func() result {
+				return result{Named: &config{Name: "named"}, Grouped: &config{Name: "grouped"}}
+			}),
+			Module("child",
+				Supply(&config{Name: "private"}, Private),
+				Invoke(func(p params) {
+					assert.Equal(t, 5, p.Private.Timeout)
+					assert.Equal(t, 5, p.Named.Timeout)
+					require.Len(t, p.Grouped, 1)
+					assert.Equal(t, 5, p.Grouped[0].Timeout)
+				}),
+			),
+		)
+		defer app.RequireStart().RequireStop()
+	})
+
+	t.Run("runs before decorators", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		var got *config
+		app := fxtest.New(t,
+			MapResult(new(*config), func(c *config) *config {
+				calls = append(calls, "map first")
+				c.Name += "-first"
+				return c
+			}),
+			MapResult(new(*config), func(c *config) *config {
+				calls = append(calls, "map second")
+				c.Name += "-second"
+				return c
+			}),
+			Provide(func() *config { return &config{Name: "foo"} }),
+			Decorate(func(c *config) *config {
+				calls = append(calls, "decorate")
+				return &config{Name: c.Name + "-decorated"}
+			}),
+			Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, []string{"map first", "map second", "decorate"}, calls)
+		assert.Equal(t, "foo-first-second-decorated", got.Name)
+	})
+
+	t.Run("other types untouched", func(t *testing.T) {
+		t.Parallel()
+
+		var got string
+		app := fxtest.New(t,
+			MapResult(new(*config), func(c *config) *config {
+				t.Error("mapper must not run")
+				return c
+			}),
+			Provide(func() string { return "foo" }),
+			Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "foo", got)
+	})
+
+	t.Run("invalid mapper", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, MapResult(new(*config), func(c config) config { return c }))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			"fx.MapResult(*fx_test.config): mapper must be a func(*fx_test.config) *fx_test.config")
+	})
+
+	t.Run("target not a pointer", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, MapResult(config{}, withDefaults))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.MapResult: target must be a pointer to a type")
+	})
+
+	t.Run("in module", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, Module("child", MapResult(new(*config), withDefaults)))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			"fx.MapResult Option should be passed to top-level App, not to fx.Module")
+	})
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MermaidGraph contains a Mermaid flowchart of the dependency graph in an
+// Fx application. It is provided in the container by default alongside
+// [DotGraph], and it can be embedded in Markdown documents that support
+// Mermaid diagrams without installing Graphviz.
+//
+// Modules are rendered as subgraphs of the functions passed to them.
+// Constructors are rendered as rectangles, functions passed to [Decorate]
+// and [Replace] as hexagons, and functions passed to [Invoke] as stadiums.
+// Edges go from the function that provides or decorates a value to the
+// functions that depend on it, labeled with the type of the value.
+type MermaidGraph string
+
+// writeMermaidGraph renders g as a Mermaid flowchart.
+func writeMermaidGraph(w io.Writer, g Graph) {
+	io.WriteString(w, "flowchart LR\n")
+	mw := mermaidWriter{w: w}
+	mw.writeModuleContents(g.Root, "\t")
+	for _, e := range g.Edges {
+		fmt.Fprintf(w, "\tn%d -->|\"%v\"| n%d\n", e.To, mermaidEscape(mermaidValue(e.Value)), e.From)
+	}
+}
+
+type mermaidWriter struct {
+	w       io.Writer
+	modules int
+}
+
+func (mw *mermaidWriter) writeModuleContents(gm GraphModule, indent string) {
+	for _, n := range gm.Nodes {
+		name := mermaidEscape(n.Name)
+		switch n.Kind {
+		case "decorate", "replace":
+			fmt.Fprintf(mw.w, "%vn%d{{\"%v\"}}\n", indent, n.ID, name)
+		case "invoke":
+			fmt.Fprintf(mw.w, "%vn%d([\"%v\"])\n", indent, n.ID, name)
+		default:
+			fmt.Fprintf(mw.w, "%vn%d[\"%v\"]\n", indent, n.ID, name)
+		}
+	}
+	for _, mod := range gm.Modules {
+		fmt.Fprintf(mw.w, "%vsubgraph module_%d [\"%v\"]\n", indent, mw.modules, mermaidEscape(mod.Name))
+		mw.modules++
+		mw.writeModuleContents(mod, indent+"\t")
+		fmt.Fprintf(mw.w, "%vend\n", indent)
+	}
+}
+
+// mermaidValue describes a value in the label of an edge.
+func mermaidValue(v GraphValue) string {
+	switch {
+	case len(v.Name) > 0:
+		return fmt.Sprintf("%v name:%v", v.Type, v.Name)
+	case len(v.Group) > 0:
+		return fmt.Sprintf("%v group:%v", v.Type, v.Group)
+	default:
+		return v.Type
+	}
+}
+
+var _mermaidEscaper = strings.NewReplacer(
+	`"`, "#quot;",
+	"<", "#lt;",
+	">", "#gt;",
+)
+
+// mermaidEscape escapes characters that may not appear
+// in quoted Mermaid labels.
+func mermaidEscape(s string) string {
+	return _mermaidEscaper.Replace(s)
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMermaidGraph(t *testing.T) {
+	t.Parallel()
+
+	type config struct{}
+	type server struct{}
+
+	var g MermaidGraph
+	app := New(
+		NopLogger,
+		Provide(func() *config { return &config{} }),
+		Module("server",
+			Provide(Annotate(func(*config) *server { return &server{} }, ResultTags(`name:"http"`))),
+			Decorate(func(c *config) *config { return c }),
+		),
+		Invoke(Annotate(func(*server) {}, ParamTags(`name:"http"`))),
+		Populate(&g),
+	)
+	require.NoError(t, app.Err())
+
+	for _, want := range []string{
+		"flowchart LR\n",
+		`n3["go.uber.org/fx.TestMermaidGraph.func1()"]`,
+		"\tsubgraph module_0 [\"server\"]\n" +
+			"\t\tn5[\"fx.Annotate(go.uber.org/fx.TestMermaidGraph.func2(), fx.ResultTags([#quot;name:\\#quot;http\\#quot;#quot;])\"]\n" +
+			"\t\tn6{{\"go.uber.org/fx.TestMermaidGraph.func3()\"}}\n" +
+			"\tend\n",
+		`n4(["fx.Annotate(go.uber.org/fx.TestMermaidGraph.func4(), fx.ParamTags([#quot;name:\#quot;http\#quot;#quot;])"])`,
+		`n5 -->|"*fx.server name:http"| n4`,
+		`n3 -->|"*fx.config"| n5`,
+		`n6 -->|"*fx.config"| n5`,
+	} {
+		assert.Contains(t, string(g), want)
+	}
+}
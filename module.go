@@ -22,10 +22,14 @@ package fx
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
+	"time"
 
 	"go.uber.org/dig"
 	"go.uber.org/fx/fxevent"
 	"go.uber.org/fx/internal/fxreflect"
+	"go.uber.org/fx/internal/lifecycle"
 	"go.uber.org/multierr"
 )
 
@@ -112,11 +116,51 @@ func (o moduleOption) apply(mod *module) {
 		app:    mod.app,
 	}
 	for _, opt := range o.options {
-		opt.apply(newModule)
+		newModule.applyOption(opt)
 	}
 	mod.modules = append(mod.modules, newModule)
 }
 
+// applyOption applies opt to m, recording it in App.AppliedOptions.
+// Options, modules, and included conditional options are recorded as the
+// options they contain. Skipped conditional options are not recorded.
+func (m *module) applyOption(opt Option) {
+	switch o := opt.(type) {
+	case optionGroup:
+		for _, opt := range o {
+			m.applyOption(opt)
+		}
+		return
+	case *conditionalOption:
+		if o.Enabled() {
+			for _, opt := range o.opts {
+				m.applyOption(opt)
+			}
+		}
+		return
+	case moduleOption, subAppOf:
+	default:
+		if m.trialRoot() == nil {
+			m.app.appliedOptions = append(m.app.appliedOptions, m.optionPrefix()+fmt.Sprint(opt))
+		}
+	}
+	opt.apply(m)
+}
+
+// optionPrefix returns the prefix of options applied to m
+// in App.AppliedOptions: the path of the module followed by ": ",
+// or an empty string for the top-level application.
+func (m *module) optionPrefix() string {
+	var names []string
+	for mod := m; mod.parent != nil; mod = mod.parent {
+		names = append([]string{mod.name}, names...)
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return strings.Join(names, "/") + ": "
+}
+
 type module struct {
 	parent         *module
 	name           string
@@ -124,12 +168,226 @@ type module struct {
 	scope          scope
 	provides       []provide
 	invokes        []invoke
+	evaluates      []evaluate
 	decorators     []decorator
 	modules        []*module
 	app            *App
 	log            fxevent.Logger
 	fallbackLogger fxevent.Logger
 	logConstructor *provide
+
+	// Value groups that constructors provided to this module contribute to.
+	groups []groupContribution
+
+	// Annotations applied to every constructor provided to this module
+	// and its descendants.
+	defaultAnnotations []Annotation
+
+	// Types provided to the scope of this module.
+	provided []providedOutput
+
+	// Values built by constructors provided to the scope of this module,
+	// before decoration. Recorded only if a module uses Undecorate.
+	rawValues *instanceTracker
+
+	// Where each type provided to this module was provided, by type name.
+	// Recorded only with WarnAmbiguousDecorations.
+	providedAt map[string]fxreflect.Stack
+
+	// Functions registered with the container of this module,
+	// for the application's DotGraph.
+	graphNodes []graphNode
+
+	// Metadata passed to the module with ModuleInfo.
+	info    ModuleInfo
+	hasInfo bool
+
+	// Error handlers registered with ErrorHook in this module.
+	errorHooks []ErrorHandler
+
+	// Bounds the time taken by the hooks of this module,
+	// if it was given StartTimeout or StopTimeout.
+	hookBudget *lifecycle.Budget
+
+	// Set for the modules created by App.Try and App.NewScope
+	// to the name of the method. Values exported from
+	// within them stay in their scope instead of reaching the root.
+	trial string
+}
+
+// trialRoot returns the closest ancestor of m (including m) created by
+// App.Try or App.NewScope, or nil if m is not part of one.
+func (m *module) trialRoot() *module {
+	for mod := m; mod != nil; mod = mod.parent {
+		if len(mod.trial) > 0 {
+			return mod
+		}
+	}
+	return nil
+}
+
+// exportRoot returns the module that values exported from m are provided
+// to instead of the root of the container, or nil if they reach the root:
+// the closest ancestor of m created by App.Try or App.NewScope,
+// or the top-level module of an application that shares the container
+// of its parent.
+func (m *module) exportRoot() *module {
+	if t := m.trialRoot(); t != nil {
+		return t
+	}
+	if m.app.sharedContainer {
+		return m.app.root
+	}
+	return nil
+}
+
+// valueGroup identifies a value group by its name and element type.
+type valueGroup struct {
+	Name string
+	Type reflect.Type
+}
+
+// groupContribution records that a constructor contributes to a value group.
+type groupContribution struct {
+	valueGroup
+
+	Private bool
+}
+
+// outputKey identifies a value provided by a constructor:
+// its type, and the name or value group it's provided with, if any.
+type outputKey struct {
+	Type  reflect.Type
+	Name  string
+	Group string
+}
+
+// String renders the key the way dig renders outputs.
+func (k outputKey) String() string {
+	switch {
+	case len(k.Name) > 0:
+		return fmt.Sprintf("%v[name = %q]", k.Type, k.Name)
+	case len(k.Group) > 0:
+		return fmt.Sprintf("%v[group = %q]", k.Type, k.Group)
+	default:
+		return k.Type.String()
+	}
+}
+
+// constructorOutputs returns the values provided by the given target of
+// fx.Provide or fx.Supply, as declared by its result types, their tags,
+// and its annotations.
+func constructorOutputs(target interface{}) []outputKey {
+	var name, group string
+	switch t := target.(type) {
+	case annotated:
+		ctor, err := t.Build()
+		if err != nil {
+			return nil
+		}
+		target = ctor
+	case Annotated:
+		name, group, target = t.Name, t.Group, t.Target
+	}
+
+	ft := reflect.TypeOf(target)
+	if ft == nil || ft.Kind() != reflect.Func {
+		return nil
+	}
+	cleanup := cleanupResult(ft)
+	var keys []outputKey
+	for i := 0; i < ft.NumOut(); i++ {
+		t := ft.Out(i)
+		switch {
+		case i == cleanup, t == _typeOfError:
+		case isOut(t):
+			keys = appendOutFields(keys, t)
+		default:
+			keys = append(keys, outputKey{Type: t, Name: name, Group: group})
+		}
+	}
+	return keys
+}
+
+// appendOutFields appends the values provided by the fields of the
+// fx.Out struct t to keys.
+func appendOutFields(keys []outputKey, t reflect.Type) []outputKey {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		switch {
+		case f.Type == _typeOfOut:
+		case isOut(f.Type):
+			keys = appendOutFields(keys, f.Type)
+		case len(f.PkgPath) > 0:
+			// Unexported fields are not provided.
+		default:
+			key := outputKey{Type: f.Type, Name: f.Tag.Get("name")}
+			if tag := f.Tag.Get("group"); len(tag) > 0 {
+				opts := strings.Split(tag, ",")
+				key.Group = opts[0]
+				for _, opt := range opts[1:] {
+					if opt == "flatten" && f.Type.Kind() == reflect.Slice {
+						key.Type = f.Type.Elem()
+					}
+				}
+			}
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (m *module) recordGroups(outputs []outputKey, private bool) {
+	for _, o := range outputs {
+		if len(o.Group) > 0 {
+			g := valueGroup{Name: o.Group, Type: o.Type}
+			m.groups = append(m.groups, groupContribution{valueGroup: g, Private: private})
+		}
+	}
+}
+
+// groupPresent reports whether a constructor visible from this module
+// contributes to the value group with the given name and element type.
+//
+// Exported constructors are visible everywhere in the application,
+// while private ones are only visible to the module they were provided to
+// and its descendants.
+func (m *module) groupPresent(name string, t reflect.Type) bool {
+	want := valueGroup{Name: name, Type: t}
+	for mod := m; mod != nil; mod = mod.parent {
+		for _, g := range mod.groups {
+			if g.valueGroup == want {
+				return true
+			}
+		}
+	}
+	return m.app.root.exportsGroup(want)
+}
+
+// exportsGroup reports whether a non-private constructor in this module
+// or any of its descendants contributes to the given value group.
+func (m *module) exportsGroup(want valueGroup) bool {
+	for _, g := range m.groups {
+		if !g.Private && g.valueGroup == want {
+			return true
+		}
+	}
+	for _, mod := range m.modules {
+		if mod.exportsGroup(want) {
+			return true
+		}
+	}
+	return false
+}
+
+// bindAnnotated binds annotated functions to this module so that
+// annotations depending on the module's scope can inspect it.
+func (m *module) bindAnnotated(target interface{}) interface{} {
+	if ann, ok := target.(annotated); ok {
+		ann.groupPresent = m.groupPresent
+		return ann
+	}
+	return target
 }
 
 // scope is a private wrapper interface for dig.Container and dig.Scope.
@@ -147,10 +405,16 @@ type scope interface {
 // after applyModules' are called because the App's Container needs to
 // be built for any Scopes to be initialized, and applys' should be called
 // before the Container can get initialized.
-func (m *module) build(app *App, root *dig.Container) {
-	if m.parent == nil {
+func (m *module) build(app *App, root scope) {
+	switch {
+	case m.parent == nil:
 		m.scope = root
-	} else {
+	case len(m.trial) > 0:
+		// root is the container of the trial, which holds the values
+		// of the application: the trial's own may shadow them.
+		m.scope = root.Scope(m.name)
+		m.log = m.parent.log
+	default:
 		parentScope := m.parent.scope
 		m.scope = parentScope.Scope(m.name)
 		// use parent module's logger by default
@@ -162,7 +426,7 @@ func (m *module) build(app *App, root *dig.Container) {
 		// to hold all messages until user supplied logger is
 		// instantiated. Then we flush those messages after fully
 		// constructing the custom logger.
-		m.fallbackLogger, m.log = m.log, new(logBuffer)
+		m.fallbackLogger, m.log = m.log, &logBuffer{limit: app.eventBufferLimit}
 	}
 
 	for _, mod := range m.modules {
@@ -170,6 +434,33 @@ func (m *module) build(app *App, root *dig.Container) {
 	}
 }
 
+// checkEmptyModules returns an error for every module under m
+// that contributes nothing to the application.
+func (m *module) checkEmptyModules() error {
+	var err error
+	for _, mod := range m.modules {
+		if mod.empty() {
+			err = multierr.Append(err, fmt.Errorf(
+				"fx.NoEmptyModules: module %q is empty: "+
+					"it has no provides, invokes, decorators, or child modules "+
+					"(declared at %v)",
+				mod.name, mod.trace[0]))
+		}
+		err = multierr.Append(err, mod.checkEmptyModules())
+	}
+	return err
+}
+
+// empty reports whether m contributes nothing to the application.
+func (m *module) empty() bool {
+	return len(m.provides) == 0 &&
+		len(m.invokes) == 0 &&
+		len(m.evaluates) == 0 &&
+		len(m.decorators) == 0 &&
+		len(m.modules) == 0 &&
+		m.logConstructor == nil
+}
+
 func (m *module) provideAll() {
 	for _, p := range m.provides {
 		m.provide(p)
@@ -181,7 +472,7 @@ func (m *module) provideAll() {
 }
 
 func (m *module) provide(p provide) {
-	if m.app.err != nil {
+	if m.app.stopped() {
 		return
 	}
 
@@ -190,63 +481,163 @@ func (m *module) provide(p provide) {
 		return
 	}
 
+	// Constructors exported from within a trial are provided to the
+	// trial's scope so that they don't outlive it.
+	owner, export := m, !p.Private
+	if t := m.exportRoot(); t != nil && export {
+		owner, export = t, false
+	}
+
 	funcName := fxreflect.FuncName(p.Target)
-	var info dig.ProvideInfo
+	kind := "provide"
+	if p.IsDerived {
+		kind = "derive"
+	}
+	var (
+		info       dig.ProvideInfo
+		runtime    time.Duration
+		panicStack []byte
+		node       int // index of the constructor in m.graphNodes
+		outputs    []outputKey
+	)
 	opts := []dig.ProvideOption{
 		dig.FillProvideInfo(&info),
-		dig.Export(!p.Private),
+		dig.Export(export),
 		dig.WithProviderCallback(func(ci dig.CallbackInfo) {
+			m.claimHooks(node)
+			m.app.analysis.recordConstructor(funcName)
+			if ci.Error == nil {
+				m.app.recordBuilt(outputs)
+			}
+			m.graphNodes[node].Ran = true
+			m.graphNodes[node].Runtime = runtime
+			m.recordPlanStep(kind, funcName, p.Target)
 			m.log.LogEvent(&fxevent.Run{
-				Name:       funcName,
-				Kind:       "provide",
-				ModuleName: m.name,
-				Err:        ci.Error,
+				Name:        funcName,
+				Kind:        kind,
+				ModuleName:  m.name,
+				ModuleOwner: m.owner(),
+				Runtime:     runtime,
+				Err:         ci.Error,
 			})
+			if pe, ok := ci.Error.(dig.PanicError); ok {
+				m.recoverPanic("provide", funcName, pe, panicStack)
+			}
 		}),
 	}
 
-	if err := runProvide(m.scope, p, opts...); err != nil {
-		m.app.err = err
+	var transientType reflect.Type
+	switch {
+	case p.Transient:
+		transientType = reflect.TypeOf(p.Target).Out(0)
+		opts = append(opts,
+			dig.Name(_transientName),
+			dig.LocationForPC(reflect.ValueOf(p.Target).Pointer()),
+		)
+		p.Target = m.app.transients.provider(p.Target)
+	case p.IsProvided:
+		p.Target = m.withDefaultAnnotations(p.Target)
 	}
+	outputs = constructorOutputs(p.Target)
+	if transientType != nil {
+		for i := range outputs {
+			outputs[i].Name = _transientName
+		}
+	}
+	p.Target = m.app.priorities.annotateGroup(p.Target)
+	p.Target = m.bindAnnotated(p.Target)
+	c := m.app.instrumentConstructor(m.app.providerContainer(owner.scope, p.Target, export, owner.rawValueTracker(export)), funcName, &runtime, &panicStack)
+	provideErr := runProvide(c, p, opts...)
+	if provideErr != nil {
+		m.app.recordError(provideErr, &ProvideError{Constructor: funcName, Module: m.path(), Err: provideErr}, m)
+	}
+	owner.recordGroups(outputs, p.Private)
+	owner.recordProvidedOutputs(outputs, p.Stack, export)
 	outputNames := make([]string, len(info.Outputs))
 	for i, o := range info.Outputs {
-		outputNames[i] = o.String()
+		outputNames[i] = m.app.priorities.outputName(o.String())
+	}
+	if transientType != nil {
+		// Report the type rather than the factory provided for it.
+		outputNames = []string{transientType.String()}
 	}
+	m.app.analysis.recordProvided(outputNames)
+	m.recordProvidedAt(outputNames, p.Stack)
+	node = len(m.graphNodes)
+	m.recordGraphNode(graphNode{
+		Kind:     kind,
+		Name:     funcName,
+		Private:  p.Private,
+		Inputs:   inputGraphValues(info.Inputs),
+		Outputs:  outputGraphValues(info.Outputs),
+		Location: targetLocation(p.Target),
+	})
 
 	m.log.LogEvent(&fxevent.Provided{
 		ConstructorName: funcName,
 		StackTrace:      p.Stack.Strings(),
 		ModuleTrace:     append([]string{p.Stack[0].String()}, m.trace...),
 		ModuleName:      m.name,
+		ModuleOwner:     m.owner(),
 		OutputTypeNames: outputNames,
-		Err:             m.app.err,
+		Err:             provideErr,
 		Private:         p.Private,
+		Derived:         p.IsDerived,
 	})
 }
 
 func (m *module) supply(p provide) {
+	owner, export := m, !p.Private
+	if t := m.exportRoot(); t != nil && export {
+		owner, export = t, false
+	}
+
 	typeName := p.SupplyType.String()
+	var info dig.ProvideInfo
+	outputs := constructorOutputs(p.Target)
 	opts := []dig.ProvideOption{
-		dig.Export(!p.Private),
+		dig.FillProvideInfo(&info),
+		dig.Export(export),
 		dig.WithProviderCallback(func(ci dig.CallbackInfo) {
+			m.app.recordBuilt(outputs)
+			m.recordPlanStep("supply", fmt.Sprintf("fx.Supply(%v)", typeName), nil)
 			m.log.LogEvent(&fxevent.Run{
-				Name:       fmt.Sprintf("stub(%v)", typeName),
-				Kind:       "supply",
-				ModuleName: m.name,
+				Name:        fmt.Sprintf("stub(%v)", typeName),
+				Kind:        "supply",
+				ModuleName:  m.name,
+				ModuleOwner: m.owner(),
 			})
 		}),
 	}
 
-	if err := runProvide(m.scope, p, opts...); err != nil {
-		m.app.err = err
+	p.Target = m.app.priorities.annotateGroup(p.Target)
+	c := m.app.providerContainer(owner.scope, p.Target, export, owner.rawValueTracker(export))
+	provideErr := runProvide(c, p, opts...)
+	if provideErr != nil {
+		m.app.recordError(provideErr, &ProvideError{
+			Constructor: fmt.Sprintf("fx.Supply(%v)", typeName),
+			Module:      m.path(),
+			Err:         provideErr,
+		}, m)
 	}
+	owner.recordGroups(outputs, p.Private)
+	owner.recordProvidedOutputs(outputs, p.Stack, export)
+	m.app.analysis.recordProvided([]string{typeName})
+	m.recordProvidedAt([]string{typeName}, p.Stack)
+	m.recordGraphNode(graphNode{
+		Kind:    "supply",
+		Name:    fmt.Sprintf("fx.Supply(%v)", typeName),
+		Private: p.Private,
+		Outputs: outputGraphValues(info.Outputs),
+	})
 
 	m.log.LogEvent(&fxevent.Supplied{
 		TypeName:    typeName,
 		StackTrace:  p.Stack.Strings(),
 		ModuleTrace: append([]string{p.Stack[0].String()}, m.trace...),
 		ModuleName:  m.name,
-		Err:         m.app.err,
+		ModuleOwner: m.owner(),
+		Err:         provideErr,
 	})
 }
 
@@ -260,6 +651,7 @@ func (m *module) constructAllCustomLoggers() {
 				m.log = m.fallbackLogger
 				buffer.Connect(m.log)
 			}
+			m.app.droppedEvents += buffer.dropped
 		}
 		m.fallbackLogger = nil
 	} else if m.parent != nil {
@@ -289,26 +681,83 @@ func (m *module) constructCustomLogger(buffer *logBuffer) (err error) {
 			fname, p.Stack, m.name, err)
 	}
 
-	return m.scope.Invoke(func(log fxevent.Logger) {
-		m.log = log
-		buffer.Connect(log)
+	defer m.claimHooks(-1)
+	err = m.scope.Invoke(func(log fxevent.Logger) {
+		m.log = m.app.subscribedLogger(log)
+		buffer.Connect(m.log)
 	})
+	return newDependencyError(err, m.app.root.moduleLocations(nil))
+}
+
+// claimHooks attributes to m the lifecycle hooks appended since hooks were
+// last claimed. Constructors, decorators, and invoked functions never run
+// concurrently, so these were appended by the function that just ran:
+// the one at index node in m.graphNodes, or an unknown one if node is -1.
+func (m *module) claimHooks(node int) {
+	budget := m.inheritedBudget()
+	for n := m.app.lifecycle.HookCount(); len(m.app.hookModules) < n; {
+		if budget != nil {
+			m.app.lifecycle.SetBudget(len(m.app.hookModules), budget)
+		}
+		m.app.hookModules = append(m.app.hookModules, m.name)
+		m.app.hookNodes = append(m.app.hookNodes, hookNode{mod: m, node: node})
+	}
+}
+
+// budget returns the budget for the hooks of m, creating it if needed.
+func (m *module) budget() *lifecycle.Budget {
+	if m.hookBudget == nil {
+		m.hookBudget = &lifecycle.Budget{Name: fmt.Sprintf("module %q", m.path())}
+	}
+	return m.hookBudget
 }
 
-func (m *module) executeInvokes() error {
+// inheritedBudget returns the budget of the closest module to m,
+// including m, that was given StartTimeout or StopTimeout, if any.
+func (m *module) inheritedBudget() *lifecycle.Budget {
+	for mod := m; mod != nil; mod = mod.parent {
+		if mod.hookBudget != nil {
+			return mod.hookBudget
+		}
+	}
+	return nil
+}
+
+// executeInvokes runs the functions invoked in m and the modules it contains,
+// returning the module whose invoked function failed, if any.
+func (m *module) executeInvokes() (*module, error) {
 	for _, m := range m.modules {
-		if err := m.executeInvokes(); err != nil {
-			return err
+		if mod, err := m.executeInvokes(); err != nil {
+			return mod, err
 		}
 	}
 
 	for _, invoke := range m.invokes {
+		m.app.invoked++
+		m.app.analysis.recordInvoke(fxreflect.FuncName(invoke.Target))
 		if err := m.executeInvoke(invoke); err != nil {
-			return err
+			if m.app.analysis.recordInvokeError(err) {
+				continue
+			}
+			m.app.failure = newInvokeError(fxreflect.FuncName(invoke.Target), m, err)
+			return m, err
 		}
 	}
 
-	return nil
+	return nil, nil
+}
+
+// path returns the names of the modules that contain m, from the outermost,
+// and m's own name, separated by "/".
+// It's empty for the top-level module.
+func (m *module) path() string {
+	if m.parent == nil {
+		return ""
+	}
+	if p := m.parent.path(); p != "" {
+		return p + "/" + m.name
+	}
+	return m.name
 }
 
 func (m *module) executeInvoke(i invoke) (err error) {
@@ -316,30 +765,54 @@ func (m *module) executeInvoke(i invoke) (err error) {
 	m.log.LogEvent(&fxevent.Invoking{
 		FunctionName: fnName,
 		ModuleName:   m.name,
+		ModuleOwner:  m.owner(),
 	})
-	err = runInvoke(m.scope, i)
+	i.Target = m.bindAnnotated(i.Target)
+	var info dig.InvokeInfo
+	err = runInvoke(m.app.transients.container(m.scope), i, dig.FillInvokeInfo(&info))
+	if err == nil {
+		m.recordPlanStep("invoke", fnName, i.Target)
+	}
+	if pe, ok := err.(dig.PanicError); ok {
+		m.recoverPanic("invoke", fnName, pe, nil)
+	}
+	m.recordGraphNode(graphNode{
+		Kind:     "invoke",
+		Name:     fnName,
+		Inputs:   inputGraphValues(info.Inputs),
+		Location: targetLocation(i.Target),
+	})
+	err = m.app.attachPanic(m.app.withSuggestions(newDependencyError(err, m.app.root.moduleLocations(nil))))
+	m.claimHooks(len(m.graphNodes) - 1)
 	m.log.LogEvent(&fxevent.Invoked{
 		FunctionName: fnName,
 		ModuleName:   m.name,
+		ModuleOwner:  m.owner(),
 		Err:          err,
 		Trace:        fmt.Sprintf("%+v", i.Stack), // format stack trace as multi-line
 	})
 	return err
 }
 
-func (m *module) decorateAll() error {
+func (m *module) decorateAll() (errs error) {
 	for _, d := range m.decorators {
 		if err := m.decorate(d); err != nil {
-			return err
+			if !m.app.reportAllErrors {
+				return err
+			}
+			errs = multierr.Append(errs, err)
 		}
 	}
 
 	for _, m := range m.modules {
 		if err := m.decorateAll(); err != nil {
-			return err
+			if !m.app.reportAllErrors {
+				return err
+			}
+			errs = multierr.Append(errs, err)
 		}
 	}
-	return nil
+	return errs
 }
 
 func (m *module) decorate(d decorator) (err error) {
@@ -348,30 +821,50 @@ func (m *module) decorate(d decorator) (err error) {
 	}
 
 	funcName := fxreflect.FuncName(d.Target)
+	if d.IsUndecorate {
+		funcName = fmt.Sprintf("fx.Undecorate(%v)", d.UndecorateType)
+	}
 	var info dig.DecorateInfo
+	node := len(m.graphNodes) // index of the decorator in m.graphNodes
 	opts := []dig.DecorateOption{
 		dig.FillDecorateInfo(&info),
 		dig.WithDecoratorCallback(func(ci dig.CallbackInfo) {
+			m.claimHooks(node)
+			m.recordPlanStep("decorate", funcName, d.Target)
 			m.log.LogEvent(&fxevent.Run{
-				Name:       funcName,
-				Kind:       "decorate",
-				ModuleName: m.name,
-				Err:        ci.Error,
+				Name:        funcName,
+				Kind:        "decorate",
+				ModuleName:  m.name,
+				ModuleOwner: m.owner(),
+				Err:         ci.Error,
 			})
+			if pe, ok := ci.Error.(dig.PanicError); ok {
+				m.recoverPanic("decorate", funcName, pe, nil)
+			}
 		}),
 	}
 
-	err = runDecorator(m.scope, d, opts...)
+	d.Target = m.bindAnnotated(d.Target)
+	err = runDecorator(m.app.transients.container(m.scope), d, opts...)
 	outputNames := make([]string, len(info.Outputs))
 	for i, o := range info.Outputs {
 		outputNames[i] = o.String()
 	}
+	m.warnAmbiguousDecorations(outputNames, d.Stack)
+	m.recordGraphNode(graphNode{
+		Kind:     "decorate",
+		Name:     funcName,
+		Inputs:   inputGraphValues(info.Inputs),
+		Outputs:  outputGraphValues(info.Outputs),
+		Location: targetLocation(d.Target),
+	})
 
 	m.log.LogEvent(&fxevent.Decorated{
 		DecoratorName:   funcName,
 		StackTrace:      d.Stack.Strings(),
 		ModuleTrace:     append([]string{d.Stack[0].String()}, m.trace...),
 		ModuleName:      m.name,
+		ModuleOwner:     m.owner(),
 		OutputTypeNames: outputNames,
 		Err:             err,
 	})
@@ -381,20 +874,30 @@ func (m *module) decorate(d decorator) (err error) {
 
 func (m *module) replace(d decorator) error {
 	typeName := d.ReplaceType.String()
+	var info dig.DecorateInfo
 	opts := []dig.DecorateOption{
+		dig.FillDecorateInfo(&info),
 		dig.WithDecoratorCallback(func(ci dig.CallbackInfo) {
+			m.recordPlanStep("replace", fmt.Sprintf("fx.Replace(%v)", typeName), nil)
 			m.log.LogEvent(&fxevent.Run{
-				Name:       fmt.Sprintf("stub(%v)", typeName),
-				Kind:       "replace",
-				ModuleName: m.name,
-				Err:        ci.Error,
+				Name:        fmt.Sprintf("stub(%v)", typeName),
+				Kind:        "replace",
+				ModuleName:  m.name,
+				ModuleOwner: m.owner(),
+				Err:         ci.Error,
 			})
 		}),
 	}
 
 	err := runDecorator(m.scope, d, opts...)
+	m.recordGraphNode(graphNode{
+		Kind:    "replace",
+		Name:    fmt.Sprintf("fx.Replace(%v)", typeName),
+		Outputs: outputGraphValues(info.Outputs),
+	})
 	m.log.LogEvent(&fxevent.Replaced{
 		ModuleName:      m.name,
+		ModuleOwner:     m.owner(),
 		StackTrace:      d.Stack.Strings(),
 		ModuleTrace:     append([]string{d.Stack[0].String()}, m.trace...),
 		OutputTypeNames: []string{typeName},
@@ -22,6 +22,7 @@ package fx_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"log"
 	"testing"
@@ -584,18 +585,14 @@ func TestModuleFailures(t *testing.T) {
 			desc string
 			opt  fx.Option
 		}{
-			{
-				desc: "StartTimeout Option",
-				opt:  fx.StartTimeout(time.Second),
-			},
-			{
-				desc: "StopTimeout Option",
-				opt:  fx.StopTimeout(time.Second),
-			},
 			{
 				desc: "Logger Option",
 				opt:  fx.Logger(log.New(&bytes.Buffer{}, "", 0)),
 			},
+			{
+				desc: "WithClock Option",
+				opt:  fx.WithClock(fxtest.NewClock()),
+			},
 		}
 
 		for _, tt := range tests {
@@ -724,3 +721,152 @@ func TestModuleFailures(t *testing.T) {
 		}
 	})
 }
+
+func TestNoEmptyModules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc    string
+		give    fx.Option
+		wantErr []string
+	}{
+		{
+			desc: "populated modules",
+			give: fx.Options(
+				fx.Module("provides", fx.Provide(func() int { return 0 })),
+				fx.Module("supplies", fx.Supply("foo")),
+				fx.Module("invokes", fx.Invoke(func() {})),
+				fx.Module("decorates", fx.Decorate(func(i int) int { return i })),
+				fx.Module("logs", fx.WithLogger(func() fxevent.Logger { return fxevent.NopLogger })),
+			),
+		},
+		{
+			desc: "only child modules",
+			give: fx.Module("parent",
+				fx.Module("child", fx.Invoke(func() {})),
+			),
+		},
+		{
+			desc:    "empty module",
+			give:    fx.Module("empty"),
+			wantErr: []string{`fx.NoEmptyModules: module "empty" is empty`},
+		},
+		{
+			desc:    "empty module with empty options",
+			give:    fx.Module("empty", fx.Options()),
+			wantErr: []string{`fx.NoEmptyModules: module "empty" is empty`},
+		},
+		{
+			desc: "nested empty modules",
+			give: fx.Module("parent",
+				fx.Module("first"),
+				fx.Module("second"),
+			),
+			wantErr: []string{
+				`module "first" is empty`,
+				`module "second" is empty`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			app := NewForTest(t, fx.NoEmptyModules(), tt.give)
+			err := app.Err()
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			for _, want := range tt.wantErr {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, fx.Module("empty"))
+		assert.NoError(t, app.Err())
+	})
+
+	t.Run("in module", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, fx.Module("child", fx.NoEmptyModules()))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			"fx.NoEmptyModules Option should be passed to top-level App, not to fx.Module")
+	})
+}
+
+func TestModuleTimeouts(t *testing.T) {
+	t.Parallel()
+
+	// blocking returns a hook function that ignores its context
+	// until the test ends.
+	blocking := func(t *testing.T) func(context.Context) error {
+		release := make(chan struct{})
+		t.Cleanup(func() { close(release) })
+		return func(context.Context) error {
+			<-release
+			return nil
+		}
+	}
+
+	t.Run("start", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			fx.Module("fast",
+				fx.StartTimeout(time.Minute),
+				fx.Invoke(func(lc fx.Lifecycle) {
+					lc.Append(fx.StartHook(func(ctx context.Context) {
+						deadline, ok := ctx.Deadline()
+						require.True(t, ok, "expected a deadline on the hook context")
+						assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 10*time.Second)
+					}))
+				}),
+			),
+			fx.Module("slow",
+				fx.StartTimeout(10*time.Millisecond),
+				fx.Module("inner",
+					fx.Invoke(func(lc fx.Lifecycle) {
+						lc.Append(fx.Hook{OnStart: blocking(t)})
+					}),
+				),
+			),
+		)
+
+		err := app.Start(context.Background())
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Contains(t, err.Error(), `module "slow" did not start within its 10ms timeout`)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			fx.Module("outer",
+				fx.Module("slow",
+					fx.StopTimeout(10*time.Millisecond),
+					fx.Invoke(func(lc fx.Lifecycle) {
+						lc.Append(fx.Hook{OnStop: blocking(t)})
+					}),
+				),
+			),
+		)
+		app.RequireStart()
+
+		err := app.Stop(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `module "outer/slow" did not stop within its 10ms timeout`)
+	})
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ModuleInfo describes a [Module]: its version, the team that owns it,
+// and what it's for. Pass it to Module alongside the module's other options.
+//
+//	var Module = fx.Module("storage",
+//		fx.ModuleInfo{
+//			Version:     "1.4.0",
+//			Owner:       "storage-team",
+//			Description: "Clients for the blob store.",
+//		},
+//		fx.Provide(New),
+//	)
+//
+// Fx reports the owner on the events about functions passed to the module,
+// includes the info in [App.Graph], and lists it in the [Modules]
+// available to every application.
+// Modules without an owner are reported with the owner
+// of the closest module that contains them.
+//
+// ModuleInfo may be passed at most once to each module,
+// and not to the top-level App.
+type ModuleInfo struct {
+	// Version of the module, in any format.
+	Version string `json:"version,omitempty"`
+
+	// Owner of the module, such as a team name or an email address.
+	Owner string `json:"owner,omitempty"`
+
+	// Description of the module.
+	Description string `json:"description,omitempty"`
+}
+
+func (i ModuleInfo) apply(m *module) {
+	switch {
+	case m.parent == nil:
+		m.app.err = fmt.Errorf("fx.ModuleInfo Option should be passed to fx.Module, " +
+			"not to the top-level App")
+	case m.hasInfo:
+		m.app.err = fmt.Errorf("fx.ModuleInfo passed more than once to fx.Module(%q)", m.name)
+	default:
+		m.info = i
+		m.hasInfo = true
+	}
+}
+
+func (i ModuleInfo) String() string {
+	var fields []string
+	if len(i.Version) > 0 {
+		fields = append(fields, fmt.Sprintf("Version: %q", i.Version))
+	}
+	if len(i.Owner) > 0 {
+		fields = append(fields, fmt.Sprintf("Owner: %q", i.Owner))
+	}
+	if len(i.Description) > 0 {
+		fields = append(fields, fmt.Sprintf("Description: %q", i.Description))
+	}
+	return fmt.Sprintf("fx.ModuleInfo{%v}", strings.Join(fields, ", "))
+}
+
+// owner returns the owner of m, or of the closest module containing it
+// that has one.
+func (m *module) owner() string {
+	for mod := m; mod != nil; mod = mod.parent {
+		if mod.info.Owner != "" {
+			return mod.info.Owner
+		}
+	}
+	return ""
+}
+
+// Modules lists the modules of an application.
+// Fx provides it to every application.
+//
+//	fx.Invoke(func(mods fx.Modules) {
+//		for _, m := range mods {
+//			log.Printf("%v %v owned by %v", m.Path, m.Info.Version, m.Info.Owner)
+//		}
+//	})
+type Modules []LoadedModule
+
+// LoadedModule is a module in [Modules].
+type LoadedModule struct {
+	// Name of the module.
+	Name string
+
+	// Path of the module: the names of the modules that contain it,
+	// from the outermost, and its own name, separated by "/".
+	Path string
+
+	// Info is the [ModuleInfo] passed to the module, if any.
+	Info ModuleInfo
+}
+
+// loadedModules lists the modules contained in m, depth-first,
+// in the order they were declared.
+func (m *module) loadedModules() Modules {
+	var mods Modules
+	for _, mod := range m.modules {
+		mods = append(mods, LoadedModule{
+			Name: mod.name,
+			Path: mod.path(),
+			Info: mod.info,
+		})
+		mods = append(mods, mod.loadedModules()...)
+	}
+	return mods
+}
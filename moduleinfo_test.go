@@ -0,0 +1,120 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestModuleInfo(t *testing.T) {
+	t.Parallel()
+
+	type storage struct{}
+
+	storageInfo := fx.ModuleInfo{
+		Version:     "1.4.0",
+		Owner:       "storage-team",
+		Description: "Clients for the blob store.",
+	}
+
+	t.Run("catalog", func(t *testing.T) {
+		t.Parallel()
+
+		var mods fx.Modules
+		fxtest.New(t,
+			fx.Module("storage",
+				storageInfo,
+				fx.Module("cache"),
+			),
+			fx.Module("server"),
+			fx.Populate(&mods),
+		)
+		assert.Equal(t, fx.Modules{
+			{Name: "storage", Path: "storage", Info: storageInfo},
+			{Name: "cache", Path: "storage/cache"},
+			{Name: "server", Path: "server"},
+		}, mods)
+	})
+
+	t.Run("owner on events", func(t *testing.T) {
+		t.Parallel()
+
+		app, spy := NewSpied(
+			fx.Module("storage",
+				storageInfo,
+				fx.Provide(func() *storage { return &storage{} }),
+				fx.Module("cache",
+					fx.Invoke(func(*storage) {}),
+				),
+			),
+		)
+		require.NoError(t, app.Err())
+
+		var provided *fxevent.Provided
+		for _, e := range spy.Events().SelectByTypeName("Provided") {
+			if p := e.(*fxevent.Provided); p.ModuleName == "storage" {
+				provided = p
+			}
+		}
+		require.NotNil(t, provided)
+		assert.Equal(t, "storage-team", provided.ModuleOwner)
+
+		invoked := spy.Events().SelectByTypeName("Invoked")
+		require.Len(t, invoked, 1)
+		assert.Equal(t, "cache", invoked[0].(*fxevent.Invoked).ModuleName)
+		assert.Equal(t, "storage-team", invoked[0].(*fxevent.Invoked).ModuleOwner,
+			"modules without an owner must inherit it")
+	})
+
+	t.Run("graph", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			fx.Module("storage", storageInfo),
+			fx.Module("server"),
+		)
+		g := app.Graph()
+		require.Len(t, g.Root.Modules, 2)
+		assert.Equal(t, &storageInfo, g.Root.Modules[0].Info)
+		assert.Nil(t, g.Root.Modules[1].Info)
+	})
+
+	t.Run("top-level", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, storageInfo)
+		assert.ErrorContains(t, app.Err(),
+			"fx.ModuleInfo Option should be passed to fx.Module, not to the top-level App")
+	})
+
+	t.Run("more than once", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, fx.Module("storage", storageInfo, fx.ModuleInfo{Owner: "other"}))
+		assert.ErrorContains(t, app.Err(), `fx.ModuleInfo passed more than once to fx.Module("storage")`)
+	})
+}
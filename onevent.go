@@ -0,0 +1,112 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// OnEvent registers a function that's called with every event Fx logs,
+// alongside the application's [fxevent.Logger].
+// Use it to react to lifecycle events without replacing the logger:
+// for example, to flip a readiness gauge once the application has started.
+//
+//	fx.OnEvent(func(e fxevent.Event) {
+//		if e, ok := e.(*fxevent.Started); ok && e.Err == nil {
+//			ready.Set(1)
+//		}
+//	})
+//
+// OnEvent may be passed to any module, and may be passed more than once;
+// every function sees the events of the whole application,
+// in the order they were registered, once the logger has logged each event.
+// Events logged while a logger given to [WithLogger] is being built
+// are passed on once it has been built.
+//
+// The functions are called synchronously from Fx,
+// so they should return quickly.
+func OnEvent(fn func(fxevent.Event)) Option {
+	return onEventOption{fn}
+}
+
+type onEventOption struct {
+	fn func(fxevent.Event)
+}
+
+func (o onEventOption) apply(m *module) {
+	if o.fn == nil {
+		m.app.err = fmt.Errorf("fx.OnEvent: function must not be nil")
+		return
+	}
+	if m.rejectInTrial("fx.OnEvent") {
+		return
+	}
+	m.app.eventSubscribers = append(m.app.eventSubscribers, o.fn)
+}
+
+func (o onEventOption) String() string {
+	return fmt.Sprintf("fx.OnEvent(%v)", fxreflect.FuncName(o.fn))
+}
+
+// subscribedLogger returns a logger that passes the events it logs
+// to log, and then to the functions given to OnEvent.
+func (app *App) subscribedLogger(log fxevent.Logger) fxevent.Logger {
+	if len(app.eventSubscribers) == 0 {
+		return log
+	}
+	if _, ok := log.(subscriberLogger); ok {
+		return log
+	}
+	return subscriberLogger{Logger: log, app: app}
+}
+
+// subscriberLogger passes the events it logs to the functions
+// given to OnEvent.
+type subscriberLogger struct {
+	fxevent.Logger
+
+	app *App
+}
+
+func (l subscriberLogger) LogEvent(e fxevent.Event) {
+	l.Logger.LogEvent(e)
+	for _, fn := range l.app.eventSubscribers {
+		fn(e)
+	}
+}
+
+// unwrapLogger returns the logger that log passes events to,
+// if log only records them or passes them on to other functions.
+func unwrapLogger(log fxevent.Logger) fxevent.Logger {
+	for {
+		switch l := log.(type) {
+		case subscriberLogger:
+			log = l.Logger
+		case historyLogger:
+			log = l.Logger
+		default:
+			return log
+		}
+	}
+}
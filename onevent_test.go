@@ -0,0 +1,154 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestOnEvent(t *testing.T) {
+	t.Parallel()
+
+	// countEvents returns the number of events of each kind.
+	countEvents := func(events []fxevent.Event) map[string]int {
+		counts := make(map[string]int)
+		for _, e := range events {
+			switch e.(type) {
+			case *fxevent.Provided:
+				counts["Provided"]++
+			case *fxevent.LoggerInitialized:
+				counts["LoggerInitialized"]++
+			case *fxevent.Invoked:
+				counts["Invoked"]++
+			case *fxevent.Started:
+				counts["Started"]++
+			case *fxevent.Stopped:
+				counts["Stopped"]++
+			}
+		}
+		return counts
+	}
+
+	t.Run("lifecycle", func(t *testing.T) {
+		t.Parallel()
+
+		var events []fxevent.Event
+		app := fxtest.New(t,
+			OnEvent(func(e fxevent.Event) { events = append(events, e) }),
+			Provide(func() int { return 1 }),
+			Invoke(func(int) {}),
+		)
+		app.RequireStart().RequireStop()
+
+		counts := countEvents(events)
+		assert.Equal(t, 1, counts["Invoked"])
+		assert.Equal(t, 1, counts["Started"])
+		assert.Equal(t, 1, counts["Stopped"])
+	})
+
+	t.Run("in order, after the logger", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		logger := loggerFunc(func(e fxevent.Event) {
+			if _, ok := e.(*fxevent.Started); ok {
+				calls = append(calls, "logger")
+			}
+		})
+		subscriber := func(name string) func(fxevent.Event) {
+			return func(e fxevent.Event) {
+				if _, ok := e.(*fxevent.Started); ok {
+					calls = append(calls, name)
+				}
+			}
+		}
+		app := fxtest.New(t,
+			WithLogger(func() fxevent.Logger { return logger }),
+			OnEvent(subscriber("first")),
+			Module("child", OnEvent(subscriber("second"))),
+		)
+		app.RequireStart().RequireStop()
+
+		assert.Equal(t, []string{"logger", "first", "second"}, calls)
+	})
+
+	t.Run("custom logger", func(t *testing.T) {
+		t.Parallel()
+
+		var events, logged []fxevent.Event
+		app := fxtest.New(t,
+			WithLogger(func() fxevent.Logger {
+				return loggerFunc(func(e fxevent.Event) { logged = append(logged, e) })
+			}),
+			OnEvent(func(e fxevent.Event) { events = append(events, e) }),
+			Provide(func() int { return 1 }),
+			Invoke(func(int) {}),
+		)
+		app.RequireStart().RequireStop()
+
+		counts := countEvents(events)
+		assert.NotZero(t, counts["Provided"], "events logged while the logger was built must be passed on")
+		assert.Equal(t, 1, counts["LoggerInitialized"])
+		assert.Equal(t, 1, counts["Started"])
+		assert.Equal(t, countEvents(logged), counts, "subscribers must see what the logger logs")
+		assert.Equal(t, "fx_test.loggerFunc", app.LoggerType())
+	})
+
+	t.Run("failed start", func(t *testing.T) {
+		t.Parallel()
+
+		var startErr error
+		app := New(
+			NopLogger,
+			OnEvent(func(e fxevent.Event) {
+				if e, ok := e.(*fxevent.Started); ok {
+					startErr = e.Err
+				}
+			}),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{OnStart: func(context.Context) error { return assert.AnError }})
+			}),
+		)
+		require.NoError(t, app.Err())
+		require.Error(t, app.Start(context.Background()))
+		assert.ErrorIs(t, startErr, assert.AnError)
+	})
+
+	t.Run("nil function", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, OnEvent(nil))
+		assert.ErrorContains(t, app.Err(), "fx.OnEvent: function must not be nil")
+	})
+}
+
+func ignoreEvent(fxevent.Event) {}
+
+type loggerFunc func(fxevent.Event)
+
+func (f loggerFunc) LogEvent(e fxevent.Event) { f(e) }
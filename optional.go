@@ -0,0 +1,66 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import "reflect"
+
+// Optional is a parameter object for a single optional dependency of
+// type T. It spares functions that have one from declaring a struct that
+// embeds [In] with an `optional:"true"` field.
+//
+//	func NewServer(cfg Config, tracer fx.Optional[*Tracer]) *Server {
+//		if t, ok := tracer.Get(); ok {
+//			// ...
+//		}
+//	}
+//
+// Like other parameter objects, Optional may also be the type of a field
+// of a struct that embeds [In].
+//
+// The container doesn't report whether it found a value,
+// so a value that's the zero value of T is taken to be missing.
+// This rarely matters for pointers and interfaces,
+// which constructors don't usually build as nil.
+type Optional[T any] struct {
+	In
+
+	// Value is the value of type T,
+	// or the zero value of T if there's none.
+	Value T `optional:"true"`
+}
+
+// Get returns the value, and reports whether there is one.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Present()
+}
+
+// Present reports whether there is a value.
+func (o Optional[T]) Present() bool {
+	return !reflect.ValueOf(&o.Value).Elem().IsZero()
+}
+
+// OrElse returns the value if there is one, or def otherwise.
+func (o Optional[T]) OrElse(def T) T {
+	if o.Present() {
+		return o.Value
+	}
+	return def
+}
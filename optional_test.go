@@ -0,0 +1,119 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestOptional(t *testing.T) {
+	t.Parallel()
+
+	type tracer struct{ name string }
+
+	t.Run("present", func(t *testing.T) {
+		t.Parallel()
+
+		var got Optional[*tracer]
+		app := fxtest.New(t,
+			Supply(&tracer{name: "jaeger"}),
+			Invoke(func(o Optional[*tracer]) { got = o }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		tr, ok := got.Get()
+		assert.True(t, ok)
+		assert.Equal(t, "jaeger", tr.name)
+		assert.True(t, got.Present())
+		assert.Same(t, tr, got.OrElse(&tracer{name: "noop"}))
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		t.Parallel()
+
+		var got Optional[*tracer]
+		app := fxtest.New(t,
+			Invoke(func(o Optional[*tracer]) { got = o }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		tr, ok := got.Get()
+		assert.False(t, ok)
+		assert.Nil(t, tr)
+		assert.Equal(t, "noop", got.OrElse(&tracer{name: "noop"}).name)
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		t.Parallel()
+
+		var got Optional[int]
+		app := fxtest.New(t,
+			Supply(0),
+			Invoke(func(o Optional[int]) { got = o }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.False(t, got.Present(), "zero values are reported as missing")
+	})
+
+	t.Run("field of parameter object", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			In
+
+			Name   string
+			Tracer Optional[*tracer]
+		}
+
+		var got params
+		app := fxtest.New(t,
+			Supply("server"),
+			Invoke(func(p params) { got = p }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "server", got.Name)
+		assert.False(t, got.Tracer.Present())
+	})
+
+	t.Run("constructor", func(t *testing.T) {
+		t.Parallel()
+
+		type server struct{ tracer string }
+
+		var got *server
+		app := fxtest.New(t,
+			Supply(&tracer{name: "jaeger"}),
+			Provide(func(o Optional[*tracer]) *server {
+				return &server{tracer: o.OrElse(&tracer{}).name}
+			}),
+			Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "jaeger", got.tracer)
+	})
+}
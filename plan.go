@@ -0,0 +1,125 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// Plan is the order in which an application would run its functions,
+// as reported by [PlanApp].
+type Plan struct {
+	// Steps are the functions the application would run, in order.
+	Steps []PlanStep
+}
+
+// PlanStep is a function that an application would run.
+type PlanStep struct {
+	// Kind is the option the function was passed to:
+	// "provide", "supply", "decorate", "replace", or "invoke".
+	// Constructors provided with SupplyDerived are "derive".
+	Kind string
+
+	// Name of the function.
+	Name string
+
+	// Module is the path of the module the function was passed to,
+	// such as "server/http", or empty for the top-level module.
+	Module string
+
+	// Hooks are the lifecycle hooks declared for the function
+	// with the [OnStart] and [OnStop] annotations,
+	// such as "OnStart: mypkg.(*Server).Listen".
+	Hooks []string
+}
+
+// String renders the plan as a numbered list of steps,
+// one per line, followed by their hooks.
+func (p Plan) String() string {
+	var sb strings.Builder
+	for i, s := range p.Steps {
+		fmt.Fprintf(&sb, "%d. %v %v", i+1, s.Kind, s.Name)
+		if len(s.Module) > 0 {
+			fmt.Fprintf(&sb, " in module %q", s.Module)
+		}
+		sb.WriteString("\n")
+		for _, h := range s.Hooks {
+			fmt.Fprintf(&sb, "\t%v\n", h)
+		}
+	}
+	return sb.String()
+}
+
+// PlanApp reports the functions that an application built with the given
+// options would run in [New], in the order it would run them,
+// without running any of them.
+// Use it to review the effect of adding a module to an application.
+//
+//	plan, err := fx.PlanApp(opts...)
+//	fmt.Print(plan)
+//
+// Like [ValidateApp], PlanApp returns an error if the application is
+// missing dependencies.
+//
+// Hooks appended to the [Lifecycle] by constructors or invoked functions
+// are only known once those functions run, so the plan only lists hooks
+// declared with the [OnStart] and [OnStop] annotations.
+func PlanApp(opts ...Option) (Plan, error) {
+	var plan Plan
+	opts = append(opts, validate(true), planOption{&plan})
+	app := New(opts...)
+
+	return plan, app.Err()
+}
+
+type planOption struct{ plan *Plan }
+
+func (o planOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.plan Option should be passed to top-level App, " +
+			"not to fx.Module")
+	} else {
+		m.app.plan = o.plan
+	}
+}
+
+func (planOption) String() string {
+	return "fx.plan()"
+}
+
+// recordPlanStep records the function of the given kind and name,
+// passed to m as target, in the plan of PlanApp, if any.
+func (m *module) recordPlanStep(kind, name string, target interface{}) {
+	if m.app.plan == nil {
+		return
+	}
+
+	step := PlanStep{Kind: kind, Name: name, Module: m.path()}
+	if ann, ok := target.(annotated); ok {
+		for _, h := range ann.Hooks {
+			step.Hooks = append(step.Hooks, fmt.Sprintf("%v: %v", h, fxreflect.FuncName(h.Target)))
+		}
+	}
+	m.app.plan.Steps = append(m.app.plan.Steps, step)
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+type planDB struct{}
+
+func newPlanDB() *planDB { return &planDB{} }
+
+func (*planDB) Open(context.Context) error { return nil }
+
+type planServer struct{}
+
+func newPlanServer(*planDB) *planServer { return &planServer{} }
+
+func decoratePlanDB(db *planDB) *planDB { return db }
+
+func runPlanServer(*planServer) {}
+
+func TestPlanApp(t *testing.T) {
+	t.Parallel()
+
+	t.Run("order", func(t *testing.T) {
+		t.Parallel()
+
+		plan, err := fx.PlanApp(
+			fx.NopLogger,
+			fx.Module("server",
+				fx.Provide(newPlanServer),
+				fx.Invoke(runPlanServer),
+			),
+			fx.Module("db",
+				fx.Provide(fx.Annotate(newPlanDB, fx.OnStart((*planDB).Open))),
+			),
+			fx.Decorate(decoratePlanDB),
+			fx.Supply(42),
+			fx.Invoke(func(int) {
+				t.Error("invoked functions must not run")
+			}),
+		)
+		require.NoError(t, err)
+
+		var steps []fx.PlanStep
+		for _, s := range plan.Steps {
+			// Fx's own constructors, such as the one for the Lifecycle,
+			// are also part of the plan.
+			if !strings.HasPrefix(s.Name, "go.uber.org/fx.") {
+				steps = append(steps, s)
+			}
+		}
+		assert.Equal(t, []fx.PlanStep{
+			{
+				Kind:   "provide",
+				Name:   "fx.Annotate(go.uber.org/fx_test.newPlanDB()",
+				Module: "db",
+				Hooks:  []string{"OnStart: go.uber.org/fx_test.(*planDB).Open()"},
+			},
+			{Kind: "decorate", Name: "go.uber.org/fx_test.decoratePlanDB()"},
+			{Kind: "provide", Name: "go.uber.org/fx_test.newPlanServer()", Module: "server"},
+			{Kind: "invoke", Name: "go.uber.org/fx_test.runPlanServer()", Module: "server"},
+			{Kind: "supply", Name: "fx.Supply(int)"},
+			{Kind: "invoke", Name: "go.uber.org/fx_test.TestPlanApp.func1.1()"},
+		}, steps)
+	})
+
+	t.Run("String", func(t *testing.T) {
+		t.Parallel()
+
+		plan := fx.Plan{Steps: []fx.PlanStep{
+			{Kind: "provide", Name: "db.New()", Module: "db", Hooks: []string{"OnStart: db.Open()"}},
+			{Kind: "invoke", Name: "main.run()"},
+		}}
+		assert.Equal(t, "1. provide db.New() in module \"db\"\n"+
+			"\tOnStart: db.Open()\n"+
+			"2. invoke main.run()\n", plan.String())
+	})
+
+	t.Run("missing dependency", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := fx.PlanApp(fx.NopLogger, fx.Invoke(runPlanServer))
+		assert.ErrorContains(t, err, "missing type: *fx_test.planServer")
+	})
+}
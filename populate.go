@@ -58,6 +58,13 @@ import (
 //	...
 //	fx.Populate(&target)
 //
+// Value groups can be populated the same way, into a pointer to a slice:
+//
+//	var routes []Route
+//	fx.Populate(fx.Annotate(&routes, fx.ParamTags(`group:"routes"`)))
+//
+// Annotations other than ParamTags are not supported by Populate.
+//
 // This is most helpful in unit tests: it lets tests leverage Fx's automatic
 // constructor wiring to build a few structs, but then extract those structs
 // for further testing.
@@ -78,9 +85,22 @@ func Populate(targets ...interface{}) Option {
 			tag reflect.StructTag
 		)
 		switch t := t.(type) {
+		case annotationError:
+			return Error(fmt.Errorf("failed to Populate: target %v: %w", i+1, t.err))
 		case annotated:
+			if len(t.ResultTags) > 0 || t.ParamStruct != nil || len(t.As) > 0 || len(t.From) > 0 || len(t.Hooks) > 0 || t.GroupPresence {
+				return Error(fmt.Errorf("failed to Populate: target %v: "+
+					"only fx.ParamTags annotations are supported, got %v", i+1, t))
+			}
+			switch len(t.ParamTags) {
+			case 0:
+			case 1:
+				tag = reflect.StructTag(t.ParamTags[0])
+			default:
+				return Error(fmt.Errorf("failed to Populate: target %v: "+
+					"expected a single parameter tag, got %d", i+1, len(t.ParamTags)))
+			}
 			rt = reflect.TypeOf(t.Target)
-			tag = reflect.StructTag(t.ParamTags[0])
 			targets[i] = t.Target
 		default:
 			rt = reflect.TypeOf(t)
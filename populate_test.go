@@ -224,6 +224,41 @@ func TestPopulate(t *testing.T) {
 		assert.False(t, v1 == v2, "values should be different")
 	})
 
+	t.Run("annotated populate group", func(t *testing.T) {
+		t.Parallel()
+
+		var group []*t1
+		app := fxtest.New(t,
+			Provide(
+				Annotate(func() *t1 { return &t1{} }, ResultTags(`group:"g"`)),
+				Annotate(func() *t1 { return &t1{} }, ResultTags(`group:"g"`)),
+			),
+			Populate(Annotate(&group, ParamTags(`group:"g"`))),
+		)
+		app.RequireStart().RequireStop()
+
+		require.Len(t, group, 2, "Expected group to have 2 values")
+		// Cannot use assert.Equal here as we want to compare pointers.
+		assert.False(t, group[0] == group[1], "group values should be different")
+	})
+
+	t.Run("annotated populate optional", func(t *testing.T) {
+		t.Parallel()
+
+		var v1, v2 *t1
+		app := fxtest.New(t,
+			Provide(func() *t1 { return &t1{} }),
+			Populate(
+				Annotate(&v1),
+				Annotate(&v2, ParamTags(`name:"missing" optional:"true"`)),
+			),
+		)
+		app.RequireStart().RequireStop()
+
+		assert.NotNil(t, v1, "did not populate unannotated argument")
+		assert.Nil(t, v2, "populated missing optional argument")
+	})
+
 	t.Run("populate group", func(t *testing.T) {
 		t.Parallel()
 
@@ -310,6 +345,21 @@ func TestPopulateErrors(t *testing.T) {
 			opt:     Populate(&v, t1{}),
 			wantErr: "target 2 is not a pointer type",
 		},
+		{
+			msg:     "annotation error",
+			opt:     Populate(Annotate(&v, ParamTags(`foo:"bar"`))),
+			wantErr: "target 1: tag key is invalid",
+		},
+		{
+			msg:     "multiple param tags",
+			opt:     Populate(Annotate(&v, ParamTags(`name:"a"`, `name:"b"`))),
+			wantErr: "target 1: expected a single parameter tag, got 2",
+		},
+		{
+			msg:     "result tags",
+			opt:     Populate(Annotate(&v, ResultTags(`name:"a"`))),
+			wantErr: "target 1: only fx.ParamTags annotations are supported",
+		},
 		{
 			msg:     "nil argument",
 			opt:     Populate(&v, nil, &v),
@@ -0,0 +1,171 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// HealthProbes makes the application serve Kubernetes-style probes over
+// HTTP on the given address, such as ":8081".
+//
+//   - /readyz reports whether the application is ready to serve traffic.
+//     It succeeds once [App.Start] has completed, and fails again as soon
+//     as [App.Stop] begins.
+//   - /healthz reports whether the application is alive.
+//     It always succeeds, unless [ProbeHealthChecks] is used.
+//
+// Probes are served from the beginning of App.Start,
+// before any OnStart hooks run, to the end of App.Stop.
+// Successful probes respond with 200 OK, and failed probes with
+// 503 Service Unavailable.
+// Requests whose headers take more than 10 seconds to arrive are dropped.
+//
+//	fx.New(
+//		fx.HealthProbes(":8081", fx.ProbeHealthChecks()),
+//		...
+//	).Run()
+func HealthProbes(addr string, opts ...ProbeOption) Option {
+	return healthProbesOption{addr: addr, opts: opts}
+}
+
+// ProbeOption customizes the probes served with [HealthProbes].
+type ProbeOption interface {
+	apply(*probeServer)
+}
+
+type probeHealthChecksOption struct{}
+
+func (probeHealthChecksOption) apply(s *probeServer) {
+	s.checkHealth = true
+}
+
+var _ ProbeOption = probeHealthChecksOption{}
+
+// ProbeHealthChecks is a [ProbeOption] that makes /healthz run
+// [App.HealthCheck] and fail if any [HealthChecker] in the "health"
+// value group is unhealthy.
+// Failed probes list the unhealthy components in their response.
+func ProbeHealthChecks() ProbeOption {
+	return probeHealthChecksOption{}
+}
+
+type healthProbesOption struct {
+	addr string
+	opts []ProbeOption
+}
+
+func (o healthProbesOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.HealthProbes Option should be passed to top-level App, " +
+			"not to fx.Module")
+		return
+	}
+
+	s := newProbeServer(m.app, o.addr)
+	for _, opt := range o.opts {
+		opt.apply(s)
+	}
+	m.app.probes = s
+}
+
+func (o healthProbesOption) String() string {
+	return fmt.Sprintf("fx.HealthProbes(%q)", o.addr)
+}
+
+// probeServer serves the probes of an application.
+// A nil probeServer serves nothing.
+type probeServer struct {
+	app         *App
+	checkHealth bool
+
+	ready atomic.Bool
+	srv   httpServer
+}
+
+func newProbeServer(app *App, addr string) *probeServer {
+	s := &probeServer{app: app}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/readyz", s.readyz)
+	mux.HandleFunc("/healthz", s.healthz)
+	s.srv = httpServer{name: "fx.HealthProbes", addr: addr, handler: mux}
+	return s
+}
+
+// start begins serving probes if it isn't doing so already.
+func (s *probeServer) start() error {
+	if s == nil {
+		return nil
+	}
+	return s.srv.start()
+}
+
+// setReady sets the result of the readiness probe.
+func (s *probeServer) setReady(ready bool) {
+	if s == nil {
+		return
+	}
+	s.ready.Store(ready)
+}
+
+// stop stops serving probes, waiting for in-flight probes
+// until ctx is done.
+func (s *probeServer) stop(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	return s.srv.stop(ctx)
+}
+
+func (s *probeServer) readyz(w http.ResponseWriter, _ *http.Request) {
+	if !s.ready.Load() {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
+
+func (s *probeServer) healthz(w http.ResponseWriter, r *http.Request) {
+	if !s.checkHealth {
+		fmt.Fprintln(w, "ok")
+		return
+	}
+
+	report, err := s.app.HealthCheck(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if !report.Healthy() {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		for _, c := range report.Components {
+			if !c.Healthy() {
+				fmt.Fprintf(w, "%v: %v\n", c.Name, c.Err)
+			}
+		}
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
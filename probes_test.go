@@ -0,0 +1,161 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthProbes(t *testing.T) {
+	t.Parallel()
+
+	// probe requests the given probe of app,
+	// returning the status code and body of the response.
+	probe := func(t *testing.T, app *App, path string) (int, string) {
+		res, err := http.Get("http://" + app.probes.srv.listenAddr().String() + path)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res.StatusCode, string(body)
+	}
+
+	t.Run("readiness follows the lifecycle", func(t *testing.T) {
+		t.Parallel()
+
+		var app *App
+		app = New(
+			NopLogger,
+			HealthProbes("127.0.0.1:0"),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{
+					OnStart: func(context.Context) error {
+						code, _ := probe(t, app, "/readyz")
+						assert.Equal(t, http.StatusServiceUnavailable, code, "starting")
+						code, _ = probe(t, app, "/healthz")
+						assert.Equal(t, http.StatusOK, code, "starting")
+						return nil
+					},
+					OnStop: func(context.Context) error {
+						code, _ := probe(t, app, "/readyz")
+						assert.Equal(t, http.StatusServiceUnavailable, code, "stopping")
+						return nil
+					},
+				})
+			}),
+		)
+		require.NoError(t, app.Err())
+
+		ctx := context.Background()
+		require.NoError(t, app.Start(ctx))
+		addr := app.probes.srv.listenAddr().String()
+
+		code, body := probe(t, app, "/readyz")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "ok\n", body)
+
+		require.NoError(t, app.Stop(ctx))
+		_, err := http.Get("http://" + addr + "/readyz")
+		assert.Error(t, err, "probes must not be served once stopped")
+	})
+
+	t.Run("health checks", func(t *testing.T) {
+		t.Parallel()
+
+		app := New(
+			NopLogger,
+			HealthProbes("127.0.0.1:0", ProbeHealthChecks()),
+			Provide(
+				Annotate(
+					func() HealthChecker { return fakeHealthChecker{name: "cache"} },
+					ResultTags(`group:"health"`),
+				),
+				Annotate(
+					func() HealthChecker {
+						return fakeHealthChecker{name: "db", err: errors.New("great sadness")}
+					},
+					ResultTags(`group:"health"`),
+				),
+			),
+		)
+		require.NoError(t, app.Err())
+
+		ctx := context.Background()
+		require.NoError(t, app.Start(ctx))
+		defer app.Stop(ctx)
+
+		code, body := probe(t, app, "/healthz")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "db: great sadness\n", body)
+
+		code, _ = probe(t, app, "/readyz")
+		assert.Equal(t, http.StatusOK, code, "readiness doesn't depend on health")
+	})
+
+	t.Run("failed start", func(t *testing.T) {
+		t.Parallel()
+
+		app := New(
+			NopLogger,
+			HealthProbes("127.0.0.1:0"),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(StartHook(func() error { return errors.New("great sadness") }))
+			}),
+		)
+		require.NoError(t, app.Err())
+
+		assert.Error(t, app.Start(context.Background()))
+		assert.Nil(t, app.probes.srv.listenAddr(), "probes must not be served after a failed start")
+	})
+
+	t.Run("listen error", func(t *testing.T) {
+		t.Parallel()
+
+		app := New(NopLogger, HealthProbes("not an address"))
+		require.NoError(t, app.Err())
+		assert.ErrorContains(t, app.Start(context.Background()), "fx.HealthProbes: ")
+	})
+
+	t.Run("in module", func(t *testing.T) {
+		t.Parallel()
+
+		app := New(NopLogger, Module("child", HealthProbes(":0")))
+		assert.ErrorContains(t, app.Err(),
+			"fx.HealthProbes Option should be passed to top-level App, not to fx.Module")
+	})
+}
+
+type fakeHealthChecker struct {
+	name string
+	err  error
+}
+
+func (c fakeHealthChecker) Name() string { return c.name }
+
+func (c fakeHealthChecker) CheckHealth(context.Context) error { return c.err }
@@ -0,0 +1,148 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+
+	"go.uber.org/multierr"
+)
+
+// ProfileStartup makes the application capture profiles of its startup,
+// from [New] until [App.Start] returns, and write them to the given
+// directory:
+//
+//   - cpu.pprof is a CPU profile of the startup.
+//   - heap.pprof is a heap profile taken once App.Start has returned.
+//
+// Constructors and OnStart hooks run with a pprof label named "fx",
+// set to the function's name prefixed with "fx.Provide: " or
+// "fx.OnStart: ", so that the CPU profile can be broken down by function:
+//
+//	go tool pprof -tagfocus 'fx=fx.Provide: main.NewDB' startup/cpu.pprof
+//
+// The directory is created if needed.
+// New fails if the profiles can't be created,
+// or if a CPU profile is already being captured;
+// errors writing them are returned by App.Start.
+//
+// If the application is stopped without having been started,
+// App.Stop writes the profiles instead.
+// An application that is never started nor stopped keeps capturing
+// the CPU profile, preventing others from being captured.
+// ProfileStartup has no effect on [ValidateApp], [ValidateAppStrict],
+// [Analyze], and [PlanApp], which never start the application.
+func ProfileStartup(dir string) Option {
+	return profileStartupOption(dir)
+}
+
+type profileStartupOption string
+
+func (o profileStartupOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.ProfileStartup Option should be passed to top-level App, " +
+			"not to fx.Module")
+		return
+	}
+	m.app.startupProfile = &startupProfile{dir: string(o)}
+}
+
+func (o profileStartupOption) String() string {
+	return fmt.Sprintf("fx.ProfileStartup(%q)", string(o))
+}
+
+// startupProfile captures the profiles of ProfileStartup.
+// A nil startupProfile captures nothing.
+type startupProfile struct {
+	dir string
+
+	mu   sync.Mutex
+	cpu  *os.File // non-nil while capturing
+	heap *os.File
+}
+
+// begin starts capturing the CPU profile.
+func (p *startupProfile) begin() (err error) {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("fx.ProfileStartup: %w", err)
+		}
+	}()
+
+	if err := os.MkdirAll(p.dir, 0o755); err != nil {
+		return err
+	}
+	// Create both files now so that the directory
+	// is known to be writable before startup.
+	cpu, err := os.Create(filepath.Join(p.dir, "cpu.pprof"))
+	if err != nil {
+		return err
+	}
+	heap, err := os.Create(filepath.Join(p.dir, "heap.pprof"))
+	if err != nil {
+		return multierr.Append(err, cpu.Close())
+	}
+	if err := pprof.StartCPUProfile(cpu); err != nil {
+		return multierr.Combine(err, cpu.Close(), heap.Close())
+	}
+
+	p.cpu, p.heap = cpu, heap
+	return nil
+}
+
+// end stops capturing the CPU profile, and writes the heap profile.
+// It does nothing if the profiles have already been captured.
+func (p *startupProfile) end() (err error) {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cpu == nil {
+		return nil
+	}
+
+	pprof.StopCPUProfile()
+	err = p.cpu.Close()
+
+	runtime.GC() // report memory in use as of now
+	err = multierr.Append(err, pprof.WriteHeapProfile(p.heap))
+	err = multierr.Append(err, p.heap.Close())
+
+	p.cpu, p.heap = nil, nil
+	if err != nil {
+		err = fmt.Errorf("fx.ProfileStartup: %w", err)
+	}
+	return err
+}
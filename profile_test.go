@@ -0,0 +1,144 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+// Only one CPU profile may be captured at a time,
+// so these tests don't run in parallel.
+func TestProfileStartup(t *testing.T) {
+	t.Run("writes profiles", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "startup")
+
+		var label string
+		app := fxtest.New(t,
+			fx.ProfileStartup(dir),
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.Hook{
+					OnStart: func(ctx context.Context) error {
+						label, _ = pprof.Label(ctx, "fx")
+						return nil
+					},
+				})
+			}),
+		)
+		app.RequireStart().RequireStop()
+
+		assert.Regexp(t, `^fx\.OnStart: go.uber.org/fx_test.TestProfileStartup.func\S+$`, label)
+		for _, name := range []string{"cpu.pprof", "heap.pprof"} {
+			info, err := os.Stat(filepath.Join(dir, name))
+			require.NoError(t, err)
+			assert.NotZero(t, info.Size(), name)
+		}
+	})
+
+	t.Run("failed build", func(t *testing.T) {
+		app := NewForTest(t,
+			fx.ProfileStartup(t.TempDir()),
+			fx.Invoke(func() error { return errors.New("great sadness") }),
+		)
+		require.Error(t, app.Err())
+
+		// The profile must have been stopped for the next one to start.
+		fxtest.New(t, fx.ProfileStartup(t.TempDir())).RequireStart().RequireStop()
+	})
+
+	t.Run("stopped without starting", func(t *testing.T) {
+		dir := t.TempDir()
+		app := fxtest.New(t, fx.ProfileStartup(dir))
+		app.RequireStop()
+
+		info, err := os.Stat(filepath.Join(dir, "heap.pprof"))
+		require.NoError(t, err)
+		assert.NotZero(t, info.Size())
+
+		// The profile must have been stopped for the next one to start.
+		fxtest.New(t, fx.ProfileStartup(t.TempDir())).RequireStart().RequireStop()
+	})
+
+	t.Run("never started", func(t *testing.T) {
+		tests := []struct {
+			desc string
+			run  func(...fx.Option) error
+		}{
+			{desc: "ValidateApp", run: fx.ValidateApp},
+			{desc: "ValidateAppStrict", run: fx.ValidateAppStrict},
+			{
+				desc: "Analyze",
+				run: func(opts ...fx.Option) error {
+					_, err := fx.Analyze(opts...)
+					return err
+				},
+			},
+			{
+				desc: "PlanApp",
+				run: func(opts ...fx.Option) error {
+					_, err := fx.PlanApp(opts...)
+					return err
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				dir := filepath.Join(t.TempDir(), "startup")
+				require.NoError(t, tt.run(fx.ProfileStartup(dir)))
+
+				_, err := os.Stat(dir)
+				assert.True(t, os.IsNotExist(err), "must not capture profiles")
+
+				// No profile may be left running.
+				fxtest.New(t, fx.ProfileStartup(t.TempDir())).RequireStart().RequireStop()
+			})
+		}
+	})
+
+	t.Run("unwritable directory", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(file, nil, 0o644))
+
+		app := NewForTest(t, fx.ProfileStartup(file))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.ProfileStartup: ")
+	})
+
+	t.Run("in module", func(t *testing.T) {
+		app := NewForTest(t, fx.Module("child", fx.ProfileStartup(t.TempDir())))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			"fx.ProfileStartup Option should be passed to top-level App, not to fx.Module")
+	})
+}
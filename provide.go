@@ -56,11 +56,28 @@ import (
 // including optional parameters and named instances.
 //
 // See the documentation for [Private] for restricting access to constructors.
+// See the documentation for [Transient] for building a new value
+// for every function that depends on one.
 //
 // Constructor functions should perform as little external interaction as
 // possible, and should avoid spawning goroutines. Things like server listen
 // loops, background timer loops, and background processing goroutines should
 // instead be managed using Lifecycle callbacks.
+//
+// # Cleanup functions
+//
+// A constructor may return a cleanup function, either a func() or a
+// func(context.Context) error, after the values it produces and before
+// its error, if any. The cleanup function is not provided to the
+// application: it's appended to the Lifecycle as an OnStop hook instead.
+// It's ignored if the constructor returns a non-nil error.
+//
+//	// Constructs type *sql.DB, and closes it when the application stops.
+//	func(*Config) (*sql.DB, func(), error)
+//
+// A constructor that returns only a function provides that function.
+// Cleanup functions are not supported for constructors annotated with
+// [Annotate]; use the [OnStop] annotation for those.
 func Provide(constructors ...interface{}) Option {
 	return provideOption{
 		Targets: constructors,
@@ -68,28 +85,80 @@ func Provide(constructors ...interface{}) Option {
 	}
 }
 
+// ProvideT is a type-checked [Provide] for a single constructor
+// that builds a T from its parameter, P.
+// Constructors of the wrong shape fail to compile
+// rather than failing [New].
+//
+// P is either the constructor's only dependency,
+// or a parameter object that embeds [In] for constructors with several
+// dependencies. Use a parameter object with no fields
+// for constructors with no dependencies.
+// T may be given explicitly to check the type the constructor provides.
+//
+//	type ServerParams struct {
+//	  fx.In
+//
+//	  Config *Config
+//	  Logger *zap.Logger
+//	}
+//
+//	func NewServer(p ServerParams) (*Server, error) {
+//	  // ...
+//	}
+//
+//	fx.ProvideT[*Server](NewServer)
+//
+// T may be a result object that embeds [Out].
+// Use [Provide] for constructors that can't fail, or that return
+// several values.
+func ProvideT[T, P any](constructor func(P) (T, error)) Option {
+	return provideOption{
+		Targets: []interface{}{constructor},
+		Stack:   fxreflect.CallerStack(1, 0),
+	}
+}
+
 type provideOption struct {
 	Targets []interface{}
 	Stack   fxreflect.Stack
 }
 
 func (o provideOption) apply(mod *module) {
-	var private bool
+	var private, transient bool
 
 	targets := make([]interface{}, 0, len(o.Targets))
 	for _, target := range o.Targets {
-		if _, ok := target.(privateOption); ok {
+		switch target.(type) {
+		case privateOption:
 			private = true
-			continue
+		case transientOption:
+			transient = true
+		default:
+			targets = append(targets, target)
 		}
-		targets = append(targets, target)
 	}
 
+	if transient && mod.app.transients == nil {
+		mod.app.transients = &transients{
+			types: make(map[reflect.Type]struct{}),
+		}
+	}
 	for _, target := range targets {
+		if transient {
+			// Record the type right away: functions provided or invoked
+			// before this constructor may depend on it too.
+			if err := mod.app.transients.add(target); err != nil {
+				mod.app.err = err
+				return
+			}
+		}
 		mod.provides = append(mod.provides, provide{
-			Target:  target,
-			Stack:   o.Stack,
-			Private: private,
+			Target:     target,
+			Stack:      o.Stack,
+			Private:    private,
+			Transient:  transient,
+			IsProvided: true,
 		})
 	}
 }
@@ -97,7 +166,7 @@ func (o provideOption) apply(mod *module) {
 type privateOption struct{}
 
 // Private is an option that can be passed as an argument to [Provide] or [Supply] to
-// restrict access to the constructors being provided. Specifically,
+// restrict access to the constructors or values being provided. Specifically,
 // corresponding constructors can only be used within the current module
 // or modules the current module contains. Other modules that contain this
 // module won't be able to use the constructor.
@@ -109,6 +178,9 @@ type privateOption struct{}
 //		fx.Module("SubModule", fx.Provide(func() int { return 0 }, fx.Private)),
 //		fx.Invoke(func(a int) {}),
 //	)
+//
+// Private may also be passed to [Decorate], where it has no effect:
+// decorations are always restricted to the module they're declared in.
 var Private = privateOption{}
 
 func (o provideOption) String() string {
@@ -158,11 +230,20 @@ func runProvide(c container, p provide, opts ...dig.ProvideOption) error {
 			opts = append(opts, dig.Group(ann.Group))
 		}
 
-		if err := c.Provide(ann.Target, opts...); err != nil {
+		target := ann.Target
+		if ft := reflect.TypeOf(target); ft != nil && ft.Kind() == reflect.Func {
+			if idx := cleanupResult(ft); idx >= 0 {
+				opts = append(opts, dig.LocationForPC(reflect.ValueOf(target).Pointer()))
+				target = withCleanup(target, idx)
+			}
+		}
+
+		if err := c.Provide(target, opts...); err != nil {
 			return fmt.Errorf("fx.Provide(%v) from:\n%+vFailed: %w", ann, p.Stack, err)
 		}
 
 	default:
+		target := constructor
 		if reflect.TypeOf(constructor).Kind() == reflect.Func {
 			ft := reflect.ValueOf(constructor).Type()
 
@@ -177,9 +258,14 @@ func runProvide(c container, p provide, opts ...dig.ProvideOption) error {
 						fxreflect.FuncName(constructor), p.Stack)
 				}
 			}
+
+			if idx := cleanupResult(ft); idx >= 0 {
+				opts = append(opts, dig.LocationForPC(reflect.ValueOf(constructor).Pointer()))
+				target = withCleanup(constructor, idx)
+			}
 		}
 
-		if err := c.Provide(constructor, opts...); err != nil {
+		if err := c.Provide(target, opts...); err != nil {
 			return fmt.Errorf("fx.Provide(%v) from:\n%+vFailed: %w", fxreflect.FuncName(constructor), p.Stack, err)
 		}
 	}
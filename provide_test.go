@@ -0,0 +1,129 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestProvideT(t *testing.T) {
+	t.Parallel()
+
+	type config struct{ Name string }
+	type server struct{ Config *config }
+
+	t.Run("single dependency", func(t *testing.T) {
+		t.Parallel()
+
+		var got *server
+		app := fxtest.New(t,
+			fx.Supply(&config{Name: "foo"}),
+			fx.ProvideT[*server](func(c *config) (*server, error) {
+				return &server{Config: c}, nil
+			}),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "foo", got.Config.Name)
+	})
+
+	t.Run("parameter and result objects", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			fx.In
+
+			Config *config
+			Name   string `name:"name"`
+		}
+		type result struct {
+			fx.Out
+
+			Server *server
+			Port   int `name:"port"`
+		}
+
+		var got struct {
+			fx.In
+
+			Server *server
+			Port   int `name:"port"`
+		}
+		app := fxtest.New(t,
+			fx.Supply(&config{}, fx.Annotated{Name: "name", Target: "bar"}),
+			fx.ProvideT(func(p params) (result, error) {
+				p.Config.Name = p.Name
+				return result{Server: &server{Config: p.Config}, Port: 8080}, nil
+			}),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "bar", got.Server.Config.Name)
+		assert.Equal(t, 8080, got.Port)
+	})
+
+	t.Run("no dependencies", func(t *testing.T) {
+		t.Parallel()
+
+		var got *config
+		app := fxtest.New(t,
+			fx.ProvideT(func(struct{ fx.In }) (*config, error) {
+				return &config{Name: "baz"}, nil
+			}),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "baz", got.Name)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		sadness := errors.New("great sadness")
+		app := NewForTest(t,
+			fx.ProvideT(func(struct{ fx.In }) (*config, error) {
+				return nil, sadness
+			}),
+			fx.Invoke(func(*config) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.ErrorIs(t, err, sadness)
+	})
+
+	t.Run("string", func(t *testing.T) {
+		t.Parallel()
+
+		opt := fx.ProvideT(newProvideTConfig)
+		assert.Equal(t, "fx.Provide(go.uber.org/fx_test.newProvideTConfig())", opt.String())
+	})
+}
+
+func newProvideTConfig(struct{ fx.In }) (string, error) { return "", nil }
@@ -74,6 +74,10 @@ import (
 //	fx.Replace(
 //		fx.Annotate(os.Stderr, fx.As(new(io.Writer)))
 //	)
+//
+// Or, equivalently, with [ReplaceAs]:
+//
+//	fx.ReplaceAs[io.Writer](os.Stderr)
 func Replace(values ...interface{}) Option {
 	decorators := make([]interface{}, len(values)) // one function per value
 	types := make([]reflect.Type, len(values))
@@ -96,7 +100,34 @@ func Replace(values ...interface{}) Option {
 	}
 }
 
+// ReplaceAs replaces the value of type T in the container with value,
+// as if it had been provided using a decorator with fx.Decorate.
+// Unlike [Replace], it uses T rather than the most specific type of value,
+// so it can replace a value provided as an interface.
+//
+//	fx.ReplaceAs[Store](mockStore)
+//
+// Is equivalent to,
+//
+//	fx.Replace(fx.Annotate(mockStore, fx.As(new(Store))))
+//
+// ReplaceAs panics if T is the error type.
+func ReplaceAs[T any](value T) Option {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ == _typeOfError {
+		panic("error type passed to fx.ReplaceAs")
+	}
+
+	return replaceOption{
+		Name:    "fx.ReplaceAs",
+		Targets: []interface{}{func() T { return value }},
+		Types:   []reflect.Type{typ},
+		Stack:   fxreflect.CallerStack(1, 0),
+	}
+}
+
 type replaceOption struct {
+	Name    string // name of the option, if not fx.Replace
 	Targets []interface{}
 	Types   []reflect.Type // type of value produced by constructor[i]
 	Stack   fxreflect.Stack
@@ -118,7 +149,11 @@ func (o replaceOption) String() string {
 	for _, typ := range o.Types {
 		items = append(items, typ.String())
 	}
-	return fmt.Sprintf("fx.Replace(%s)", strings.Join(items, ", "))
+	name := o.Name
+	if name == "" {
+		name = "fx.Replace"
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(items, ", "))
 }
 
 // Returns a function that takes no parameters, and returns the given value.
@@ -21,7 +21,9 @@
 package fx_test
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -99,6 +101,37 @@ func TestReplaceSuccess(t *testing.T) {
 		defer app.RequireStart().RequireStop()
 	})
 
+	t.Run("replace an interface with ReplaceAs", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			fx.Provide(func() fmt.Stringer { return &bytes.Buffer{} }),
+			fx.Module("child",
+				fx.ReplaceAs[fmt.Stringer](stringer("B")),
+				fx.Invoke(func(s fmt.Stringer) {
+					assert.Equal(t, "B", s.String())
+				}),
+			),
+			fx.Invoke(func(s fmt.Stringer) {
+				assert.Empty(t, s.String(), "replacement must be scoped to the module")
+			}),
+		)
+		defer app.RequireStart().RequireStop()
+	})
+
+	t.Run("ReplaceAs with a nil value", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			fx.Provide(func() fmt.Stringer { return &bytes.Buffer{} }),
+			fx.ReplaceAs[fmt.Stringer](nil),
+			fx.Invoke(func(s fmt.Stringer) {
+				assert.Nil(t, s)
+			}),
+		)
+		defer app.RequireStart().RequireStop()
+	})
+
 	t.Run("replace a value group with annotate", func(t *testing.T) {
 		t.Parallel()
 
@@ -203,5 +236,16 @@ func TestReplaceFailure(t *testing.T) {
 			t,
 			func() { fx.Replace(A{}, (*B)(nil)) },
 			"a wrapped nil should not panic")
+
+		require.PanicsWithValuef(
+			t,
+			"error type passed to fx.ReplaceAs",
+			func() { fx.ReplaceAs[error](errors.New("some error")) },
+			"replacing an error should panic",
+		)
 	})
 }
+
+type stringer string
+
+func (s stringer) String() string { return string(s) }
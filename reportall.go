@@ -0,0 +1,126 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/multierr"
+)
+
+// ReportAllErrors causes [New] to report every error in the application's
+// dependency graph at once, rather than only the first one,
+// to save a round trip per error when wiring a large application.
+//
+// With ReportAllErrors, New keeps providing and decorating after
+// a constructor or decorator fails to be registered,
+// such as when it has an invalid signature or a type is provided twice.
+// Once New has failed, the functions passed to [Invoke] that haven't run
+// are checked as [ValidateApp] would, without running any constructors
+// or invoked functions, so that the dependencies they'd miss are
+// reported as well.
+//
+// The errors are combined into the one returned by [App.Err];
+// use [go.uber.org/multierr.Errors] to get them individually.
+// Handlers registered with [ErrorHook] still receive the first error only.
+// Functions passed to [Evaluate] don't run in the check,
+// so invoked functions are not checked if any options were evaluated.
+func ReportAllErrors() Option {
+	return reportAllErrorsOption{}
+}
+
+type reportAllErrorsOption struct{}
+
+func (reportAllErrorsOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.ReportAllErrors Option should be passed to top-level App, " +
+			"not to fx.Module")
+	} else {
+		m.app.reportAllErrors = true
+	}
+}
+
+func (reportAllErrorsOption) String() string {
+	return "fx.ReportAllErrors()"
+}
+
+// recordError records err, which m failed with while the application was
+// being built. failure is the error to pass to the ErrorHook handlers.
+// Unless ReportAllErrors is set, New stops at the first error.
+func (app *App) recordError(err, failure error, m *module) {
+	if app.err == nil {
+		app.failure, app.failedModule = failure, m
+	}
+	app.err = multierr.Append(app.err, err)
+}
+
+// stopped reports whether the application stopped being built
+// because of an error.
+func (app *App) stopped() bool {
+	return app.err != nil && !app.reportAllErrors
+}
+
+// remainingInvokeErrors returns the errors that the invoked functions
+// of an application built from opts would fail with, other than the first
+// invoked ones that ran, by validating the application.
+// It's used with ReportAllErrors.
+func (app *App) remainingInvokeErrors(opts []Option, ran int) error {
+	if !app.reportAllErrors || app.validate || app.parent != nil || app.evaluated {
+		return nil
+	}
+
+	var a Analysis
+	opts = append(opts[:len(opts):len(opts)],
+		validate(true),
+		analyze(&a),
+		checkOnly(),
+		WithLogger(func() fxevent.Logger { return fxevent.NopLogger }),
+	)
+	if check := New(opts...); check.err != nil {
+		// New doesn't run invoked functions if the graph failed to build.
+		_, _ = check.root.executeInvokes()
+	}
+
+	var err error
+	for i, invokeErr := range a.UnsatisfiedInvokes {
+		if a.unsatisfiedAt[i] >= ran {
+			err = multierr.Append(err, invokeErr)
+		}
+	}
+	return err
+}
+
+// checkOnly makes an App built to check another one for ReportAllErrors
+// not call error handlers.
+func checkOnly() Option {
+	return checkOnlyOption{}
+}
+
+type checkOnlyOption struct{}
+
+func (checkOnlyOption) apply(m *module) {
+	m.app.checkOnly = true
+}
+
+func (checkOnlyOption) String() string {
+	return "fx.checkOnly()"
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+	"go.uber.org/multierr"
+)
+
+func TestReportAllErrors(t *testing.T) {
+	t.Parallel()
+
+	type a struct{}
+	type b struct{}
+	type c struct{}
+
+	errorStrings := func(err error) []string {
+		var msgs []string
+		for _, err := range multierr.Errors(err) {
+			msgs = append(msgs, err.Error())
+		}
+		return msgs
+	}
+
+	t.Run("provides and invokes", func(t *testing.T) {
+		t.Parallel()
+
+		var ran bool
+		app := NewForTest(t,
+			ReportAllErrors(),
+			Provide(func() *a { return &a{} }),
+			Provide(func() *a { return &a{} }), // provided twice
+			Provide(func() {}),                 // provides nothing
+			Invoke(func(*a) { ran = true }),
+			Invoke(func(*c) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+
+		msgs := errorStrings(err)
+		require.Len(t, msgs, 3)
+		assert.Contains(t, msgs[0], "already provided")
+		assert.Contains(t, msgs[1], "must provide at least one non-error type")
+		assert.Contains(t, msgs[2], "missing type: *fx_test.c")
+		assert.False(t, ran, "invoked functions must not run in the check")
+	})
+
+	t.Run("after failed invoke", func(t *testing.T) {
+		t.Parallel()
+
+		var ran []string
+		app := NewForTest(t,
+			ReportAllErrors(),
+			Provide(func() *a { return &a{} }),
+			Invoke(func(*a) { ran = append(ran, "first") }),
+			Invoke(func(*a) error {
+				ran = append(ran, "second")
+				return errors.New("great sadness")
+			}),
+			Invoke(func(*a) { ran = append(ran, "third") }),
+			Invoke(func(*b) { ran = append(ran, "fourth") }),
+		)
+		err := app.Err()
+		require.Error(t, err)
+
+		msgs := errorStrings(err)
+		require.Len(t, msgs, 2)
+		assert.Contains(t, msgs[0], "great sadness")
+		assert.Contains(t, msgs[1], "missing type: *fx_test.b")
+		assert.Equal(t, []string{"first", "second"}, ran)
+	})
+
+	t.Run("decorators", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			ReportAllErrors(),
+			Provide(func() *a { return &a{} }),
+			Decorate(func(*a) *a { return nil }),
+			Decorate(func(*a) *a { return nil }),
+			Module("child",
+				Decorate(func(*b) *b { return nil }),
+				Decorate(func(*b) *b { return nil }),
+			),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		msgs := errorStrings(err)
+		require.Len(t, msgs, 2)
+		assert.Contains(t, msgs[0], "*fx_test.a already decorated")
+		assert.Contains(t, msgs[1], "*fx_test.b already decorated")
+	})
+
+	t.Run("error hooks receive the first error", func(t *testing.T) {
+		t.Parallel()
+
+		var handled []error
+		app := NewForTest(t,
+			ReportAllErrors(),
+			ErrorHook(errHandlerFunc(func(err error) { handled = append(handled, err) })),
+			Provide(func() *a { return &a{} }),
+			Provide(func() *a { return &a{} }),
+			Invoke(func(*c) {}),
+		)
+		require.Error(t, app.Err())
+		assert.Len(t, multierr.Errors(app.Err()), 2)
+		require.Len(t, handled, 1)
+		assert.Contains(t, handled[0].Error(), "already provided")
+	})
+
+	t.Run("first error without the option", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			Provide(func() *a { return &a{} }),
+			Provide(func() *a { return &a{} }),
+			Invoke(func(*c) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Len(t, multierr.Errors(err), 1)
+	})
+
+	t.Run("not top level", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, Module("child", ReportAllErrors()))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.ReportAllErrors Option should be passed to top-level App")
+	})
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Resolve returns the value of type T that the application built.
+// It's an escape hatch for code outside the application
+// that can't receive its dependencies through [Invoke],
+// such as legacy code that is being migrated to Fx.
+//
+//	app := fx.New(opts...)
+//	if err := app.Start(ctx); err != nil {
+//	  // ...
+//	}
+//	db, err := fx.Resolve[*sql.DB](app)
+//
+// Resolve never runs a constructor.
+// It fails if the application didn't build a value of type T in [New],
+// either because nothing provides T or because nothing depends on it,
+// and if the application failed to build.
+// Values are resolved as the top-level module sees them,
+// after its decorations, so values provided with [Private]
+// inside a module can't be resolved.
+// Named values and value groups can't be resolved.
+//
+// Prefer [Invoke] wherever possible:
+// dependencies on resolved values are invisible to Fx.
+func Resolve[T any](app *App) (T, error) {
+	var zero T
+	typ := reflect.TypeOf(&zero).Elem()
+
+	if err := app.Err(); err != nil {
+		return zero, fmt.Errorf("fx.Resolve[%v]: application failed to build: %w", typ, err)
+	}
+
+	// App.Try and App.NewScope may run constructors concurrently.
+	var value reflect.Value
+	err := app.containerLock().Do(func() (err error) {
+		if _, ok := app.builtOutputs[outputKey{Type: typ}]; !ok {
+			return errors.New("no value of this type was built: " +
+				"provide it, and depend on it from a function passed to fx.Invoke")
+		}
+		value, err = app.resolve(typ)
+		return err
+	})
+	if err != nil {
+		return zero, fmt.Errorf("fx.Resolve[%v]: %w", typ, err)
+	}
+	// The value is nil if T is an interface and its constructor returned nil.
+	v, _ := value.Interface().(T)
+	return v, nil
+}
+
+// recordBuilt records that a constructor producing outputs has run.
+// Constructors run in New, or with the container locked.
+func (app *App) recordBuilt(outputs []outputKey) {
+	if app.builtOutputs == nil {
+		app.builtOutputs = make(map[outputKey]struct{})
+	}
+	for _, o := range outputs {
+		app.builtOutputs[o] = struct{}{}
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	type config struct{ Name string }
+
+	t.Run("built value", func(t *testing.T) {
+		t.Parallel()
+
+		var built *config
+		app := fxtest.New(t,
+			fx.Provide(func() *config { return &config{Name: "built"} }),
+			fx.Populate(&built),
+		)
+		defer app.RequireStart().RequireStop()
+
+		got, err := fx.Resolve[*config](app.App)
+		require.NoError(t, err)
+		assert.Same(t, built, got)
+	})
+
+	t.Run("decorated, supplied, and interface values", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			fx.Supply(&config{Name: "supplied"}),
+			fx.Decorate(func(c *config) *config {
+				return &config{Name: c.Name + " and decorated"}
+			}),
+			fx.Provide(func(c *config) io.Reader { return strings.NewReader(c.Name) }),
+			fx.Invoke(func(io.Reader) {}),
+		)
+
+		c, err := fx.Resolve[*config](app.App)
+		require.NoError(t, err)
+		assert.Equal(t, "supplied and decorated", c.Name)
+
+		r, err := fx.Resolve[io.Reader](app.App)
+		require.NoError(t, err)
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "supplied and decorated", string(b))
+	})
+
+	t.Run("never built", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			fx.Provide(func() *config {
+				assert.Fail(t, "constructor should not run")
+				return &config{}
+			}),
+		)
+
+		_, err := fx.Resolve[*config](app.App)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.Resolve[*fx_test.config]: no value of this type was built")
+
+		_, err = fx.Resolve[io.Reader](app.App)
+		assert.ErrorContains(t, err, "fx.Resolve[io.Reader]: no value of this type was built")
+	})
+
+	t.Run("type of the same name built", func(t *testing.T) {
+		t.Parallel()
+
+		// Both types are named fx_test.config.
+		type built = config
+		type config struct{ Other string }
+		app := fxtest.New(t,
+			fx.Provide(func() *built { return &built{} }),
+			fx.Provide(func() *config {
+				assert.Fail(t, "constructor should not run")
+				return &config{}
+			}),
+			fx.Invoke(func(*built) {}),
+		)
+
+		_, err := fx.Resolve[*config](app.App)
+		assert.ErrorContains(t, err, "no value of this type was built")
+	})
+
+	t.Run("private", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			fx.Module("child",
+				fx.Provide(fx.Private, func() *config { return &config{} }),
+				fx.Invoke(func(*config) {}),
+			),
+		)
+
+		_, err := fx.Resolve[*config](app.App)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.Resolve[*fx_test.config]: ")
+		assert.Contains(t, err.Error(), "missing type: *fx_test.config")
+	})
+
+	t.Run("failed application", func(t *testing.T) {
+		t.Parallel()
+
+		sadness := errors.New("great sadness")
+		app := NewForTest(t,
+			fx.Provide(func() *config { return &config{} }),
+			fx.Invoke(func(*config) error { return sadness }),
+		)
+
+		_, err := fx.Resolve[*config](app)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, sadness)
+		assert.Contains(t, err.Error(), "application failed to build")
+	})
+}
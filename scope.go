@@ -0,0 +1,157 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.uber.org/fx/internal/fxreflect"
+	"go.uber.org/multierr"
+)
+
+// Scope is a short-lived child scope of an application,
+// created with [App.NewScope].
+type Scope struct {
+	app       *App
+	lifecycle *lifecycleWrapper
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewScope creates a child scope of the application, applies the given
+// options to it, and runs any functions given to [Invoke] within it.
+// Use it for values that live shorter than the application,
+// such as those specific to a request or a job.
+//
+//	scope, err := app.NewScope(
+//		fx.Supply(req),
+//		fx.Provide(NewRequestLogger),
+//		fx.Invoke(handle),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	defer scope.Close(ctx)
+//
+// The scope behaves like a [Module] nested under the application:
+// it can depend on any type the application exports, sharing the
+// application's values, and it may shadow them.
+// Values provided within the scope, including values exported by modules
+// nested in it, stay in the scope and are never visible to the
+// application or to other scopes.
+//
+// Constructors and invoked functions within the scope receive a [Lifecycle]
+// of the scope's own: its OnStart hooks run before NewScope returns,
+// and its OnStop hooks, including cleanup functions returned by
+// constructors, run when the scope is closed with [Scope.Close].
+// The hooks are bound by the application's start timeout.
+// If NewScope fails, the OnStop hooks of the OnStart hooks that succeeded
+// run before it returns, bound by the stop timeout.
+//
+// Scopes may be created concurrently, but Fx creates them one at a time,
+// as it runs trials with [App.Try]. Functions run while a scope is created
+// get an error if they call NewScope, App.Try, [App.Resolve],
+// or [App.HealthCheck] on the application.
+// Each scope has a container of its own, which the application doesn't
+// reference: the values of a scope can be garbage collected once it's
+// closed and no longer reachable.
+//
+// NewScope returns the application's initialization error, if any,
+// without creating a scope.
+func (app *App) NewScope(opts ...Option) (*Scope, error) {
+	s, err := app.newScope(fxreflect.CallerStack(1, 2)[0], opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := app.clock.WithTimeout(app.rootContext(), app.StartTimeout())
+	defer cancel()
+	if err := s.lifecycle.Start(ctx); err != nil {
+		return nil, multierr.Append(err, s.stop())
+	}
+	return s, nil
+}
+
+func (app *App) newScope(caller fxreflect.Frame, opts []Option) (*Scope, error) {
+	mu := app.containerLock()
+	if err := mu.Lock(); err != nil {
+		return nil, err
+	}
+	defer mu.Unlock()
+
+	if app.newErr != nil {
+		return nil, app.newErr
+	}
+
+	s := &Scope{app: app, lifecycle: app.newLifecycle()}
+	mod := &module{
+		name:   "fx.Scope",
+		parent: app.root,
+		trace: append(
+			[]string{fmt.Sprintf("%v (fx.NewScope)", caller)},
+			app.root.trace...,
+		),
+		app:   app,
+		trial: "App.NewScope",
+	}
+
+	// As with Try, errors are recorded on the App while the options
+	// are applied. They belong to the scope only.
+	err := useContainer(func() error {
+		return app.try(mod, s.lifecycle, opts)
+	})
+	app.err = nil
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// errScopeClosed is returned by Scope.Close if the scope was already closed.
+var errScopeClosed = errors.New("scope already closed")
+
+// Close closes the scope, running the OnStop hooks appended to its
+// [Lifecycle] in reverse order, bound by the given context.
+// It returns their errors combined.
+//
+// Close returns an error if the scope was already closed.
+func (s *Scope) Close(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errScopeClosed
+	}
+	s.closed = true
+	return s.lifecycle.Stop(ctx)
+}
+
+// stop runs the OnStop hooks of a scope that failed to start,
+// bound by the application's stop timeout.
+func (s *Scope) stop() error {
+	ctx, cancel := s.app.clock.WithTimeout(s.app.rootContext(), s.app.StopTimeout())
+	defer cancel()
+	return s.Close(ctx)
+}
@@ -0,0 +1,353 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestNewScope(t *testing.T) {
+	t.Parallel()
+
+	type config struct{ name string }
+	type request struct{ id int }
+	type handler struct {
+		cfg *config
+		req *request
+	}
+
+	newHandler := func(cfg *config, req *request) *handler {
+		return &handler{cfg: cfg, req: req}
+	}
+
+	t.Run("shares application values", func(t *testing.T) {
+		t.Parallel()
+
+		var configs int
+		app := fxtest.New(t, Provide(func() *config {
+			configs++
+			return &config{name: "foo"}
+		}))
+		defer app.RequireStart().RequireStop()
+
+		var handlers []*handler
+		for i := 0; i < 2; i++ {
+			scope, err := app.NewScope(
+				Supply(&request{id: i}),
+				Provide(newHandler),
+				Invoke(func(h *handler) { handlers = append(handlers, h) }),
+			)
+			require.NoError(t, err)
+			require.NoError(t, scope.Close(context.Background()))
+		}
+
+		require.Len(t, handlers, 2)
+		assert.Equal(t, 1, configs, "application values must be shared")
+		assert.Same(t, handlers[0].cfg, handlers[1].cfg)
+		assert.Equal(t, 0, handlers[0].req.id)
+		assert.Equal(t, 1, handlers[1].req.id)
+	})
+
+	t.Run("application values of every kind", func(t *testing.T) {
+		t.Parallel()
+
+		type limiter struct{ id int }
+
+		var limiters int
+		app := fxtest.New(t,
+			Provide(
+				Annotate(func() *config { return &config{name: "named"} }, ResultTags(`name:"primary"`)),
+				Annotate(func() *config { return &config{name: "app"} }, ResultTags(`group:"configs"`)),
+			),
+			Provide(func() *limiter {
+				limiters++
+				return &limiter{id: limiters}
+			}, Transient),
+			Supply(&request{id: 1}),
+			Decorate(func(r *request) *request { return &request{id: r.id + 1} }),
+		)
+		defer app.RequireStart().RequireStop()
+
+		type params struct {
+			In
+
+			Primary *config   `name:"primary"`
+			Configs []*config `group:"configs"`
+			Request *request
+			L1, L2  *limiter
+		}
+		scope, err := app.NewScope(
+			Provide(Annotate(func() *config { return &config{name: "scope"} }, ResultTags(`group:"configs"`))),
+			Invoke(func(p params) {
+				assert.Equal(t, "named", p.Primary.name)
+				names := make([]string, 0, len(p.Configs))
+				for _, c := range p.Configs {
+					names = append(names, c.name)
+				}
+				assert.ElementsMatch(t, []string{"app", "scope"}, names)
+				assert.Equal(t, 2, p.Request.id, "decorations of the application must apply")
+				assert.NotSame(t, p.L1, p.L2)
+			}),
+		)
+		require.NoError(t, err)
+		require.NoError(t, scope.Close(context.Background()))
+		assert.Equal(t, 2, limiters)
+	})
+
+	t.Run("values are released", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t, Supply(&config{}))
+		defer app.RequireStart().RequireStop()
+
+		released := make(chan struct{})
+		scope, err := app.NewScope(
+			Provide(func() *request {
+				r := &request{}
+				runtime.SetFinalizer(r, func(*request) { close(released) })
+				return r
+			}),
+			Invoke(func(*request) {}),
+		)
+		require.NoError(t, err)
+		require.NoError(t, scope.Close(context.Background()))
+		scope = nil
+
+		timeout := time.After(5 * time.Second)
+		for {
+			runtime.GC()
+			select {
+			case <-released:
+				return
+			case <-timeout:
+				t.Fatal("value of a closed scope was not garbage collected")
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	})
+
+	t.Run("provided values do not leak", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t, Supply(&config{}))
+		defer app.RequireStart().RequireStop()
+
+		scope, err := app.NewScope(Module("nested", Supply(&request{})))
+		require.NoError(t, err)
+		defer scope.Close(context.Background())
+
+		err = app.Try(Invoke(func(*request) {}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing type: *fx_test.request")
+
+		_, err = app.NewScope(Invoke(func(*request) {}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing type: *fx_test.request")
+	})
+
+	t.Run("hooks and cleanup", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t)
+		defer app.RequireStart().RequireStop()
+
+		var events []string
+		scope, err := app.NewScope(
+			Provide(func(lc Lifecycle) *request {
+				lc.Append(Hook{
+					OnStart: func(context.Context) error {
+						events = append(events, "start")
+						return nil
+					},
+					OnStop: func(context.Context) error {
+						events = append(events, "stop")
+						return nil
+					},
+				})
+				return &request{}
+			}),
+			Provide(func(*request) (*config, func()) {
+				return &config{}, func() { events = append(events, "cleanup") }
+			}),
+			Invoke(func(*config) {}),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"start"}, events)
+
+		require.NoError(t, scope.Close(context.Background()))
+		assert.Equal(t, []string{"start", "cleanup", "stop"}, events)
+
+		assert.EqualError(t, scope.Close(context.Background()), "scope already closed")
+		assert.Len(t, events, 3, "hooks must run once")
+	})
+
+	t.Run("invoke error", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t, Supply(&config{}))
+		defer app.RequireStart().RequireStop()
+
+		scope, err := app.NewScope(Invoke(func(*config) error {
+			return errors.New("great sadness")
+		}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+		assert.Nil(t, scope)
+		assert.NoError(t, app.Err(), "application must be unaffected")
+	})
+
+	t.Run("start error", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t)
+		defer app.RequireStart().RequireStop()
+
+		var stopped bool
+		_, err := app.NewScope(Invoke(func(lc Lifecycle) {
+			lc.Append(Hook{
+				OnStop: func(context.Context) error {
+					stopped = true
+					return nil
+				},
+			})
+			lc.Append(Hook{
+				OnStart: func(context.Context) error {
+					return errors.New("great sadness")
+				},
+			})
+		}))
+		assert.EqualError(t, err, "great sadness")
+		assert.True(t, stopped, "started hooks must be stopped")
+	})
+
+	t.Run("top-level option", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t)
+		defer app.RequireStart().RequireStop()
+
+		_, err := app.NewScope(StartTimeout(time.Second))
+		assert.EqualError(t, err, "fx.StartTimeout Option should be passed to top-level App, "+
+			"not to App.NewScope")
+	})
+
+	t.Run("concurrent scopes", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t, Provide(func() *config { return &config{} }))
+		defer app.RequireStart().RequireStop()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			i := i
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				scope, err := app.NewScope(
+					Supply(&request{id: i}),
+					Provide(newHandler),
+					Invoke(func(h *handler) { assert.Equal(t, i, h.req.id) }),
+				)
+				if assert.NoError(t, err) {
+					assert.NoError(t, scope.Close(context.Background()))
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("failure leaves app unaffected", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t)
+		defer app.RequireStart().RequireStop()
+
+		_, err := app.NewScope(Invoke(func() error { return errors.New("great sadness") }))
+		require.Error(t, err)
+		assert.NoError(t, app.Err())
+
+		_, err = app.NewScope(Invoke(func() {}))
+		assert.NoError(t, err)
+	})
+
+	t.Run("concurrently with the application", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			Provide(func() *config { return &config{} }),
+			Invoke(func(*config) {}),
+		)
+		defer app.RequireStart().RequireStop()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			i := i
+			wg.Add(3)
+			go func() {
+				defer wg.Done()
+
+				scope, err := app.NewScope(
+					Supply(&request{id: i}),
+					Provide(newHandler),
+					Invoke(func(*handler) {}),
+				)
+				if assert.NoError(t, err) {
+					assert.NoError(t, scope.Close(context.Background()))
+				}
+			}()
+			go func() {
+				defer wg.Done()
+
+				_, err := Resolve[*config](app.App)
+				assert.NoError(t, err)
+			}()
+			go func() {
+				defer wg.Done()
+
+				_, err := app.HealthCheck(context.Background())
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("app error", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, Invoke(func() error { return errors.New("great sadness") }))
+
+		scope, err := app.NewScope()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+		assert.Nil(t, scope)
+	})
+}
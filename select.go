@@ -0,0 +1,204 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// Select provides a value of the type pointed to by target, built by one of
+// several candidate constructors. The candidate is picked at runtime by
+// looking up a string value named selector in the container.
+//
+// For example, given:
+//
+//	fx.Supply(fx.Annotated{Name: "store", Target: "sql"}),
+//	fx.Select(new(Store), "store", map[string]interface{}{
+//		"memory": NewMemoryStore,
+//		"sql":    NewSQLStore,
+//	}),
+//
+// A Store will be provided using NewSQLStore.
+// Only the selected constructor is called,
+// and only its dependencies are requested from the container.
+//
+// Each constructor must be a function that returns a value assignable to
+// the target type, optionally followed by an error.
+// Constructors may accept any dependencies, including [In] structs.
+//
+// If the selector value does not match any of the constructors,
+// the construction of the target type fails with an error
+// listing the accepted values.
+//
+// Because the selected constructor is only known at runtime,
+// its dependencies are not visible to [ValidateApp] or in [DotGraph].
+func Select(target interface{}, selector string, constructors map[string]interface{}) Option {
+	return selectOption{
+		Target:       target,
+		Selector:     selector,
+		Constructors: constructors,
+		Stack:        fxreflect.CallerStack(1, 0),
+	}
+}
+
+type selectOption struct {
+	Target       interface{}
+	Selector     string
+	Constructors map[string]interface{}
+	Stack        fxreflect.Stack
+}
+
+func (o selectOption) apply(m *module) {
+	typ, err := o.validate()
+	if err != nil {
+		m.app.err = err
+		return
+	}
+
+	m.provides = append(m.provides, provide{
+		Target: Annotate(o.newConstructor(m, typ), ParamTags(fmt.Sprintf("name:%q", o.Selector))),
+		Stack:  o.Stack,
+	})
+}
+
+func (o selectOption) String() string {
+	names := o.names()
+	items := make([]string, len(names))
+	for i, name := range names {
+		items[i] = fmt.Sprintf("%v=%v", name, fxreflect.FuncName(o.Constructors[name]))
+	}
+
+	typ := reflect.TypeOf(o.Target)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return fmt.Sprintf("fx.Select(%v, %q, %v)", typ, o.Selector, strings.Join(items, ", "))
+}
+
+// names returns the accepted selector values in sorted order.
+func (o selectOption) names() []string {
+	names := make([]string, 0, len(o.Constructors))
+	for name := range o.Constructors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validate verifies that every constructor produces the target type
+// and returns that type.
+func (o selectOption) validate() (reflect.Type, error) {
+	typ := reflect.TypeOf(o.Target)
+	if typ == nil || typ.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("fx.Select: target must be a pointer to a type, got %T", o.Target)
+	}
+	typ = typ.Elem()
+
+	if len(o.Constructors) == 0 {
+		return nil, fmt.Errorf("fx.Select: no constructors given for %v", typ)
+	}
+
+	for _, name := range o.names() {
+		ft := reflect.TypeOf(o.Constructors[name])
+		if ft == nil || ft.Kind() != reflect.Func {
+			return nil, fmt.Errorf("fx.Select: constructor for %q must be a function, got %T",
+				name, o.Constructors[name])
+		}
+
+		switch {
+		case ft.NumOut() == 1 && ft.Out(0).AssignableTo(typ):
+		case ft.NumOut() == 2 && ft.Out(0).AssignableTo(typ) && ft.Out(1) == _typeOfError:
+		default:
+			return nil, fmt.Errorf("fx.Select: constructor for %q must return %v or (%v, error), got %v",
+				name, typ, typ, ft)
+		}
+	}
+
+	return typ, nil
+}
+
+// newConstructor builds a function that accepts the selector value and
+// returns typ, invoking the selected constructor within m's scope.
+func (o selectOption) newConstructor(m *module, typ reflect.Type) interface{} {
+	ft := reflect.FuncOf(
+		[]reflect.Type{reflect.TypeOf("")},
+		[]reflect.Type{typ, _typeOfError},
+		false,
+	)
+	fv := reflect.MakeFunc(ft, func(args []reflect.Value) []reflect.Value {
+		name := args[0].String()
+		ctor, ok := o.Constructors[name]
+		if !ok {
+			err := fmt.Errorf("fx.Select: unknown %v %q for %v, must be one of %q",
+				o.Selector, name, typ, o.names())
+			return []reflect.Value{reflect.Zero(typ), reflect.ValueOf(&err).Elem()}
+		}
+
+		result, err := o.call(m, ctor)
+		if err != nil {
+			return []reflect.Value{reflect.Zero(typ), reflect.ValueOf(&err).Elem()}
+		}
+		out := reflect.New(typ).Elem()
+		out.Set(result)
+		return []reflect.Value{out, _nilError}
+	})
+	return fv.Interface()
+}
+
+// call invokes ctor in m's scope so that only its own dependencies are
+// resolved, and returns the value it produced.
+func (o selectOption) call(m *module, ctor interface{}) (reflect.Value, error) {
+	cv := reflect.ValueOf(ctor)
+	ct := cv.Type()
+
+	in := make([]reflect.Type, ct.NumIn())
+	for i := range in {
+		in[i] = ct.In(i)
+	}
+
+	var result reflect.Value
+	invoke := reflect.MakeFunc(
+		reflect.FuncOf(in, []reflect.Type{_typeOfError}, ct.IsVariadic()),
+		func(args []reflect.Value) []reflect.Value {
+			var out []reflect.Value
+			if ct.IsVariadic() {
+				out = cv.CallSlice(args)
+			} else {
+				out = cv.Call(args)
+			}
+			result = out[0]
+			if len(out) > 1 && !out[1].IsNil() {
+				return out[1:]
+			}
+			return []reflect.Value{_nilError}
+		},
+	)
+
+	if err := m.scope.Invoke(invoke.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	return result, nil
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+type selectStore interface {
+	Name() string
+}
+
+type memoryStore struct{}
+
+func (memoryStore) Name() string { return "memory" }
+
+type sqlStore struct{ dsn string }
+
+func (s *sqlStore) Name() string { return "sql:" + s.dsn }
+
+func TestSelect(t *testing.T) {
+	t.Parallel()
+
+	type dsn string
+
+	newStores := func(called map[string]bool) map[string]interface{} {
+		return map[string]interface{}{
+			"memory": func() selectStore {
+				called["memory"] = true
+				return memoryStore{}
+			},
+			"sql": func(d dsn) (*sqlStore, error) {
+				called["sql"] = true
+				return &sqlStore{dsn: string(d)}, nil
+			},
+		}
+	}
+
+	tests := []struct {
+		desc     string
+		selector string
+		want     string
+	}{
+		{desc: "memory", selector: "memory", want: "memory"},
+		{desc: "sql", selector: "sql", want: "sql:db"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			called := make(map[string]bool)
+			var store selectStore
+			app := fxtest.New(t,
+				Supply(
+					Annotated{Name: "store", Target: tt.selector},
+					dsn("db"),
+				),
+				Select(new(selectStore), "store", newStores(called)),
+				Populate(&store),
+			)
+			defer app.RequireStart().RequireStop()
+
+			require.NotNil(t, store)
+			assert.Equal(t, tt.want, store.Name())
+			assert.Equal(t, map[string]bool{tt.selector: true}, called,
+				"only the selected constructor must be called")
+		})
+	}
+
+	t.Run("dependencies of unselected constructors", func(t *testing.T) {
+		t.Parallel()
+
+		// dsn is not provided, but it is only needed by "sql".
+		var store selectStore
+		app := fxtest.New(t,
+			Supply(Annotated{Name: "store", Target: "memory"}),
+			Select(new(selectStore), "store", newStores(make(map[string]bool))),
+			Populate(&store),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "memory", store.Name())
+	})
+
+	t.Run("unknown selector", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			Supply(Annotated{Name: "store", Target: "redis"}),
+			Select(new(selectStore), "store", newStores(make(map[string]bool))),
+			Invoke(func(selectStore) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			`fx.Select: unknown store "redis" for fx_test.selectStore, must be one of ["memory" "sql"]`)
+	})
+
+	t.Run("constructor error", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			Supply(Annotated{Name: "store", Target: "broken"}),
+			Select(new(selectStore), "store", map[string]interface{}{
+				"broken": func() (selectStore, error) {
+					return nil, errors.New("great sadness")
+				},
+			}),
+			Invoke(func(selectStore) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+	})
+
+	t.Run("invalid constructors", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc         string
+			target       interface{}
+			constructors map[string]interface{}
+			wantErr      string
+		}{
+			{
+				desc:         "target not a pointer",
+				target:       memoryStore{},
+				constructors: map[string]interface{}{"memory": func() memoryStore { return memoryStore{} }},
+				wantErr:      "fx.Select: target must be a pointer to a type, got fx_test.memoryStore",
+			},
+			{
+				desc:    "no constructors",
+				target:  new(selectStore),
+				wantErr: "fx.Select: no constructors given for fx_test.selectStore",
+			},
+			{
+				desc:         "not a function",
+				target:       new(selectStore),
+				constructors: map[string]interface{}{"memory": memoryStore{}},
+				wantErr:      `fx.Select: constructor for "memory" must be a function, got fx_test.memoryStore`,
+			},
+			{
+				desc:         "wrong result type",
+				target:       new(selectStore),
+				constructors: map[string]interface{}{"memory": func() string { return "" }},
+				wantErr:      `fx.Select: constructor for "memory" must return fx_test.selectStore or (fx_test.selectStore, error), got func() string`,
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := NewForTest(t, Select(tt.target, "store", tt.constructors))
+				err := app.Err()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+
+	t.Run("in module", func(t *testing.T) {
+		t.Parallel()
+
+		var store selectStore
+		app := fxtest.New(t,
+			Module("stores",
+				Supply(
+					Annotated{Name: "store", Target: "sql"},
+					dsn("child"),
+				),
+				Select(new(selectStore), "store", newStores(make(map[string]bool))),
+				Populate(&store),
+			),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "sql:child", store.Name())
+	})
+}
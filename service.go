@@ -0,0 +1,62 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import "fmt"
+
+// WindowsService makes [App.Run] register the application with the Windows
+// Service Control Manager under the given service name when the process
+// was started as a Windows service.
+//
+// The application reports that it is starting while its OnStart hooks
+// run, that it is running once they have succeeded, and that it is
+// stopping while its OnStop hooks run.
+// Stop and Shutdown requests from the Service Control Manager shut the
+// application down as [Shutdowner.Shutdown] does.
+// If the application exits with a non-zero exit code,
+// it's reported to the Service Control Manager as a service-specific
+// exit code.
+//
+// WindowsService has no effect on other operating systems,
+// or when the process wasn't started by the Service Control Manager,
+// such as when it's run from a terminal.
+// In those cases, App.Run behaves as usual.
+func WindowsService(name string) Option {
+	return windowsServiceOption(name)
+}
+
+type windowsServiceOption string
+
+func (o windowsServiceOption) apply(m *module) {
+	switch {
+	case m.parent != nil:
+		m.app.err = fmt.Errorf("fx.WindowsService Option should be passed to top-level App, " +
+			"not to fx.Module")
+	case len(o) == 0:
+		m.app.err = fmt.Errorf("fx.WindowsService: service name must not be empty")
+	default:
+		m.app.serviceName = string(o)
+	}
+}
+
+func (o windowsServiceOption) String() string {
+	return fmt.Sprintf("fx.WindowsService(%q)", string(o))
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestWindowsService(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not a service", func(t *testing.T) {
+		t.Parallel()
+
+		// Tests never run under the Service Control Manager,
+		// so the application runs as usual.
+		var shutdowner fx.Shutdowner
+		app := fxtest.New(t,
+			fx.WindowsService("myservice"),
+			fx.Populate(&shutdowner),
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StartHook(func() error {
+					return shutdowner.Shutdown()
+				}))
+			}),
+		)
+		app.Run()
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc    string
+			give    fx.Option
+			wantErr string
+		}{
+			{
+				desc:    "empty name",
+				give:    fx.WindowsService(""),
+				wantErr: "fx.WindowsService: service name must not be empty",
+			},
+			{
+				desc: "in module",
+				give: fx.Module("child", fx.WindowsService("myservice")),
+				wantErr: "fx.WindowsService Option should be passed to top-level App, " +
+					"not to fx.Module",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := NewForTest(t, fx.NopLogger, tt.give)
+				err := app.Err()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+}
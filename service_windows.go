@@ -0,0 +1,124 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:build windows
+// +build windows
+
+package fx
+
+import (
+	"golang.org/x/sys/windows/svc"
+)
+
+// runService runs the application as a Windows service
+// if it was started by the Service Control Manager,
+// reporting whether it did so.
+func (app *App) runService() (exitCode int, ok bool) {
+	if len(app.serviceName) == 0 {
+		return 0, false
+	}
+	if isService, err := svc.IsWindowsService(); err != nil || !isService {
+		return 0, false
+	}
+
+	h := serviceHandler{app: app}
+	if err := svc.Run(app.serviceName, &h); err != nil {
+		return 1, true
+	}
+	return h.exitCode, true
+}
+
+// serviceHandler runs an App on behalf of the Service Control Manager.
+type serviceHandler struct {
+	app      *App
+	exitCode int
+}
+
+var _ svc.Handler = (*serviceHandler)(nil)
+
+func (h *serviceHandler) Execute(
+	_ []string,
+	requests <-chan svc.ChangeRequest,
+	status chan<- svc.Status,
+) (svcSpecificEC bool, exitCode uint32) {
+	status <- svc.Status{State: svc.StartPending}
+
+	// Execute returning reports the service as stopped.
+	// Status updates must not be sent after that.
+	done := make(chan struct{})
+	defer close(done)
+
+	h.exitCode = h.app.run(func() <-chan ShutdownSignal {
+		// run waits on this once the application has started.
+		stopping := make(chan ShutdownSignal, 1)
+		go h.serve(requests, status, stopping, done)
+		return stopping
+	})
+	if h.exitCode != 0 {
+		return true, uint32(h.exitCode)
+	}
+	return false, 0
+}
+
+// serve reports the running application to the Service Control Manager
+// and relays its requests until Execute returns.
+// It forwards the shutdown signal to stopping
+// once it has reported that the application is stopping.
+func (h *serviceHandler) serve(
+	requests <-chan svc.ChangeRequest,
+	status chan<- svc.Status,
+	stopping chan<- ShutdownSignal,
+	done <-chan struct{},
+) {
+	current := svc.Status{
+		State:   svc.Running,
+		Accepts: svc.AcceptStop | svc.AcceptShutdown,
+	}
+	report := func(s svc.Status) {
+		select {
+		case status <- s:
+		case <-done:
+		}
+	}
+	report(current)
+
+	wait := h.app.Wait()
+	for {
+		select {
+		case <-done:
+			return
+
+		case sig := <-wait:
+			wait = nil
+			current = svc.Status{State: svc.StopPending}
+			report(current)
+			stopping <- sig
+
+		case req := <-requests:
+			switch req.Cmd {
+			case svc.Interrogate:
+				report(current)
+			case svc.Stop, svc.Shutdown:
+				// Errors mean a shutdown is already under way.
+				_ = h.app.receivers.Broadcast(ShutdownSignal{Signal: _sigTERM})
+			}
+		}
+	}
+}
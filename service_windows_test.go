@@ -0,0 +1,105 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:build windows
+// +build windows
+
+package fx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/windows/svc"
+)
+
+func TestServiceHandler(t *testing.T) {
+	t.Parallel()
+
+	type result struct {
+		svcSpecificEC bool
+		exitCode      uint32
+	}
+
+	execute := func(app *App) (chan<- svc.ChangeRequest, <-chan svc.Status, <-chan result) {
+		requests := make(chan svc.ChangeRequest)
+		status := make(chan svc.Status)
+		done := make(chan result, 1)
+		go func() {
+			h := serviceHandler{app: app}
+			ec, code := h.Execute(nil, requests, status)
+			done <- result{ec, code}
+		}()
+		return requests, status, done
+	}
+
+	t.Run("stop request", func(t *testing.T) {
+		t.Parallel()
+
+		var stopped bool
+		app := New(NopLogger, Invoke(func(lc Lifecycle) {
+			lc.Append(StopHook(func() { stopped = true }))
+		}))
+		requests, status, done := execute(app)
+
+		assert.Equal(t, svc.StartPending, (<-status).State)
+		running := <-status
+		assert.Equal(t, svc.Running, running.State)
+		assert.Equal(t, svc.AcceptStop|svc.AcceptShutdown, running.Accepts)
+
+		requests <- svc.ChangeRequest{Cmd: svc.Interrogate}
+		assert.Equal(t, svc.Running, (<-status).State)
+
+		requests <- svc.ChangeRequest{Cmd: svc.Stop}
+		assert.Equal(t, svc.StopPending, (<-status).State)
+		assert.Equal(t, result{}, <-done)
+		assert.True(t, stopped, "OnStop hooks must run")
+	})
+
+	t.Run("exit code", func(t *testing.T) {
+		t.Parallel()
+
+		var shutdowner Shutdowner
+		app := New(NopLogger, Populate(&shutdowner))
+		_, status, done := execute(app)
+
+		assert.Equal(t, svc.StartPending, (<-status).State)
+		assert.Equal(t, svc.Running, (<-status).State)
+		assert.NoError(t, shutdowner.Shutdown(ExitCode(3)))
+		assert.Equal(t, svc.StopPending, (<-status).State)
+		assert.Equal(t, result{true, 3}, <-done)
+	})
+
+	t.Run("start failure", func(t *testing.T) {
+		t.Parallel()
+
+		app := New(NopLogger, Invoke(func(lc Lifecycle) {
+			lc.Append(Hook{
+				OnStart: func(context.Context) error { return errors.New("great sadness") },
+			})
+		}))
+		_, status, done := execute(app)
+
+		assert.Equal(t, svc.StartPending, (<-status).State)
+		assert.Equal(t, result{true, 1}, <-done)
+	})
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+
+	"go.uber.org/fx/internal/fxreflect"
+	"go.uber.org/multierr"
+)
+
+// NoShadowing causes [New] to fail if a type provided to a [Module] shadows
+// the same type provided to one of its ancestors, reporting every such
+// conflict.
+//
+// A module shadows a type when it provides it with [Private] while an
+// ancestor, or the application itself, also provides it:
+// the module and its descendants see the module's value,
+// while the rest of the application sees the ancestor's.
+// For example, the following fails.
+//
+//	fx.New(
+//		fx.NoShadowing(),
+//		fx.Provide(NewConfig),
+//		fx.Module("server",
+//			fx.Provide(NewTestConfig, fx.Private),
+//		),
+//	)
+//
+// Modules that are not nested within each other never shadow each other,
+// so sibling modules may each privately provide the same type.
+// A type a module provides without [Private] is provided to the application,
+// so a sibling that privately provides the same type shadows it.
+// Value groups are never considered shadowed.
+func NoShadowing() Option {
+	return noShadowingOption{}
+}
+
+type noShadowingOption struct{}
+
+func (noShadowingOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.NoShadowing Option should be passed to top-level App, " +
+			"not to fx.Module")
+	} else {
+		m.app.noShadowing = true
+	}
+}
+
+func (noShadowingOption) String() string {
+	return "fx.NoShadowing()"
+}
+
+// providedOutput is a type provided to the scope of a module.
+type providedOutput struct {
+	outputKey
+
+	Stack fxreflect.Stack
+}
+
+// recordProvidedOutputs records the outputs provided to the scope of m
+// so that NoShadowing can check them,
+// and so that App.Try and App.NewScope can build those of the root.
+// Exported outputs are provided to the root of the container,
+// so they are recorded on the root module.
+func (m *module) recordProvidedOutputs(outputs []outputKey, stack fxreflect.Stack, export bool) {
+	if export {
+		m = m.app.root
+	}
+	for _, o := range outputs {
+		m.provided = append(m.provided, providedOutput{outputKey: o, Stack: stack})
+	}
+}
+
+// checkShadowing reports an error for every type provided to the scope of
+// m or its descendants that shadows a type provided to an ancestor scope.
+func (m *module) checkShadowing() error {
+	var err error
+	for _, p := range m.provided {
+		if len(p.Group) > 0 {
+			continue
+		}
+		for anc := m.parent; anc != nil; anc = anc.parent {
+			shadowed, ok := anc.providedOutput(p.outputKey)
+			if !ok {
+				continue
+			}
+			err = multierr.Append(err, fmt.Errorf(
+				"fx.NoShadowing: %v provided to %v at %v shadows %v provided to %v at %v",
+				p.outputKey, m.scopeName(), p.Stack[0],
+				shadowed.outputKey, anc.scopeName(), shadowed.Stack[0]))
+			break
+		}
+	}
+	for _, mod := range m.modules {
+		err = multierr.Append(err, mod.checkShadowing())
+	}
+	return err
+}
+
+// providedOutput returns the output with the given key provided to the
+// scope of m, if any.
+func (m *module) providedOutput(key outputKey) (providedOutput, bool) {
+	for _, p := range m.provided {
+		if p.outputKey == key {
+			return p, true
+		}
+	}
+	return providedOutput{}, false
+}
+
+// scopeName describes the scope of m for use in error messages.
+func (m *module) scopeName() string {
+	if m.parent == nil {
+		return "the application"
+	}
+	return fmt.Sprintf("module %q", m.name)
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/multierr"
+)
+
+func TestNoShadowing(t *testing.T) {
+	t.Parallel()
+
+	newString := func() string { return "" }
+
+	t.Run("shadowed", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc string
+			opts []Option
+			want []string
+		}{
+			{
+				desc: "by child of the application",
+				opts: []Option{
+					Provide(newString),
+					Module("child", Provide(newString, Private)),
+				},
+				want: []string{
+					`fx.NoShadowing: string provided to module "child" at ` +
+						`go.uber.org/fx_test.TestNoShadowing.func2 (`,
+					"shadowing_test.go:",
+				},
+			},
+			{
+				desc: "exported after the shadow",
+				opts: []Option{
+					Module("child", Provide(newString, Private)),
+					Supply("foo"),
+				},
+				want: []string{
+					`string provided to module "child" at`,
+					"shadows string provided to the application at",
+				},
+			},
+			{
+				desc: "every conflict",
+				opts: []Option{
+					Module("parent",
+						Provide(newString, Private),
+						Module("child", Provide(newString, Private)),
+					),
+					Provide(newString),
+				},
+				want: []string{
+					`string provided to module "child" at`,
+					`shadows string provided to module "parent" at`,
+					`string provided to module "parent" at`,
+					"shadows string provided to the application at",
+				},
+			},
+			{
+				desc: "exported by a sibling",
+				opts: []Option{
+					Module("a", Provide(newString)),
+					Module("b", Provide(newString, Private)),
+				},
+				want: []string{
+					`string provided to module "b" at`,
+					"shadows string provided to the application at",
+				},
+			},
+			{
+				desc: "named value",
+				opts: []Option{
+					Provide(Annotate(newString, ResultTags(`name:"foo"`))),
+					Module("child", Provide(Annotate(newString, ResultTags(`name:"foo"`)), Private)),
+				},
+				want: []string{
+					`string[name = "foo"] provided to module "child" at`,
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := NewForTest(t, append(tt.opts, NoShadowing())...)
+				err := app.Err()
+				require.Error(t, err)
+				for _, want := range tt.want {
+					assert.Contains(t, err.Error(), want)
+				}
+			})
+		}
+	})
+
+	t.Run("every conflict reported", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			NoShadowing(),
+			Provide(newString, func() int { return 0 }),
+			Module("a", Provide(newString, Private)),
+			Module("b", Provide(func() int { return 1 }, Private)),
+		)
+		assert.Len(t, multierr.Errors(app.Err()), 2)
+	})
+
+	t.Run("not shadowed", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc string
+			opts []Option
+		}{
+			{
+				desc: "sibling modules",
+				opts: []Option{
+					Module("a", Provide(newString, Private)),
+					Module("b", Provide(newString, Private)),
+				},
+			},
+			{
+				desc: "different names",
+				opts: []Option{
+					Provide(newString),
+					Module("child", Provide(Annotate(newString, ResultTags(`name:"foo"`)), Private)),
+				},
+			},
+			{
+				desc: "value groups",
+				opts: []Option{
+					Provide(Annotate(newString, ResultTags(`group:"foo"`))),
+					Module("child", Provide(Annotate(newString, ResultTags(`group:"foo"`)), Private)),
+				},
+			},
+			{
+				desc: "replaced",
+				opts: []Option{
+					Provide(newString),
+					Module("child", Replace("foo")),
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := fxtest.New(t, append(tt.opts, NoShadowing())...)
+				app.RequireStart().RequireStop()
+			})
+		}
+	})
+
+	t.Run("in module", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, Module("child", NoShadowing()))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			"fx.NoShadowing Option should be passed to top-level App, not to fx.Module")
+	})
+}
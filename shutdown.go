@@ -21,6 +21,7 @@
 package fx
 
 import (
+	"errors"
 	"time"
 )
 
@@ -35,13 +36,13 @@ type Shutdowner interface {
 // ShutdownOption provides a way to configure properties of the shutdown
 // process. Currently, no options have been implemented.
 type ShutdownOption interface {
-	apply(*shutdowner)
+	apply(*shutdownOptions)
 }
 
 type exitCodeOption int
 
-func (code exitCodeOption) apply(s *shutdowner) {
-	s.exitCode = int(code)
+func (code exitCodeOption) apply(o *shutdownOptions) {
+	o.exitCode = int(code)
 }
 
 var _ ShutdownOption = exitCodeOption(0)
@@ -54,9 +55,60 @@ func ExitCode(code int) ShutdownOption {
 	return exitCodeOption(code)
 }
 
+type shutdownReasonOption struct{ err error }
+
+func (r shutdownReasonOption) apply(o *shutdownOptions) {
+	o.reason = r.err
+}
+
+var _ ShutdownOption = shutdownReasonOption{}
+
+// ShutdownReason is a [ShutdownOption] that records why the application
+// is being shut down.
+// The given error will be broadcasted to any receiver waiting
+// on a [ShutdownSignal] from the [Wait] method in its Reason field,
+// and is logged with the [fxevent.Stopping] event by [App.Run].
+//
+//	if err := consumer.Run(ctx); err != nil {
+//	  shutdowner.Shutdown(fx.ExitCode(1), fx.ShutdownReason(err))
+//	}
+func ShutdownReason(err error) ShutdownOption {
+	return shutdownReasonOption{err: err}
+}
+
+type shutdownWaitOption struct{}
+
+func (shutdownWaitOption) apply(o *shutdownOptions) {
+	o.wait = true
+}
+
+var _ ShutdownOption = shutdownWaitOption{}
+
+// ShutdownWait is a [ShutdownOption] that makes the Shutdown method of the
+// [Shutdowner] interface block until the application has stopped,
+// including all of its OnStop hooks,
+// and return the error from stopping it, if any.
+// Use it to sequence work after a shutdown that's triggered from code.
+//
+//	if err := shutdowner.Shutdown(fx.ShutdownWait()); err != nil {
+//	  // ...
+//	}
+//	flushMetrics()
+//
+// The application is stopped by [App.Run],
+// or by whoever calls [App.Stop] after receiving from [App.Wait] or [App.Done];
+// Shutdown blocks until then.
+// Shutdown fails immediately if it's called with ShutdownWait
+// while the application isn't fully started,
+// such as from an OnStart or OnStop hook,
+// because the application can't stop before the hook returns.
+func ShutdownWait() ShutdownOption {
+	return shutdownWaitOption{}
+}
+
 type shutdownTimeoutOption time.Duration
 
-func (shutdownTimeoutOption) apply(*shutdowner) {}
+func (shutdownTimeoutOption) apply(*shutdownOptions) {}
 
 var _ ShutdownOption = shutdownTimeoutOption(0)
 
@@ -70,23 +122,104 @@ func ShutdownTimeout(timeout time.Duration) ShutdownOption {
 	return shutdownTimeoutOption(timeout)
 }
 
+type drainTimeoutOption time.Duration
+
+func (d drainTimeoutOption) apply(o *shutdownOptions) {
+	o.drainTimeout = time.Duration(d)
+}
+
+var _ ShutdownOption = drainTimeoutOption(0)
+
+// DrainTimeout is a [ShutdownOption] that limits how long the
+// [Hook.OnDrain] callbacks may run when the application stops
+// after this call to Shutdown.
+// Once the timeout elapses, the application proceeds with OnStop callbacks.
+func DrainTimeout(timeout time.Duration) ShutdownOption {
+	return drainTimeoutOption(timeout)
+}
+
+// shutdownOptions are the options of a single call to Shutdown.
+type shutdownOptions struct {
+	exitCode     int
+	drainTimeout time.Duration
+	reason       error
+	wait         bool
+}
+
 type shutdowner struct {
-	app      *App
-	exitCode int
+	app *App
 }
 
 // Shutdown broadcasts a signal to all of the application's Done channels
 // and begins the Stop process. Applications can be shut down only after they
 // have finished starting up.
 func (s *shutdowner) Shutdown(opts ...ShutdownOption) error {
+	var o shutdownOptions
 	for _, opt := range opts {
-		opt.apply(s)
+		opt.apply(&o)
+	}
+
+	var stopped chan error
+	if o.wait {
+		if !s.app.lifecycle.Started() {
+			return errors.New("fx.ShutdownWait can only be used " +
+				"once the application has started and before it stops")
+		}
+		// Register before broadcasting so the stop can't be missed.
+		stopped = s.app.awaitStop()
 	}
 
-	return s.app.receivers.Broadcast(ShutdownSignal{
+	// The next Stop carries out the most recent Shutdown.
+	s.app.shutdownOpts.Store(&o)
+	err := s.app.receivers.Broadcast(ShutdownSignal{
 		Signal:   _sigTERM,
-		ExitCode: s.exitCode,
+		ExitCode: o.exitCode,
+		Reason:   o.reason,
 	})
+	if stopped == nil {
+		return err
+	}
+	if err != nil {
+		s.app.cancelAwaitStop(stopped)
+		return err
+	}
+	return <-stopped
+}
+
+// awaitStop returns a channel that receives the error from the next
+// App.Stop that stops the application.
+func (app *App) awaitStop() chan error {
+	app.stopWaitersMu.Lock()
+	defer app.stopWaitersMu.Unlock()
+
+	ch := make(chan error, 1)
+	app.stopWaiters = append(app.stopWaiters, ch)
+	return ch
+}
+
+// cancelAwaitStop unregisters a channel returned by awaitStop.
+func (app *App) cancelAwaitStop(ch chan error) {
+	app.stopWaitersMu.Lock()
+	defer app.stopWaitersMu.Unlock()
+
+	for i, c := range app.stopWaiters {
+		if c == ch {
+			app.stopWaiters = append(app.stopWaiters[:i], app.stopWaiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// notifyStopped sends the error from stopping the application
+// to the channels returned by awaitStop.
+func (app *App) notifyStopped(err error) {
+	app.stopWaitersMu.Lock()
+	defer app.stopWaitersMu.Unlock()
+
+	for _, ch := range app.stopWaiters {
+		ch <- err
+	}
+	app.stopWaiters = nil
 }
 
 func (app *App) shutdowner() Shutdowner {
@@ -22,6 +22,7 @@ package fx_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -107,6 +108,23 @@ func TestShutdown(t *testing.T) {
 		require.Equal(t, 2, wait.ExitCode)
 	})
 
+	t.Run("with reason", func(t *testing.T) {
+		t.Parallel()
+		var s fx.Shutdowner
+		app := fxtest.New(
+			t,
+			fx.Populate(&s),
+		)
+
+		require.NoError(t, app.Start(context.Background()), "error starting app")
+		sadness := errors.New("great sadness")
+		assert.NoError(t, s.Shutdown(fx.ExitCode(1), fx.ShutdownReason(sadness)), "error in app shutdown")
+		wait := <-app.Wait()
+		defer app.Stop(context.Background())
+		assert.Equal(t, 1, wait.ExitCode)
+		assert.Same(t, sadness, wait.Reason)
+	})
+
 	t.Run("with exit code and multiple Wait", func(t *testing.T) {
 		t.Parallel()
 		var s fx.Shutdowner
@@ -169,6 +187,219 @@ func TestShutdown(t *testing.T) {
 			app.RequireStop()
 		}
 	})
+
+	t.Run("options of earlier calls", func(t *testing.T) {
+		t.Parallel()
+
+		var shutdowner fx.Shutdowner
+		app := fxtest.New(t, fx.Populate(&shutdowner))
+
+		app.RequireStart()
+		require.NoError(t, shutdowner.Shutdown(fx.ExitCode(3), fx.ShutdownReason(errors.New("great sadness"))))
+		assert.Equal(t, 3, (<-app.Wait()).ExitCode)
+		app.RequireStop()
+
+		app.RequireStart()
+		defer app.RequireStop()
+		require.NoError(t, shutdowner.Shutdown())
+		sig := <-app.Wait()
+		assert.Zero(t, sig.ExitCode, "exit code must not carry over from an earlier Shutdown")
+		assert.NoError(t, sig.Reason, "reason must not carry over from an earlier Shutdown")
+	})
+
+	t.Run("concurrently", func(t *testing.T) {
+		t.Parallel()
+
+		var shutdowner fx.Shutdowner
+		app := fxtest.New(t, fx.Populate(&shutdowner))
+		app.RequireStart()
+		defer app.RequireStop()
+
+		var wg sync.WaitGroup
+		for i := 1; i <= 2; i++ {
+			wg.Add(1)
+			go func(code int) {
+				defer wg.Done()
+				_ = shutdowner.Shutdown(
+					fx.ExitCode(code),
+					fx.ShutdownReason(fmt.Errorf("shutdown %d", code)),
+					fx.DrainTimeout(time.Second),
+				)
+			}(i)
+		}
+		wg.Wait()
+
+		sig := <-app.Wait()
+		assert.Contains(t, []int{1, 2}, sig.ExitCode)
+		assert.EqualError(t, sig.Reason, fmt.Sprintf("shutdown %d", sig.ExitCode),
+			"exit code and reason must come from the same call")
+	})
+}
+
+func TestDrain(t *testing.T) {
+	t.Parallel()
+
+	t.Run("runs before OnStop", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		hook := func(name string) fx.Hook {
+			return fx.Hook{
+				OnDrain: func(context.Context) error {
+					calls = append(calls, "drain "+name)
+					return nil
+				},
+				OnStop: func(context.Context) error {
+					calls = append(calls, "stop "+name)
+					return nil
+				},
+			}
+		}
+
+		app := fxtest.New(t,
+			fx.BeforeStop(func() { calls = append(calls, "before stop") }),
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(hook("db"))
+				lc.Append(hook("server"))
+			}),
+		)
+		app.RequireStart().RequireStop()
+
+		assert.Equal(t, []string{
+			"before stop",
+			"drain server",
+			"drain db",
+			"stop server",
+			"stop db",
+		}, calls)
+	})
+
+	t.Run("errors do not prevent OnStop", func(t *testing.T) {
+		t.Parallel()
+
+		var stopped bool
+		app := fxtest.New(t,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.Hook{
+					OnDrain: func(context.Context) error { return errors.New("great sadness") },
+					OnStop: func(context.Context) error {
+						stopped = true
+						return nil
+					},
+				})
+			}),
+		)
+		app.RequireStart()
+
+		err := app.Stop(context.Background())
+		assert.ErrorContains(t, err, "great sadness")
+		assert.True(t, stopped, "OnStop must run after a failed OnDrain")
+	})
+
+	t.Run("not run if OnStart failed", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.Hook{
+					OnStart: func(context.Context) error { return errors.New("great sadness") },
+					OnDrain: func(context.Context) error {
+						t.Error("OnDrain must not run")
+						return nil
+					},
+				})
+			}),
+		)
+		assert.Error(t, app.Start(context.Background()))
+		assert.NoError(t, app.Stop(context.Background()))
+	})
+
+	t.Run("DrainTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			shutdowner fx.Shutdowner
+			stopped    bool
+		)
+		app := fxtest.New(t,
+			fx.Populate(&shutdowner),
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.Hook{
+					OnDrain: func(context.Context) error {
+						t.Error("remaining drain hooks must be skipped")
+						return nil
+					},
+					OnStop: func(context.Context) error {
+						stopped = true
+						return nil
+					},
+				})
+				lc.Append(fx.Hook{
+					OnDrain: func(ctx context.Context) error {
+						deadline, ok := ctx.Deadline()
+						assert.True(t, ok, "expected a drain deadline")
+						assert.WithinDuration(t, time.Now().Add(10*time.Millisecond), deadline, time.Second)
+						<-ctx.Done()
+						return nil
+					},
+				})
+			}),
+		)
+		app.RequireStart()
+
+		require.NoError(t, shutdowner.Shutdown(fx.DrainTimeout(10*time.Millisecond)))
+		<-app.Wait()
+		require.NoError(t, app.Stop(context.Background()))
+		assert.True(t, stopped, "OnStop must run after the drain timeout")
+	})
+
+	t.Run("ShutdownWait", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			shutdowner fx.Shutdowner
+			stopped    bool
+		)
+		sadness := errors.New("great sadness")
+		app := fxtest.New(t,
+			fx.Populate(&shutdowner),
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StopHook(func() error {
+					stopped = true
+					return sadness
+				}))
+			}),
+		)
+		app.RequireStart()
+
+		stopErr := make(chan error, 1)
+		go func() {
+			<-app.Wait()
+			stopErr <- app.Stop(context.Background())
+		}()
+
+		err := shutdowner.Shutdown(fx.ShutdownWait())
+		assert.ErrorIs(t, err, sadness)
+		assert.True(t, stopped, "Shutdown must return after OnStop hooks ran")
+		assert.ErrorIs(t, <-stopErr, sadness)
+	})
+
+	t.Run("ShutdownWait from a hook", func(t *testing.T) {
+		t.Parallel()
+
+		var shutdowner fx.Shutdowner
+		app := fxtest.New(t,
+			fx.Populate(&shutdowner),
+			fx.Invoke(func(lc fx.Lifecycle) {
+				lc.Append(fx.StartHook(func() {
+					err := shutdowner.Shutdown(fx.ShutdownWait())
+					assert.ErrorContains(t, err, "fx.ShutdownWait can only be used "+
+						"once the application has started")
+				}))
+			}),
+		)
+		app.RequireStart().RequireStop()
+	})
 }
 
 func TestDataRace(t *testing.T) {
@@ -34,9 +34,13 @@ import (
 //
 // Should the application receive an operating system signal,
 // the Signal field will be populated with the received os.Signal.
+//
+// Should the Shutdown method be called with a [ShutdownReason],
+// the error given to it will be populated in the Reason field.
 type ShutdownSignal struct {
 	Signal   os.Signal
 	ExitCode int
+	Reason   error
 }
 
 // String will render a ShutdownSignal type as a string suitable for printing.
@@ -44,6 +48,108 @@ func (sig ShutdownSignal) String() string {
 	return fmt.Sprintf("%v", sig.Signal)
 }
 
+// DelegateSignals makes the application react to the signals sent on the
+// given channel instead of registering for operating system signals itself.
+// Use it when Fx runs under a supervisor that owns signal handling:
+// the supervisor receives the signals and forwards those it wants the
+// application to shut down on.
+//
+// With DelegateSignals, Fx never calls [signal.Notify].
+// The first signal received on the channel is delivered to the channels
+// returned by [App.Done] and [App.Wait], as operating system signals
+// otherwise would be.
+// Closing the channel stops the application from receiving signals;
+// it does not shut the application down.
+func DelegateSignals(ch <-chan os.Signal) Option {
+	return delegateSignalsOption{ch}
+}
+
+type delegateSignalsOption struct {
+	ch <-chan os.Signal
+}
+
+func (o delegateSignalsOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.DelegateSignals Option should be passed to top-level App, " +
+			"not to fx.Module")
+	} else {
+		m.app.receivers.delegated = o.ch
+	}
+}
+
+func (o delegateSignalsOption) String() string {
+	return fmt.Sprintf("fx.DelegateSignals(%v)", o.ch)
+}
+
+// ReloadOnSignal makes the application reload, instead of shutting down,
+// when it receives one of the given signals, such as syscall.SIGHUP.
+// Reloading runs the [Hook.OnReload] callbacks as [App.Reload] does,
+// and the application keeps running afterwards.
+// Reloads triggered by a signal are bounded by the start timeout,
+// and their result is reported with an [fxevent.Reloaded] event.
+//
+// Signals are only received while the application is waiting for
+// shutdown, such as with [App.Run], [App.Done], or [App.Wait].
+// This also applies to signals forwarded with [DelegateSignals].
+func ReloadOnSignal(sigs ...os.Signal) Option {
+	return reloadOnSignalOption(sigs)
+}
+
+type reloadOnSignalOption []os.Signal
+
+func (o reloadOnSignalOption) apply(m *module) {
+	switch {
+	case m.parent != nil:
+		m.app.err = fmt.Errorf("fx.ReloadOnSignal Option should be passed to top-level App, " +
+			"not to fx.Module")
+	case len(o) == 0:
+		m.app.err = fmt.Errorf("fx.ReloadOnSignal: at least one signal is required")
+	default:
+		m.app.receivers.reloadSignals = append(m.app.receivers.reloadSignals, o...)
+	}
+}
+
+func (o reloadOnSignalOption) String() string {
+	return fmt.Sprintf("fx.ReloadOnSignal(%v)", []os.Signal(o))
+}
+
+// ShutdownSignals sets the signals that make the application shut down,
+// replacing the default of SIGINT and SIGTERM.
+// For example, an interactive tool that handles Ctrl-C itself
+// can leave SIGINT out.
+//
+//	fx.ShutdownSignals(syscall.SIGTERM, syscall.SIGQUIT)
+//
+// Passing no signals means that only the [Shutdowner] shuts the
+// application down.
+// Signals given to both ShutdownSignals and [ReloadOnSignal] reload the
+// application.
+// Signals forwarded with [DelegateSignals] that aren't in the set
+// are ignored.
+func ShutdownSignals(sigs ...os.Signal) Option {
+	return shutdownSignalsOption(sigs)
+}
+
+type shutdownSignalsOption []os.Signal
+
+func (o shutdownSignalsOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.ShutdownSignals Option should be passed to top-level App, " +
+			"not to fx.Module")
+		return
+	}
+
+	recv := &m.app.receivers
+	if recv.shutdownSignals == nil {
+		recv.shutdownSignals = []os.Signal{}
+	}
+	recv.shutdownSignals = append(recv.shutdownSignals, o...)
+}
+
+func (o shutdownSignalsOption) String() string {
+	return fmt.Sprintf("fx.ShutdownSignals(%v)", []os.Signal(o))
+}
+
 func newSignalReceivers() signalReceivers {
 	return signalReceivers{
 		notify:     signal.Notify,
@@ -59,6 +165,18 @@ type signalReceivers struct {
 
 	// our os.Signal channel we relay from
 	signals chan os.Signal
+	// if set, the channel we relay from instead of signals,
+	// and operating system signals are not registered for
+	delegated <-chan os.Signal
+
+	// signals that shut the application down,
+	// or nil for the default signals
+	shutdownSignals []os.Signal
+
+	// signals that reload the application with reload
+	// instead of shutting it down
+	reloadSignals []os.Signal
+	reload        func(os.Signal)
 	// when written to, will instruct the signal relayer to shutdown
 	shutdown chan struct{}
 	// is written to when signal relay has finished shutting down
@@ -86,16 +204,60 @@ func (recv *signalReceivers) relayer() {
 		recv.finished <- struct{}{}
 	}()
 
-	select {
-	case <-recv.shutdown:
-		return
-	case signal := <-recv.signals:
-		recv.Broadcast(ShutdownSignal{
-			Signal: signal,
-		})
+	var signals <-chan os.Signal = recv.signals
+	if recv.delegated != nil {
+		signals = recv.delegated
+	}
+
+	for {
+		select {
+		case <-recv.shutdown:
+			return
+		case signal, ok := <-signals:
+			if !ok {
+				// The delegated channel was closed:
+				// no more signals will arrive.
+				return
+			}
+			if recv.isReloadSignal(signal) {
+				recv.reload(signal)
+				continue
+			}
+			if !recv.isShutdownSignal(signal) {
+				continue
+			}
+			recv.Broadcast(ShutdownSignal{
+				Signal: signal,
+			})
+			return
+		}
 	}
 }
 
+// isReloadSignal reports whether sig was registered with ReloadOnSignal.
+func (recv *signalReceivers) isReloadSignal(sig os.Signal) bool {
+	for _, s := range recv.reloadSignals {
+		if s == sig {
+			return true
+		}
+	}
+	return false
+}
+
+// isShutdownSignal reports whether sig shuts the application down.
+// All signals do, unless they were set with ShutdownSignals.
+func (recv *signalReceivers) isShutdownSignal(sig os.Signal) bool {
+	if recv.shutdownSignals == nil {
+		return true
+	}
+	for _, s := range recv.shutdownSignals {
+		if s == sig {
+			return true
+		}
+	}
+	return false
+}
+
 // running returns true if the the signal relay go-routine is running.
 // this method must be invoked under locked mutex to avoid race condition.
 func (recv *signalReceivers) running() bool {
@@ -113,14 +275,26 @@ func (recv *signalReceivers) Start() {
 
 	recv.finished = make(chan struct{}, 1)
 	recv.shutdown = make(chan struct{}, 1)
-	recv.notify(recv.signals, os.Interrupt, _sigINT, _sigTERM)
+	if recv.delegated == nil {
+		sigs := []os.Signal{os.Interrupt, _sigINT, _sigTERM}
+		if recv.shutdownSignals != nil {
+			sigs = recv.shutdownSignals
+		}
+		sigs = append(sigs[:len(sigs):len(sigs)], recv.reloadSignals...)
+		// Notify relays every signal if it's given none.
+		if len(sigs) > 0 {
+			recv.notify(recv.signals, sigs...)
+		}
+	}
 	go recv.relayer()
 }
 
 func (recv *signalReceivers) Stop(ctx context.Context) error {
 	recv.m.Lock()
 	defer recv.m.Unlock()
-	recv.stopNotify(recv.signals)
+	if recv.delegated == nil {
+		recv.stopNotify(recv.signals)
+	}
 
 	// if the relayer is not running; return nil error
 	if !recv.running() {
@@ -25,6 +25,7 @@ import (
 	"os"
 	"syscall"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -115,6 +116,258 @@ func TestSignal(t *testing.T) {
 				require.Equal(t, 1, stopCalledTimes)
 				close(stub)
 			})
+			t.Run("delegated", func(t *testing.T) {
+				delegated := make(chan os.Signal, 1)
+				recv := newSignalReceivers()
+				recv.delegated = delegated
+				recv.notify = func(chan<- os.Signal, ...os.Signal) {
+					t.Error("signal.Notify must not be called")
+				}
+				recv.stopNotify = func(chan<- os.Signal) {
+					t.Error("signal.Stop must not be called")
+				}
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				recv.Start()
+				delegated <- syscall.SIGTERM
+				require.Equal(t, syscall.SIGTERM, <-recv.Done())
+				require.NoError(t, recv.Stop(ctx))
+			})
 		})
 	})
+
+	t.Run("closed delegated channel", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc            string
+			shutdownSignals []os.Signal
+		}{
+			{desc: "default signals"},
+			{desc: "ShutdownSignals", shutdownSignals: []os.Signal{syscall.SIGTERM}},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				delegated := make(chan os.Signal)
+				close(delegated)
+				recv := newSignalReceivers()
+				recv.delegated = delegated
+				recv.shutdownSignals = tt.shutdownSignals
+				wait := recv.Wait()
+				recv.finished = make(chan struct{}, 1)
+				recv.shutdown = make(chan struct{}, 1)
+
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					recv.relayer()
+				}()
+
+				select {
+				case <-done:
+				case <-time.After(time.Second):
+					t.Fatal("relayer did not return after the channel was closed")
+				}
+				select {
+				case sig := <-wait:
+					t.Fatalf("unexpected shutdown on %v", sig.Signal)
+				default:
+				}
+			})
+		}
+	})
+
+	t.Run("DelegateSignals", func(t *testing.T) {
+		t.Parallel()
+
+		delegated := make(chan os.Signal, 1)
+		app := New(NopLogger, DelegateSignals(delegated))
+		require.NoError(t, app.Err())
+		app.receivers.notify = func(chan<- os.Signal, ...os.Signal) {
+			t.Error("signal.Notify must not be called")
+		}
+		app.receivers.stopNotify = func(chan<- os.Signal) {
+			t.Error("signal.Stop must not be called")
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		require.NoError(t, app.Start(ctx))
+		wait := app.Wait()
+		delegated <- syscall.SIGINT
+		assert.Equal(t, syscall.SIGINT, (<-wait).Signal)
+		require.NoError(t, app.Stop(ctx))
+	})
+
+	t.Run("ReloadOnSignal", func(t *testing.T) {
+		t.Parallel()
+
+		delegated := make(chan os.Signal, 1)
+		reloaded := make(chan struct{}, 1)
+		app := New(
+			NopLogger,
+			DelegateSignals(delegated),
+			ReloadOnSignal(syscall.SIGHUP),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{
+					OnReload: func(context.Context) error {
+						reloaded <- struct{}{}
+						return nil
+					},
+				})
+			}),
+		)
+		require.NoError(t, app.Err())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		require.NoError(t, app.Start(ctx))
+		wait := app.Wait()
+
+		delegated <- syscall.SIGHUP
+		<-reloaded
+		select {
+		case sig := <-wait:
+			t.Fatalf("unexpected shutdown on %v", sig.Signal)
+		default:
+		}
+
+		delegated <- syscall.SIGTERM
+		assert.Equal(t, syscall.SIGTERM, (<-wait).Signal)
+		require.NoError(t, app.Stop(ctx))
+	})
+
+	t.Run("ReloadOnSignal errors", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc    string
+			give    Option
+			wantErr string
+		}{
+			{
+				desc:    "no signals",
+				give:    ReloadOnSignal(),
+				wantErr: "fx.ReloadOnSignal: at least one signal is required",
+			},
+			{
+				desc: "in module",
+				give: Module("child", ReloadOnSignal(syscall.SIGHUP)),
+				wantErr: "fx.ReloadOnSignal Option should be passed to top-level App, " +
+					"not to fx.Module",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := New(NopLogger, tt.give)
+				assert.ErrorContains(t, app.Err(), tt.wantErr)
+			})
+		}
+	})
+
+	t.Run("ShutdownSignals", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc       string
+			give       []os.Signal
+			wantNotify []os.Signal
+		}{
+			{
+				desc:       "custom set",
+				give:       []os.Signal{syscall.SIGTERM, syscall.SIGQUIT},
+				wantNotify: []os.Signal{syscall.SIGTERM, syscall.SIGQUIT, syscall.SIGHUP},
+			},
+			{
+				desc:       "none",
+				give:       []os.Signal{},
+				wantNotify: []os.Signal{syscall.SIGHUP},
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := New(NopLogger,
+					ShutdownSignals(tt.give...),
+					ReloadOnSignal(syscall.SIGHUP),
+				)
+				require.NoError(t, app.Err())
+
+				stub := make(chan os.Signal)
+				defer close(stub)
+				app.receivers.notify = func(ch chan<- os.Signal, sigs ...os.Signal) {
+					assert.Equal(t, tt.wantNotify, sigs)
+					go func() {
+						for sig := range stub {
+							ch <- sig
+						}
+					}()
+				}
+				app.receivers.stopNotify = func(chan<- os.Signal) {}
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				require.NoError(t, app.Start(ctx))
+				wait := app.Wait()
+
+				// Signals outside the set are ignored.
+				stub <- syscall.SIGINT
+				stub <- syscall.SIGINT // blocks until the first was handled
+				select {
+				case sig := <-wait:
+					t.Fatalf("unexpected shutdown on %v", sig.Signal)
+				default:
+				}
+				require.NoError(t, app.Stop(ctx))
+			})
+		}
+	})
+
+	t.Run("ShutdownSignals delegated", func(t *testing.T) {
+		t.Parallel()
+
+		delegated := make(chan os.Signal)
+		app := New(NopLogger,
+			DelegateSignals(delegated),
+			ShutdownSignals(syscall.SIGTERM),
+		)
+		require.NoError(t, app.Err())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		require.NoError(t, app.Start(ctx))
+		wait := app.Wait()
+
+		delegated <- syscall.SIGINT
+		delegated <- syscall.SIGTERM
+		assert.Equal(t, syscall.SIGTERM, (<-wait).Signal)
+		require.NoError(t, app.Stop(ctx))
+	})
+
+	t.Run("ShutdownSignals in module", func(t *testing.T) {
+		t.Parallel()
+
+		app := New(NopLogger, Module("child", ShutdownSignals(syscall.SIGTERM)))
+		assert.ErrorContains(t, app.Err(),
+			"fx.ShutdownSignals Option should be passed to top-level App, not to fx.Module")
+	})
+
+	t.Run("DelegateSignals in module", func(t *testing.T) {
+		t.Parallel()
+
+		app := New(NopLogger, Module("child", DelegateSignals(make(chan os.Signal))))
+		assert.ErrorContains(t, app.Err(),
+			"fx.DelegateSignals Option should be passed to top-level App, not to fx.Module")
+	})
 }
@@ -0,0 +1,118 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+
+	"go.uber.org/fx/internal/lifecycle"
+)
+
+// StartProgress reports that an OnStart hook is about to run
+// as the application starts.
+// See [App.StartProgress].
+type StartProgress struct {
+	// Hook is the position of the hook among the OnStart hooks
+	// run by Start, from 1 to Total.
+	Hook  int
+	Total int
+
+	// Name of the OnStart function, or the Name of its [Hook] if set.
+	Name string
+
+	// Caller is the function that appended the hook to the lifecycle.
+	Caller string
+
+	// Module is the name of the module whose function appended the hook,
+	// or empty for the top-level module.
+	Module string
+}
+
+// String describes the progress as "executing OnStart 7/23: name".
+func (p StartProgress) String() string {
+	return fmt.Sprintf("executing OnStart %d/%d: %v", p.Hook, p.Total, p.Name)
+}
+
+// StartProgress returns a channel that receives a [StartProgress]
+// before each OnStart hook runs during the next call to [App.Start],
+// and is closed once Start returns.
+// Use it to report the progress of an application that's slow to start.
+//
+//	progress := app.StartProgress()
+//	go func() {
+//		for p := range progress {
+//			status.Set(p.String())
+//		}
+//	}()
+//	err := app.Start(ctx)
+//
+// Start doesn't wait for the channel to be read:
+// it's buffered to hold one StartProgress for each hook appended
+// to the lifecycle when StartProgress is called,
+// and progress that doesn't fit is dropped.
+func (app *App) StartProgress() <-chan StartProgress {
+	app.progressMu.Lock()
+	defer app.progressMu.Unlock()
+
+	n := app.lifecycle.HookCount()
+	if n == 0 {
+		n = 1
+	}
+	ch := make(chan StartProgress, n)
+	app.progress = append(app.progress, ch)
+	return ch
+}
+
+// reportStartProgress sends p to the channels returned by StartProgress.
+func (app *App) reportStartProgress(p lifecycle.StartProgress) {
+	app.progressMu.Lock()
+	defer app.progressMu.Unlock()
+
+	if len(app.progress) == 0 {
+		return
+	}
+	progress := StartProgress{
+		Hook:   p.Position,
+		Total:  p.Total,
+		Name:   p.FunctionName,
+		Caller: p.CallerName,
+	}
+	if p.Index < len(app.hookModules) {
+		progress.Module = app.hookModules[p.Index]
+	}
+	for _, ch := range app.progress {
+		select {
+		case ch <- progress:
+		default:
+		}
+	}
+}
+
+// endStartProgress closes the channels returned by StartProgress.
+func (app *App) endStartProgress() {
+	app.progressMu.Lock()
+	defer app.progressMu.Unlock()
+
+	for _, ch := range app.progress {
+		close(ch)
+	}
+	app.progress = nil
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestStartProgress(t *testing.T) {
+	t.Parallel()
+
+	noop := func(context.Context) error { return nil }
+
+	t.Run("reports each OnStart hook", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{Name: "database", OnStart: noop})
+				lc.Append(Hook{OnStop: noop}) // no OnStart
+			}),
+			Module("server",
+				Invoke(func(lc Lifecycle) {
+					lc.Append(Hook{Name: "http-server", OnStart: noop})
+				}),
+			),
+		)
+
+		progress := app.StartProgress()
+		app.RequireStart()
+		defer app.RequireStop()
+
+		var got []StartProgress
+		for p := range progress {
+			got = append(got, p)
+		}
+		require.Len(t, got, 2)
+
+		// Functions invoked in modules run first.
+		assert.Equal(t, 1, got[0].Hook)
+		assert.Equal(t, 2, got[0].Total)
+		assert.Equal(t, "http-server", got[0].Name)
+		assert.Equal(t, "server", got[0].Module)
+		assert.Contains(t, got[0].Caller, "TestStartProgress")
+
+		assert.Equal(t, "database", got[1].Name)
+		assert.Empty(t, got[1].Module)
+		assert.Equal(t, "executing OnStart 2/2: database", got[1].String())
+	})
+
+	t.Run("closed when start fails", func(t *testing.T) {
+		t.Parallel()
+
+		app := New(
+			NopLogger,
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{Name: "broken", OnStart: func(context.Context) error {
+					return assert.AnError
+				}})
+				lc.Append(Hook{Name: "never", OnStart: noop})
+			}),
+		)
+		require.NoError(t, app.Err())
+
+		progress := app.StartProgress()
+		require.Error(t, app.Start(context.Background()))
+
+		var names []string
+		for p := range progress {
+			names = append(names, p.Name)
+		}
+		assert.Equal(t, []string{"broken"}, names)
+	})
+
+	t.Run("only the next start", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{OnStart: noop})
+			}),
+		)
+
+		first := app.StartProgress()
+		app.RequireStart().RequireStop()
+		assert.Len(t, first, 1)
+
+		second := app.StartProgress()
+		app.RequireStart().RequireStop()
+		assert.Len(t, second, 1, "each channel must only receive progress of one start")
+	})
+
+	t.Run("no hooks", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t)
+		progress := app.StartProgress()
+		app.RequireStart().RequireStop()
+
+		_, ok := <-progress
+		assert.False(t, ok, "channel must be closed")
+	})
+}
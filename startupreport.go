@@ -0,0 +1,186 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import "time"
+
+// StartupReport describes how long the application took to start,
+// as returned by [App.StartupReport].
+type StartupReport struct {
+	// Constructors are the constructors that have run,
+	// in the order of their IDs in [App.Graph].
+	Constructors []ConstructorTiming
+
+	// Hooks are the hooks appended to the application's Lifecycle,
+	// as reported by [App.RegisteredHooks].
+	// Their StartRuntime is how long their OnStart function ran.
+	Hooks []HookInfo
+
+	// ConstructorRuntime is the total runtime of Constructors,
+	// and HookRuntime the total runtime of the OnStart functions of Hooks.
+	ConstructorRuntime time.Duration
+	HookRuntime        time.Duration
+
+	// CriticalPath is the chain of constructors depending on each other
+	// with the longest total runtime.
+	// It starts with a constructor that has no dependencies,
+	// and each constructor depends on the one before it.
+	//
+	// Fx runs constructors one at a time, so the critical path is the
+	// sequential work that remains however the others are sped up:
+	// it bounds how fast the constructors could run.
+	CriticalPath []ConstructorTiming
+
+	// CriticalPathRuntime is the total runtime of CriticalPath.
+	CriticalPathRuntime time.Duration
+}
+
+// ConstructorTiming is a constructor in a [StartupReport].
+type ConstructorTiming struct {
+	// ID identifies the constructor in the nodes of [App.Graph].
+	ID int
+
+	// Name of the constructor.
+	Name string
+
+	// Module is the name of the module the constructor was provided to,
+	// or empty for the top-level module.
+	Module string
+
+	// Runtime is how long the constructor ran.
+	Runtime time.Duration
+
+	// Dependencies are the IDs of the constructors that built
+	// the values it depends on.
+	Dependencies []int
+}
+
+// StartupReport reports how long each constructor and OnStart hook of the
+// application ran, which constructors depend on each other, and the
+// critical path through them.
+// Track it to catch regressions in the time an application takes to start.
+//
+// Constructors run in [New], and OnStart hooks in [App.Start]:
+// call StartupReport once the application has started
+// to get a complete report.
+// Constructor runtimes are only measured if the application
+// uses [TimeConstructors].
+func (app *App) StartupReport() StartupReport {
+	g, nodes := app.graph()
+
+	var report StartupReport
+	index := make(map[int]int) // node ID to index in report.Constructors
+	for _, n := range nodes {
+		if !n.ran || (n.Kind != "provide" && n.Kind != "derive") {
+			continue
+		}
+		index[n.ID] = len(report.Constructors)
+		report.Constructors = append(report.Constructors, ConstructorTiming{
+			ID:      n.ID,
+			Name:    n.Name,
+			Module:  n.mod.name,
+			Runtime: n.runtime,
+		})
+		report.ConstructorRuntime += n.runtime
+	}
+	for _, e := range g.Edges {
+		from, ok := index[e.From]
+		if !ok {
+			continue
+		}
+		if _, ok := index[e.To]; !ok {
+			continue
+		}
+		c := &report.Constructors[from]
+		if !containsInt(c.Dependencies, e.To) {
+			c.Dependencies = append(c.Dependencies, e.To)
+		}
+	}
+
+	report.Hooks = app.RegisteredHooks()
+	for _, h := range report.Hooks {
+		report.HookRuntime += h.StartRuntime
+	}
+
+	report.CriticalPath, report.CriticalPathRuntime = criticalPath(report.Constructors, index)
+	return report
+}
+
+// criticalPath returns the chain of constructors with the longest
+// total runtime, and that runtime.
+// index maps the IDs of constructors to their position in cs.
+func criticalPath(cs []ConstructorTiming, index map[int]int) ([]ConstructorTiming, time.Duration) {
+	var (
+		// longest[i] is the runtime of the longest chain ending with cs[i],
+		// and prev[i] the index of the constructor before it, or -1.
+		longest = make([]time.Duration, len(cs))
+		prev    = make([]int, len(cs))
+		visited = make([]bool, len(cs))
+	)
+	var visit func(i int) time.Duration
+	visit = func(i int) time.Duration {
+		if visited[i] {
+			// Already computed, or a cycle,
+			// which can't happen among constructors that ran.
+			return longest[i]
+		}
+		visited[i] = true
+
+		prev[i] = -1
+		var before time.Duration
+		for _, id := range cs[i].Dependencies {
+			j := index[id]
+			if d := visit(j); d > before || prev[i] < 0 {
+				before, prev[i] = d, j
+			}
+		}
+		longest[i] = before + cs[i].Runtime
+		return longest[i]
+	}
+
+	end := -1
+	for i := range cs {
+		if d := visit(i); end < 0 || d > longest[end] {
+			end = i
+		}
+	}
+	if end < 0 {
+		return nil, 0
+	}
+
+	var path []ConstructorTiming
+	for i := end; i >= 0; i = prev[i] {
+		path = append(path, cs[i])
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, longest[end]
+}
+
+func containsInt(xs []int, x int) bool {
+	for _, y := range xs {
+		if y == x {
+			return true
+		}
+	}
+	return false
+}
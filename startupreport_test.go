@@ -0,0 +1,121 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestStartupReport(t *testing.T) {
+	t.Parallel()
+
+	type (
+		A struct{}
+		B struct{}
+		C struct{}
+		D struct{}
+	)
+
+	clock := fxtest.NewClock()
+	app := fxtest.New(t,
+		fx.WithClock(clock),
+		fx.TimeConstructors(),
+		fx.Module("db",
+			fx.Provide(
+				func() A { clock.Add(10 * time.Millisecond); return A{} },
+				func(A) B { clock.Add(40 * time.Millisecond); return B{} },
+			),
+		),
+		fx.Provide(
+			func() C { clock.Add(30 * time.Millisecond); return C{} },
+			func(B, C) D { clock.Add(5 * time.Millisecond); return D{} },
+		),
+		fx.Invoke(func(lc fx.Lifecycle, _ D) {
+			lc.Append(fx.StartHook(func() { clock.Add(7 * time.Millisecond) }))
+		}),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	report := app.StartupReport()
+
+	runtimes := func(cs []fx.ConstructorTiming) []time.Duration {
+		var ds []time.Duration
+		for _, c := range cs {
+			ds = append(ds, c.Runtime)
+		}
+		return ds
+	}
+	// Fx's own constructors run instantly with the test clock.
+	var ran []fx.ConstructorTiming
+	byID := make(map[int]fx.ConstructorTiming)
+	for _, c := range report.Constructors {
+		byID[c.ID] = c
+		if c.Runtime > 0 {
+			ran = append(ran, c)
+		}
+	}
+	require.Len(t, ran, 4)
+	assert.ElementsMatch(t, []time.Duration{
+		10 * time.Millisecond,
+		40 * time.Millisecond,
+		30 * time.Millisecond,
+		5 * time.Millisecond,
+	}, runtimes(ran))
+	assert.Equal(t, 85*time.Millisecond, report.ConstructorRuntime)
+
+	require.Len(t, report.Hooks, 1)
+	assert.Equal(t, 7*time.Millisecond, report.HookRuntime)
+
+	assert.Equal(t, []time.Duration{
+		10 * time.Millisecond,
+		40 * time.Millisecond,
+		5 * time.Millisecond,
+	}, runtimes(report.CriticalPath))
+	assert.Equal(t, 55*time.Millisecond, report.CriticalPathRuntime)
+	assert.Equal(t, "db", report.CriticalPath[0].Module)
+	assert.Equal(t, "db", report.CriticalPath[1].Module)
+	assert.Empty(t, report.CriticalPath[2].Module)
+
+	// Each constructor on the path depends on the one before it.
+	for i := 1; i < len(report.CriticalPath); i++ {
+		assert.Contains(t, report.CriticalPath[i].Dependencies, report.CriticalPath[i-1].ID)
+	}
+	d := report.CriticalPath[2]
+	require.Len(t, d.Dependencies, 2)
+	assert.ElementsMatch(t,
+		[]time.Duration{40 * time.Millisecond, 30 * time.Millisecond},
+		[]time.Duration{byID[d.Dependencies[0]].Runtime, byID[d.Dependencies[1]].Runtime})
+}
+
+func TestStartupReportEmpty(t *testing.T) {
+	t.Parallel()
+
+	report := fxtest.New(t).StartupReport()
+	assert.Empty(t, report.Hooks)
+	assert.Zero(t, report.HookRuntime)
+}
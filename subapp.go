@@ -0,0 +1,406 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+
+	"go.uber.org/fx/internal/fxreflect"
+	"go.uber.org/multierr"
+)
+
+// SubAppFactory builds child applications of the application that
+// provided it. Use it to host sub-applications, such as plugins,
+// that need stronger isolation than a [Module] offers.
+//
+// A child application is a separate App with its own container,
+// [Lifecycle], and [Shutdowner]; it shares nothing with its parent except
+// its event logger and the values explicitly requested with [Inherit],
+// unless it's built with [ShareParentContainer].
+// The child is started independently of the parent,
+// but it is stopped when the parent is stopped,
+// before any of the parent's OnStop hooks run.
+// The parent releases children once they're stopped,
+// and never retains children that failed to build.
+//
+// A SubAppFactory is available to constructors and invoked functions of
+// applications built with the [SubApps] option.
+type SubAppFactory interface {
+	// New builds a child application from the given options,
+	// like the top-level [New] function does.
+	New(opts ...Option) *App
+}
+
+// SubApps makes a [SubAppFactory] available to the application.
+func SubApps() Option {
+	return subAppsOption{Stack: fxreflect.CallerStack(1, 0)}
+}
+
+type subAppsOption struct {
+	Stack fxreflect.Stack
+}
+
+func (o subAppsOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.SubApps Option should be passed to top-level App, " +
+			"not to fx.Module")
+		return
+	}
+
+	app := m.app
+	m.provides = append(m.provides, provide{
+		Target: func() SubAppFactory { return subAppFactory{parent: app} },
+		Stack:  o.Stack,
+	})
+}
+
+func (subAppsOption) String() string {
+	return "fx.SubApps()"
+}
+
+type subAppFactory struct {
+	parent *App
+}
+
+func (f subAppFactory) New(opts ...Option) *App {
+	child := New(append([]Option{subAppOf{f.parent}}, opts...)...)
+	if child.Err() == nil {
+		f.parent.addSubApp(child)
+	}
+	return child
+}
+
+// subAppOf marks an application as the child of parent.
+// It must be applied before any other option.
+type subAppOf struct {
+	parent *App
+}
+
+func (o subAppOf) apply(m *module) {
+	m.app.parent = o.parent
+	m.log = o.parent.log()
+}
+
+func (subAppOf) String() string {
+	return "fx.subAppOf()"
+}
+
+// addSubApp records child as a child of app to stop with it,
+// unless it's already recorded.
+func (app *App) addSubApp(child *App) {
+	app.subAppsMu.Lock()
+	defer app.subAppsMu.Unlock()
+
+	for _, c := range app.subApps {
+		if c == child {
+			return
+		}
+	}
+	app.subApps = append(app.subApps, child)
+}
+
+// removeSubApp forgets child once it's stopped,
+// so that app doesn't retain the children it no longer needs to stop.
+func (app *App) removeSubApp(child *App) {
+	app.subAppsMu.Lock()
+	defer app.subAppsMu.Unlock()
+
+	for i, c := range app.subApps {
+		if c == child {
+			app.subApps = append(app.subApps[:i], app.subApps[i+1:]...)
+			return
+		}
+	}
+}
+
+// stopSubApps stops the children of app that were built by its
+// SubAppFactory, most recent first.
+func (app *App) stopSubApps(ctx context.Context) error {
+	app.subAppsMu.Lock()
+	children := app.subApps
+	app.subApps = nil
+	app.subAppsMu.Unlock()
+
+	var err error
+	for i := len(children) - 1; i >= 0; i-- {
+		err = multierr.Append(err, children[i].Stop(ctx))
+	}
+	return err
+}
+
+// Inherit provides values of the given types to a child application built
+// by a [SubAppFactory], resolving them from its parent.
+// Arguments must be pointers to the types to inherit, as with [Populate].
+//
+//	factory.New(
+//		fx.Inherit(new(*zap.Logger), new(Config)),
+//		fx.Provide(NewPlugin),
+//	)
+//
+// The child receives the parent's instances; they are not constructed
+// again, and they are constructed in the parent if they were not yet built.
+// Only types available at the parent's top level can be inherited.
+//
+// Inherit fails if the application is not a child application.
+func Inherit(targets ...interface{}) Option {
+	return inheritOption{
+		Targets: targets,
+		Stack:   fxreflect.CallerStack(1, 0),
+	}
+}
+
+type inheritOption struct {
+	Targets []interface{}
+	Stack   fxreflect.Stack
+}
+
+func (o inheritOption) apply(m *module) {
+	parent := m.app.parent
+	if parent == nil {
+		m.app.err = fmt.Errorf("fx.Inherit Option should be passed to an App built by " +
+			"fx.SubAppFactory")
+		return
+	}
+
+	for _, target := range o.Targets {
+		typ := reflect.TypeOf(target)
+		if typ == nil || typ.Kind() != reflect.Ptr {
+			m.app.err = fmt.Errorf("fx.Inherit: target must be a pointer to a type, got %T", target)
+			return
+		}
+		typ = typ.Elem()
+
+		var value reflect.Value
+		err := parent.containerLock().Do(func() (err error) {
+			value, err = parent.resolve(typ)
+			return err
+		})
+		if err != nil {
+			m.app.err = fmt.Errorf("fx.Inherit(%v) from:\n%+vFailed: %w", typ, o.Stack, err)
+			return
+		}
+
+		m.provides = append(m.provides, provide{
+			Target:     newInheritConstructor(typ, value),
+			Stack:      o.Stack,
+			IsSupply:   true,
+			SupplyType: typ,
+		})
+	}
+}
+
+func (o inheritOption) String() string {
+	items := make([]string, len(o.Targets))
+	for i, target := range o.Targets {
+		items[i] = fmt.Sprint(reflect.TypeOf(target).Elem())
+	}
+	return fmt.Sprintf("fx.Inherit(%s)", strings.Join(items, ", "))
+}
+
+// ShareParentContainer builds a child application built by a
+// [SubAppFactory] in the container of its parent, instead of a container
+// of its own.
+// Use it for applications that come and go while their parent runs,
+// like tenants of a multi-tenant process, to share the parent's
+// infrastructure without listing every type with [Inherit].
+//
+//	factory.New(
+//		fx.ShareParentContainer(),
+//		fx.Supply(tenantConfig),
+//		fx.Provide(NewTenantServer),
+//		fx.Invoke(func(*TenantServer) {}),
+//	)
+//
+// The child application behaves like a [Module] nested under the top level
+// of its parent: it can depend on any type available there,
+// and receives the parent's instances of them. It may shadow them.
+// Values provided to the child, including values exported by its modules,
+// stay within the child and are never visible to the parent or to other
+// children.
+//
+// The child still has its own [Lifecycle] and [Shutdowner], and is started
+// and stopped independently of its parent, as other child applications are.
+// Values the parent hasn't built yet are built for it when the child
+// first needs them; their constructors receive the parent's Lifecycle,
+// whose hooks don't run if the parent was already started.
+// Prefer sharing values the parent has already built, such as those its
+// invoked functions depend on.
+//
+// The child uses the options of the parent's container, like
+// [RecoverFromPanics]. Building it, and calling [App.Try] and
+// [App.NewScope] on it, is serialized with those of its parent,
+// and with [Resolve] and [App.HealthCheck] on the parent.
+// So the child can't be built from functions run by the parent's
+// [App.Try] or [App.NewScope]: [New] fails if it is.
+// Nor can they wait for a goroutine that builds it:
+// the child would wait for them to return.
+// Its own constructors and invoked functions can't call these methods on
+// the parent: they fail instead.
+// Calls from other goroutines wait until the child is built.
+// In particular, with [ProbeHealthChecks], the liveness probe of
+// [HealthProbes] on the parent doesn't respond while a child is built.
+// As with [App.NewScope], the container retains the values of the child
+// for as long as the parent is reachable.
+//
+// ShareParentContainer fails if the application is not a child
+// application.
+func ShareParentContainer() Option {
+	return shareParentContainerOption{}
+}
+
+type shareParentContainerOption struct{}
+
+func (shareParentContainerOption) apply(m *module) {
+	switch {
+	case m.parent != nil:
+		m.app.err = fmt.Errorf("fx.ShareParentContainer Option should be passed to top-level App, " +
+			"not to fx.Module")
+	case m.app.parent == nil:
+		m.app.err = fmt.Errorf("fx.ShareParentContainer Option should be passed to an App built by " +
+			"fx.SubAppFactory")
+	default:
+		m.app.sharedContainer = true
+	}
+}
+
+func (shareParentContainerOption) String() string {
+	return "fx.ShareParentContainer()"
+}
+
+// containerLock returns the lock that serializes the use of the
+// application's container after it's built.
+// Applications that share the container of their parent use its lock.
+func (app *App) containerLock() *containerMutex {
+	for app.sharedContainer {
+		app = app.parent
+	}
+	return &app.scopeMu
+}
+
+// errContainerInUse is returned when a function run with the container
+// locked tries to lock it again.
+var errContainerInUse = errors.New("the container is in use by a function run by App.Try, " +
+	"App.NewScope, App.Resolve, or App.HealthCheck, or by New for a child application " +
+	"with fx.ShareParentContainer: functions run by them cannot call these methods, " +
+	"or fx.Inherit, on the same application")
+
+// containerMutex serializes the use of an application's container.
+//
+// It's not reentrant. Functions run with it held, which may call user code,
+// run within useContainer, so that a call chain that holds a container
+// lock can be told apart from other goroutines waiting for it:
+// Lock fails instead of deadlocking when a function that holds a container
+// lock tries to lock one that's held.
+type containerMutex struct {
+	mu sync.Mutex
+}
+
+// Do runs f with m locked, or fails if m is held and the current call chain
+// already holds a container lock.
+func (m *containerMutex) Do(f func() error) error {
+	if err := m.Lock(); err != nil {
+		return err
+	}
+	defer m.Unlock()
+	return useContainer(f)
+}
+
+// Lock locks m, or fails if m is held and the current call chain
+// already holds a container lock.
+// Functions run before m is unlocked must run with useContainer.
+func (m *containerMutex) Lock() error {
+	if m.mu.TryLock() {
+		return nil
+	}
+	if inContainerUse() {
+		return errContainerInUse
+	}
+	m.mu.Lock()
+	return nil
+}
+
+// Unlock unlocks m.
+func (m *containerMutex) Unlock() {
+	m.mu.Unlock()
+}
+
+// useContainer runs f, marking the call chain as one that holds
+// a container lock until f returns.
+//
+//go:noinline
+func useContainer(f func() error) error {
+	return f()
+}
+
+var _useContainerEntry = reflect.ValueOf(useContainer).Pointer()
+
+// inContainerUse reports whether the current call chain runs within
+// useContainer.
+func inContainerUse() bool {
+	pcs := make([]uintptr, 64)
+	for skip := 2; ; skip += len(pcs) {
+		n := runtime.Callers(skip, pcs)
+		frames := runtime.CallersFrames(pcs[:n])
+		for {
+			frame, more := frames.Next()
+			if frame.Entry == _useContainerEntry {
+				return true
+			}
+			if !more {
+				break
+			}
+		}
+		if n < len(pcs) {
+			return false
+		}
+	}
+}
+
+// resolve retrieves a value of type typ from the top level of app.
+// The caller must hold the lock of app's container.
+func (app *App) resolve(typ reflect.Type) (reflect.Value, error) {
+	var value reflect.Value
+	fn := reflect.MakeFunc(
+		reflect.FuncOf([]reflect.Type{typ}, nil, false),
+		func(args []reflect.Value) []reflect.Value {
+			value = args[0]
+			return nil
+		},
+	)
+	if err := app.root.scope.Invoke(fn.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	return value, nil
+}
+
+// Returns a function that takes no parameters, and returns value as typ.
+func newInheritConstructor(typ reflect.Type, value reflect.Value) interface{} {
+	ft := reflect.FuncOf(nil, []reflect.Type{typ}, false)
+	return reflect.MakeFunc(ft, func([]reflect.Value) []reflect.Value {
+		return []reflect.Value{value}
+	}).Interface()
+}
@@ -0,0 +1,373 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestSubApps(t *testing.T) {
+	t.Parallel()
+
+	type config struct{ name string }
+	type plugin struct{ name string }
+
+	t.Run("stopped with parent", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		var factory SubAppFactory
+		parent := fxtest.New(t,
+			SubApps(),
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{OnStop: func(context.Context) error {
+					calls = append(calls, "parent stopped")
+					return nil
+				}})
+			}),
+			Populate(&factory),
+		)
+		parent.RequireStart()
+
+		child := factory.New(
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{
+					OnStart: func(context.Context) error {
+						calls = append(calls, "child started")
+						return nil
+					},
+					OnStop: func(context.Context) error {
+						calls = append(calls, "child stopped")
+						return nil
+					},
+				})
+			}),
+		)
+		require.NoError(t, child.Err())
+		require.NoError(t, child.Start(context.Background()))
+
+		parent.RequireStop()
+		assert.Equal(t, []string{
+			"child started",
+			"child stopped",
+			"parent stopped",
+		}, calls)
+
+		// The child was already stopped by its parent.
+		require.NoError(t, child.Stop(context.Background()))
+		assert.Len(t, calls, 3)
+	})
+
+	t.Run("inherit", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &config{name: "foo"}
+		var factory SubAppFactory
+		parent := fxtest.New(t,
+			SubApps(),
+			Supply(cfg),
+			Populate(&factory),
+		)
+		defer parent.RequireStart().RequireStop()
+
+		var got *plugin
+		child := factory.New(
+			Inherit(new(*config)),
+			Provide(func(c *config) *plugin {
+				assert.Same(t, cfg, c, "child must receive the parent's instance")
+				return &plugin{name: c.name}
+			}),
+			Populate(&got),
+		)
+		require.NoError(t, child.Err())
+		assert.Equal(t, "foo", got.name)
+	})
+
+	t.Run("isolated", func(t *testing.T) {
+		t.Parallel()
+
+		var factory SubAppFactory
+		parent := fxtest.New(t,
+			SubApps(),
+			Supply(&config{}),
+			Populate(&factory),
+		)
+		defer parent.RequireStart().RequireStop()
+
+		child := factory.New(Invoke(func(*config) {}))
+		err := child.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing type: *fx_test.config")
+	})
+
+	t.Run("inherit missing type", func(t *testing.T) {
+		t.Parallel()
+
+		var factory SubAppFactory
+		parent := fxtest.New(t, SubApps(), Populate(&factory))
+		defer parent.RequireStart().RequireStop()
+
+		child := factory.New(Inherit(new(*config)))
+		err := child.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.Inherit(*fx_test.config)")
+		assert.Contains(t, err.Error(), "missing type: *fx_test.config")
+	})
+
+	t.Run("inherit without parent", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, Inherit(new(*config)))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			"fx.Inherit Option should be passed to an App built by fx.SubAppFactory")
+	})
+
+	t.Run("share parent container", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &config{name: "foo"}
+		var factory SubAppFactory
+		parent := fxtest.New(t,
+			SubApps(),
+			Supply(cfg),
+			Populate(&factory),
+		)
+		defer parent.RequireStart().RequireStop()
+
+		var hooks []string
+		newTenant := func(name string) *App {
+			return factory.New(
+				ShareParentContainer(),
+				Module("tenant",
+					Provide(func(c *config, lc Lifecycle) *plugin {
+						assert.Same(t, cfg, c, "child must receive the parent's instance")
+						lc.Append(Hook{OnStop: func(context.Context) error {
+							hooks = append(hooks, name+" stopped")
+							return nil
+						}})
+						return &plugin{name: name}
+					}),
+				),
+				Invoke(func(p *plugin) { assert.Equal(t, name, p.name) }),
+			)
+		}
+
+		a, b := newTenant("a"), newTenant("b")
+		require.NoError(t, a.Err())
+		require.NoError(t, b.Err())
+
+		require.NoError(t, a.Start(context.Background()))
+		require.NoError(t, b.Start(context.Background()))
+		require.NoError(t, a.Stop(context.Background()))
+		assert.Equal(t, []string{"a stopped"}, hooks,
+			"children must have their own lifecycle")
+
+		err := parent.Try(Invoke(func(*plugin) {}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing type: *fx_test.plugin",
+			"values of the child must not reach the parent")
+	})
+
+	t.Run("scope of a child sharing the parent container", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &config{name: "foo"}
+		var factory SubAppFactory
+		parent := fxtest.New(t,
+			SubApps(),
+			Supply(cfg),
+			Populate(&factory),
+		)
+		defer parent.RequireStart().RequireStop()
+
+		child := factory.New(
+			ShareParentContainer(),
+			Provide(func() *plugin { return &plugin{name: "child"} }),
+		)
+		require.NoError(t, child.Err())
+
+		scope, err := child.NewScope(Invoke(func(c *config, p *plugin) {
+			assert.Same(t, cfg, c, "scope must receive the parent's instance")
+			assert.Equal(t, "child", p.name)
+		}))
+		require.NoError(t, err)
+		require.NoError(t, scope.Close(context.Background()))
+	})
+
+	t.Run("share parent container from a trial", func(t *testing.T) {
+		t.Parallel()
+
+		var factory SubAppFactory
+		parent := fxtest.New(t, SubApps(), Populate(&factory))
+
+		done := make(chan error, 1)
+		go func() {
+			done <- parent.Try(Invoke(func() error {
+				return factory.New(ShareParentContainer()).Err()
+			}))
+		}()
+
+		select {
+		case err := <-done:
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "the container is in use by a function run by App.Try")
+		case <-time.After(5 * time.Second):
+			t.Fatal("building a child from a trial of its parent deadlocked")
+		}
+
+		// The parent's container is usable again.
+		require.NoError(t, factory.New(ShareParentContainer()).Err())
+		require.NoError(t, parent.Try(Invoke(func() {})))
+	})
+
+	t.Run("inherit from a trial", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &config{name: "foo"}
+		var factory SubAppFactory
+		parent := fxtest.New(t, SubApps(), Supply(cfg), Populate(&factory))
+
+		err := parent.Try(Invoke(func() error {
+			return factory.New(Inherit(new(*config))).Err()
+		}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "the container is in use by a function run by App.Try")
+	})
+
+	t.Run("use parent container while sharing it", func(t *testing.T) {
+		t.Parallel()
+
+		var factory SubAppFactory
+		parent := fxtest.New(t,
+			SubApps(),
+			Provide(func() *config { return &config{name: "parent"} }),
+			Invoke(func(*config) {}),
+			Populate(&factory),
+		)
+
+		tests := []struct {
+			desc string
+			call func() error
+		}{
+			{desc: "Resolve", call: func() error {
+				_, err := Resolve[*config](parent.App)
+				return err
+			}},
+			{desc: "HealthCheck", call: func() error {
+				_, err := parent.HealthCheck(context.Background())
+				return err
+			}},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				done := make(chan error, 1)
+				go func() {
+					done <- factory.New(ShareParentContainer(), Invoke(tt.call)).Err()
+				}()
+
+				select {
+				case err := <-done:
+					require.Error(t, err)
+					assert.Contains(t, err.Error(), "the container is in use by a function run by App.Try")
+				case <-time.After(5 * time.Second):
+					t.Fatal("using the parent's container from a child sharing it deadlocked")
+				}
+
+				// The parent's container is usable again.
+				require.NoError(t, tt.call())
+			})
+		}
+	})
+
+	t.Run("share parent container alongside a trial", func(t *testing.T) {
+		t.Parallel()
+
+		var factory SubAppFactory
+		parent := fxtest.New(t, SubApps(), Populate(&factory))
+
+		entered, release := make(chan struct{}), make(chan struct{})
+		tried := make(chan error, 1)
+		go func() {
+			tried <- parent.Try(Invoke(func() {
+				close(entered)
+				<-release
+			}))
+		}()
+		<-entered
+
+		// The child is built once the trial returns.
+		built := make(chan error, 1)
+		go func() {
+			built <- factory.New(ShareParentContainer()).Err()
+		}()
+		select {
+		case <-built:
+			t.Fatal("child must not be built alongside a trial of its parent")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		close(release)
+		require.NoError(t, <-tried)
+		require.NoError(t, <-built)
+	})
+
+	t.Run("applied options", func(t *testing.T) {
+		t.Parallel()
+
+		var factory SubAppFactory
+		fxtest.New(t, SubApps(), Populate(&factory))
+
+		child := factory.New(Supply(&config{}))
+		require.NoError(t, child.Err())
+		assert.Equal(t, []string{"fx.Supply(*fx_test.config)"}, child.AppliedOptions())
+	})
+
+	t.Run("share parent container without parent", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, ShareParentContainer())
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			"fx.ShareParentContainer Option should be passed to an App built by fx.SubAppFactory")
+	})
+
+	t.Run("in module", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, Module("child", SubApps()))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			"fx.SubApps Option should be passed to top-level App, not to fx.Module")
+	})
+}
@@ -79,6 +79,10 @@ import (
 //	fx.Supply(
 //		fx.Annotate(handler, fx.As(new(http.Handler))),
 //	)
+//
+// Or, more simply, with [SupplyAs].
+//
+//	fx.SupplyAs[http.Handler](handler)
 func Supply(values ...interface{}) Option {
 	constructors := make([]interface{}, 0, len(values))
 	types := make([]reflect.Type, 0, len(values))
@@ -113,7 +117,37 @@ func Supply(values ...interface{}) Option {
 	}
 }
 
+// SupplyAs provides value as a T for dependency injection,
+// as if it had been provided using a constructor that simply returns it.
+// Unlike [Supply], it uses T rather than the most specific type of value,
+// so it can supply a value as an interface.
+//
+//	fx.SupplyAs[http.Handler](handler)
+//
+// Is equivalent to,
+//
+//	fx.Supply(fx.Annotate(handler, fx.As(new(http.Handler))))
+//
+// To restrict access to the value, use [Supply] with [Private]
+// and the form above.
+//
+// SupplyAs panics if T is the error type.
+func SupplyAs[T any](value T) Option {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ == _typeOfError {
+		panic("error type passed to fx.SupplyAs")
+	}
+
+	return supplyOption{
+		Name:    "fx.SupplyAs",
+		Targets: []interface{}{func() T { return value }},
+		Types:   []reflect.Type{typ},
+		Stack:   fxreflect.CallerStack(1, 0),
+	}
+}
+
 type supplyOption struct {
+	Name    string // name of the option, if not fx.Supply
 	Targets []interface{}
 	Types   []reflect.Type // type of value produced by constructor[i]
 	Stack   fxreflect.Stack
@@ -137,7 +171,11 @@ func (o supplyOption) String() string {
 	for _, typ := range o.Types {
 		items = append(items, typ.String())
 	}
-	return fmt.Sprintf("fx.Supply(%s)", strings.Join(items, ", "))
+	name := o.Name
+	if name == "" {
+		name = "fx.Supply"
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(items, ", "))
 }
 
 // Returns a function that takes no parameters, and returns the given value.
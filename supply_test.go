@@ -131,6 +131,44 @@ func TestSupply(t *testing.T) {
 		require.Same(t, &give, out.Got)
 	})
 
+	t.Run("SupplyAs", func(t *testing.T) {
+		t.Parallel()
+
+		var out struct {
+			fx.In
+
+			Got io.Writer
+		}
+
+		var spy fxlog.Spy
+		var give bytes.Buffer
+		app := fxtest.New(t,
+			fx.WithLogger(func() fxevent.Logger { return &spy }),
+			fx.SupplyAs[io.Writer](&give),
+			fx.Populate(&out),
+		)
+		defer app.RequireStart().RequireStop()
+
+		require.Same(t, &give, out.Got)
+
+		supplied := spy.Events().SelectByTypeName("Supplied")
+		require.Len(t, supplied, 1)
+		assert.Equal(t, "io.Writer", supplied[0].(*fxevent.Supplied).TypeName)
+	})
+
+	t.Run("SupplyAsNil", func(t *testing.T) {
+		t.Parallel()
+
+		var got io.Writer = &bytes.Buffer{}
+		app := fxtest.New(t,
+			fx.SupplyAs[io.Writer](nil),
+			fx.Populate(&got),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Nil(t, got)
+	})
+
 	t.Run("InvalidArgumentIsSupplied", func(t *testing.T) {
 		t.Parallel()
 
@@ -152,6 +190,13 @@ func TestSupply(t *testing.T) {
 			func() { fx.Supply(A{}, errors.New("fail")) },
 			"an error value should panic",
 		)
+
+		require.PanicsWithValuef(
+			t,
+			"error type passed to fx.SupplyAs",
+			func() { fx.SupplyAs[error](errors.New("fail")) },
+			"supplying an error should panic",
+		)
 	})
 
 	t.Run("SupplyCollision", func(t *testing.T) {
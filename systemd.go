@@ -0,0 +1,163 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SystemdNotify makes the application report its state to systemd
+// with the sd_notify protocol, for services with Type=notify.
+//
+// The application sends READY=1 once all OnStart hooks have succeeded,
+// and STOPPING=1 when it starts shutting down.
+// If the service has a watchdog (WatchdogSec=), the application also
+// sends WATCHDOG=1 at half the watchdog interval while it's running.
+// The watchdog is driven by the application's [Clock].
+//
+// SystemdNotify has no effect when the process wasn't started by
+// systemd, that is, when the NOTIFY_SOCKET environment variable is unset.
+// Failures to notify systemd are ignored: systemd reports services that
+// don't notify it as failed on its own.
+func SystemdNotify() Option {
+	return systemdNotifyOption{}
+}
+
+type systemdNotifyOption struct{}
+
+func (systemdNotifyOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.SystemdNotify Option should be passed to top-level App, " +
+			"not to fx.Module")
+		return
+	}
+	m.app.systemd = newSystemdNotifier(os.Getenv)
+}
+
+func (systemdNotifyOption) String() string {
+	return "fx.SystemdNotify()"
+}
+
+// systemdNotifier sends sd_notify messages to systemd.
+// A nil systemdNotifier sends nothing.
+type systemdNotifier struct {
+	socket   string        // $NOTIFY_SOCKET
+	watchdog time.Duration // interval between watchdog pings, or zero
+
+	mu           sync.Mutex
+	stopWatchdog chan struct{} // closed to stop the watchdog, if running
+	watchdogDone chan struct{} // closed once the watchdog has stopped
+}
+
+// newSystemdNotifier builds a systemdNotifier from the environment
+// systemd starts services with.
+// It returns nil if the process wasn't started by systemd.
+func newSystemdNotifier(getenv func(string) string) *systemdNotifier {
+	socket := getenv("NOTIFY_SOCKET")
+	if len(socket) == 0 {
+		return nil
+	}
+
+	n := &systemdNotifier{socket: socket}
+	// WATCHDOG_PID names the process that must send watchdog pings,
+	// if it isn't the main process of the service.
+	if pid := getenv("WATCHDOG_PID"); len(pid) > 0 && pid != strconv.Itoa(os.Getpid()) {
+		return n
+	}
+	if usec, err := strconv.ParseInt(getenv("WATCHDOG_USEC"), 10, 64); err == nil && usec > 0 {
+		// Ping twice per interval as recommended by sd_watchdog_enabled(3).
+		n.watchdog = time.Duration(usec) * time.Microsecond / 2
+	}
+	return n
+}
+
+// notify sends the given message to systemd.
+func (n *systemdNotifier) notify(state string) error {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: n.socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// ready tells systemd that the application has started,
+// and starts pinging its watchdog.
+func (n *systemdNotifier) ready(clock Clock) {
+	if n == nil {
+		return
+	}
+	_ = n.notify("READY=1")
+
+	if n.watchdog <= 0 {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.stopWatchdog != nil {
+		return // already running
+	}
+	n.stopWatchdog = make(chan struct{})
+	n.watchdogDone = make(chan struct{})
+	go n.pingWatchdog(clock, n.stopWatchdog, n.watchdogDone)
+}
+
+// stopping tells systemd that the application is shutting down,
+// and stops pinging its watchdog.
+func (n *systemdNotifier) stopping() {
+	if n == nil {
+		return
+	}
+
+	n.mu.Lock()
+	if n.stopWatchdog != nil {
+		close(n.stopWatchdog)
+		<-n.watchdogDone
+		n.stopWatchdog, n.watchdogDone = nil, nil
+	}
+	n.mu.Unlock()
+
+	_ = n.notify("STOPPING=1")
+}
+
+func (n *systemdNotifier) pingWatchdog(clock Clock, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	for {
+		ctx, cancel := clock.WithTimeout(context.Background(), n.watchdog)
+		select {
+		case <-stop:
+			cancel()
+			return
+		case <-ctx.Done():
+			cancel()
+			_ = n.notify("WATCHDOG=1")
+		}
+	}
+}
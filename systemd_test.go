@@ -0,0 +1,161 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/internal/fxclock"
+)
+
+func TestNewSystemdNotifier(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc string
+		env  map[string]string
+		want *systemdNotifier
+	}{
+		{
+			desc: "not started by systemd",
+			env:  map[string]string{"WATCHDOG_USEC": "1000000"},
+		},
+		{
+			desc: "no watchdog",
+			env:  map[string]string{"NOTIFY_SOCKET": "/run/notify"},
+			want: &systemdNotifier{socket: "/run/notify"},
+		},
+		{
+			desc: "watchdog",
+			env: map[string]string{
+				"NOTIFY_SOCKET": "/run/notify",
+				"WATCHDOG_USEC": "1000000",
+			},
+			want: &systemdNotifier{socket: "/run/notify", watchdog: 500 * time.Millisecond},
+		},
+		{
+			desc: "watchdog for this process",
+			env: map[string]string{
+				"NOTIFY_SOCKET": "/run/notify",
+				"WATCHDOG_USEC": "1000000",
+				"WATCHDOG_PID":  strconv.Itoa(os.Getpid()),
+			},
+			want: &systemdNotifier{socket: "/run/notify", watchdog: 500 * time.Millisecond},
+		},
+		{
+			desc: "watchdog for another process",
+			env: map[string]string{
+				"NOTIFY_SOCKET": "/run/notify",
+				"WATCHDOG_USEC": "1000000",
+				"WATCHDOG_PID":  strconv.Itoa(os.Getpid() + 1),
+			},
+			want: &systemdNotifier{socket: "/run/notify"},
+		},
+		{
+			desc: "invalid watchdog",
+			env: map[string]string{
+				"NOTIFY_SOCKET": "/run/notify",
+				"WATCHDOG_USEC": "soon",
+			},
+			want: &systemdNotifier{socket: "/run/notify"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			got := newSystemdNotifier(func(key string) string { return tt.env[key] })
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSystemdNotify(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("systemd does not run on Windows")
+	}
+
+	// Unix socket paths are limited to about 100 bytes,
+	// which t.TempDir can exceed.
+	dir, err := os.MkdirTemp("", "fx")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	socket := filepath.Join(dir, "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	receive := func() string {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		buf := make([]byte, 64)
+		n, err := conn.Read(buf)
+		require.NoError(t, err)
+		return string(buf[:n])
+	}
+
+	clock := fxclock.NewMock()
+	var started bool
+	app := New(
+		NopLogger,
+		WithClock(clock),
+		Invoke(func(lc Lifecycle) {
+			lc.Append(StartHook(func() { started = true }))
+		}),
+	)
+	require.NoError(t, app.Err())
+	app.systemd = &systemdNotifier{socket: socket, watchdog: time.Second}
+
+	ctx := context.Background()
+	require.NoError(t, app.Start(ctx))
+	assert.Equal(t, "READY=1", receive())
+	assert.True(t, started, "READY=1 must be sent after OnStart hooks ran")
+
+	for i := 0; i < 3; i++ {
+		clock.AwaitScheduled(1)
+		clock.Add(time.Second)
+		assert.Equal(t, "WATCHDOG=1", receive(), "ping %d", i)
+	}
+
+	require.NoError(t, app.Stop(ctx))
+	assert.Equal(t, "STOPPING=1", receive())
+}
+
+func TestSystemdNotifyOption(t *testing.T) {
+	t.Parallel()
+
+	app := New(NopLogger, Module("child", SystemdNotify()))
+	assert.ErrorContains(t, app.Err(),
+		"fx.SystemdNotify Option should be passed to top-level App, not to fx.Module")
+}
@@ -0,0 +1,269 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"runtime/pprof"
+	"runtime/trace"
+	"time"
+
+	"go.uber.org/dig"
+	"go.uber.org/fx/internal/fxclock"
+)
+
+// TraceRegions makes Fx run every constructor and lifecycle hook in a
+// [runtime/trace] region, so that the startup and shutdown of the
+// application can be inspected with "go tool trace" alongside
+// other runtime events.
+//
+// Regions are named after the function they run, prefixed with
+// "fx.Provide: ", "fx.OnStart: ", or "fx.OnStop: ".
+// Hook regions belong to the task of the context passed to [App.Start]
+// or [App.Stop], if any.
+//
+// Constructor regions do not nest: Fx builds the dependencies of a
+// constructor before calling it, so the regions of the constructors of
+// its dependencies precede its own region rather than enclosing it.
+// Constructors run by a hook, such as those of an [App.Try] or
+// [App.NewScope] called from it, run in regions nested in the hook's.
+//
+// Regions are only recorded while a trace is being collected,
+// but TraceRegions adds a small overhead to every hook call
+// even when it is not.
+func TraceRegions() Option {
+	return traceRegionsOption{}
+}
+
+type traceRegionsOption struct{}
+
+func (traceRegionsOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.TraceRegions Option should be passed to top-level App, " +
+			"not to fx.Module")
+	} else {
+		m.app.traceRegions = true
+	}
+}
+
+func (traceRegionsOption) String() string {
+	return "fx.TraceRegions()"
+}
+
+// A Tracer records the steps Fx takes to build, start, and stop an
+// application as spans of a distributed trace. See [WithTracer].
+//
+// The go.uber.org/fx/fxotel module provides a Tracer for OpenTelemetry.
+type Tracer interface {
+	// StartSpan starts a span named name as a child of the span of ctx,
+	// if any, and returns a context that carries the new span.
+	// Fx calls end once the step is over, with its error, if any.
+	StartSpan(ctx context.Context, name string) (_ context.Context, end func(error))
+}
+
+// WithTracer makes Fx record spans with t while it builds, starts, and
+// stops the application:
+//
+//   - An "fx.New" span covers [New].
+//     Constructors it runs are recorded as its children.
+//   - "fx.Start" and "fx.Stop" spans cover [App.Start] and [App.Stop],
+//     and [App.Run] calls them. OnStart and OnStop hooks they run are
+//     recorded as their children.
+//
+// Spans of constructors and hooks are named like the regions of
+// [TraceRegions]. Hooks receive the context of their own span,
+// so spans started from it are nested in it.
+// The "fx.Start" and "fx.Stop" spans are children of the span of the
+// context passed to [App.Start] or [App.Stop], and the "fx.New" span is
+// a child of the span of the context of [WithRootContext], if any.
+//
+// Constructors that run after New returns, such as those run by
+// [App.Try] or [App.NewScope], have no parent span: they don't receive
+// a context to take it from.
+func WithTracer(t Tracer) Option {
+	return tracerOption{t}
+}
+
+type tracerOption struct{ t Tracer }
+
+func (o tracerOption) apply(m *module) {
+	switch {
+	case m.parent != nil:
+		m.app.err = fmt.Errorf("fx.WithTracer Option should be passed to top-level App, " +
+			"not to fx.Module")
+	case o.t == nil:
+		m.app.err = errors.New("fx.WithTracer: tracer must not be nil")
+	default:
+		m.app.tracer = o.t
+	}
+}
+
+func (o tracerOption) String() string {
+	return fmt.Sprintf("fx.WithTracer(%T)", o.t)
+}
+
+// startSpan starts a span named name with the application's tracer,
+// if it has one, and returns a function that ends it.
+func (app *App) startSpan(ctx context.Context, name string) (context.Context, func(error)) {
+	if app.tracer == nil {
+		return ctx, func(error) {}
+	}
+	return app.tracer.StartSpan(ctx, name)
+}
+
+// TimeConstructors makes Fx measure how long every constructor runs.
+// The runtimes are reported by [fxevent.Run] events
+// and by [App.StartupReport]; without TimeConstructors, they are zero.
+//
+// Measuring adds a small overhead to every constructor call.
+func TimeConstructors() Option {
+	return timeConstructorsOption{}
+}
+
+type timeConstructorsOption struct{}
+
+func (timeConstructorsOption) apply(m *module) {
+	if m.parent != nil {
+		m.app.err = fmt.Errorf("fx.TimeConstructors Option should be passed to top-level App, " +
+			"not to fx.Module")
+	} else {
+		m.app.timeConstructors = true
+	}
+}
+
+func (timeConstructorsOption) String() string {
+	return "fx.TimeConstructors()"
+}
+
+// instrumentConstructor returns a container that records in runtime how
+// long constructors provided to c take to run if the application uses
+// TimeConstructors, and runs them in a trace region named after funcName
+// if it uses TraceRegions,
+// with a pprof label named after it if it uses ProfileStartup,
+// or in a span named after it if it uses WithTracer.
+// If the application uses RecoverFromPanics,
+// it records the stack of their panics in panicStack.
+// It returns c unchanged if the application uses none of these.
+func (app *App) instrumentConstructor(c container, funcName string, runtime *time.Duration, panicStack *[]byte) container {
+	ic := instrumentedContainer{container: c, clock: app.clock}
+	if app.timeConstructors {
+		ic.runtime = runtime
+	}
+	if app.recoverFromPanics {
+		ic.panicStack = panicStack
+	}
+	if app.traceRegions {
+		ic.regionType = "fx.Provide: " + funcName
+	}
+	if app.startupProfile != nil {
+		ic.profileLabel = "fx.Provide: " + funcName
+	}
+	if app.tracer != nil {
+		ic.tracer = app.tracer
+		ic.spanName = "fx.Provide: " + funcName
+		ic.spanParent = &app.newCtx
+	}
+	if ic.runtime == nil && ic.panicStack == nil &&
+		len(ic.regionType) == 0 && len(ic.profileLabel) == 0 && ic.tracer == nil {
+		return c
+	}
+	return ic
+}
+
+// instrumentedContainer wraps constructors provided to it so that their
+// runtime is recorded if runtime is set, so that they run in a runtime/trace region
+// if regionType is set, with a pprof label if profileLabel is set,
+// in a span of tracer that's a child of the one of *spanParent if tracer is set,
+// and so that the stack of their panics is recorded if panicStack is set.
+type instrumentedContainer struct {
+	container
+
+	clock        fxclock.Clock
+	runtime      *time.Duration
+	regionType   string
+	profileLabel string
+	panicStack   *[]byte
+	tracer       Tracer
+	spanName     string
+	spanParent   *context.Context
+}
+
+func (c instrumentedContainer) Provide(ctor interface{}, opts ...dig.ProvideOption) error {
+	fv := reflect.ValueOf(ctor)
+	if fv.Kind() != reflect.Func {
+		return c.container.Provide(ctor, opts...)
+	}
+
+	return provideWrapper(c.container, fv, fv.Type(), func(args []reflect.Value) (results []reflect.Value) {
+		if c.tracer != nil {
+			parent := *c.spanParent
+			if parent == nil {
+				parent = context.Background()
+			}
+			_, end := c.tracer.StartSpan(parent, c.spanName)
+			defer func() {
+				if p := recover(); p != nil {
+					end(fmt.Errorf("panic: %v", p))
+					panic(p)
+				}
+				var err error
+				if n := len(results); n > 0 && fv.Type().Out(n-1) == _typeOfError && !results[n-1].IsNil() {
+					err = results[n-1].Interface().(error)
+				}
+				end(err)
+			}()
+		}
+		if c.runtime != nil {
+			begin := c.clock.Now()
+			defer func() { *c.runtime = c.clock.Since(begin) }()
+		}
+		if c.panicStack != nil {
+			// The stack is gone by the time dig recovers from the panic,
+			// so capture it on the way and let the panic continue.
+			defer func() {
+				if p := recover(); p != nil {
+					*c.panicStack = debug.Stack()
+					panic(p)
+				}
+			}()
+		}
+
+		run := func() { results = callFunc(fv, args) }
+		if len(c.profileLabel) > 0 {
+			unlabeled := run
+			run = func() {
+				pprof.Do(context.Background(), pprof.Labels("fx", c.profileLabel), func(context.Context) {
+					unlabeled()
+				})
+			}
+		}
+		if len(c.regionType) == 0 {
+			run()
+			return results
+		}
+		trace.WithRegion(context.Background(), c.regionType, run)
+		return results
+	}, opts)
+}
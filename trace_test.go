@@ -0,0 +1,324 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/trace"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+type (
+	tracedDB     struct{}
+	tracedServer struct{}
+)
+
+func newTracedDB() *tracedDB { return &tracedDB{} }
+
+func newTracedServer(lc fx.Lifecycle, _ *tracedDB) *tracedServer {
+	lc.Append(fx.StartStopHook(startTracedServer, stopTracedServer))
+	return &tracedServer{}
+}
+
+func startTracedServer(context.Context) error { return nil }
+func stopTracedServer(context.Context) error  { return nil }
+
+type tracedClient struct{}
+
+func newTracedClient() *tracedClient { return &tracedClient{} }
+
+// collectTrace runs f while collecting an execution trace,
+// and returns the trace.
+// Tests that use it must not run in parallel:
+// only one execution trace can be collected at a time.
+func collectTrace(t *testing.T, f func()) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, trace.Start(&buf))
+	f()
+	trace.Stop()
+	return buf.Bytes()
+}
+
+// traceRegion is a region of an execution trace.
+type traceRegion struct {
+	Type   string
+	Parent string // type of the enclosing region, if any
+}
+
+var _regionEventRe = regexp.MustCompile(`^M=\S+ P=\S+ G=(\d+) Region(Begin|End) .*Type="(.*)"$`)
+
+// parseTraceRegions parses the regions of an execution trace with
+// "go tool trace", in the order they begin.
+// It fails the test if the regions of a goroutine don't end in the
+// reverse of the order they began.
+func parseTraceRegions(t *testing.T, data []byte) []traceRegion {
+	goTool := filepath.Join(runtime.GOROOT(), "bin", "go")
+	if _, err := os.Stat(goTool); err != nil {
+		t.Skipf("go tool not available to parse the trace: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "trace.out")
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	out, err := exec.Command(goTool, "tool", "trace", "-d=parsed", path).Output()
+	require.NoError(t, err, "parse trace")
+
+	var regions []traceRegion
+	open := make(map[string][]string) // types of the open regions by goroutine
+	for _, line := range strings.Split(string(out), "\n") {
+		m := _regionEventRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		g, typ := m[1], m[3]
+		stack := open[g]
+		if m[2] == "Begin" {
+			r := traceRegion{Type: typ}
+			if len(stack) > 0 {
+				r.Parent = stack[len(stack)-1]
+			}
+			regions = append(regions, r)
+			open[g] = append(stack, typ)
+			continue
+		}
+		require.NotEmpty(t, stack, "region %q ended without beginning", typ)
+		require.Equal(t, stack[len(stack)-1], typ, "regions must end in the reverse of the order they began")
+		open[g] = stack[:len(stack)-1]
+	}
+	for g, stack := range open {
+		assert.Empty(t, stack, "regions of goroutine %v never ended", g)
+	}
+	return regions
+}
+
+func TestTraceRegions(t *testing.T) {
+	var app *fxtest.App
+	got := collectTrace(t, func() {
+		app = fxtest.New(t,
+			fx.TraceRegions(),
+			fx.Provide(newTracedDB, newTracedServer),
+			fx.Invoke(func(lc fx.Lifecycle, _ *tracedServer) {
+				// Values built by a hook are built within its region.
+				lc.Append(fx.StartHook(func() error {
+					return app.Try(
+						fx.Provide(newTracedClient),
+						fx.Invoke(func(*tracedClient) {}),
+					)
+				}))
+			}),
+		)
+		app.RequireStart().RequireStop()
+	})
+
+	parents := make(map[string]string)
+	for _, r := range parseTraceRegions(t, got) {
+		if strings.HasPrefix(r.Type, "fx.") {
+			parents[r.Type] = r.Parent
+		}
+	}
+
+	for _, region := range []string{
+		"fx.Provide: go.uber.org/fx_test.newTracedDB()",
+		"fx.Provide: go.uber.org/fx_test.newTracedServer()",
+		"fx.OnStart: go.uber.org/fx_test.startTracedServer()",
+		"fx.OnStop: go.uber.org/fx_test.stopTracedServer()",
+	} {
+		parent, ok := parents[region]
+		if assert.True(t, ok, "trace must contain region %q", region) {
+			assert.Empty(t, parent, "region %q must not be nested", region)
+		}
+	}
+
+	parent, ok := parents["fx.Provide: go.uber.org/fx_test.newTracedClient()"]
+	require.True(t, ok, "trace must contain the region of the constructor run by the hook")
+	assert.True(t, strings.HasPrefix(parent, "fx.OnStart: "),
+		"constructor run by a hook must be nested in its region, got parent %q", parent)
+}
+
+func TestTraceRegionsDisabled(t *testing.T) {
+	got := collectTrace(t, func() {
+		app := fxtest.New(t,
+			fx.Provide(newTracedDB, newTracedServer),
+			fx.Invoke(func(*tracedServer) {}),
+		)
+		app.RequireStart().RequireStop()
+	})
+
+	for _, r := range parseTraceRegions(t, got) {
+		assert.False(t, strings.HasPrefix(r.Type, "fx."),
+			"trace must not contain regions without fx.TraceRegions, got %q", r.Type)
+	}
+}
+
+// recordedSpan is a span recorded by a spanRecorder.
+type recordedSpan struct {
+	Parent string // name of the parent span, if any
+	Err    error
+	Ended  bool
+}
+
+type recordedSpanKey struct{}
+
+// spanRecorder is an fx.Tracer that records spans by name.
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans map[string]*recordedSpan
+}
+
+func newSpanRecorder() *spanRecorder {
+	return &spanRecorder{spans: make(map[string]*recordedSpan)}
+}
+
+func (r *spanRecorder) StartSpan(ctx context.Context, name string) (context.Context, func(error)) {
+	var s recordedSpan
+	if parent, ok := ctx.Value(recordedSpanKey{}).(string); ok {
+		s.Parent = parent
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans[name] = &s
+	return context.WithValue(ctx, recordedSpanKey{}, name), func(err error) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		s.Err, s.Ended = err, true
+	}
+}
+
+// Span returns the span named name.
+func (r *spanRecorder) Span(t *testing.T, name string) recordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.spans[name]
+	require.True(t, ok, "span %q must be recorded", name)
+	return *s
+}
+
+func TestWithTracer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("spans", func(t *testing.T) {
+		t.Parallel()
+
+		tracer := newSpanRecorder()
+		rootCtx, endRoot := tracer.StartSpan(context.Background(), "root")
+		defer endRoot(nil)
+
+		var app *fx.App
+		app = fx.New(
+			fx.NopLogger,
+			fx.WithTracer(tracer),
+			fx.WithRootContext(rootCtx),
+			fx.Provide(newTracedDB, newTracedServer),
+			fx.Invoke(func(lc fx.Lifecycle, _ *tracedServer) {
+				lc.Append(fx.StartHook(func(ctx context.Context) error {
+					_, end := tracer.StartSpan(ctx, "hook")
+					end(nil)
+					return app.Try(
+						fx.Provide(newTracedClient),
+						fx.Invoke(func(*tracedClient) {}),
+					)
+				}))
+			}),
+		)
+		require.NoError(t, app.Err())
+
+		startCtx, endStart := tracer.StartSpan(context.Background(), "test start")
+		require.NoError(t, app.Start(startCtx))
+		endStart(nil)
+		stopCtx, endStop := tracer.StartSpan(context.Background(), "test stop")
+		require.NoError(t, app.Stop(stopCtx))
+		endStop(nil)
+
+		for name, parent := range map[string]string{
+			"fx.New":   "root",
+			"fx.Start": "test start",
+			"fx.Stop":  "test stop",
+
+			"fx.Provide: go.uber.org/fx_test.newTracedDB()":       "fx.New",
+			"fx.Provide: go.uber.org/fx_test.newTracedServer()":   "fx.New",
+			"fx.OnStart: go.uber.org/fx_test.startTracedServer()": "fx.Start",
+			"fx.OnStop: go.uber.org/fx_test.stopTracedServer()":   "fx.Stop",
+
+			// Constructors run after New have no parent span.
+			"fx.Provide: go.uber.org/fx_test.newTracedClient()": "",
+		} {
+			s := tracer.Span(t, name)
+			assert.Equal(t, parent, s.Parent, "parent of span %q", name)
+			assert.True(t, s.Ended, "span %q must be ended", name)
+			assert.NoError(t, s.Err, "span %q", name)
+		}
+
+		hook := tracer.Span(t, "hook")
+		assert.True(t, strings.HasPrefix(hook.Parent, "fx.OnStart: "),
+			"spans started by a hook must be nested in its span, got parent %q", hook.Parent)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		tracer := newSpanRecorder()
+		app := fx.New(
+			fx.NopLogger,
+			fx.WithTracer(tracer),
+			fx.Provide(func() (*tracedDB, error) {
+				return nil, errors.New("great sadness")
+			}),
+			fx.Invoke(func(*tracedDB) {}),
+		)
+		require.Error(t, app.Err())
+
+		s := tracer.Span(t, "fx.Provide: go.uber.org/fx_test.TestWithTracer.func2.1()")
+		assert.Equal(t, "fx.New", s.Parent)
+		assert.ErrorContains(t, s.Err, "great sadness")
+		assert.ErrorContains(t, tracer.Span(t, "fx.New").Err, "great sadness")
+	})
+
+	t.Run("module", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(
+			fx.NopLogger,
+			fx.Module("foo", fx.WithTracer(newSpanRecorder())),
+		)
+		assert.ErrorContains(t, app.Err(),
+			"fx.WithTracer Option should be passed to top-level App, not to fx.Module")
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		app := fx.New(fx.NopLogger, fx.WithTracer(nil))
+		assert.ErrorContains(t, app.Err(), "fx.WithTracer: tracer must not be nil")
+	})
+}
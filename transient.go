@@ -0,0 +1,349 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.uber.org/dig"
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// Transient is an option that can be passed as an argument to [Provide]
+// to have its constructors build a new value for every function that
+// depends on it, instead of a single value shared by all of them.
+//
+//	fx.Provide(NewRateLimiter, fx.Transient),
+//	fx.Invoke(func(a *RateLimiter, b *RateLimiter) {
+//		// a and b are different values
+//	}),
+//
+// A transient constructor must return a single value, optionally followed
+// by an error. Its dependencies are built once, as usual, and are shared
+// by the values it builds, unless they are transient themselves.
+//
+// Parameters of constructors, decorators, and invoked functions receive
+// transient values, and so do the fields of parameter objects that embed
+// [In], including optional ones.
+// Transient values are never named nor part of a value group:
+// fields tagged with a name or group receive values provided without
+// Transient.
+// Transient values are not passed to [Decorate]d functions of their
+// own type, and [MapResult] doesn't apply to them.
+var Transient = transientOption{}
+
+type transientOption struct{}
+
+func (transientOption) String() string {
+	return "fx.Transient"
+}
+
+// Name under which the factories of transient values are provided.
+const _transientName = "fx.transient"
+
+// transients holds the types provided with Transient.
+type transients struct {
+	types map[reflect.Type]struct{}
+}
+
+// add records the type built by ctor, which was provided with Transient.
+func (t *transients) add(ctor interface{}) error {
+	ft := reflect.TypeOf(ctor)
+	if ft == nil || ft.Kind() != reflect.Func {
+		return fmt.Errorf("fx.Transient: %v must be a function", ctor)
+	}
+
+	switch {
+	case ft.NumOut() == 2 && ft.Out(1) == _typeOfError:
+	case ft.NumOut() == 1:
+	default:
+		return fmt.Errorf("fx.Transient: %v must return a single value, "+
+			"optionally followed by an error", fxreflect.FuncName(ctor))
+	}
+
+	typ := ft.Out(0)
+	if typ == _typeOfError || dig.IsOut(typ) {
+		return fmt.Errorf("fx.Transient: %v must return a single value, "+
+			"optionally followed by an error", fxreflect.FuncName(ctor))
+	}
+	t.types[typ] = struct{}{}
+	return nil
+}
+
+// container returns a container that passes the values of transient types
+// to the functions given to c that depend on them.
+func (t *transients) container(c container) container {
+	if t == nil {
+		return c
+	}
+	return transientContainer{container: c, transients: t}
+}
+
+// params returns the parameter types of ft, each replaced as by param.
+// It reports whether any was replaced.
+func (t *transients) params(ft reflect.Type) ([]reflect.Type, bool) {
+	var replaced bool
+	in := make([]reflect.Type, ft.NumIn())
+	for i := range in {
+		var ok bool
+		in[i], ok = t.param(ft.In(i))
+		replaced = replaced || ok
+	}
+	return in, replaced
+}
+
+// param returns the type of parameter to request instead of typ,
+// and reports whether it differs from typ:
+// a parameter object that requests a factory of its values if typ is
+// transient, or a copy of typ whose fields are replaced likewise
+// if it's a parameter object with transient fields.
+func (t *transients) param(typ reflect.Type) (reflect.Type, bool) {
+	if _, ok := t.types[typ]; ok {
+		return transientParamType(typ), true
+	}
+	if typ.Kind() != reflect.Struct || !dig.IsIn(typ) {
+		return typ, false
+	}
+
+	var replaced bool
+	fields := make([]reflect.StructField, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		switch {
+		case f.Type == _typeOfIn:
+		case f.Anonymous:
+			// Embedded types can't always be recreated with StructOf.
+			return typ, false
+		case !f.IsExported():
+			// dig either rejects the parameter object,
+			// or leaves the field unset if it ignores unexported fields.
+			if !ignoresUnexported(typ) {
+				return typ, false
+			}
+			replaced = true
+			continue
+		case len(f.Tag.Get("name")) > 0 || len(f.Tag.Get("group")) > 0:
+		default:
+			if _, ok := t.types[f.Type]; ok {
+				tag := fmt.Sprintf("name:%q", _transientName)
+				if optional := f.Tag.Get("optional"); len(optional) > 0 {
+					tag += fmt.Sprintf(" optional:%q", optional)
+				}
+				f.Type = transientFactoryType(f.Type)
+				f.Tag = reflect.StructTag(tag)
+				replaced = true
+			} else if pt, ok := t.param(f.Type); ok {
+				f.Type = pt
+				replaced = true
+			}
+		}
+		fields = append(fields, f)
+	}
+	if !replaced {
+		return typ, false
+	}
+	return reflect.StructOf(fields), true
+}
+
+// ignoresUnexported reports whether dig leaves the unexported fields
+// of the parameter object typ unset, rather than rejecting it.
+func ignoresUnexported(typ reflect.Type) bool {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.Type == _typeOfIn {
+			return f.Tag.Get("ignore-unexported") == "true"
+		}
+	}
+	return false
+}
+
+// args returns the arguments of a function of type ft, given those of a
+// function with parameters returned by params, as built by arg.
+func (t *transients) args(ft reflect.Type, args []reflect.Value) ([]reflect.Value, error) {
+	built := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		var err error
+		if built[i], err = t.arg(ft.In(i), arg); err != nil {
+			return nil, err
+		}
+	}
+	return built, nil
+}
+
+// arg returns the value of type typ for the argument arg, of the type
+// returned by param, by building a new value for each transient one.
+func (t *transients) arg(typ reflect.Type, arg reflect.Value) (reflect.Value, error) {
+	switch {
+	case arg.Type() == typ:
+		return arg, nil
+	case arg.Kind() == reflect.Func:
+		// The factory of an optional value that isn't provided.
+		if arg.IsNil() {
+			return reflect.Zero(typ), nil
+		}
+		results := arg.Call(nil)
+		if err, _ := results[1].Interface().(error); err != nil {
+			return reflect.Value{}, fmt.Errorf("failed to build %v: %w", typ, err)
+		}
+		return results[0], nil
+	}
+	if _, ok := t.types[typ]; ok {
+		return t.arg(typ, arg.Field(1))
+	}
+
+	v := reflect.New(typ).Elem()
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		fv, err := t.arg(f.Type, arg.FieldByName(f.Name))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.Field(i).Set(fv)
+	}
+	return v, nil
+}
+
+// consumer returns a function that builds the transient values fn
+// depends on before calling it, or fn itself if it doesn't depend on any.
+// The returned function also returns an error if fn doesn't.
+func (t *transients) consumer(fn interface{}) interface{} {
+	fv := reflect.ValueOf(fn)
+	wt, body, ok := t.consume(fv)
+	if !ok {
+		return fn
+	}
+	return reflect.MakeFunc(wt, body).Interface()
+}
+
+// consume returns the type and the body of the function returned by
+// consumer for fv, or false if fv doesn't depend on transient values.
+func (t *transients) consume(fv reflect.Value) (reflect.Type, func([]reflect.Value) []reflect.Value, bool) {
+	if fv.Kind() != reflect.Func {
+		return nil, nil, false
+	}
+	ft := fv.Type()
+	in, ok := t.params(ft)
+	if !ok {
+		return nil, nil, false
+	}
+
+	out := make([]reflect.Type, ft.NumOut())
+	for i := range out {
+		out[i] = ft.Out(i)
+	}
+	failable := len(out) > 0 && out[len(out)-1] == _typeOfError
+	if !failable {
+		out = append(out, _typeOfError)
+	}
+
+	wt := reflect.FuncOf(in, out, ft.IsVariadic())
+	return wt, func(args []reflect.Value) []reflect.Value {
+		built, err := t.args(ft, args)
+		if err != nil {
+			results := make([]reflect.Value, len(out))
+			for i, typ := range out {
+				results[i] = reflect.Zero(typ)
+			}
+			results[len(out)-1] = reflect.ValueOf(&err).Elem()
+			return results
+		}
+
+		results := callFunc(fv, built)
+		if !failable {
+			results = append(results, reflect.Zero(_typeOfError))
+		}
+		return results
+	}, true
+}
+
+// provider returns a constructor of a factory of the values built by ctor,
+// which was provided with Transient.
+// Every call to the factory calls ctor again.
+func (t *transients) provider(ctor interface{}) interface{} {
+	fv := reflect.ValueOf(ctor)
+	ft := fv.Type()
+	typ := ft.Out(0)
+	factory := transientFactoryType(typ)
+
+	in, _ := t.params(ft)
+	pt := reflect.FuncOf(in, []reflect.Type{factory}, ft.IsVariadic())
+	return reflect.MakeFunc(pt, func(args []reflect.Value) []reflect.Value {
+		return []reflect.Value{reflect.MakeFunc(factory, func([]reflect.Value) []reflect.Value {
+			built, err := t.args(ft, args)
+			if err != nil {
+				return []reflect.Value{reflect.Zero(typ), reflect.ValueOf(&err).Elem()}
+			}
+
+			results := callFunc(fv, built)
+			if len(results) == 1 {
+				results = append(results, reflect.Zero(_typeOfError))
+			}
+			return results
+		})}
+	}).Interface()
+}
+
+// transientFactoryType returns the type of factories of values of typ.
+func transientFactoryType(typ reflect.Type) reflect.Type {
+	return reflect.FuncOf(nil, []reflect.Type{typ, _typeOfError}, false)
+}
+
+// transientParamType returns the type of parameter objects that request
+// the factory of values of typ.
+func transientParamType(typ reflect.Type) reflect.Type {
+	return reflect.StructOf([]reflect.StructField{
+		{Name: "In", Type: reflect.TypeOf(In{}), Anonymous: true},
+		{
+			Name: "New",
+			Type: transientFactoryType(typ),
+			Tag:  reflect.StructTag(fmt.Sprintf("name:%q", _transientName)),
+		},
+	})
+}
+
+// transientContainer rewrites functions given to it so that they receive
+// new values of the transient types they depend on.
+type transientContainer struct {
+	container
+
+	transients *transients
+}
+
+func (c transientContainer) Provide(ctor interface{}, opts ...dig.ProvideOption) error {
+	fv := reflect.ValueOf(ctor)
+	ft, body, ok := c.transients.consume(fv)
+	if !ok {
+		return c.container.Provide(ctor, opts...)
+	}
+	return provideWrapper(c.container, fv, ft, body, opts)
+}
+
+func (c transientContainer) Decorate(decorator interface{}, opts ...dig.DecorateOption) error {
+	return c.container.Decorate(c.transients.consumer(decorator), opts...)
+}
+
+func (c transientContainer) Invoke(fn interface{}, opts ...dig.InvokeOption) error {
+	return c.container.Invoke(c.transients.consumer(fn), opts...)
+}
@@ -0,0 +1,281 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestTransient(t *testing.T) {
+	t.Parallel()
+
+	type config struct{}
+	type limiter struct{ cfg *config }
+	type handler struct{ l *limiter }
+
+	t.Run("new value per consumer", func(t *testing.T) {
+		t.Parallel()
+
+		var configs, limiters int
+		var handlers []*handler
+		app := fxtest.New(t,
+			Provide(func() *config {
+				configs++
+				return &config{}
+			}),
+			Provide(func(cfg *config) *limiter {
+				limiters++
+				return &limiter{cfg: cfg}
+			}, Transient),
+			Module("handlers",
+				Provide(func(l *limiter) (*handler, error) {
+					h := &handler{l: l}
+					handlers = append(handlers, h)
+					return h, nil
+				}, Private),
+				Invoke(func(h *handler, l *limiter) {
+					assert.NotSame(t, h.l, l)
+				}),
+				Invoke(func(h *handler, l1, l2 *limiter) {
+					assert.NotSame(t, l1, l2)
+					assert.Same(t, handlers[0], h)
+				}),
+			),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, 1, configs, "dependencies must be shared")
+		assert.Equal(t, 4, limiters)
+		require.Len(t, handlers, 1, "consumers must be memoized")
+	})
+
+	t.Run("transient dependencies", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			Provide(func() *limiter { return &limiter{} }, Transient),
+			Provide(func(l *limiter) *handler { return &handler{l: l} }, Transient),
+			Invoke(func(h1, h2 *handler) {
+				assert.NotSame(t, h1, h2)
+				assert.NotSame(t, h1.l, h2.l)
+			}),
+		)
+		defer app.RequireStart().RequireStop()
+	})
+
+	t.Run("decorators", func(t *testing.T) {
+		t.Parallel()
+
+		var decorated bool
+		app := fxtest.New(t,
+			Provide(func() *limiter { return &limiter{} }, Transient),
+			Provide(func() *handler { return &handler{} }),
+			Decorate(func(h *handler, l *limiter) *handler {
+				decorated = true
+				return &handler{l: l}
+			}),
+			Invoke(func(h *handler, l *limiter) {
+				assert.NotNil(t, h.l)
+				assert.NotSame(t, h.l, l)
+			}),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.True(t, decorated)
+	})
+
+	t.Run("provided event", func(t *testing.T) {
+		t.Parallel()
+
+		app, spy := NewSpied(
+			Provide(func() *limiter { return &limiter{} }, Transient),
+		)
+		require.NoError(t, app.Err())
+
+		provided := spy.Events().SelectByTypeName("Provided")
+		require.NotEmpty(t, provided)
+		last := provided[len(provided)-1].(*fxevent.Provided)
+		assert.Equal(t, []string{"*fx_test.limiter"}, last.OutputTypeNames)
+	})
+
+	t.Run("constructor error", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			Provide(func() (*limiter, error) {
+				return nil, errors.New("great sadness")
+			}, Transient),
+			Invoke(func(*limiter) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to build *fx_test.limiter")
+		assert.Contains(t, err.Error(), "great sadness")
+	})
+
+	t.Run("parameter objects", func(t *testing.T) {
+		t.Parallel()
+
+		type nested struct {
+			In
+
+			Limiter *limiter
+		}
+		type params struct {
+			In
+
+			Limiter  *limiter
+			Optional *limiter `optional:"true"`
+			Named    *limiter `name:"shared"`
+			Nested   nested
+			Config   *config
+		}
+		type unexported struct {
+			In `ignore-unexported:"true"`
+
+			Limiter *limiter
+			limiter *limiter
+		}
+
+		shared := &limiter{}
+		var limiters int
+		app := fxtest.New(t,
+			Provide(func() *config { return &config{} }),
+			Provide(func() *limiter {
+				limiters++
+				return &limiter{}
+			}, Transient),
+			Supply(Annotated{Name: "shared", Target: shared}),
+			Invoke(func(p params, l *limiter) {
+				assert.NotNil(t, p.Limiter)
+				assert.NotNil(t, p.Optional)
+				assert.NotNil(t, p.Nested.Limiter)
+				assert.NotNil(t, p.Config)
+				assert.Same(t, shared, p.Named, "named values are not transient")
+				assert.NotSame(t, p.Limiter, p.Optional)
+				assert.NotSame(t, p.Limiter, p.Nested.Limiter)
+				assert.NotSame(t, p.Limiter, l)
+			}),
+			Invoke(func(p unexported) {
+				assert.NotNil(t, p.Limiter)
+				assert.Nil(t, p.limiter)
+			}),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, 5, limiters)
+	})
+
+	t.Run("optional parameter object fields", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			In
+
+			Limiter *limiter `optional:"true"`
+		}
+
+		app := fxtest.New(t,
+			Provide(func() *handler { return &handler{} }, Transient),
+			Invoke(func(p params) {
+				assert.Nil(t, p.Limiter)
+			}),
+		)
+		defer app.RequireStart().RequireStop()
+	})
+
+	t.Run("parameter object constructor error", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			In
+
+			Limiter *limiter
+		}
+
+		app := NewForTest(t,
+			Provide(func() (*limiter, error) {
+				return nil, errors.New("great sadness")
+			}, Transient),
+			Invoke(func(params) {}),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to build *fx_test.limiter")
+		assert.Contains(t, err.Error(), "great sadness")
+	})
+
+	t.Run("invalid constructors", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc    string
+			give    interface{}
+			wantErr string
+		}{
+			{
+				desc:    "not a function",
+				give:    &limiter{},
+				wantErr: "fx.Transient: &{<nil>} must be a function",
+			},
+			{
+				desc:    "several results",
+				give:    func() (*limiter, *handler) { return nil, nil },
+				wantErr: "must return a single value, optionally followed by an error",
+			},
+			{
+				desc:    "only an error",
+				give:    func() error { return nil },
+				wantErr: "must return a single value, optionally followed by an error",
+			},
+			{
+				desc: "result object",
+				give: func() struct {
+					Out
+
+					Limiter *limiter
+				} {
+					panic("unreachable")
+				},
+				wantErr: "must return a single value, optionally followed by an error",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := NewForTest(t, Provide(tt.give, Transient))
+				err := app.Err()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+}
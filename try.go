@@ -0,0 +1,255 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/dig"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/internal/fxreflect"
+	"go.uber.org/multierr"
+)
+
+// Try speculatively applies the given options to a trial scope of the
+// application and runs any functions given to [Invoke] within it.
+// It returns the first error encountered, and leaves the application
+// unaffected if one occurs; this makes it suitable for probing optional
+// features and falling back if they can't be initialized.
+//
+// The trial scope behaves like a [Module] nested under the application:
+// it can depend on any type the application exports,
+// and it may shadow them.
+// Values provided within the trial, including values exported by modules
+// nested in it, stay in the trial scope and are never visible to the
+// application, whether or not the trial succeeds.
+//
+// Values of the application's own constructors that are built to satisfy
+// the trial are retained by the application,
+// exactly as if an [Invoke] had requested them.
+//
+// Constructors and invoked functions within the trial receive a [Lifecycle]
+// that discards hooks appended to it: no OnStart or OnStop hooks from the
+// trial are ever run.
+//
+// Options that affect the whole application rather than a scope,
+// such as [BeforeStop], [StartMiddleware], [OnEvent] and [MapResult],
+// fail the trial.
+//
+// Try returns the application's initialization error, if any, without
+// running the trial. A failed trial never changes the error reported by
+// [App.Err]. Try may be called concurrently with other methods of the App:
+// Fx runs trials one at a time, and never alongside [App.Resolve]
+// or [App.HealthCheck]. Functions run by the trial that call these methods,
+// or [App.NewScope], on the application get an error from them.
+func (app *App) Try(opts ...Option) error {
+	mu := app.containerLock()
+	if err := mu.Lock(); err != nil {
+		return err
+	}
+	defer mu.Unlock()
+
+	if app.newErr != nil {
+		return app.newErr
+	}
+
+	trial := &module{
+		name:   "fx.Try",
+		parent: app.root,
+		trace: append(
+			[]string{fmt.Sprintf("%v (fx.Try)", fxreflect.CallerStack(1, 2)[0])},
+			app.root.trace...,
+		),
+		app:   app,
+		trial: "App.Try",
+	}
+
+	// Errors from the trial are recorded on the App while it runs,
+	// as they would be for New. They belong to the trial only.
+	err := useContainer(func() error {
+		return app.try(trial, discardLifecycle{}, opts)
+	})
+	app.err = nil
+	return err
+}
+
+// try applies opts to trial, a module created by Try or NewScope,
+// and runs the functions given to Invoke within it.
+// Constructors and invoked functions within the trial receive lc.
+func (app *App) try(trial *module, lc Lifecycle, opts []Option) error {
+	for _, opt := range opts {
+		opt.apply(trial)
+	}
+	if app.err != nil {
+		return app.err
+	}
+
+	c, err := app.trialContainer()
+	if err != nil {
+		return err
+	}
+	trial.build(app, c)
+	if err := trial.scope.Provide(func() Lifecycle { return lc }); err != nil {
+		return err
+	}
+
+	trial.provideAll()
+	if app.err == nil {
+		app.err = trial.evaluateAll()
+	}
+	app.err = multierr.Append(app.err, trial.decorateAll())
+	trial.constructAllCustomLoggers()
+	if app.err != nil {
+		return app.err
+	}
+
+	if err := trial.deriveAll(); err != nil {
+		return err
+	}
+	_, err = trial.executeInvokes()
+	return err
+}
+
+// trialContainer returns a new container for a module created by Try or
+// NewScope, which provides the values available to the root of the
+// application's container by resolving them from it.
+//
+// The trial could instead use a scope of the application's container,
+// but the container would then retain the trial's values forever.
+func (app *App) trialContainer() (*dig.Container, error) {
+	c := dig.New(app.containerOptions()...)
+	seen := make(map[outputKey]struct{})
+	provide := func(key outputKey) error {
+		if _, ok := seen[key]; ok {
+			return nil
+		}
+		seen[key] = struct{}{}
+		ctor, opts := app.rootValueProvider(key)
+		return c.Provide(ctor, append(opts, _applicationValueLocation)...)
+	}
+
+	// Applications that share the container of their parent
+	// can also resolve the values of its root.
+	for a := app; a != nil; a = a.parent {
+		for _, p := range a.root.provided {
+			if err := provide(p.outputKey); err != nil {
+				return nil, err
+			}
+		}
+		if a.root.logConstructor != nil {
+			if err := provide(outputKey{Type: _typeOfLogger}); err != nil {
+				return nil, err
+			}
+		}
+		if !a.sharedContainer {
+			break
+		}
+	}
+	return c, nil
+}
+
+var _typeOfLogger = reflect.TypeOf((*fxevent.Logger)(nil)).Elem()
+
+// applicationValue names the constructors of rootValueProvider
+// in the errors of the container of a trial.
+func applicationValue() {}
+
+var _applicationValueLocation = dig.LocationForPC(reflect.ValueOf(applicationValue).Pointer())
+
+// rootValueProvider returns a constructor that resolves the value
+// identified by key from the root of the application's container,
+// and the options to provide it with.
+// Values of a group are resolved together.
+func (app *App) rootValueProvider(key outputKey) (interface{}, []dig.ProvideOption) {
+	// Transient values are provided as factories under _transientName,
+	// so they're resolved like any other named value.
+	typ := key.Type
+	var opts []dig.ProvideOption
+	param := reflect.StructField{Name: "Value", Type: typ}
+	result := typ
+	switch {
+	case len(key.Group) > 0:
+		param.Type = reflect.SliceOf(typ)
+		param.Tag = reflect.StructTag(fmt.Sprintf("group:%q", key.Group))
+		result = reflect.StructOf([]reflect.StructField{
+			{Name: "Out", Type: _typeOfOut, Anonymous: true},
+			{
+				Name: "Value",
+				Type: param.Type,
+				Tag:  reflect.StructTag(fmt.Sprintf("group:%q", key.Group+",flatten")),
+			},
+		})
+	case len(key.Name) > 0:
+		param.Tag = reflect.StructTag(fmt.Sprintf("name:%q", key.Name))
+		opts = append(opts, dig.Name(key.Name))
+	}
+	paramType := reflect.StructOf([]reflect.StructField{
+		{Name: "In", Type: _typeOfIn, Anonymous: true},
+		param,
+	})
+
+	ft := reflect.FuncOf(nil, []reflect.Type{result, _typeOfError}, false)
+	ctor := reflect.MakeFunc(ft, func([]reflect.Value) []reflect.Value {
+		var value reflect.Value
+		fn := reflect.MakeFunc(
+			reflect.FuncOf([]reflect.Type{paramType}, nil, false),
+			func(args []reflect.Value) []reflect.Value {
+				value = args[0].Field(1)
+				return nil
+			},
+		)
+		if err := app.root.scope.Invoke(fn.Interface()); err != nil {
+			// Report why the value couldn't be built,
+			// rather than that fn's arguments couldn't be.
+			if cause := errors.Unwrap(err); cause != nil {
+				err = cause
+			}
+			return []reflect.Value{reflect.Zero(result), reflect.ValueOf(&err).Elem()}
+		}
+		if len(key.Group) > 0 {
+			out := reflect.New(result).Elem()
+			out.Field(1).Set(value)
+			value = out
+		}
+		return []reflect.Value{value, _nilError}
+	})
+	return ctor.Interface(), opts
+}
+
+// rejectInTrial reports whether m is within a trial created by App.Try or
+// App.NewScope, recording an error if so. It's used by options that affect
+// the whole application, which would otherwise outlive the trial.
+func (m *module) rejectInTrial(option string) bool {
+	t := m.trialRoot()
+	if t == nil {
+		return false
+	}
+	m.app.err = fmt.Errorf("%v Option cannot be used within %v: "+
+		"it affects the whole application", option, t.trial)
+	return true
+}
+
+// discardLifecycle is the Lifecycle available within App.Try.
+type discardLifecycle struct{}
+
+func (discardLifecycle) Append(Hook) {}
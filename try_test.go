@@ -0,0 +1,261 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestTry(t *testing.T) {
+	t.Parallel()
+
+	type config struct{ name string }
+	type feature struct{ name string }
+
+	newFeature := func(c *config) *feature {
+		return &feature{name: c.name}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t, Supply(&config{name: "foo"}))
+
+		var got *feature
+		require.NoError(t, app.Try(
+			Provide(newFeature),
+			Invoke(func(f *feature) { got = f }),
+		))
+		require.NotNil(t, got)
+		assert.Equal(t, "foo", got.name)
+
+		app.RequireStart().RequireStop()
+	})
+
+	t.Run("failure leaves app unaffected", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t, Supply(&config{name: "foo"}))
+
+		err := app.Try(
+			Provide(newFeature),
+			Invoke(func(*feature) error { return errors.New("great sadness") }),
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+		assert.NoError(t, app.Err())
+
+		// The failed trial's constructor is gone:
+		// providing it again does not conflict.
+		require.NoError(t, app.Try(
+			Provide(newFeature),
+			Invoke(func(*feature) {}),
+		))
+
+		app.RequireStart().RequireStop()
+	})
+
+	t.Run("provided values do not leak", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t, Supply(&config{name: "foo"}))
+
+		require.NoError(t, app.Try(
+			Module("nested", Provide(newFeature)),
+			Invoke(func(*feature) {}),
+		))
+
+		err := app.Try(Invoke(func(*feature) {}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing type: *fx_test.feature")
+	})
+
+	t.Run("provide error", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t)
+
+		err := app.Try(
+			Provide(newFeature),
+			Invoke(func(*feature) {}),
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing type: *fx_test.config")
+		assert.NoError(t, app.Err())
+
+		app.RequireStart().RequireStop()
+	})
+
+	t.Run("top-level option", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t)
+
+		err := app.Try(StartTimeout(time.Second))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.StartTimeout Option should be passed to top-level App")
+		assert.NoError(t, app.Err())
+	})
+
+	t.Run("app-wide options", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			desc    string
+			give    Option
+			wantErr string
+		}{
+			{
+				desc:    "BeforeStop",
+				give:    BeforeStop(func() { t.Error("BeforeStop from a trial must not run") }),
+				wantErr: "fx.BeforeStop Option cannot be used within App.Try",
+			},
+			{
+				desc: "StartMiddleware",
+				give: StartMiddleware(func(ctx context.Context, next func(context.Context) error) error {
+					t.Error("StartMiddleware from a trial must not run")
+					return next(ctx)
+				}),
+				wantErr: "fx.StartMiddleware Option cannot be used within App.Try",
+			},
+			{
+				desc:    "MapResult",
+				give:    MapResult(new(*feature), func(f *feature) *feature { return f }),
+				wantErr: "fx.MapResult Option cannot be used within App.Try",
+			},
+			{
+				desc:    "OnEvent in a module",
+				give:    Module("child", OnEvent(func(fxevent.Event) {})),
+				wantErr: "fx.OnEvent Option cannot be used within App.Try",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				app := fxtest.New(t)
+				err := app.Try(tt.give)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.NoError(t, app.Err())
+				app.RequireStart().RequireStop()
+			})
+		}
+	})
+
+	t.Run("hooks are not run", func(t *testing.T) {
+		t.Parallel()
+
+		var started, stopped bool
+		app := fxtest.New(t)
+
+		require.NoError(t, app.Try(
+			Invoke(func(lc Lifecycle) {
+				lc.Append(Hook{
+					OnStart: func(context.Context) error {
+						started = true
+						return nil
+					},
+					OnStop: func(context.Context) error {
+						stopped = true
+						return nil
+					},
+				})
+			}),
+		))
+
+		app.RequireStart().RequireStop()
+		assert.False(t, started, "OnStart must not run")
+		assert.False(t, stopped, "OnStop must not run")
+	})
+
+	t.Run("app error", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, Invoke(func() error { return errors.New("great sadness") }))
+
+		var called bool
+		err := app.Try(Invoke(func() { called = true }))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+		assert.False(t, called, "trial must not run")
+	})
+
+	t.Run("container use from the trial", func(t *testing.T) {
+		t.Parallel()
+
+		app := fxtest.New(t,
+			Provide(func() *config { return &config{name: "app"} }),
+			Invoke(func(*config) {}),
+		)
+
+		tests := []struct {
+			desc string
+			call func() error
+		}{
+			{desc: "Try", call: func() error { return app.Try() }},
+			{desc: "NewScope", call: func() error {
+				_, err := app.NewScope()
+				return err
+			}},
+			{desc: "Resolve", call: func() error {
+				_, err := Resolve[*config](app.App)
+				return err
+			}},
+			{desc: "HealthCheck", call: func() error {
+				_, err := app.HealthCheck(context.Background())
+				return err
+			}},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				done := make(chan error, 1)
+				go func() {
+					done <- app.Try(Invoke(tt.call))
+				}()
+
+				select {
+				case err := <-done:
+					require.Error(t, err)
+					assert.Contains(t, err.Error(), "the container is in use by a function run by App.Try")
+				case <-time.After(5 * time.Second):
+					t.Fatal("using the container from a trial deadlocked")
+				}
+
+				// The container is usable again.
+				require.NoError(t, tt.call())
+			})
+		}
+	})
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.uber.org/fx/internal/fxreflect"
+)
+
+// Undecorate opts a module out of the decorations its ancestors apply to
+// the given types. Arguments must be pointers to the types,
+// as with [Populate].
+//
+// For example, given a parent that wraps every Handler with
+// authentication middleware,
+//
+//	fx.Module("server",
+//		fx.Provide(NewHandler),
+//		fx.Decorate(WithAuth),
+//		fx.Module("health",
+//			fx.Undecorate(new(Handler)),
+//			fx.Invoke(RegisterHealthCheck),
+//		),
+//	)
+//
+// RegisterHealthCheck receives the Handler as returned by NewHandler,
+// while the rest of the "server" module receives the decorated Handler.
+//
+// Undecorate follows the same scoping rules as [Decorate]:
+// it affects the module that declares it and that module's descendants,
+// and it never affects the module's parent or siblings.
+// A descendant that decorates the type again sees,
+// and decorates, the undecorated value.
+// A module may not both Undecorate and Decorate the same type.
+//
+// The undecorated value is the one built by the constructor or [Supply]
+// for the type that the module would otherwise depend on,
+// after any [MapResult] was applied: the closest one provided to the module
+// or its ancestors, or the one exported by any module.
+// Values provided privately to other modules are never used.
+// Undecorate fails if the value was not built by a constructor,
+// such as when an ancestor uses [Replace] for it.
+func Undecorate(targets ...interface{}) Option {
+	return undecorateOption{
+		Targets: targets,
+		Stack:   fxreflect.CallerStack(1, 0),
+	}
+}
+
+type undecorateOption struct {
+	Targets []interface{}
+	Stack   fxreflect.Stack
+}
+
+func (o undecorateOption) apply(m *module) {
+	m.app.undecorate = true
+	for _, target := range o.Targets {
+		typ := reflect.TypeOf(target)
+		if typ == nil || typ.Kind() != reflect.Ptr {
+			m.app.err = fmt.Errorf("fx.Undecorate: target must be a pointer to a type, got %T", target)
+			return
+		}
+		typ = typ.Elem()
+
+		m.decorators = append(m.decorators, decorator{
+			Target:         newUndecorator(m, typ),
+			Stack:          o.Stack,
+			IsUndecorate:   true,
+			UndecorateType: typ,
+		})
+	}
+}
+
+func (o undecorateOption) String() string {
+	items := make([]string, len(o.Targets))
+	for i, target := range o.Targets {
+		items[i] = fmt.Sprint(reflect.TypeOf(target).Elem())
+	}
+	return fmt.Sprintf("fx.Undecorate(%s)", strings.Join(items, ", "))
+}
+
+// Returns a decorator for typ that replaces the decorated value
+// with the value built for typ by the constructor visible from m.
+//
+// The decorator accepts typ so that resolving it builds the value,
+// and runs any decorators of the ancestor modules as usual.
+func newUndecorator(m *module, typ reflect.Type) interface{} {
+	ft := reflect.FuncOf([]reflect.Type{typ}, []reflect.Type{typ, _typeOfError}, false)
+	return reflect.MakeFunc(ft, func([]reflect.Value) []reflect.Value {
+		v, ok := m.rawValue(typ)
+		if !ok {
+			err := fmt.Errorf("no %v was built by a constructor", typ)
+			return []reflect.Value{reflect.Zero(typ), reflect.ValueOf(&err).Elem()}
+		}
+		value := reflect.New(typ).Elem()
+		if v != nil {
+			value.Set(reflect.ValueOf(v))
+		}
+		return []reflect.Value{value, reflect.Zero(_typeOfError)}
+	}).Interface()
+}
+
+// rawValueTracker returns the tracker that records the values built by
+// constructors provided to m, or nil if no module uses Undecorate.
+// Exported constructors are visible from every module,
+// so the values they build are recorded by the top-level module.
+func (m *module) rawValueTracker(export bool) *instanceTracker {
+	if !m.app.undecorate {
+		return nil
+	}
+	if export {
+		m = m.app.root
+	}
+	if m.rawValues == nil {
+		m.rawValues = &instanceTracker{
+			values: make(map[reflect.Type]interface{}),
+		}
+	}
+	return m.rawValues
+}
+
+// rawValue returns the value of type typ built by the constructor that
+// m depends on for it, mirroring how the container resolves it:
+// the closest module, starting with m, whose constructors built one.
+func (m *module) rawValue(typ reflect.Type) (interface{}, bool) {
+	for mod := m; mod != nil; mod = mod.parent {
+		if mod.rawValues == nil {
+			continue
+		}
+		if v, ok := mod.rawValues.get(typ); ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
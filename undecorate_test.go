@@ -0,0 +1,175 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+type handler interface{ Name() string }
+
+type namedHandler string
+
+func (h namedHandler) Name() string { return string(h) }
+
+func TestUndecorate(t *testing.T) {
+	t.Parallel()
+
+	withAuth := func(h handler) handler { return namedHandler("auth(" + h.Name() + ")") }
+
+	t.Run("child sees raw value", func(t *testing.T) {
+		t.Parallel()
+
+		var root, child, sibling, grandchild handler
+		app := fxtest.New(t,
+			fx.Provide(func() handler { return namedHandler("raw") }),
+			fx.Decorate(withAuth),
+			fx.Populate(&root),
+			fx.Module("child",
+				fx.Undecorate(new(handler)),
+				fx.Populate(&child),
+				fx.Module("grandchild",
+					fx.Populate(&grandchild),
+				),
+			),
+			fx.Module("sibling",
+				fx.Populate(&sibling),
+			),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "auth(raw)", root.Name())
+		assert.Equal(t, "raw", child.Name())
+		assert.Equal(t, "raw", grandchild.Name())
+		assert.Equal(t, "auth(raw)", sibling.Name(), "sibling must not be affected")
+	})
+
+	t.Run("descendant decorates raw value", func(t *testing.T) {
+		t.Parallel()
+
+		var got handler
+		app := fxtest.New(t,
+			fx.Provide(func() handler { return namedHandler("raw") }),
+			fx.Decorate(withAuth),
+			fx.Module("child",
+				fx.Undecorate(new(handler)),
+				fx.Module("grandchild",
+					fx.Decorate(func(h handler) handler {
+						return namedHandler("log(" + h.Name() + ")")
+					}),
+					fx.Populate(&got),
+				),
+			),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "log(raw)", got.Name())
+	})
+
+	t.Run("provided by child", func(t *testing.T) {
+		t.Parallel()
+
+		type config struct{ Name string }
+
+		var got *config
+		app := fxtest.New(t,
+			fx.Decorate(func(c *config) *config { return &config{Name: "decorated"} }),
+			fx.Module("child",
+				fx.Supply(&config{Name: "raw"}),
+				fx.Module("grandchild",
+					fx.Undecorate(new(*config)),
+					fx.Populate(&got),
+				),
+			),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "raw", got.Name)
+	})
+
+	t.Run("private value of a sibling", func(t *testing.T) {
+		t.Parallel()
+
+		var first, sibling, got handler
+		app := fxtest.New(t,
+			fx.Provide(func() handler { return namedHandler("root") }),
+			fx.Module("first", fx.Populate(&first)),
+			fx.Module("sibling",
+				fx.Provide(func() handler { return namedHandler("sibling") }, fx.Private),
+				fx.Populate(&sibling),
+			),
+			fx.Module("child",
+				fx.Undecorate(new(handler)),
+				fx.Populate(&got),
+			),
+		)
+		defer app.RequireStart().RequireStop()
+
+		assert.Equal(t, "root", first.Name())
+		assert.Equal(t, "sibling", sibling.Name())
+		assert.Equal(t, "root", got.Name(), "private values of siblings must not be used")
+	})
+
+	t.Run("replaced", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			fx.Provide(func() namedHandler { return "raw" }),
+			fx.Replace(namedHandler("stub")),
+			fx.Module("child",
+				fx.Undecorate(new(namedHandler)),
+				fx.Invoke(func(namedHandler) {}),
+			),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no fx_test.namedHandler was built by a constructor")
+	})
+
+	t.Run("decorated in the same module", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t,
+			fx.Provide(func() handler { return namedHandler("raw") }),
+			fx.Module("child",
+				fx.Undecorate(new(handler)),
+				fx.Decorate(withAuth),
+			),
+		)
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx_test.handler already decorated")
+	})
+
+	t.Run("target not a pointer", func(t *testing.T) {
+		t.Parallel()
+
+		app := NewForTest(t, fx.Undecorate(namedHandler("raw")))
+		err := app.Err()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fx.Undecorate: target must be a pointer to a type")
+	})
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.uber.org/multierr"
+)
+
+// ValidateAppStrict validates the application as [ValidateApp] does,
+// and also checks for wiring mistakes that Fx otherwise tolerates:
+//
+//   - functions passed to [Decorate] or [Replace] for a type
+//     that isn't provided to the module or its ancestors,
+//     which would never run
+//   - value groups consumed by a function that no constructor
+//     contributes to, which would always be empty
+//   - [ParamTags] or [ResultTags] given more tags than the annotated
+//     function has parameters or results, which would be ignored
+//
+// Like ValidateApp, it doesn't run any constructors or invoked functions.
+// All mistakes found are reported in the returned error.
+func ValidateAppStrict(opts ...Option) error {
+	opts = append(opts, validateOption{validate: true, strict: true})
+	app := New(opts...)
+
+	return app.Err()
+}
+
+// checkStrict runs the checks of ValidateAppStrict
+// on an application that has been built.
+func (app *App) checkStrict() error {
+	_, nodes := app.graph()
+
+	var err error
+	for _, n := range nodes {
+		switch n.Kind {
+		case "decorate", "replace":
+			for _, out := range n.Outputs {
+				if !isProduced(nodes, n.mod, out) {
+					err = multierr.Append(err, strictError(n.mod, n.Location,
+						"%v decorates %v, which is not provided", n.Name, describeValue(out)))
+				}
+			}
+		}
+		for _, in := range n.Inputs {
+			if len(in.Group) > 0 && !isProduced(nodes, n.mod, in) {
+				err = multierr.Append(err, strictError(n.mod, n.Location,
+					"%v consumes value group %q of %v, which no constructor contributes to",
+					n.Name, in.Group, in.key().Type))
+			}
+		}
+	}
+	return multierr.Append(err, app.root.checkAnnotations())
+}
+
+// isProduced reports whether a constructor or a supplied value provides
+// the value v to functions of module m.
+func isProduced(nodes []graphNodeRef, m *module, v GraphValue) bool {
+	key := v.key()
+	for _, p := range nodes {
+		switch p.Kind {
+		case "provide", "supply", "derive":
+			if p.serves(m, key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkAnnotations reports annotations of the functions passed to m and
+// its descendants that have more tags than the functions' signatures.
+func (m *module) checkAnnotations() error {
+	var targets []interface{}
+	for _, p := range m.provides {
+		targets = append(targets, p.Target)
+	}
+	for _, d := range m.decorators {
+		targets = append(targets, d.Target)
+	}
+	for _, i := range m.invokes {
+		targets = append(targets, i.Target)
+	}
+
+	var err error
+	for _, target := range targets {
+		ann, ok := target.(annotated)
+		if !ok {
+			continue
+		}
+		ft := reflect.TypeOf(ann.Target)
+		if ft == nil || ft.Kind() != reflect.Func {
+			continue // reported when the function is provided
+		}
+
+		numOut := ft.NumOut()
+		if numOut > 0 && ft.Out(numOut-1) == _typeOfError {
+			numOut--
+		}
+		if n := len(ann.ParamTags); n > ft.NumIn() {
+			err = multierr.Append(err, strictError(m, targetLocation(ann),
+				"%v has %d ParamTags for %d parameters", ann, n, ft.NumIn()))
+		}
+		if n := len(ann.ResultTags); n > numOut {
+			err = multierr.Append(err, strictError(m, targetLocation(ann),
+				"%v has %d ResultTags for %d results", ann, n, numOut))
+		}
+	}
+
+	for _, mod := range m.modules {
+		err = multierr.Append(err, mod.checkAnnotations())
+	}
+	return err
+}
+
+// strictError builds an error found by ValidateAppStrict
+// for a function of module m defined at the given location.
+func strictError(m *module, location, format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	if len(location) > 0 {
+		msg += fmt.Sprintf(" (%v)", location)
+	}
+	if m.parent != nil {
+		msg += fmt.Sprintf(" in module %q", m.name)
+	}
+	return fmt.Errorf("fx.ValidateAppStrict: %v", msg)
+}
+
+// describeValue renders v for error messages.
+func describeValue(v GraphValue) string {
+	switch {
+	case len(v.Name) > 0:
+		return fmt.Sprintf("%v[name=%q]", v.Type, v.Name)
+	case len(v.Group) > 0:
+		return fmt.Sprintf("value group %q of %v", v.Group, v.key().Type)
+	default:
+		return v.Type
+	}
+}
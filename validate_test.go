@@ -0,0 +1,128 @@
+// Copyright (c) 2022 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+func TestValidateAppStrict(t *testing.T) {
+	t.Parallel()
+
+	type (
+		A struct{}
+		B struct{}
+	)
+
+	tests := []struct {
+		desc     string
+		give     []fx.Option
+		wantErrs []string
+	}{
+		{
+			desc: "valid",
+			give: []fx.Option{
+				fx.Provide(func() A { return A{} }),
+				fx.Provide(fx.Annotate(func(A) B { return B{} }, fx.ResultTags(`group:"bs"`))),
+				fx.Module("child",
+					fx.Decorate(func(a A) A { return a }),
+					fx.Invoke(fx.Annotate(func([]B) {}, fx.ParamTags(`group:"bs"`))),
+				),
+			},
+		},
+		{
+			desc: "decorated type not provided",
+			give: []fx.Option{
+				fx.Module("child", fx.Decorate(func(a A) A { return a })),
+			},
+			wantErrs: []string{
+				"fx.ValidateAppStrict: go.uber.org/fx_test.TestValidateAppStrict.func",
+				"decorates fx_test.A, which is not provided (",
+				`validate_test.go:`,
+				`) in module "child"`,
+			},
+		},
+		{
+			desc: "replaced type not provided",
+			give: []fx.Option{
+				fx.Replace(A{}),
+			},
+			wantErrs: []string{"decorates fx_test.A, which is not provided"},
+		},
+		{
+			desc: "decorated type private to another module",
+			give: []fx.Option{
+				fx.Module("a", fx.Provide(func() A { return A{} }, fx.Private)),
+				fx.Module("b", fx.Decorate(func(a A) A { return a })),
+			},
+			wantErrs: []string{"decorates fx_test.A, which is not provided"},
+		},
+		{
+			desc: "value group without contributors",
+			give: []fx.Option{
+				fx.Invoke(fx.Annotate(func([]B) {}, fx.ParamTags(`group:"bs"`))),
+			},
+			wantErrs: []string{`consumes value group "bs" of fx_test.B, ` +
+				"which no constructor contributes to"},
+		},
+		{
+			desc: "too many tags",
+			give: []fx.Option{
+				fx.Provide(fx.Annotate(
+					func(A) (B, error) { return B{}, nil },
+					fx.ParamTags(`name:"a"`, `name:"b"`),
+					fx.ResultTags(`name:"a"`, `name:"b"`),
+				)),
+				fx.Supply(fx.Annotate(A{}, fx.ResultTags(`name:"a"`))),
+			},
+			wantErrs: []string{
+				"has 2 ParamTags for 1 parameters",
+				"has 2 ResultTags for 1 results",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			opts := append([]fx.Option{fx.NopLogger}, tt.give...)
+			err := fx.ValidateAppStrict(opts...)
+			if len(tt.wantErrs) == 0 {
+				assert.NoError(t, err)
+				require.NoError(t, fx.ValidateApp(opts...))
+				return
+			}
+
+			require.Error(t, err)
+			for _, want := range tt.wantErrs {
+				assert.Contains(t, err.Error(), want)
+			}
+			assert.NoError(t, fx.ValidateApp(opts...),
+				"ValidateApp must not run the strict checks")
+		})
+	}
+}
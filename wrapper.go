@@ -0,0 +1,50 @@
+// Copyright (c) 2024 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fx
+
+import (
+	"reflect"
+
+	"go.uber.org/dig"
+)
+
+// provideWrapper provides to c a function of type ft that runs body
+// in place of the constructor fv, given the arguments dig passes it.
+// dig reports the function at the location of fv in errors and
+// visualizations, unless the options passed by the caller say otherwise.
+func provideWrapper(
+	c container,
+	fv reflect.Value,
+	ft reflect.Type,
+	body func(args []reflect.Value) []reflect.Value,
+	opts []dig.ProvideOption,
+) error {
+	opts = append([]dig.ProvideOption{dig.LocationForPC(fv.Pointer())}, opts...)
+	return c.Provide(reflect.MakeFunc(ft, body).Interface(), opts...)
+}
+
+// callFunc calls fv with args, whether or not it's variadic.
+func callFunc(fv reflect.Value, args []reflect.Value) []reflect.Value {
+	if fv.Type().IsVariadic() {
+		return fv.CallSlice(args)
+	}
+	return fv.Call(args)
+}